@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"PhoenixOracle/web/presenters"
+	"github.com/urfave/cli"
+	"go.uber.org/multierr"
+)
+
+type AlertNotifierPresenter struct {
+	JAID
+	presenters.AlertNotifierResource
+}
+
+func (p *AlertNotifierPresenter) ToRow() []string {
+	config, err := json.Marshal(p.Config)
+	if err != nil {
+		panic(err)
+	}
+
+	return []string{
+		p.GetID(),
+		p.Name,
+		string(p.Type),
+		string(config),
+		p.CreatedAt.String(),
+	}
+}
+
+var alertNotifierHeaders = []string{"ID", "Name", "Type", "Config", "Created"}
+
+// RenderTable implements TableRenderer
+func (p *AlertNotifierPresenter) RenderTable(rt RendererTable) error {
+	renderList(alertNotifierHeaders, [][]string{p.ToRow()}, rt.Writer)
+	return nil
+}
+
+type AlertNotifierPresenters []AlertNotifierPresenter
+
+// RenderTable implements TableRenderer
+func (ps AlertNotifierPresenters) RenderTable(rt RendererTable) error {
+	rows := [][]string{}
+	for _, p := range ps {
+		rows = append(rows, p.ToRow())
+	}
+	renderList(alertNotifierHeaders, rows, rt.Writer)
+	return nil
+}
+
+type AlertRulePresenter struct {
+	JAID
+	presenters.AlertRuleResource
+}
+
+func (p *AlertRulePresenter) ToRow() []string {
+	ids := []string{}
+	for _, id := range p.NotifierIDs {
+		ids = append(ids, strconv.FormatInt(id, 10))
+	}
+	lastFired := ""
+	if p.LastFiredAt.Valid {
+		lastFired = p.LastFiredAt.Time.String()
+	}
+
+	return []string{
+		p.GetID(),
+		p.Name,
+		string(p.Type),
+		p.Threshold.String(),
+		p.Window,
+		strings.Join(ids, ","),
+		strconv.FormatBool(p.Enabled),
+		lastFired,
+	}
+}
+
+var alertRuleHeaders = []string{"ID", "Name", "Type", "Threshold", "Window", "Notifiers", "Enabled", "Last Fired"}
+
+// RenderTable implements TableRenderer
+func (p *AlertRulePresenter) RenderTable(rt RendererTable) error {
+	renderList(alertRuleHeaders, [][]string{p.ToRow()}, rt.Writer)
+	return nil
+}
+
+type AlertRulePresenters []AlertRulePresenter
+
+// RenderTable implements TableRenderer
+func (ps AlertRulePresenters) RenderTable(rt RendererTable) error {
+	rows := [][]string{}
+	for _, p := range ps {
+		rows = append(rows, p.ToRow())
+	}
+	renderList(alertRuleHeaders, rows, rt.Writer)
+	return nil
+}
+
+func (cli *Client) ListAlertNotifiers(c *cli.Context) (err error) {
+	return cli.getPage("/v2/alerts/notifiers", 0, &AlertNotifierPresenters{})
+}
+
+func (cli *Client) CreateAlertNotifier(c *cli.Context) (err error) {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("must pass in the notifier's parameters [JSON blob | JSON filepath]"))
+	}
+
+	buf, err := getBufferFromJSON(c.Args().First())
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	resp, err := cli.HTTP.Post("/v2/alerts/notifiers", buf)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+
+	return cli.renderAPIResponse(resp, &AlertNotifierPresenter{}, "Notifier created")
+}
+
+func (cli *Client) DeleteAlertNotifier(c *cli.Context) (err error) {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("must pass the id of the notifier to be removed"))
+	}
+	resp, err := cli.HTTP.Delete("/v2/alerts/notifiers/" + c.Args().First())
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	_, err = cli.parseResponse(resp)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	fmt.Printf("Notifier %v deleted\n", c.Args().First())
+	return nil
+}
+
+// TestAlertNotifier fires a synthetic alert through the given notifier.
+func (cli *Client) TestAlertNotifier(c *cli.Context) (err error) {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("must pass the id of the notifier to test"))
+	}
+	resp, err := cli.HTTP.Post("/v2/alerts/notifiers/"+c.Args().First()+"/test", nil)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	_, err = cli.parseResponse(resp)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	fmt.Printf("Test alert sent through notifier %v\n", c.Args().First())
+	return nil
+}
+
+func (cli *Client) ListAlertRules(c *cli.Context) (err error) {
+	return cli.getPage("/v2/alerts/rules", 0, &AlertRulePresenters{})
+}
+
+func (cli *Client) CreateAlertRule(c *cli.Context) (err error) {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("must pass in the rule's parameters [JSON blob | JSON filepath]"))
+	}
+
+	buf, err := getBufferFromJSON(c.Args().First())
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	resp, err := cli.HTTP.Post("/v2/alerts/rules", buf)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+
+	return cli.renderAPIResponse(resp, &AlertRulePresenter{}, "Rule created")
+}
+
+func (cli *Client) DeleteAlertRule(c *cli.Context) (err error) {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("must pass the id of the rule to be removed"))
+	}
+	resp, err := cli.HTTP.Delete("/v2/alerts/rules/" + c.Args().First())
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	_, err = cli.parseResponse(resp)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	fmt.Printf("Rule %v deleted\n", c.Args().First())
+	return nil
+}
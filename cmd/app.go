@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"time"
 
 	"PhoenixOracle/build/static"
 	"github.com/urfave/cli"
@@ -50,6 +51,60 @@ func NewApp(client *Client) *cli.App {
 			},
 		},
 
+		{
+			Name:  "alerts",
+			Usage: "Commands for managing balance and health alerting",
+			Subcommands: []cli.Command{
+				{
+					Name:  "notifiers",
+					Usage: "Commands for managing alert notifiers (webhook, smtp, slack)",
+					Subcommands: cli.Commands{
+						{
+							Name:   "list",
+							Usage:  "List all alert notifiers",
+							Action: client.ListAlertNotifiers,
+						},
+						{
+							Name:   "create",
+							Usage:  "Create an alert notifier from a JSON blob or file",
+							Action: client.CreateAlertNotifier,
+						},
+						{
+							Name:   "delete",
+							Usage:  "Delete an alert notifier by ID",
+							Action: client.DeleteAlertNotifier,
+						},
+						{
+							Name:   "test",
+							Usage:  "Send a test alert through a notifier by ID",
+							Action: client.TestAlertNotifier,
+						},
+					},
+				},
+				{
+					Name:  "rules",
+					Usage: "Commands for managing alert rules",
+					Subcommands: cli.Commands{
+						{
+							Name:   "list",
+							Usage:  "List all alert rules",
+							Action: client.ListAlertRules,
+						},
+						{
+							Name:   "create",
+							Usage:  "Create an alert rule from a JSON blob or file",
+							Action: client.CreateAlertRule,
+						},
+						{
+							Name:   "delete",
+							Usage:  "Delete an alert rule by ID",
+							Action: client.DeleteAlertRule,
+						},
+					},
+				},
+			},
+		},
+
 		{
 			Name:  "config",
 			Usage: "Commands for the node's configuration",
@@ -180,6 +235,312 @@ func NewApp(client *Client) *cli.App {
 					Name:   "run",
 					Usage:  "Trigger a V2 job run",
 					Action: client.TriggerPipelineRun,
+					Flags: []cli.Flag{
+						cli.BoolFlag{
+							Name:  "sync",
+							Usage: "wait for the run to finish and show its outputs and errors",
+						},
+						cli.DurationFlag{
+							Name:  "timeout",
+							Usage: "how long to wait for the run with --sync, at most HTTP_SERVER_WRITE_TIMEOUT less a margin on the node",
+							Value: 5 * time.Second,
+						},
+					},
+				},
+				{
+					Name:   "ocr-status",
+					Usage:  "Show the round, epoch, peers and recent activity of an OCR job",
+					Action: client.ShowOCRStatus,
+				},
+			},
+		},
+		{
+			Name:  "ocr",
+			Usage: "Commands for administering OffchainAggregator contracts",
+			Subcommands: []cli.Command{
+				{
+					Name:   "set-config",
+					Usage:  format(`Generate a config with fresh encrypted shared secrets for an OffchainAggregator contract and print its setConfig calldata, or submit it with --send`),
+					Action: client.SetOCRConfig,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "contract",
+							Usage: "address of the OffchainAggregator contract (required)",
+						},
+						cli.StringFlag{
+							Name:  "oracles",
+							Usage: "`FILE` containing a JSON list of oracles, each with onChainSigningAddress, offChainPublicKey, configPublicKey, peerID and transmitterAddress (required)",
+						},
+						cli.IntFlag{
+							Name:  "f",
+							Usage: "maximum number of faulty oracles",
+							Value: 1,
+						},
+						cli.DurationFlag{
+							Name:  "delta-progress",
+							Usage: "duration after which an epoch without progress is abandoned",
+							Value: 35 * time.Second,
+						},
+						cli.DurationFlag{
+							Name:  "delta-resend",
+							Usage: "interval at which oracles resend their epoch state",
+							Value: 17 * time.Second,
+						},
+						cli.DurationFlag{
+							Name:  "delta-round",
+							Usage: "duration of a round",
+							Value: 30 * time.Second,
+						},
+						cli.DurationFlag{
+							Name:  "delta-grace",
+							Usage: "grace period for late observations",
+							Value: 12 * time.Second,
+						},
+						cli.DurationFlag{
+							Name:  "delta-c",
+							Usage: "heartbeat: maximum time between transmissions",
+							Value: 10 * time.Minute,
+						},
+						cli.DurationFlag{
+							Name:  "delta-stage",
+							Usage: "delay between stages of the transmission schedule",
+							Value: 1 * time.Minute,
+						},
+						cli.Uint64Flag{
+							Name:  "alpha-ppb",
+							Usage: "deviation threshold in parts per billion",
+							Value: 10000000,
+						},
+						cli.UintFlag{
+							Name:  "r-max",
+							Usage: "maximum number of rounds in an epoch",
+							Value: 3,
+						},
+						cli.IntSliceFlag{
+							Name:  "s",
+							Usage: "transmission schedule, one entry per stage (defaults to one oracle per stage)",
+						},
+						cli.BoolFlag{
+							Name:  "skip-chain-checks",
+							Usage: "skip the minimum timings enforced for well-known chains",
+						},
+						cli.BoolFlag{
+							Name:  "send",
+							Usage: "submit the setConfig transaction through the node's transaction manager",
+						},
+						cli.StringFlag{
+							Name:  "from",
+							Usage: "node ETH address to send the transaction from (required with --send)",
+						},
+						cli.Uint64Flag{
+							Name:  "gas-limit",
+							Usage: "gas limit of the transaction (defaults to ETH_GAS_LIMIT_DEFAULT)",
+						},
+					},
+				},
+				{
+					Name:   "costs",
+					Usage:  format(`Show the gas used and ETH spent on OCR transmissions per job and period, including reverted and failed transmissions`),
+					Action: client.ShowOCRTransmissionCosts,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "job-id",
+							Usage: "only report on this job",
+						},
+						cli.StringFlag{
+							Name:  "from",
+							Usage: "RFC3339 start of the report (defaults to 30 days before --to)",
+						},
+						cli.StringFlag{
+							Name:  "to",
+							Usage: "RFC3339 end of the report (defaults to now)",
+						},
+						cli.StringFlag{
+							Name:  "period",
+							Usage: "aggregate per \"day\" or \"month\"",
+							Value: "day",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:  "operator",
+			Usage: "Commands for the Operator contracts served by direct request jobs",
+			Subcommands: []cli.Command{
+				{
+					Name:   "earnings",
+					Usage:  format(`Show the requests fulfilled and PHB earned by direct request jobs per job or requester and period`),
+					Action: client.ShowOperatorEarnings,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "job-id",
+							Usage: "only report on this job",
+						},
+						cli.StringFlag{
+							Name:  "requester",
+							Usage: "only report on requests from this address",
+						},
+						cli.StringFlag{
+							Name:  "from",
+							Usage: "RFC3339 start of the report (defaults to 30 days before --to)",
+						},
+						cli.StringFlag{
+							Name:  "to",
+							Usage: "RFC3339 end of the report (defaults to now)",
+						},
+						cli.StringFlag{
+							Name:  "group-by",
+							Usage: "aggregate per \"job\" or \"requester\"",
+							Value: "job",
+						},
+						cli.StringFlag{
+							Name:  "period",
+							Usage: "aggregate per \"day\" or \"month\"",
+							Value: "day",
+						},
+					},
+				},
+				{
+					Name:   "withdraw",
+					Usage:  format(`Withdraw PHB earned by an Operator contract, sending the transaction from the operator owner's key`),
+					Action: client.WithdrawOperatorPHB,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "contract",
+							Usage: "address of the Operator contract (required)",
+						},
+						cli.StringFlag{
+							Name:  "recipient",
+							Usage: "address to send the PHB to (required)",
+						},
+						cli.StringFlag{
+							Name:  "amount",
+							Usage: "amount to withdraw in juels (defaults to everything withdrawable)",
+						},
+						cli.StringFlag{
+							Name:  "from",
+							Usage: "node ETH address to send the transaction from (defaults to the operator owner)",
+						},
+						cli.Uint64Flag{
+							Name:  "gas-limit",
+							Usage: "gas limit of the transaction (defaults to ETH_GAS_LIMIT_DEFAULT)",
+						},
+					},
+				},
+				{
+					Name:  "policies",
+					Usage: "Commands for the node's requester policies, applied by every direct request job",
+					Subcommands: []cli.Command{
+						{
+							Name:   "list",
+							Usage:  "List the requester policies, the default policy first",
+							Action: client.ListRequesterPolicies,
+						},
+						{
+							Name:   "set",
+							Usage:  format(`Set the policy of a requester, or the default policy for all requesters without --requester, replacing any existing one`),
+							Action: client.SetRequesterPolicy,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "requester",
+									Usage: "address of the requester (omit for the default policy)",
+								},
+								cli.BoolFlag{
+									Name:  "deny",
+									Usage: "reject all requests; deny by default and allow some requesters for an allow-list",
+								},
+								cli.StringFlag{
+									Name:  "min-payment",
+									Usage: "minimum payment in juels, overriding that of the job and node",
+								},
+								cli.StringFlag{
+									Name:  "min-payment-per-gwei",
+									Usage: "minimum payment in juels per gwei of the current gas price",
+								},
+								cli.UintFlag{
+									Name:  "max-requests",
+									Usage: "maximum number of requests accepted per --rate-limit-period, across all jobs",
+								},
+								cli.DurationFlag{
+									Name:  "rate-limit-period",
+									Usage: "period of the rate limit (required with --max-requests)",
+								},
+								cli.BoolFlag{
+									Name:  "no-rate-limit",
+									Usage: "exempt the requester from the rate limit of the default policy",
+								},
+							},
+						},
+						{
+							Name:   "delete",
+							Usage:  "Delete a requester policy by ID",
+							Action: client.DeleteRequesterPolicy,
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:  "p2p",
+			Usage: "Commands for inspecting and managing the node's P2P connectivity",
+			Subcommands: []cli.Command{
+				{
+					Name:   "status",
+					Usage:  format(`Show connections, known peer addresses, discovered announcements and message counters of the P2P peer`),
+					Action: client.ShowP2PDiagnostics,
+				},
+				{
+					Name:   "add-peer",
+					Usage:  format(`Add a static address for a remote peer and dial it`),
+					Action: client.AddP2PPeer,
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "peer-id",
+							Usage: "peer ID of the remote peer (required)",
+						},
+						cli.StringFlag{
+							Name:  "address",
+							Usage: "multiaddr of the remote peer, e.g. /ip4/1.2.3.4/tcp/6690 (required)",
+						},
+					},
+				},
+				{
+					Name:   "ban",
+					Usage:  format(`Disconnect from a remote peer and refuse connections with it until it is unbanned`),
+					Action: client.BanP2PPeer,
+				},
+				{
+					Name:   "unban",
+					Usage:  format(`Lift the ban of a remote peer`),
+					Action: client.UnbanP2PPeer,
+				},
+				{
+					Name:  "bootstrap-lists",
+					Usage: "Commands for managing shared bootstrap peer lists, referenced by OCR jobs with p2pBootstrapPeerList",
+					Subcommands: []cli.Command{
+						{
+							Name:   "list",
+							Usage:  format(`List the shared bootstrap peer lists`),
+							Action: client.ListP2PBootstrapPeerLists,
+						},
+						{
+							Name:   "set",
+							Usage:  format(`Create a bootstrap peer list or replace its peers. Running jobs pick up the change without a restart.`),
+							Action: client.SetP2PBootstrapPeerList,
+							Flags: []cli.Flag{
+								cli.StringSliceFlag{
+									Name:  "peer",
+									Usage: "multiaddr of a bootstrap peer, e.g. /ip4/1.2.3.4/tcp/6690/p2p/12D3KooW...; may be repeated",
+								},
+							},
+						},
+						{
+							Name:   "delete",
+							Usage:  format(`Delete a bootstrap peer list that no job references`),
+							Action: client.DeleteP2PBootstrapPeerList,
+						},
+					},
 				},
 			},
 		},
@@ -187,6 +548,29 @@ func NewApp(client *Client) *cli.App {
 			Name:  "keys",
 			Usage: "Commands for managing various types of keys used by the Oracle node",
 			Subcommands: []cli.Command{
+				{
+					Name:  "rotate-password",
+					Usage: format(`Re-encrypt all keys in the node's keystore with a new password`),
+					Flags: []cli.Flag{
+						cli.StringFlag{
+							Name:  "oldpassword",
+							Usage: "`FILE` containing the current keystore password (required)",
+						},
+						cli.StringFlag{
+							Name:  "newpassword",
+							Usage: "`FILE` containing the new keystore password (required)",
+						},
+						cli.IntFlag{
+							Name:  "scrypt-n",
+							Usage: "optional new scrypt N parameter (must be a power of 2)",
+						},
+						cli.IntFlag{
+							Name:  "scrypt-p",
+							Usage: "optional new scrypt P parameter",
+						},
+					},
+					Action: client.RotateKeystorePassword,
+				},
 				{
 					Name:  "eth",
 					Usage: "Remote commands for administering the node's Ethereum keys",
@@ -201,6 +585,25 @@ func NewApp(client *Client) *cli.App {
 							Usage:  "List available Ethereum accounts with their ETH & PHB balances, nonces, and other metadata",
 							Action: client.ListETHKeys,
 						},
+						{
+							Name:  "add-external",
+							Usage: format(`Register an ETH key held by an external signer (Clef or PKCS#11), by address`),
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "signer",
+									Usage: "external signer type, one of: clef, pkcs11",
+								},
+								cli.StringFlag{
+									Name:  "key-ref",
+									Usage: "reference of the key within the signer (the object label for pkcs11)",
+								},
+								cli.BoolFlag{
+									Name:  "funding",
+									Usage: "mark the key as a funding key",
+								},
+							},
+							Action: client.AddExternalETHKey,
+						},
 						{
 							Name:  "delete",
 							Usage: format(`Delete the ETH key by address`),
@@ -518,6 +921,82 @@ func NewApp(client *Client) *cli.App {
 						},
 					},
 				},
+				{
+					Name:  "vrf",
+					Usage: "Commands for reproducing and checking VRF proofs offline, e.g. to investigate a disputed request",
+					Subcommands: []cli.Command{
+						{
+							Name:   "generate-proof",
+							Usage:  "Generate the proof for a randomness request with a VRF key from the local keystore, as the vrf and vrfv2 tasks do",
+							Action: client.GenerateVRFProof,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "password, p",
+									Usage: "text file holding the password for the node's account",
+								},
+								cli.StringFlag{
+									Name:  "publicKey, pk",
+									Usage: "public key of the VRF key to prove with",
+								},
+								cli.StringFlag{
+									Name:  "preSeed",
+									Usage: "preSeed of the request, in decimal or 0x-prefixed hex",
+								},
+								cli.StringFlag{
+									Name:  "blockHash",
+									Usage: "hash of the block the request was made in",
+								},
+								cli.Uint64Flag{
+									Name:  "blockNum",
+									Usage: "number of the block the request was made in",
+								},
+								cli.BoolFlag{
+									Name:  "v2",
+									Usage: "generate a proof for VRFCoordinatorV2, as the vrfv2 task does",
+								},
+								cli.Uint64Flag{
+									Name:  "subId",
+									Usage: "v2 only: subscription ID of the request",
+								},
+								cli.UintFlag{
+									Name:  "callbackGasLimit",
+									Usage: "v2 only: callback gas limit of the request",
+								},
+								cli.UintFlag{
+									Name:  "numWords",
+									Usage: "v2 only: number of random words requested",
+								},
+								cli.StringFlag{
+									Name:  "sender",
+									Usage: "v2 only: address of the requesting consumer",
+								},
+							},
+						},
+						{
+							Name:   "verify-proof",
+							Usage:  "Verify an on-chain VRF proof against a public key",
+							Action: client.VerifyVRFProof,
+							Flags: []cli.Flag{
+								cli.StringFlag{
+									Name:  "publicKey, pk",
+									Usage: "public key the proof should be by",
+								},
+								cli.StringFlag{
+									Name:  "proof",
+									Usage: "0x-prefixed hex of the on-chain proof: the vrf task output, or for v2 the fulfillRandomWords call data",
+								},
+								cli.StringFlag{
+									Name:  "blockHash",
+									Usage: "hash of the block the request was made in",
+								},
+								cli.BoolFlag{
+									Name:  "v2",
+									Usage: "verify a proof for VRFCoordinatorV2",
+								},
+							},
+						},
+					},
+				},
 				{
 					Name:   "status",
 					Usage:  "Displays the health of various services running inside the node.",
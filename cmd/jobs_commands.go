@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"time"
 
 	"github.com/pkg/errors"
@@ -102,6 +103,14 @@ func (p JobPresenter) FriendlyCreatedAt() string {
 		if p.WebhookSpec != nil {
 			return p.WebhookSpec.CreatedAt.Format(time.RFC3339)
 		}
+	case presenters.EventTriggerJobSpec:
+		if p.EventTriggerSpec != nil {
+			return p.EventTriggerSpec.CreatedAt.Format(time.RFC3339)
+		}
+	case presenters.BlockTriggerJobSpec:
+		if p.BlockTriggerSpec != nil {
+			return p.BlockTriggerSpec.CreatedAt.Format(time.RFC3339)
+		}
 	default:
 		return "unknown"
 	}
@@ -203,7 +212,11 @@ func (cli *Client) TriggerPipelineRun(c *cli.Context) error {
 	if !c.Args().Present() {
 		return cli.errorOut(errors.New("Must pass the job id to trigger a run"))
 	}
-	resp, err := cli.HTTP.Post("/v2/jobs/"+c.Args().First()+"/runs", nil)
+	path := "/v2/jobs/" + c.Args().First() + "/runs"
+	if c.Bool("sync") {
+		path += "?sync=true&timeout=" + url.QueryEscape(c.Duration("timeout").String())
+	}
+	resp, err := cli.HTTP.Post(path, nil)
 	if err != nil {
 		return cli.errorOut(err)
 	}
@@ -217,3 +230,77 @@ func (cli *Client) TriggerPipelineRun(c *cli.Context) error {
 	err = cli.renderAPIResponse(resp, &run, "Pipeline run successfully triggered")
 	return err
 }
+
+type OCRStatusPresenter struct {
+	JAID
+	presenters.OCRStatusResource
+}
+
+func (p *OCRStatusPresenter) RenderTable(rt RendererTable) error {
+	summary := rt.newTable([]string{"Job ID", "Contract", "Running", "Config Digest", "Epoch", "Round", "Leader", "Persisted Epoch", "Gas Spike"})
+	row := []string{p.ID, p.ContractAddress, fmt.Sprintf("%v", p.Running), "", "", "", "", "", fmt.Sprintf("%v", p.GasSpike)}
+	if p.Round != nil {
+		row[3] = p.Round.ConfigDigest
+		row[4] = fmt.Sprintf("%d", p.Round.Epoch)
+		row[5] = fmt.Sprintf("%d", p.Round.Round)
+		row[6] = fmt.Sprintf("%d", p.Round.Leader)
+	}
+	if p.PersistentState != nil {
+		row[7] = fmt.Sprintf("%d", p.PersistentState.Epoch)
+		if row[3] == "" {
+			row[3] = p.PersistentState.ConfigDigest
+		}
+	}
+	summary.Append(row)
+	render("OCR Status", summary)
+
+	activity := rt.newTable([]string{"Kind", "Detail", "Reference", "Error", "At"})
+	if o := p.LastObservation; o != nil {
+		value := ""
+		if o.Value != nil {
+			value = *o.Value
+		}
+		if o.Source != "" {
+			value = fmt.Sprintf("%s (%s, %d failed tasks)", value, o.Source, o.FailedTasks)
+		}
+		activity.Append([]string{"observation", value, fmt.Sprintf("run %d", o.PipelineRunID), o.Error, o.ObservedAt.Format(time.RFC3339)})
+	}
+	if t := p.LastTransmission; t != nil {
+		activity.Append([]string{"transmission", fmt.Sprintf("epoch %d round %d", t.Epoch, t.Round), fmt.Sprintf("eth tx %d", t.EthTxID), t.Error, t.TransmittedAt.Format(time.RFC3339)})
+	}
+	for _, pt := range p.PendingTransmissions {
+		activity.Append([]string{"pending transmission", fmt.Sprintf("epoch %d round %d", pt.Epoch, pt.Round), "median " + pt.Median, "", pt.Time.Format(time.RFC3339)})
+	}
+	render("Activity", activity)
+
+	peers := rt.newTable([]string{"Oracle ID", "Peer ID", "Self", "Connectedness"})
+	for _, peer := range p.Peers {
+		peers.Append([]string{fmt.Sprintf("%d", peer.OracleID), peer.PeerID, fmt.Sprintf("%v", peer.Self), peer.Connectedness})
+	}
+	render("Peers", peers)
+
+	errs := rt.newTable([]string{"Message", "At"})
+	for _, e := range p.RecentErrors {
+		errs.Append([]string{e.Message, e.Time.Format(time.RFC3339)})
+	}
+	render("Recent Errors", errs)
+	return nil
+}
+
+// ShowOCRStatus shows the round, epoch, peers and recent activity of an OCR job
+func (cli *Client) ShowOCRStatus(c *cli.Context) (err error) {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("must pass the job id"))
+	}
+	resp, err := cli.HTTP.Get("/v2/jobs/" + c.Args().First() + "/ocr/status")
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+
+	return cli.renderAPIResponse(resp, &OCRStatusPresenter{})
+}
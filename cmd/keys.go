@@ -59,13 +59,14 @@ func (p *EthKeyPresenter) ToRow() []string {
 		p.EthBalance.String(),
 		p.PhbBalance.String(),
 		fmt.Sprintf("%v", p.IsFunding),
+		p.ExternalSigner,
 		p.CreatedAt.String(),
 		p.UpdatedAt.String(),
 	}
 }
 
 func (p *EthKeyPresenter) RenderTable(rt RendererTable) error {
-	headers := []string{"Address", "ETH", "PHB", "Is funding", "Created", "Updated"}
+	headers := []string{"Address", "ETH", "PHB", "Is funding", "External signer", "Created", "Updated"}
 	rows := [][]string{p.ToRow()}
 
 	renderList(headers, rows, rt.Writer)
@@ -76,7 +77,7 @@ func (p *EthKeyPresenter) RenderTable(rt RendererTable) error {
 type EthKeyPresenters []EthKeyPresenter
 
 func (ps EthKeyPresenters) RenderTable(rt RendererTable) error {
-	headers := []string{"Address", "ETH", "PHB", "Is funding", "Created", "Updated"}
+	headers := []string{"Address", "ETH", "PHB", "Is funding", "External signer", "Created", "Updated"}
 	rows := [][]string{}
 
 	for _, p := range ps {
@@ -4,12 +4,15 @@ import (
 	"PhoenixOracle/db/models"
 	"PhoenixOracle/lib/signatures/secp256k1"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
 
 	"PhoenixOracle/util"
+	"PhoenixOracle/web/controllers"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli"
 	"go.uber.org/multierr"
@@ -44,6 +47,40 @@ func (cli *Client) CreateETHKey(c *cli.Context) (err error) {
 	return cli.renderAPIResponse(resp, &EthKeyPresenter{}, "ETH key created.\n\n🔑 New key")
 }
 
+// AddExternalETHKey registers an ETH key that is held by an external signer
+func (cli *Client) AddExternalETHKey(c *cli.Context) (err error) {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("Must pass the address of the external key"))
+	}
+	if c.String("signer") == "" {
+		return cli.errorOut(errors.New("Must specify --signer flag"))
+	}
+	address := c.Args().Get(0)
+	if !common.IsHexAddress(address) {
+		return cli.errorOut(errors.Errorf("invalid address: %s", address))
+	}
+	request, err := json.Marshal(controllers.AddExternalETHKeyRequest{
+		Address:   common.HexToAddress(address),
+		Signer:    c.String("signer"),
+		KeyRef:    c.String("key-ref"),
+		IsFunding: c.Bool("funding"),
+	})
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	resp, err := cli.HTTP.Post("/v2/keys/eth/external", bytes.NewReader(request))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+
+	return cli.renderAPIResponse(resp, &EthKeyPresenter{}, "🔑 Added external ETH key")
+}
+
 func (cli *Client) DeleteETHKey(c *cli.Context) (err error) {
 	if !c.Args().Present() {
 		return cli.errorOut(errors.New("Must pass the address of the key to be deleted"))
@@ -665,3 +702,56 @@ func (cli *Client) ListVRFKeys(c *cli.Context) error {
 func noFileToOverwrite(path string) bool {
 	return os.IsNotExist(utils.JustError(os.Stat(path)))
 }
+
+// RotateKeystorePassword re-encrypts all keys in the node's keystore under a
+// new password
+func (cli *Client) RotateKeystorePassword(c *cli.Context) (err error) {
+	oldPasswordFile := c.String("oldpassword")
+	if len(oldPasswordFile) == 0 {
+		return cli.errorOut(errors.New("Must specify --oldpassword flag"))
+	}
+	newPasswordFile := c.String("newpassword")
+	if len(newPasswordFile) == 0 {
+		return cli.errorOut(errors.New("Must specify --newpassword flag"))
+	}
+	oldPassword, err := passwordFromFile(oldPasswordFile)
+	if err != nil {
+		return cli.errorOut(errors.Wrap(err, "Could not read password file"))
+	}
+	newPassword, err := passwordFromFile(newPasswordFile)
+	if err != nil {
+		return cli.errorOut(errors.Wrap(err, "Could not read password file"))
+	}
+	if err = utils.VerifyPasswordComplexity(newPassword); err != nil {
+		return cli.errorOut(err)
+	}
+
+	request, err := json.Marshal(controllers.RotateKeystorePasswordRequest{
+		OldPassword: oldPassword,
+		NewPassword: newPassword,
+		ScryptN:     c.Int("scrypt-n"),
+		ScryptP:     c.Int("scrypt-p"),
+	})
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	resp, err := cli.HTTP.Post("/v2/keys/rotate_password", bytes.NewReader(request))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		fmt.Println("🔑 Keystore password rotated. Remember to update the password file used to start the node.")
+	case http.StatusConflict:
+		fmt.Println("Old password did not match.")
+	default:
+		return cli.printResponseBody(resp)
+	}
+	return nil
+}
@@ -76,6 +76,9 @@ func (cli *Client) RunNode(c *clipkg.Context) error {
 	if cli.Config.EthereumDisabled() {
 		logger.Warn("Ethereum is disabled. Phoenix will only run services that can operate without an ethereum connection")
 	}
+	if cli.Config.HAEnabled() {
+		logger.Info("HA is enabled. Phoenix will run as a standby until it acquires the leader lease")
+	}
 
 	evmcfg := config.NewEVMConfig(cli.Config)
 	app, err := cli.AppFactory.NewApplication(evmcfg)
@@ -138,6 +141,15 @@ func (cli *Client) RunNode(c *clipkg.Context) error {
 		logger.Infof("Created P2P key with ID %s", p2pKey.ID())
 	}
 
+	if cli.Config.HAEnabled() {
+		// Migrations and key setup ran under the global advisory lock. From
+		// here on the leader lease takes over, and holding the lock would
+		// keep the standby from starting at all.
+		if err = store.ReleaseAdvisoryLock(); err != nil {
+			return cli.errorOut(errors.Wrap(err, "error releasing the advisory lock"))
+		}
+	}
+
 	if e := checkFilePermissions(cli.Config.RootDir()); e != nil {
 		logger.Warn(e)
 	}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+
+	"PhoenixOracle/core/keystore/keys/ethkey"
+	"PhoenixOracle/core/service/jobs/offchainreporting"
+	"PhoenixOracle/db/models"
+	"PhoenixOracle/web/presenters"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+	"go.uber.org/multierr"
+)
+
+type OCRSetConfigPresenter struct {
+	JAID
+	presenters.OCRSetConfigResource
+}
+
+func (p *OCRSetConfigPresenter) RenderTable(rt RendererTable) error {
+	table := rt.newTable([]string{"Oracle", "Signer", "Transmitter"})
+	for i := range p.Signers {
+		table.Append([]string{fmt.Sprintf("%d", i), p.Signers[i].Hex(), p.Transmitters[i].Hex()})
+	}
+	render(fmt.Sprintf("OCR config for %s (threshold %d)", p.ContractAddress, p.Threshold), table)
+
+	if p.EthTxID != nil {
+		fmt.Printf("setConfig submitted as eth tx %d\n", *p.EthTxID)
+		return nil
+	}
+	fmt.Printf("setConfig calldata:\n%s\n", p.Calldata)
+	return nil
+}
+
+// SetOCRConfig generates a config for an OffchainAggregator contract and
+// prints its setConfig calldata, or submits it when --send is set
+func (cli *Client) SetOCRConfig(c *cli.Context) (err error) {
+	if !c.IsSet("contract") {
+		return cli.errorOut(errors.New("must pass --contract"))
+	}
+	if !c.IsSet("oracles") {
+		return cli.errorOut(errors.New("must pass --oracles"))
+	}
+
+	var request offchainreporting.SetConfigRequest
+	request.ContractAddress, err = ethkey.NewEIP55Address(c.String("contract"))
+	if err != nil {
+		return cli.errorOut(errors.Wrap(err, "invalid contract address"))
+	}
+	oracles, err := ioutil.ReadFile(c.String("oracles"))
+	if err != nil {
+		return cli.errorOut(errors.Wrap(err, "could not read oracles file"))
+	}
+	if err = json.Unmarshal(oracles, &request.Oracles); err != nil {
+		return cli.errorOut(errors.Wrap(err, "could not parse oracles file"))
+	}
+
+	for _, d := range []struct {
+		flag string
+		dst  *models.Duration
+	}{
+		{"delta-progress", &request.DeltaProgress},
+		{"delta-resend", &request.DeltaResend},
+		{"delta-round", &request.DeltaRound},
+		{"delta-grace", &request.DeltaGrace},
+		{"delta-c", &request.DeltaC},
+		{"delta-stage", &request.DeltaStage},
+	} {
+		*d.dst, err = models.MakeDuration(c.Duration(d.flag))
+		if err != nil {
+			return cli.errorOut(errors.Wrapf(err, "invalid --%s", d.flag))
+		}
+	}
+	request.AlphaPPB = c.Uint64("alpha-ppb")
+	request.RMax = uint8(c.Uint("r-max"))
+	request.S = c.IntSlice("s")
+	request.F = c.Int("f")
+	request.SkipChainSpecificChecks = c.Bool("skip-chain-checks")
+
+	if c.Bool("send") {
+		if !c.IsSet("from") {
+			return cli.errorOut(errors.New("must pass --from with --send"))
+		}
+		request.Send = true
+		from, err := ethkey.NewEIP55Address(c.String("from"))
+		if err != nil {
+			return cli.errorOut(errors.Wrap(err, "invalid from address"))
+		}
+		request.FromAddress = &from
+		request.GasLimit = c.Uint64("gas-limit")
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	resp, err := cli.HTTP.Post("/v2/ocr/set_config", bytes.NewReader(body))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+
+	return cli.renderAPIResponse(resp, &OCRSetConfigPresenter{})
+}
+
+type OCRTransmissionCostPresenter struct {
+	JAID
+	presenters.OCRTransmissionCostResource
+}
+
+func (p *OCRTransmissionCostPresenter) ToRow() []string {
+	return []string{
+		fmt.Sprintf("%d", p.JobID),
+		p.PeriodStart.Format("2006-01-02"),
+		fmt.Sprintf("%d", p.Transmissions),
+		fmt.Sprintf("%d", p.Confirmed),
+		fmt.Sprintf("%d", p.Reverted),
+		fmt.Sprintf("%d", p.Failed),
+		fmt.Sprintf("%d", p.Pending),
+		fmt.Sprintf("%d", p.GasUsed),
+		p.EthSpent.String(),
+	}
+}
+
+type OCRTransmissionCostPresenters []OCRTransmissionCostPresenter
+
+// RenderTable implements TableRenderer
+func (ps OCRTransmissionCostPresenters) RenderTable(rt RendererTable) error {
+	headers := []string{"Job ID", "Period", "Transmissions", "Confirmed", "Reverted", "Failed", "Pending", "Gas used", "ETH spent"}
+	rows := [][]string{}
+	for _, p := range ps {
+		rows = append(rows, p.ToRow())
+	}
+	renderList(headers, rows, rt.Writer)
+	return nil
+}
+
+// ShowOCRTransmissionCosts reports the gas used and ETH spent on OCR
+// transmissions per job and period
+func (cli *Client) ShowOCRTransmissionCosts(c *cli.Context) (err error) {
+	q := url.Values{}
+	for param, flag := range map[string]string{"jobID": "job-id", "from": "from", "to": "to", "period": "period"} {
+		if c.IsSet(flag) {
+			q.Set(param, c.String(flag))
+		}
+	}
+	resp, err := cli.HTTP.Get("/v2/ocr/transmission_costs?" + q.Encode())
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+
+	return cli.renderAPIResponse(resp, &OCRTransmissionCostPresenters{})
+}
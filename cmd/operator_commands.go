@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"PhoenixOracle/core/assets"
+	"PhoenixOracle/core/keystore/keys/ethkey"
+	"PhoenixOracle/core/service/jobs/request"
+	"PhoenixOracle/db/models"
+	"PhoenixOracle/web/controllers"
+	"PhoenixOracle/web/presenters"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+	"go.uber.org/multierr"
+)
+
+type DirectRequestEarningPresenter struct {
+	JAID
+	presenters.DirectRequestEarningResource
+}
+
+func (p *DirectRequestEarningPresenter) ToRow() []string {
+	group := "deleted job"
+	switch {
+	case p.Requester != nil:
+		group = p.Requester.Hex()
+	case p.JobID != nil:
+		group = fmt.Sprintf("%d", *p.JobID)
+	}
+	return []string{
+		group,
+		p.PeriodStart.Format("2006-01-02"),
+		fmt.Sprintf("%d", p.Fulfilled),
+		p.Earned.Phb(),
+	}
+}
+
+type DirectRequestEarningPresenters []DirectRequestEarningPresenter
+
+// RenderTable implements TableRenderer
+func (ps DirectRequestEarningPresenters) RenderTable(rt RendererTable) error {
+	group := "Job ID"
+	if len(ps) > 0 && ps[0].Requester != nil {
+		group = "Requester"
+	}
+	headers := []string{group, "Period", "Fulfilled", "PHB earned"}
+	rows := [][]string{}
+	for _, p := range ps {
+		rows = append(rows, p.ToRow())
+	}
+	renderList(headers, rows, rt.Writer)
+	return nil
+}
+
+// ShowOperatorEarnings reports the requests fulfilled and PHB earned by
+// direct request jobs per job or requester and period
+func (cli *Client) ShowOperatorEarnings(c *cli.Context) (err error) {
+	q := url.Values{}
+	for param, flag := range map[string]string{"jobID": "job-id", "requester": "requester", "from": "from", "to": "to", "groupBy": "group-by", "period": "period"} {
+		if c.IsSet(flag) {
+			q.Set(param, c.String(flag))
+		}
+	}
+	resp, err := cli.HTTP.Get("/v2/operator/earnings?" + q.Encode())
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+
+	return cli.renderAPIResponse(resp, &DirectRequestEarningPresenters{})
+}
+
+type OperatorWithdrawalPresenter struct {
+	JAID
+	presenters.OperatorWithdrawalResource
+}
+
+func (p *OperatorWithdrawalPresenter) RenderTable(rt RendererTable) error {
+	headers := []string{"Operator", "Recipient", "From", "PHB amount", "PHB withdrawable", "Eth tx ID"}
+	rows := [][]string{{
+		p.ContractAddress,
+		p.Recipient,
+		p.FromAddress,
+		p.Amount.Phb(),
+		p.Withdrawable.Phb(),
+		fmt.Sprintf("%d", p.EthTxID),
+	}}
+	renderList(headers, rows, rt.Writer)
+	return nil
+}
+
+// WithdrawOperatorPHB withdraws PHB earned by an Operator contract to a
+// recipient, through the node's transaction manager
+func (cli *Client) WithdrawOperatorPHB(c *cli.Context) (err error) {
+	if !c.IsSet("contract") {
+		return cli.errorOut(errors.New("must pass --contract"))
+	}
+	if !c.IsSet("recipient") {
+		return cli.errorOut(errors.New("must pass --recipient"))
+	}
+
+	var req request.WithdrawRequest
+	if req.ContractAddress, err = ethkey.NewEIP55Address(c.String("contract")); err != nil {
+		return cli.errorOut(errors.Wrap(err, "invalid contract address"))
+	}
+	if req.Recipient, err = ethkey.NewEIP55Address(c.String("recipient")); err != nil {
+		return cli.errorOut(errors.Wrap(err, "invalid recipient address"))
+	}
+	if c.IsSet("amount") {
+		amount, ok := new(assets.Phb).SetString(c.String("amount"), 10)
+		if !ok {
+			return cli.errorOut(errors.Errorf("invalid amount %q", c.String("amount")))
+		}
+		req.Amount = amount
+	}
+	if c.IsSet("from") {
+		from, err := ethkey.NewEIP55Address(c.String("from"))
+		if err != nil {
+			return cli.errorOut(errors.Wrap(err, "invalid from address"))
+		}
+		req.FromAddress = &from
+	}
+	req.GasLimit = c.Uint64("gas-limit")
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	resp, err := cli.HTTP.Post("/v2/operator/withdrawals", bytes.NewReader(body))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+
+	return cli.renderAPIResponse(resp, &OperatorWithdrawalPresenter{})
+}
+
+type RequesterPolicyPresenter struct {
+	JAID
+	presenters.RequesterPolicyResource
+}
+
+func (p *RequesterPolicyPresenter) ToRow() []string {
+	requester := "default"
+	if p.Requester != nil {
+		requester = p.Requester.String()
+	}
+	minContractPayment, minPaymentPerGwei := "", ""
+	if p.MinContractPayment != nil {
+		minContractPayment = p.MinContractPayment.Phb()
+	}
+	if p.MinPaymentPerGwei != nil {
+		minPaymentPerGwei = p.MinPaymentPerGwei.Phb()
+	}
+	rateLimit := ""
+	if p.MaxRequests > 0 {
+		rateLimit = fmt.Sprintf("%d per %s", p.MaxRequests, p.RateLimitPeriod.Duration())
+	} else if p.NoRateLimit {
+		rateLimit = "none"
+	}
+	return []string{
+		p.ID,
+		requester,
+		fmt.Sprintf("%v", p.Denied),
+		minContractPayment,
+		minPaymentPerGwei,
+		rateLimit,
+	}
+}
+
+var requesterPolicyHeaders = []string{"ID", "Requester", "Denied", "Min PHB payment", "Min PHB per gwei", "Rate limit"}
+
+// RenderTable implements TableRenderer
+func (p *RequesterPolicyPresenter) RenderTable(rt RendererTable) error {
+	renderList(requesterPolicyHeaders, [][]string{p.ToRow()}, rt.Writer)
+	return nil
+}
+
+type RequesterPolicyPresenters []RequesterPolicyPresenter
+
+// RenderTable implements TableRenderer
+func (ps RequesterPolicyPresenters) RenderTable(rt RendererTable) error {
+	rows := [][]string{}
+	for _, p := range ps {
+		rows = append(rows, p.ToRow())
+	}
+	renderList(requesterPolicyHeaders, rows, rt.Writer)
+	return nil
+}
+
+// ListRequesterPolicies lists the node's requester policies for direct
+// request jobs
+func (cli *Client) ListRequesterPolicies(c *cli.Context) (err error) {
+	resp, err := cli.HTTP.Get("/v2/operator/requester_policies")
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+
+	return cli.renderAPIResponse(resp, &RequesterPolicyPresenters{})
+}
+
+// SetRequesterPolicy sets the policy of a requester, or the default policy
+// when no requester is given
+func (cli *Client) SetRequesterPolicy(c *cli.Context) (err error) {
+	var req controllers.UpsertRequesterPolicyRequest
+	if c.IsSet("requester") {
+		requester, err := ethkey.NewEIP55Address(c.String("requester"))
+		if err != nil {
+			return cli.errorOut(errors.Wrap(err, "invalid requester address"))
+		}
+		req.Requester = &requester
+	}
+	req.Denied = c.Bool("deny")
+	for flag, dst := range map[string]**assets.Phb{"min-payment": &req.MinContractPayment, "min-payment-per-gwei": &req.MinPaymentPerGwei} {
+		if !c.IsSet(flag) {
+			continue
+		}
+		payment, ok := new(assets.Phb).SetString(c.String(flag), 10)
+		if !ok {
+			return cli.errorOut(errors.Errorf("invalid --%s %q", flag, c.String(flag)))
+		}
+		*dst = payment
+	}
+	req.MaxRequests = uint32(c.Uint("max-requests"))
+	req.RateLimitPeriod = models.Interval(c.Duration("rate-limit-period"))
+	req.NoRateLimit = c.Bool("no-rate-limit")
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	resp, err := cli.HTTP.Post("/v2/operator/requester_policies", bytes.NewReader(body))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+
+	return cli.renderAPIResponse(resp, &RequesterPolicyPresenter{})
+}
+
+// DeleteRequesterPolicy deletes a requester policy by ID
+func (cli *Client) DeleteRequesterPolicy(c *cli.Context) (err error) {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("must pass the id of the requester policy to be removed"))
+	}
+	resp, err := cli.HTTP.Delete("/v2/operator/requester_policies/" + c.Args().First())
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	_, err = cli.parseResponse(resp)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	fmt.Printf("Requester policy %v deleted\n", c.Args().First())
+	return nil
+}
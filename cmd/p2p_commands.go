@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	ocrnetworking "PhoenixOracle/lib/libocr/networking"
+	"PhoenixOracle/web/controllers"
+	"PhoenixOracle/web/presenters"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+	"go.uber.org/multierr"
+)
+
+type P2PDiagnosticsPresenter struct {
+	JAID
+	presenters.P2PDiagnosticsResource
+}
+
+func (p *P2PDiagnosticsPresenter) RenderTable(rt RendererTable) error {
+	fmt.Printf("Peer %s (networking stack %s)\n\n", p.PeerID, networkingStackName(p.Stack))
+
+	table := rt.newTable([]string{"Peer ID", "Stack", "Direction", "Remote address", "Opened", "Latency (ms)", "Streams"})
+	for _, c := range p.Connections {
+		table.Append([]string{
+			c.PeerID,
+			networkingStackName(c.Stack),
+			c.Direction,
+			c.RemoteAddress,
+			c.Opened.String(),
+			fmt.Sprintf("%d", c.LatencyMillis),
+			fmt.Sprintf("%d", c.Streams),
+		})
+	}
+	render("Connections", table)
+
+	table = rt.newTable([]string{"Peer ID", "Sent", "Received", "Dropped", "Bytes sent", "Bytes received"})
+	for _, c := range p.MessageCounters {
+		table.Append([]string{
+			c.PeerID,
+			fmt.Sprintf("%d", c.Sent),
+			fmt.Sprintf("%d", c.Received),
+			fmt.Sprintf("%d", c.Dropped),
+			fmt.Sprintf("%d", c.BytesSent),
+			fmt.Sprintf("%d", c.BytesReceived),
+		})
+	}
+	render("Messages", table)
+
+	table = rt.newTable([]string{"Peer ID", "Addresses"})
+	for _, a := range p.PeerAddresses {
+		table.Append([]string{a.PeerID, strings.Join(a.Addresses, "\n")})
+	}
+	render("Peerstore addresses", table)
+
+	table = rt.newTable([]string{"Peer ID", "Announcement size", "Updated"})
+	for _, a := range p.Announcements {
+		table.Append([]string{a.PeerID, fmt.Sprintf("%d", len(a.Announcement)), a.UpdatedAt.String()})
+	}
+	render("Discovered announcements", table)
+
+	table = rt.newTable([]string{"Peer ID"})
+	for _, id := range p.BannedPeers {
+		table.Append([]string{id})
+	}
+	render("Banned peers", table)
+	return nil
+}
+
+func networkingStackName(stack ocrnetworking.NetworkingStack) string {
+	name, err := stack.MarshalText()
+	if err != nil {
+		return "unknown"
+	}
+	return string(name)
+}
+
+// ShowP2PDiagnostics shows the connections, known addresses and message
+// counters of the node's P2P peer
+func (cli *Client) ShowP2PDiagnostics(c *cli.Context) (err error) {
+	resp, err := cli.HTTP.Get("/v2/p2p/diagnostics")
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+
+	return cli.renderAPIResponse(resp, &P2PDiagnosticsPresenter{})
+}
+
+// AddP2PPeer adds a static address for a remote peer and dials it
+func (cli *Client) AddP2PPeer(c *cli.Context) (err error) {
+	if !c.IsSet("peer-id") || !c.IsSet("address") {
+		return cli.errorOut(errors.New("must pass --peer-id and --address"))
+	}
+	return cli.postP2PPeerRequest("/v2/p2p/peers", controllers.P2PPeerRequest{
+		PeerID:  c.String("peer-id"),
+		Address: c.String("address"),
+	})
+}
+
+// BanP2PPeer disconnects from a remote peer and refuses further connections
+// with it
+func (cli *Client) BanP2PPeer(c *cli.Context) (err error) {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("must pass the peer ID to ban"))
+	}
+	return cli.postP2PPeerRequest("/v2/p2p/bans", controllers.P2PPeerRequest{PeerID: c.Args().First()})
+}
+
+// UnbanP2PPeer lifts the ban of a remote peer
+func (cli *Client) UnbanP2PPeer(c *cli.Context) (err error) {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("must pass the peer ID to unban"))
+	}
+	resp, err := cli.HTTP.Delete("/v2/p2p/bans/" + url.PathEscape(c.Args().First()))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+
+	return cli.renderAPIResponse(resp, &P2PDiagnosticsPresenter{})
+}
+
+func (cli *Client) postP2PPeerRequest(path string, request controllers.P2PPeerRequest) (err error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	resp, err := cli.HTTP.Post(path, bytes.NewReader(body))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+
+	return cli.renderAPIResponse(resp, &P2PDiagnosticsPresenter{})
+}
+
+type P2PBootstrapPeerListPresenter struct {
+	JAID
+	presenters.P2PBootstrapPeerListResource
+}
+
+func (p *P2PBootstrapPeerListPresenter) ToRow() []string {
+	return []string{
+		p.Name,
+		strings.Join(p.Peers, "\n"),
+		p.UpdatedAt.String(),
+	}
+}
+
+var p2pBootstrapPeerListHeaders = []string{"Name", "Peers", "Updated"}
+
+// RenderTable implements TableRenderer
+func (p *P2PBootstrapPeerListPresenter) RenderTable(rt RendererTable) error {
+	renderList(p2pBootstrapPeerListHeaders, [][]string{p.ToRow()}, rt.Writer)
+	return nil
+}
+
+type P2PBootstrapPeerListPresenters []P2PBootstrapPeerListPresenter
+
+// RenderTable implements TableRenderer
+func (ps P2PBootstrapPeerListPresenters) RenderTable(rt RendererTable) error {
+	rows := [][]string{}
+	for _, p := range ps {
+		rows = append(rows, p.ToRow())
+	}
+	renderList(p2pBootstrapPeerListHeaders, rows, rt.Writer)
+	return nil
+}
+
+// ListP2PBootstrapPeerLists shows the shared bootstrap peer lists that OCR
+// jobs can reference by name
+func (cli *Client) ListP2PBootstrapPeerLists(c *cli.Context) (err error) {
+	resp, err := cli.HTTP.Get("/v2/p2p/bootstrap_peer_lists")
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+
+	return cli.renderAPIResponse(resp, &P2PBootstrapPeerListPresenters{})
+}
+
+// SetP2PBootstrapPeerList creates a shared bootstrap peer list or replaces
+// its peers
+func (cli *Client) SetP2PBootstrapPeerList(c *cli.Context) (err error) {
+	if !c.Args().Present() || len(c.StringSlice("peer")) == 0 {
+		return cli.errorOut(errors.New("must pass the list name and at least one --peer"))
+	}
+	body, err := json.Marshal(controllers.P2PBootstrapPeerListRequest{
+		Name:  c.Args().First(),
+		Peers: c.StringSlice("peer"),
+	})
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	resp, err := cli.HTTP.Post("/v2/p2p/bootstrap_peer_lists", bytes.NewReader(body))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			err = multierr.Append(err, cerr)
+		}
+	}()
+
+	return cli.renderAPIResponse(resp, &P2PBootstrapPeerListPresenter{})
+}
+
+// DeleteP2PBootstrapPeerList deletes a shared bootstrap peer list
+func (cli *Client) DeleteP2PBootstrapPeerList(c *cli.Context) (err error) {
+	if !c.Args().Present() {
+		return cli.errorOut(errors.New("must pass the name of the list to delete"))
+	}
+	resp, err := cli.HTTP.Delete("/v2/p2p/bootstrap_peer_lists/" + url.PathEscape(c.Args().First()))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	_, err = cli.parseResponse(resp)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	fmt.Printf("Bootstrap peer list %s deleted\n", c.Args().First())
+	return nil
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"PhoenixOracle/core/keystore/keys/vrfkey"
+	"PhoenixOracle/core/service/ethereum"
+	"PhoenixOracle/core/service/vrf/proof"
+	"PhoenixOracle/db/config"
+	"PhoenixOracle/db/dialects"
+	"PhoenixOracle/internal/gethwrappers/generated/vrf_coordinator_v2"
+	"PhoenixOracle/lib/logger"
+	"PhoenixOracle/lib/signatures/secp256k1"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	gethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	clipkg "github.com/urfave/cli"
+	"go.uber.org/multierr"
+)
+
+var vrfCoordinatorV2ABI = ethereum.MustGetABI(vrf_coordinator_v2.VRFCoordinatorV2ABI)
+
+// VRFProofPresenter renders a VRF proof and its on-chain encoding
+type VRFProofPresenter struct {
+	PublicKey    string `json:"publicKey"`
+	Version      string `json:"version"`
+	FinalSeed    string `json:"finalSeed"`
+	Output       string `json:"output"`
+	OnChainProof string `json:"onChainProof"`
+}
+
+func (p *VRFProofPresenter) RenderTable(rt RendererTable) error {
+	headers := []string{"Public key", "Version", "Final seed", "Output", "On-chain proof"}
+	rows := [][]string{{p.PublicKey, p.Version, p.FinalSeed, p.Output, p.OnChainProof}}
+	renderList(headers, rows, rt.Writer)
+	return nil
+}
+
+func newVRFProofPresenter(pk secp256k1.PublicKey, v2 bool, p vrfkey.Proof, onChainProof []byte) *VRFProofPresenter {
+	version := "v1"
+	if v2 {
+		version = "v2"
+	}
+	return &VRFProofPresenter{
+		PublicKey:    pk.String(),
+		Version:      version,
+		FinalSeed:    p.Seed.String(),
+		Output:       p.Output.String(),
+		OnChainProof: hexutil.Encode(onChainProof),
+	}
+}
+
+// GenerateVRFProof reproduces the proof the vrf or vrfv2 pipeline task
+// generates for a randomness request, with a VRF key from the local
+// keystore, without running the node
+func (cli *Client) GenerateVRFProof(c *clipkg.Context) (err error) {
+	pk, err := secp256k1.NewPublicKeyFromHex(c.String("publicKey"))
+	if err != nil {
+		return cli.errorOut(errors.Wrap(err, "invalid publicKey"))
+	}
+	preSeed, ok := new(big.Int).SetString(c.String("preSeed"), 0)
+	if !ok {
+		return cli.errorOut(errors.Errorf("invalid preSeed %q", c.String("preSeed")))
+	}
+	seed, err := proof.BigToSeed(preSeed)
+	if err != nil {
+		return cli.errorOut(errors.Wrap(err, "invalid preSeed"))
+	}
+	blockHash, err := parseBlockHash(c.String("blockHash"))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	v2 := c.Bool("v2")
+	var sender gethCommon.Address
+	if v2 {
+		if !gethCommon.IsHexAddress(c.String("sender")) {
+			return cli.errorOut(errors.Errorf("invalid sender %q", c.String("sender")))
+		}
+		sender = gethCommon.HexToAddress(c.String("sender"))
+	}
+
+	logger.SetLogger(cli.Config.CreateProductionLogger())
+	cli.Config.SetDialect(dialects.PostgresWithoutLock)
+	app, err := cli.AppFactory.NewApplication(config.NewEVMConfig(cli.Config))
+	if err != nil {
+		return cli.errorOut(errors.Wrap(err, "creating application"))
+	}
+	defer func() {
+		if serr := app.Stop(); serr != nil {
+			err = multierr.Append(err, serr)
+		}
+	}()
+	pwd, err := passwordFromFile(c.String("password"))
+	if err != nil {
+		return cli.errorOut(fmt.Errorf("error reading password: %+v", err))
+	}
+	keyStore := app.GetKeyStore()
+	if err = keyStore.Unlock(pwd); err != nil {
+		return cli.errorOut(errors.Wrap(err, "error authenticating keystore"))
+	}
+
+	if !v2 {
+		s := proof.PreSeedData{
+			PreSeed:   seed,
+			BlockHash: blockHash,
+			BlockNum:  c.Uint64("blockNum"),
+		}
+		p, err := keyStore.VRF().GenerateProof(pk.String(), proof.FinalSeed(s))
+		if err != nil {
+			return cli.errorOut(err)
+		}
+		onChainProof, err := proof.GenerateProofResponseFromProof(p, s)
+		if err != nil {
+			return cli.errorOut(err)
+		}
+		return cli.errorOut(cli.Render(newVRFProofPresenter(pk, v2, p, onChainProof[:])))
+	}
+
+	s := proof.PreSeedDataV2{
+		PreSeed:          seed,
+		BlockHash:        blockHash,
+		BlockNum:         c.Uint64("blockNum"),
+		SubId:            c.Uint64("subId"),
+		CallbackGasLimit: uint32(c.Uint("callbackGasLimit")),
+		NumWords:         uint32(c.Uint("numWords")),
+		Sender:           sender,
+	}
+	p, err := keyStore.VRF().GenerateProof(hexutil.Encode(pk[:]), proof.FinalSeedV2(s))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	onChainProof, rc, err := proof.GenerateProofResponseFromProofV2(p, s)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	b, err := vrfCoordinatorV2ABI.Pack("fulfillRandomWords", onChainProof, rc)
+	if err != nil {
+		return cli.errorOut(err)
+	}
+	return cli.errorOut(cli.Render(newVRFProofPresenter(pk, v2, p, b)))
+}
+
+// VerifyVRFProof checks an on-chain VRF proof, as generated by the vrf or
+// vrfv2 pipeline task, against a public key. It needs neither the node nor
+// its keystore.
+func (cli *Client) VerifyVRFProof(c *clipkg.Context) error {
+	pk, err := secp256k1.NewPublicKeyFromHex(c.String("publicKey"))
+	if err != nil {
+		return cli.errorOut(errors.Wrap(err, "invalid publicKey"))
+	}
+	onChainProof, err := hexutil.Decode(c.String("proof"))
+	if err != nil {
+		return cli.errorOut(errors.Wrap(err, "invalid proof"))
+	}
+	blockHash, err := parseBlockHash(c.String("blockHash"))
+	if err != nil {
+		return cli.errorOut(err)
+	}
+
+	v2 := c.Bool("v2")
+	var p vrfkey.Proof
+	if !v2 {
+		var m proof.MarshaledOnChainResponse
+		if len(onChainProof) != len(m) {
+			return cli.errorOut(errors.Errorf("proof is %d bytes long, should be %d", len(onChainProof), len(m)))
+		}
+		copy(m[:], onChainProof)
+		p, err = proof.VerifyProofResponse(pk, m, blockHash)
+	} else {
+		var vp vrf_coordinator_v2.VRFProof
+		var rc vrf_coordinator_v2.VRFCoordinatorV2RequestCommitment
+		vp, rc, err = unpackFulfillRandomWords(onChainProof)
+		if err != nil {
+			return cli.errorOut(err)
+		}
+		p, err = proof.VerifyProofResponseV2(pk, vp, rc, blockHash)
+	}
+	if err != nil {
+		return cli.errorOut(errors.Wrap(err, "proof verification failed"))
+	}
+	return cli.errorOut(cli.Render(newVRFProofPresenter(pk, v2, p, onChainProof)))
+}
+
+// unpackFulfillRandomWords decodes the arguments of a call to
+// VRFCoordinatorV2#fulfillRandomWords, with or without the method selector
+func unpackFulfillRandomWords(b []byte) (vrf_coordinator_v2.VRFProof, vrf_coordinator_v2.VRFCoordinatorV2RequestCommitment, error) {
+	method := vrfCoordinatorV2ABI.Methods["fulfillRandomWords"]
+	if len(b)%32 == 4 {
+		if !bytes.Equal(b[:4], method.ID) {
+			return vrf_coordinator_v2.VRFProof{}, vrf_coordinator_v2.VRFCoordinatorV2RequestCommitment{}, errors.New("proof is not a call to fulfillRandomWords")
+		}
+		b = b[4:]
+	}
+	out, err := method.Inputs.Unpack(b)
+	if err != nil {
+		return vrf_coordinator_v2.VRFProof{}, vrf_coordinator_v2.VRFCoordinatorV2RequestCommitment{}, errors.Wrap(err, "invalid proof")
+	}
+	vp := *abi.ConvertType(out[0], new(vrf_coordinator_v2.VRFProof)).(*vrf_coordinator_v2.VRFProof)
+	rc := *abi.ConvertType(out[1], new(vrf_coordinator_v2.VRFCoordinatorV2RequestCommitment)).(*vrf_coordinator_v2.VRFCoordinatorV2RequestCommitment)
+	return vp, rc, nil
+}
+
+func parseBlockHash(s string) (gethCommon.Hash, error) {
+	b, err := hexutil.Decode(s)
+	if err != nil || len(b) != gethCommon.HashLength {
+		return gethCommon.Hash{}, errors.Errorf("invalid blockHash %q", s)
+	}
+	return gethCommon.BytesToHash(b), nil
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"PhoenixOracle/core/keystore/keys/vrfkey"
+	"PhoenixOracle/core/service/vrf/proof"
+	gethCommon "github.com/ethereum/go-ethereum/common"
+)
+
+func TestUnpackFulfillRandomWords(t *testing.T) {
+	key, err := vrfkey.NewV2()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed, err := proof.BigToSeed(big.NewInt(0x10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockHash := gethCommon.HexToHash("0x2a")
+	s := proof.PreSeedDataV2{
+		PreSeed:          seed,
+		BlockHash:        blockHash,
+		BlockNum:         10,
+		SubId:            1,
+		CallbackGasLimit: 100000,
+		NumWords:         2,
+		Sender:           gethCommon.HexToAddress("0xaa"),
+	}
+	p, err := key.GenerateProof(proof.FinalSeedV2(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vp, rc, err := proof.GenerateProofResponseFromProofV2(p, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	call, err := vrfCoordinatorV2ABI.Pack("fulfillRandomWords", vp, rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, b := range map[string][]byte{"with selector": call, "without selector": call[4:]} {
+		t.Run(name, func(t *testing.T) {
+			gotVP, gotRC, err := unpackFulfillRandomWords(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			repacked, err := vrfCoordinatorV2ABI.Pack("fulfillRandomWords", gotVP, gotRC)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(repacked, call) {
+				t.Errorf("expected %x to round-trip, got %x", call, repacked)
+			}
+			verified, err := proof.VerifyProofResponseV2(key.PublicKey, gotVP, gotRC, blockHash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if verified.Output.Cmp(p.Output) != 0 {
+				t.Errorf("expected output %v, got %v", p.Output, verified.Output)
+			}
+		})
+	}
+
+	t.Run("tampered witness", func(t *testing.T) {
+		tampered := append([]byte(nil), call...)
+		// uWitness is the eighth word of the proof, after the selector
+		tampered[4+8*32-1] ^= 1
+		gotVP, gotRC, err := unpackFulfillRandomWords(tampered)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := proof.VerifyProofResponseV2(key.PublicKey, gotVP, gotRC, blockHash); err == nil {
+			t.Error("expected proof to be rejected")
+		}
+	})
+
+	t.Run("other method", func(t *testing.T) {
+		other := append([]byte{0xde, 0xad, 0xbe, 0xef}, call[4:]...)
+		if _, _, err := unpackFulfillRandomWords(other); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
@@ -20,6 +20,8 @@ type (
 		EthTxReaperInterval                        time.Duration
 		EthTxReaperThreshold                       time.Duration
 		EthTxResendAfterThreshold                  time.Duration
+		FeeHistoryEstimatorBlockCount              uint16
+		FeeHistoryEstimatorRewardPercentile        uint16
 		FinalityDepth                              uint
 		FlagsContractAddress                       string
 		GasBumpPercent                             uint16
@@ -68,6 +70,8 @@ func setConfigs() {
 		EthTxReaperInterval:                        1 * time.Hour,
 		EthTxReaperThreshold:                       168 * time.Hour,
 		EthTxResendAfterThreshold:                  1 * time.Minute,
+		FeeHistoryEstimatorBlockCount:              20,
+		FeeHistoryEstimatorRewardPercentile:        60,
 		FinalityDepth:                              50,
 		GasBumpPercent:                             20,
 		GasBumpThreshold:                           3,
@@ -7,10 +7,12 @@ import (
 	"sync"
 
 	"PhoenixOracle/core/keystore/keys/ethkey"
+	"PhoenixOracle/core/keystore/signer"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/pkg/errors"
+	null "gopkg.in/guregu/null.v4"
 	"gorm.io/gorm"
 )
 
@@ -22,6 +24,8 @@ type Eth interface {
 	Delete(id string) (ethkey.KeyV2, error)
 	Import(keyJSON []byte, password string) (ethkey.KeyV2, error)
 	Export(id string, password string) ([]byte, error)
+	AddExternal(address common.Address, signerType string, keyRef string, isFunding bool) (ethkey.KeyV2, error)
+	SetExternalSigner(signerType string, s signer.Signer)
 
 	EnsureKeys() (ethkey.KeyV2, bool, ethkey.KeyV2, bool, error)
 	SubscribeToKeyChanges() (ch chan struct{}, unsub func())
@@ -42,6 +46,8 @@ type eth struct {
 	*keyManager
 	subscribers   [](chan struct{})
 	subscribersMu *sync.RWMutex
+	signers       map[string]signer.Signer
+	signersMu     *sync.RWMutex
 }
 
 var _ Eth = &eth{}
@@ -51,6 +57,8 @@ func newEthKeyStore(km *keyManager) *eth {
 		keyManager:    km,
 		subscribers:   make([](chan struct{}), 0),
 		subscribersMu: new(sync.RWMutex),
+		signers:       make(map[string]signer.Signer),
+		signersMu:     new(sync.RWMutex),
 	}
 }
 
@@ -72,6 +80,7 @@ func (ks *eth) GetAll() (keys []ethkey.KeyV2, _ error) {
 	for _, key := range ks.keyRing.Eth {
 		keys = append(keys, key)
 	}
+	keys = append(keys, ks.externalKeys()...)
 	return keys, nil
 }
 
@@ -193,6 +202,9 @@ func (ks *eth) Export(id string, password string) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	if key.IsExternal() {
+		return nil, errors.Errorf("key %s is held by an external signer and cannot be exported", id)
+	}
 	return key.ToEncryptedJSON(password, ks.scryptParams)
 }
 
@@ -206,6 +218,15 @@ func (ks *eth) Delete(id string) (ethkey.KeyV2, error) {
 	if err != nil {
 		return ethkey.KeyV2{}, err
 	}
+	if key.IsExternal() {
+		err = ks.orm.db.Where("address = ?", key.Address).Delete(ethkey.State{}).Error
+		if err != nil {
+			return ethkey.KeyV2{}, errors.Wrap(err, "unable to remove external eth key")
+		}
+		delete(ks.keyStates.Eth, key.ID())
+		ks.notify()
+		return key, nil
+	}
 	err = ks.safeRemoveKey(key, func(db *gorm.DB) error {
 		return db.Where("address = ?", key.Address).Delete(ethkey.State{}).Error
 	})
@@ -233,18 +254,71 @@ func (ks *eth) SubscribeToKeyChanges() (ch chan struct{}, unsub func()) {
 	}
 }
 
+// AddExternal registers a key that the node never holds. Transactions from
+// address are signed by the external signer of the given type, with keyRef
+// identifying the key within that signer where necessary.
+func (ks *eth) AddExternal(address common.Address, signerType string, keyRef string, isFunding bool) (ethkey.KeyV2, error) {
+	if !signer.IsValidType(signerType) {
+		return ethkey.KeyV2{}, errors.Errorf("unknown external signer type %q", signerType)
+	}
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+	if ks.isLocked() {
+		return ethkey.KeyV2{}, ErrLocked
+	}
+	key := ethkey.NewExternalKey(ethkey.EIP55AddressFromAddress(address))
+	if _, err := ks.getByID(key.ID()); err == nil {
+		return ethkey.KeyV2{}, fmt.Errorf("key with ID %s already exists", key.ID())
+	}
+	state := ethkey.State{
+		Address:        key.Address,
+		IsFunding:      isFunding,
+		ExternalSigner: null.StringFrom(signerType),
+		ExternalKeyRef: null.NewString(keyRef, keyRef != ""),
+	}
+	if err := ks.orm.db.Create(&state).Error; err != nil {
+		return ethkey.KeyV2{}, errors.Wrap(err, "unable to add external eth key")
+	}
+	ks.keyStates.Eth[key.ID()] = &state
+	ks.notify()
+	return key, nil
+}
+
+// SetExternalSigner configures the backend used to sign for external keys of
+// the given type
+func (ks *eth) SetExternalSigner(signerType string, s signer.Signer) {
+	ks.signersMu.Lock()
+	defer ks.signersMu.Unlock()
+	ks.signers[signerType] = s
+}
+
 func (ks *eth) SignTx(address common.Address, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
 	ks.lock.RLock()
-	defer ks.lock.RUnlock()
 	if ks.isLocked() {
+		ks.lock.RUnlock()
 		return nil, ErrLocked
 	}
 	key, err := ks.getByID(address.Hex())
 	if err != nil {
+		ks.lock.RUnlock()
 		return nil, err
 	}
-	signer := types.LatestSignerForChainID(chainID)
-	return types.SignTx(tx, signer, key.ToEcdsaPrivKey())
+	if !key.IsExternal() {
+		defer ks.lock.RUnlock()
+		return types.SignTx(tx, types.LatestSignerForChainID(chainID), key.ToEcdsaPrivKey())
+	}
+	state := *ks.keyStates.Eth[key.ID()]
+	// Release the lock before calling out; external signers may be slow or
+	// require manual approval
+	ks.lock.RUnlock()
+
+	ks.signersMu.RLock()
+	s, exists := ks.signers[state.ExternalSigner.String]
+	ks.signersMu.RUnlock()
+	if !exists {
+		return nil, errors.Errorf("no %s signer is configured for external key %s", state.ExternalSigner.String, key.ID())
+	}
+	return s.SignTx(state.ExternalKeyRef.String, address, tx, chainID)
 }
 
 func (ks *eth) SendingKeys() (sendingKeys []ethkey.KeyV2, err error) {
@@ -364,10 +438,22 @@ func (ks *eth) GetV1KeysAsV2() (keys []ethkey.KeyV2, states []ethkey.State, _ er
 
 func (ks *eth) getByID(id string) (ethkey.KeyV2, error) {
 	key, found := ks.keyRing.Eth[id]
-	if !found {
-		return ethkey.KeyV2{}, fmt.Errorf("unable to find eth key with id %s", id)
+	if found {
+		return key, nil
 	}
-	return key, nil
+	if state, exists := ks.keyStates.Eth[id]; exists && state.IsExternal() {
+		return ethkey.NewExternalKey(state.Address), nil
+	}
+	return ethkey.KeyV2{}, fmt.Errorf("unable to find eth key with id %s", id)
+}
+
+func (ks *eth) externalKeys() (keys []ethkey.KeyV2) {
+	for _, state := range ks.keyStates.Eth {
+		if state.IsExternal() {
+			keys = append(keys, ethkey.NewExternalKey(state.Address))
+		}
+	}
+	return keys
 }
 
 func (ks *eth) fundingKeys() (fundingKeys []ethkey.KeyV2) {
@@ -376,6 +462,11 @@ func (ks *eth) fundingKeys() (fundingKeys []ethkey.KeyV2) {
 			fundingKeys = append(fundingKeys, k)
 		}
 	}
+	for _, k := range ks.externalKeys() {
+		if ks.keyStates.Eth[k.ID()].IsFunding {
+			fundingKeys = append(fundingKeys, k)
+		}
+	}
 	sort.Slice(fundingKeys, func(i, j int) bool { return fundingKeys[i].Cmp(fundingKeys[j]) < 0 })
 	return fundingKeys
 }
@@ -386,6 +477,11 @@ func (ks *eth) sendingKeys() (sendingKeys []ethkey.KeyV2) {
 			sendingKeys = append(sendingKeys, k)
 		}
 	}
+	for _, k := range ks.externalKeys() {
+		if !ks.keyStates.Eth[k.ID()].IsFunding {
+			sendingKeys = append(sendingKeys, k)
+		}
+	}
 	sort.Slice(sendingKeys, func(i, j int) bool { return sendingKeys[i].Cmp(sendingKeys[j]) < 0 })
 	return sendingKeys
 }
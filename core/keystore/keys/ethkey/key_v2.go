@@ -56,6 +56,17 @@ func FromPrivateKey(privKey *ecdsa.PrivateKey) (key KeyV2) {
 	}
 }
 
+// NewExternalKey returns a key that only carries an address, for use with
+// keys held by an external signer
+func NewExternalKey(address EIP55Address) KeyV2 {
+	return KeyV2{Address: address}
+}
+
+// IsExternal returns true if the node does not hold the private key
+func (key KeyV2) IsExternal() bool {
+	return key.privateKey == nil
+}
+
 func (key KeyV2) ID() string {
 	return key.Address.Hex()
 }
@@ -1,15 +1,23 @@
 package ethkey
 
-import "time"
+import (
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+)
 
 type State struct {
 	ID        int32 `gorm:"primary_key"`
 	Address   EIP55Address
 	NextNonce int64
 	IsFunding bool
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	lastUsed  time.Time
+	// ExternalSigner is set for keys whose private key lives outside the
+	// node, e.g. in Clef or a PKCS#11 token
+	ExternalSigner null.String
+	ExternalKeyRef null.String
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	lastUsed       time.Time
 }
 
 func (State) TableName() string {
@@ -27,3 +35,8 @@ func (s State) LastUsed() time.Time {
 func (s *State) WasUsed() {
 	s.lastUsed = time.Now()
 }
+
+// IsExternal returns true if the key is signed for by an external signer
+func (s State) IsExternal() bool {
+	return s.ExternalSigner.Valid
+}
@@ -1,6 +1,8 @@
 package keystore
 
 import (
+	"crypto/subtle"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"sync"
@@ -19,6 +21,12 @@ import (
 
 var ErrLocked = errors.New("Keystore is locked")
 
+var ErrWrongPassword = errors.New("password does not match the keystore password")
+
+// ErrWeakScryptParams is returned when rotating the password to scrypt params
+// weaker than the configured ones
+var ErrWeakScryptParams = errors.New("scrypt params must not be weaker than the configured ones")
+
 type Master interface {
 	CSA() CSA
 	Eth() Eth
@@ -26,6 +34,7 @@ type Master interface {
 	P2P() P2P
 	VRF() VRF
 	Unlock(password string) error
+	RotatePassword(oldPassword, newPassword string, scryptParams *utils.ScryptParams) error
 	Migrate(vrfPassword string) error
 	IsEmpty() (bool, error)
 }
@@ -45,9 +54,10 @@ func New(db *gorm.DB, scryptParams utils.ScryptParams) Master {
 
 func newMaster(db *gorm.DB, scryptParams utils.ScryptParams) *master {
 	km := &keyManager{
-		orm:          NewORM(db),
-		scryptParams: scryptParams,
-		lock:         &sync.RWMutex{},
+		orm:             NewORM(db),
+		scryptParams:    scryptParams,
+		minScryptParams: scryptParams,
+		lock:            &sync.RWMutex{},
 	}
 
 	return &master{
@@ -164,10 +174,13 @@ func (ks *master) Migrate(vrfPssword string) error {
 type keyManager struct {
 	orm          ksORM
 	scryptParams utils.ScryptParams
-	keyRing      keyRing
-	keyStates    keyStates
-	lock         *sync.RWMutex
-	password     string
+	// minScryptParams are the params from the config, which the key ring is
+	// never encrypted with weaker params than
+	minScryptParams utils.ScryptParams
+	keyRing         keyRing
+	keyStates       keyStates
+	lock            *sync.RWMutex
+	password        string
 }
 
 func (km *keyManager) Unlock(password string) error {
@@ -189,6 +202,10 @@ func (km *keyManager) Unlock(password string) error {
 		return errors.Wrap(err, "unable to decrypt encrypted key ring")
 	}
 	km.keyRing = kr
+	// Keep encrypting with the params of a password rotation across restarts
+	if params, ok := ekr.scryptParams(); ok && !weakerScryptParams(params, km.minScryptParams) {
+		km.scryptParams = params
+	}
 
 	ks, err := km.orm.loadKeyStates()
 	if err != nil {
@@ -204,6 +221,76 @@ func (km *keyManager) Unlock(password string) error {
 	return nil
 }
 
+// RotatePassword re-encrypts the whole key ring under newPassword, and
+// optionally new scrypt params. These are stored with the ciphertext and used
+// again after a restart, so they must not be weaker than the configured ones.
+// The new ciphertext is written and read back
+// inside a single DB transaction, and only committed once it decrypts to the
+// same keys, so a failure at any point leaves the old ciphertext in place.
+func (km *keyManager) RotatePassword(oldPassword, newPassword string, scryptParams *utils.ScryptParams) error {
+	km.lock.Lock()
+	defer km.lock.Unlock()
+	if km.isLocked() {
+		return ErrLocked
+	}
+	if subtle.ConstantTimeCompare([]byte(oldPassword), []byte(km.password)) != 1 {
+		return ErrWrongPassword
+	}
+	if newPassword == "" {
+		return errors.New("new password must not be empty")
+	}
+	params := km.scryptParams
+	if scryptParams != nil {
+		params = *scryptParams
+	}
+	if weakerScryptParams(params, km.minScryptParams) {
+		return errors.Wrapf(ErrWeakScryptParams, "N=%d P=%d, configured N=%d P=%d", params.N, params.P, km.minScryptParams.N, km.minScryptParams.P)
+	}
+
+	expected, err := json.Marshal(km.keyRing.raw())
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal keyRing")
+	}
+	ekr, err := km.keyRing.Encrypt(newPassword, params)
+	if err != nil {
+		return errors.Wrap(err, "unable to encrypt keyRing")
+	}
+	err = postgres.GormTransactionWithDefaultContext(km.orm.db, func(tx *gorm.DB) error {
+		orm := NewORM(tx)
+		if err := orm.saveEncryptedKeyRing(&ekr); err != nil {
+			return err
+		}
+		saved, err := orm.getEncryptedKeyRing()
+		if err != nil {
+			return errors.Wrap(err, "unable to read back re-encrypted keyRing")
+		}
+		kr, err := saved.Decrypt(newPassword)
+		if err != nil {
+			return errors.Wrap(err, "verification failed: unable to decrypt re-encrypted keyRing")
+		}
+		actual, err := json.Marshal(kr.raw())
+		if err != nil {
+			return errors.Wrap(err, "unable to marshal re-encrypted keyRing")
+		}
+		if !keyRingsEqual(expected, actual) {
+			return errors.New("verification failed: re-encrypted keyRing does not match the original")
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "password rotation failed, keys remain encrypted with the old password")
+	}
+	km.password = newPassword
+	km.scryptParams = params
+	return nil
+}
+
+// weakerScryptParams reports whether params are cheaper to brute force than
+// min in either dimension
+func weakerScryptParams(params, min utils.ScryptParams) bool {
+	return params.N < min.N || params.P < min.P
+}
+
 func (km *keyManager) save(callbacks ...func(*gorm.DB) error) error {
 	ekb, err := km.keyRing.Encrypt(km.password, km.scryptParams)
 	if err != nil {
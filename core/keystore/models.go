@@ -2,6 +2,7 @@ package keystore
 
 import (
 	"encoding/json"
+	"reflect"
 	"time"
 
 	"PhoenixOracle/core/keystore/keys/csakey"
@@ -20,6 +21,24 @@ type encryptedKeyRing struct {
 	EncryptedKeys []byte
 }
 
+// scryptParams returns the scrypt params the key ring was encrypted with, if
+// it is encrypted with scrypt
+func (ekr encryptedKeyRing) scryptParams() (utils.ScryptParams, bool) {
+	if len(ekr.EncryptedKeys) == 0 {
+		return utils.ScryptParams{}, false
+	}
+	var cryptoJSON gethkeystore.CryptoJSON
+	if err := json.Unmarshal(ekr.EncryptedKeys, &cryptoJSON); err != nil || cryptoJSON.KDF != "scrypt" {
+		return utils.ScryptParams{}, false
+	}
+	n, nOK := cryptoJSON.KDFParams["n"].(float64)
+	p, pOK := cryptoJSON.KDFParams["p"].(float64)
+	if !nOK || !pOK {
+		return utils.ScryptParams{}, false
+	}
+	return utils.ScryptParams{N: int(n), P: int(p)}, true
+}
+
 func (ekr encryptedKeyRing) Decrypt(password string) (keyRing, error) {
 	if len(ekr.EncryptedKeys) == 0 {
 		return newKeyRing(), nil
@@ -162,3 +181,40 @@ func (rawKeys rawKeyRing) keys() (keyRing, error) {
 func adulteratedPassword(password string) string {
 	return "master-password-" + password
 }
+
+// keyRingsEqual compares two JSON encoded raw key rings. Key order within
+// each type is map iteration order, so keys are compared as sets.
+func keyRingsEqual(a, b []byte) bool {
+	var rawA, rawB rawKeyRing
+	if json.Unmarshal(a, &rawA) != nil || json.Unmarshal(b, &rawB) != nil {
+		return false
+	}
+	return sameRawKeys(rawA.Eth, rawB.Eth) &&
+		sameRawKeys(rawA.CSA, rawB.CSA) &&
+		sameRawKeys(rawA.OCR, rawB.OCR) &&
+		sameRawKeys(rawA.P2P, rawB.P2P) &&
+		sameRawKeys(rawA.VRF, rawB.VRF)
+}
+
+func sameRawKeys(a, b interface{}) bool {
+	setA, setB := rawKeySet(a), rawKeySet(b)
+	if len(setA) != len(setB) {
+		return false
+	}
+	for k := range setA {
+		if !setB[k] {
+			return false
+		}
+	}
+	return true
+}
+
+func rawKeySet(keys interface{}) map[string]bool {
+	set := make(map[string]bool)
+	v := reflect.ValueOf(keys)
+	for i := 0; i < v.Len(); i++ {
+		b, _ := json.Marshal(v.Index(i).Interface())
+		set[string(b)] = true
+	}
+	return set
+}
@@ -0,0 +1,98 @@
+package signer
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+)
+
+const clefRequestTimeout = 30 * time.Second
+
+var _ Signer = &ClefSigner{}
+
+// ClefSigner signs transactions using account_signTransaction on a
+// Clef-compatible JSON-RPC endpoint. Signing requests may need manual
+// approval on the signer side, hence the generous timeout.
+type ClefSigner struct {
+	url string
+
+	mu     sync.Mutex
+	client *rpc.Client
+}
+
+func NewClefSigner(url string) *ClefSigner {
+	return &ClefSigner{url: url}
+}
+
+type clefSendTxArgs struct {
+	From     common.MixedcaseAddress  `json:"from"`
+	To       *common.MixedcaseAddress `json:"to"`
+	Gas      hexutil.Uint64           `json:"gas"`
+	GasPrice hexutil.Big              `json:"gasPrice"`
+	Value    hexutil.Big              `json:"value"`
+	Nonce    hexutil.Uint64           `json:"nonce"`
+	Data     hexutil.Bytes            `json:"data"`
+	ChainID  *hexutil.Big             `json:"chainId"`
+}
+
+type clefSignTransactionResult struct {
+	Raw hexutil.Bytes `json:"raw"`
+}
+
+func (c *ClefSigner) SignTx(_ string, address common.Address, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	client, err := c.getClient()
+	if err != nil {
+		return nil, err
+	}
+	args := clefSendTxArgs{
+		From:     common.NewMixedcaseAddress(address),
+		Gas:      hexutil.Uint64(tx.Gas()),
+		GasPrice: hexutil.Big(*tx.GasPrice()),
+		Value:    hexutil.Big(*tx.Value()),
+		Nonce:    hexutil.Uint64(tx.Nonce()),
+		Data:     tx.Data(),
+		ChainID:  (*hexutil.Big)(chainID),
+	}
+	if tx.To() != nil {
+		to := common.NewMixedcaseAddress(*tx.To())
+		args.To = &to
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), clefRequestTimeout)
+	defer cancel()
+	var res clefSignTransactionResult
+	if err = client.CallContext(ctx, &res, "account_signTransaction", args); err != nil {
+		return nil, errors.Wrap(err, "clef: account_signTransaction failed")
+	}
+	signed := new(types.Transaction)
+	if err = signed.UnmarshalBinary(res.Raw); err != nil {
+		return nil, errors.Wrap(err, "clef: could not decode signed transaction")
+	}
+	if err = verifySigned(tx, signed, address, chainID); err != nil {
+		return nil, errors.Wrap(err, "clef")
+	}
+	return signed, nil
+}
+
+func (c *ClefSigner) getClient() (*rpc.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.client != nil {
+		return c.client, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), clefRequestTimeout)
+	defer cancel()
+	client, err := rpc.DialContext(ctx, c.url)
+	if err != nil {
+		return nil, errors.Wrapf(err, "clef: unable to dial %s", c.url)
+	}
+	c.client = client
+	return client, nil
+}
@@ -0,0 +1,118 @@
+//go:build pkcs11
+// +build pkcs11
+
+package signer
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/miekg/pkcs11"
+	"github.com/pkg/errors"
+)
+
+var _ Signer = &PKCS11Signer{}
+
+// PKCS11Signer signs with secp256k1 keys stored in a PKCS#11 token. Keys are
+// looked up by their CKA_LABEL. It can be exercised locally against SoftHSM.
+type PKCS11Signer struct {
+	ctx     *pkcs11.Ctx
+	session pkcs11.SessionHandle
+	mu      sync.Mutex
+}
+
+// NewPKCS11Signer loads the PKCS#11 module at modulePath and logs into the
+// token with the given label
+func NewPKCS11Signer(modulePath, tokenLabel, pin string) (Signer, error) {
+	p := pkcs11.New(modulePath)
+	if p == nil {
+		return nil, errors.Errorf("pkcs11: unable to load module %s", modulePath)
+	}
+	if err := p.Initialize(); err != nil {
+		return nil, errors.Wrap(err, "pkcs11: initialize failed")
+	}
+	slot, err := findSlot(p, tokenLabel)
+	if err != nil {
+		p.Destroy()
+		return nil, err
+	}
+	session, err := p.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		p.Destroy()
+		return nil, errors.Wrap(err, "pkcs11: open session failed")
+	}
+	if err = p.Login(session, pkcs11.CKU_USER, pin); err != nil {
+		p.CloseSession(session)
+		p.Destroy()
+		return nil, errors.Wrap(err, "pkcs11: login failed")
+	}
+	return &PKCS11Signer{ctx: p, session: session}, nil
+}
+
+func findSlot(p *pkcs11.Ctx, tokenLabel string) (uint, error) {
+	slots, err := p.GetSlotList(true)
+	if err != nil {
+		return 0, errors.Wrap(err, "pkcs11: unable to list slots")
+	}
+	for _, slot := range slots {
+		info, err := p.GetTokenInfo(slot)
+		if err != nil {
+			continue
+		}
+		if info.Label == tokenLabel {
+			return slot, nil
+		}
+	}
+	return 0, errors.Errorf("pkcs11: no token found with label %q", tokenLabel)
+}
+
+func (s *PKCS11Signer) SignTx(keyRef string, address common.Address, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signer := types.LatestSignerForChainID(chainID)
+	hash := signer.Hash(tx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, err := s.findPrivateKey(keyRef)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.ctx.SignInit(s.session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_ECDSA, nil)}, key); err != nil {
+		return nil, errors.Wrap(err, "pkcs11: sign init failed")
+	}
+	rs, err := s.ctx.Sign(s.session, hash.Bytes())
+	if err != nil {
+		return nil, errors.Wrap(err, "pkcs11: sign failed")
+	}
+	if len(rs) != 64 {
+		return nil, errors.Errorf("pkcs11: unexpected signature length %d", len(rs))
+	}
+	sig, err := signatureFromRS(hash.Bytes(), new(big.Int).SetBytes(rs[:32]), new(big.Int).SetBytes(rs[32:]), address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "pkcs11: key %q", keyRef)
+	}
+	return tx.WithSignature(signer, sig)
+}
+
+func (s *PKCS11Signer) findPrivateKey(label string) (pkcs11.ObjectHandle, error) {
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_PRIVATE_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}
+	if err := s.ctx.FindObjectsInit(s.session, template); err != nil {
+		return 0, errors.Wrap(err, "pkcs11: find objects init failed")
+	}
+	objs, _, err := s.ctx.FindObjects(s.session, 1)
+	if ferr := s.ctx.FindObjectsFinal(s.session); err == nil {
+		err = ferr
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "pkcs11: find objects failed")
+	}
+	if len(objs) == 0 {
+		return 0, errors.Errorf("pkcs11: no private key found with label %q", label)
+	}
+	return objs[0], nil
+}
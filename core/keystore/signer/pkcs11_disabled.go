@@ -0,0 +1,12 @@
+//go:build !pkcs11
+// +build !pkcs11
+
+package signer
+
+import "github.com/pkg/errors"
+
+// NewPKCS11Signer is unavailable unless the node is built with the pkcs11
+// build tag, since it requires cgo and github.com/miekg/pkcs11
+func NewPKCS11Signer(modulePath, tokenLabel, pin string) (Signer, error) {
+	return nil, errors.New("pkcs11: this node was built without PKCS#11 support; rebuild with -tags pkcs11")
+}
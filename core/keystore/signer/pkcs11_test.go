@@ -0,0 +1,127 @@
+//go:build pkcs11
+// +build pkcs11
+
+package signer
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/miekg/pkcs11"
+)
+
+// DER encoding of the secp256k1 curve OID, 1.3.132.0.10
+var secp256k1OID = []byte{0x06, 0x05, 0x2b, 0x81, 0x04, 0x00, 0x0a}
+
+// newTestPKCS11Signer logs into the token labelled PKCS11_TOKEN_LABEL
+// ("test" by default) with PKCS11_PIN ("1234" by default), of the SoftHSM
+// module at PKCS11_MODULE, e.g. /usr/lib/softhsm/libsofthsm2.so. The token is
+// created with "softhsm2-util --init-token --free --label test --pin 1234
+// --so-pin 1234".
+func newTestPKCS11Signer(t *testing.T) *PKCS11Signer {
+	modulePath := os.Getenv("PKCS11_MODULE")
+	if modulePath == "" {
+		t.Skip("PKCS11_MODULE is not set to the path of a SoftHSM module")
+	}
+	tokenLabel, pin := os.Getenv("PKCS11_TOKEN_LABEL"), os.Getenv("PKCS11_PIN")
+	if tokenLabel == "" {
+		tokenLabel = "test"
+	}
+	if pin == "" {
+		pin = "1234"
+	}
+	s, err := NewPKCS11Signer(modulePath, tokenLabel, pin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := s.(*PKCS11Signer)
+	t.Cleanup(func() {
+		_ = p.ctx.Logout(p.session)
+		_ = p.ctx.CloseSession(p.session)
+		_ = p.ctx.Finalize()
+		p.ctx.Destroy()
+	})
+	return p
+}
+
+// generateSessionKey creates a secp256k1 key pair that lives as long as the
+// session, and returns its address
+func generateSessionKey(t *testing.T, p *PKCS11Signer, label string) common.Address {
+	pub, _, err := p.ctx.GenerateKeyPair(p.session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, false),
+			pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, secp256k1OID),
+			pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+		},
+		[]*pkcs11.Attribute{
+			pkcs11.NewAttribute(pkcs11.CKA_TOKEN, false),
+			pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+			pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+			pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attrs, err := p.ctx.GetAttributeValue(p.session, pub, []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// CKA_EC_POINT is the uncompressed point wrapped in a DER octet string
+	point := attrs[0].Value
+	if len(point) != 67 || point[0] != 0x04 || point[1] != 65 {
+		t.Fatalf("unexpected EC point encoding %x", point)
+	}
+	pubKey, err := crypto.UnmarshalPubkey(point[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return crypto.PubkeyToAddress(*pubKey)
+}
+
+func TestPKCS11Signer_SignTx(t *testing.T) {
+	p := newTestPKCS11Signer(t)
+	label := fmt.Sprintf("signer-test-%d", time.Now().UnixNano())
+	address := generateSessionKey(t, p, label)
+
+	chainID := big.NewInt(1337)
+	tx := types.NewTransaction(7, common.HexToAddress("0x6f7bADc7eD84D64DB7bF3bB8db6A6dADb52607e2"), big.NewInt(1), 21000, big.NewInt(1e9), []byte{1, 2, 3})
+
+	// Sign a few times, since the recovery id differs between signatures
+	for i := 0; i < 4; i++ {
+		signed, err := p.SignTx(label, address, tx, chainID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sender != address {
+			t.Fatalf("signed transaction recovers to %s, expected %s", sender.Hex(), address.Hex())
+		}
+		if err = verifySigned(tx, signed, address, chainID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("address of another key", func(t *testing.T) {
+		if _, err := p.SignTx(label, common.HexToAddress("0x22D2a184da4E94625E3FdAd977b983e91312519E"), tx, chainID); err == nil {
+			t.Fatal("expected signing for another address to fail")
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		if _, err := p.SignTx(label+"-unknown", address, tx, chainID); err == nil {
+			t.Fatal("expected signing with an unknown key to fail")
+		}
+	})
+}
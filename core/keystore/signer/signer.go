@@ -0,0 +1,75 @@
+package signer
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+const (
+	// TypeClef signs via a Clef-compatible JSON-RPC signer
+	TypeClef = "clef"
+	// TypePKCS11 signs with a key held in a PKCS#11 token (e.g. an HSM)
+	TypePKCS11 = "pkcs11"
+)
+
+// Signer signs transactions for keys that the node does not hold in its
+// own keystore. keyRef is backend specific: it is ignored by Clef, which
+// identifies keys by address, and is the object label for PKCS#11.
+type Signer interface {
+	SignTx(keyRef string, address common.Address, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// IsValidType returns true if t names a supported external signer backend
+func IsValidType(t string) bool {
+	return t == TypeClef || t == TypePKCS11
+}
+
+var secp256k1N = crypto.S256().Params().N
+var secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+
+// signatureFromRS turns a raw (r, s) ECDSA signature over hash into the
+// 65 byte [R || S || V] form expected by go-ethereum. s is normalized to the
+// lower half of the curve order as required by EIP-2, and the recovery id is
+// found by checking which candidate recovers to address.
+func signatureFromRS(hash []byte, r, s *big.Int, address common.Address) ([]byte, error) {
+	if s.Cmp(secp256k1HalfN) > 0 {
+		s = new(big.Int).Sub(secp256k1N, s)
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	copy(sig[32-len(r.Bytes()):32], r.Bytes())
+	copy(sig[64-len(s.Bytes()):64], s.Bytes())
+	for v := byte(0); v < 2; v++ {
+		sig[64] = v
+		pub, err := crypto.SigToPub(hash, sig)
+		if err != nil {
+			continue
+		}
+		if bytes.Equal(crypto.PubkeyToAddress(*pub).Bytes(), address.Bytes()) {
+			return sig, nil
+		}
+	}
+	return nil, errors.Errorf("signature does not recover to %s", address.Hex())
+}
+
+// verifySigned checks that signed is unsigned with a valid signature from
+// address, guarding against a signer that returns a different transaction
+// than the one it was asked to sign.
+func verifySigned(unsigned, signed *types.Transaction, address common.Address, chainID *big.Int) error {
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return errors.Wrap(err, "could not recover sender of signed transaction")
+	}
+	if sender != address {
+		return errors.Errorf("signed transaction is from %s, expected %s", sender.Hex(), address.Hex())
+	}
+	signer := types.LatestSignerForChainID(chainID)
+	if signer.Hash(unsigned) != signer.Hash(signed) {
+		return errors.New("signed transaction does not match the transaction that was requested")
+	}
+	return nil
+}
@@ -14,7 +14,6 @@ import (
 type (
 	registrations struct {
 		subscribers map[uint64]*subscribers
-		decoders    map[common.Address]ParseLogFunc
 		logger      *logger.Logger
 
 		highestNumConfirmations uint64
@@ -39,15 +38,11 @@ type (
 func newRegistrations(logger *logger.Logger) *registrations {
 	return &registrations{
 		subscribers: make(map[uint64]*subscribers),
-		decoders:    make(map[common.Address]ParseLogFunc),
 		logger:      logger,
 	}
 }
 
 func (r *registrations) addSubscriber(reg registration) (needsResubscribe bool) {
-	addr := reg.opts.Contract
-	r.decoders[addr] = reg.opts.ParseLog
-
 	if _, exists := r.subscribers[reg.opts.NumConfirmations]; !exists {
 		r.subscribers[reg.opts.NumConfirmations] = newSubscribers()
 	}
@@ -128,7 +123,7 @@ func (r *registrations) sendLogs(logsToSend []logsOnBlock, latestHead models.Hea
 			}
 
 			for _, log := range logsPerBlock.Logs {
-				subscribers.sendLog(log, latestHead, broadcastsExisting, r.decoders, r.logger)
+				subscribers.sendLog(log, latestHead, broadcastsExisting, r.logger)
 			}
 		}
 	}
@@ -226,7 +221,6 @@ func (r *subscribers) isAddressRegistered(address common.Address) bool {
 
 func (r *subscribers) sendLog(log types.Log, latestHead models.Head,
 	broadcasts map[LogBroadcastAsKey]struct{},
-	decoders map[common.Address]ParseLogFunc,
 	logger *logger.Logger) {
 
 	latestBlockNumber := uint64(latestHead.Number)
@@ -249,9 +243,11 @@ func (r *subscribers) sendLog(log types.Log, latestHead models.Head,
 
 		logCopy := gethwrappers.DeepCopyLog(log)
 
+		// Each listener decodes with its own ParseLog, so listeners with
+		// different ABIs can share a contract address
 		var decodedLog generated.AbigenLog
 		var err error
-		if parseLog := decoders[log.Address]; parseLog != nil {
+		if parseLog := metadata.opts.ParseLog; parseLog != nil {
 			decodedLog, err = parseLog(logCopy)
 			if err != nil {
 				logger.Errorw("Could not parse contract log", "error", err)
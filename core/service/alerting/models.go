@@ -0,0 +1,104 @@
+package alerting
+
+import (
+	"time"
+
+	"PhoenixOracle/db/models"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"gopkg.in/guregu/null.v4"
+	"gorm.io/datatypes"
+)
+
+type NotifierType string
+
+const (
+	NotifierTypeWebhook NotifierType = "webhook"
+	NotifierTypeSMTP    NotifierType = "smtp"
+	NotifierTypeSlack   NotifierType = "slack"
+)
+
+type RuleType string
+
+const (
+	// RuleTypeEthBalanceBelow fires when any sending key holds less than
+	// Threshold ETH.
+	RuleTypeEthBalanceBelow RuleType = "eth_balance_below"
+	// RuleTypePhbBalanceBelow fires when any sending key holds less than
+	// Threshold PHB.
+	RuleTypePhbBalanceBelow RuleType = "phb_balance_below"
+	// RuleTypeHeadStalled fires when no new head has been seen for
+	// Threshold seconds.
+	RuleTypeHeadStalled RuleType = "head_stalled"
+	// RuleTypeTxUnconfirmed fires when a broadcast transaction has been
+	// unconfirmed for at least Threshold blocks.
+	RuleTypeTxUnconfirmed RuleType = "tx_unconfirmed"
+	// RuleTypeJobErrorSpike fires when at least Threshold pipeline runs
+	// errored within Window, optionally restricted to JobID.
+	RuleTypeJobErrorSpike RuleType = "job_error_spike"
+)
+
+type Notifier struct {
+	ID        int64
+	Name      string
+	Type      NotifierType
+	Config    datatypes.JSON
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (Notifier) TableName() string {
+	return "alert_notifiers"
+}
+
+type Rule struct {
+	ID          int64
+	Name        string
+	Type        RuleType
+	Threshold   decimal.Decimal
+	Window      models.Interval `gorm:"column:time_window"`
+	Cooldown    models.Interval
+	JobID       null.Int32
+	NotifierIDs pq.Int64Array `gorm:"type:bigint[]"`
+	Enabled     bool
+	LastFiredAt null.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (Rule) TableName() string {
+	return "alert_rules"
+}
+
+func (r Rule) Validate() error {
+	if r.Name == "" {
+		return errors.New("rule name must not be empty")
+	}
+	switch r.Type {
+	case RuleTypeEthBalanceBelow, RuleTypePhbBalanceBelow, RuleTypeHeadStalled, RuleTypeTxUnconfirmed:
+	case RuleTypeJobErrorSpike:
+		if r.Window.Duration() <= 0 {
+			return errors.New("job_error_spike rules require a positive window")
+		}
+	default:
+		return errors.Errorf("unknown rule type %q", r.Type)
+	}
+	if r.Threshold.IsNegative() {
+		return errors.New("rule threshold must not be negative")
+	}
+	if len(r.NotifierIDs) == 0 {
+		return errors.New("rule must reference at least one notifier")
+	}
+	return nil
+}
+
+// Alert is a single notification produced by a rule firing, or by a manual
+// test from the API.
+type Alert struct {
+	RuleName string    `json:"rule"`
+	RuleType RuleType  `json:"type"`
+	Summary  string    `json:"summary"`
+	Details  string    `json:"details"`
+	FiredAt  time.Time `json:"firedAt"`
+}
@@ -0,0 +1,164 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+
+	"PhoenixOracle/util"
+	"github.com/pkg/errors"
+)
+
+// NotifierBackend delivers an Alert to an external system.
+type NotifierBackend interface {
+	Notify(ctx context.Context, alert Alert) error
+}
+
+type WebhookConfig struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+}
+
+type SMTPConfig struct {
+	Host     string   `json:"host"`
+	Port     int      `json:"port"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
+
+type SlackConfig struct {
+	WebhookURL string `json:"webhookURL"`
+	Channel    string `json:"channel"`
+}
+
+func newNotifierBackend(n Notifier) (NotifierBackend, error) {
+	switch n.Type {
+	case NotifierTypeWebhook:
+		var cfg WebhookConfig
+		if err := json.Unmarshal(n.Config, &cfg); err != nil {
+			return nil, err
+		}
+		if cfg.URL == "" {
+			return nil, errors.New("webhook notifier requires a url")
+		}
+		return &webhookNotifier{cfg, utils.UnrestrictedClient}, nil
+	case NotifierTypeSMTP:
+		var cfg SMTPConfig
+		if err := json.Unmarshal(n.Config, &cfg); err != nil {
+			return nil, err
+		}
+		if cfg.Host == "" || cfg.From == "" || len(cfg.To) == 0 {
+			return nil, errors.New("smtp notifier requires host, from and at least one recipient")
+		}
+		if cfg.Port == 0 {
+			cfg.Port = 587
+		}
+		return &smtpNotifier{cfg}, nil
+	case NotifierTypeSlack:
+		var cfg SlackConfig
+		if err := json.Unmarshal(n.Config, &cfg); err != nil {
+			return nil, err
+		}
+		if cfg.WebhookURL == "" {
+			return nil, errors.New("slack notifier requires a webhookURL")
+		}
+		return &slackNotifier{cfg, utils.UnrestrictedClient}, nil
+	default:
+		return nil, errors.Errorf("unknown notifier type %q", n.Type)
+	}
+}
+
+type webhookNotifier struct {
+	cfg    WebhookConfig
+	client *http.Client
+}
+
+func (w *webhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, w.client, w.cfg.URL, w.cfg.Headers, body)
+}
+
+type slackNotifier struct {
+	cfg    SlackConfig
+	client *http.Client
+}
+
+func (s *slackNotifier) Notify(ctx context.Context, alert Alert) error {
+	msg := map[string]interface{}{
+		"text": fmt.Sprintf("*%s* (%s)\n%s\n%s", alert.Summary, alert.RuleName, alert.Details, alert.FiredAt.UTC().Format("2006-01-02 15:04:05 MST")),
+	}
+	if s.cfg.Channel != "" {
+		msg["channel"] = s.cfg.Channel
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, s.client, s.cfg.WebhookURL, nil, body)
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf("notifier endpoint returned %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+type smtpNotifier struct {
+	cfg SMTPConfig
+}
+
+func (s *smtpNotifier) Notify(ctx context.Context, alert Alert) error {
+	addr := net.JoinHostPort(s.cfg.Host, fmt.Sprintf("%d", s.cfg.Port))
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+	}
+	msg := strings.Join([]string{
+		"From: " + s.cfg.From,
+		"To: " + strings.Join(s.cfg.To, ", "),
+		"Subject: [Phoenix] " + alert.Summary,
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		fmt.Sprintf("Rule: %s (%s)", alert.RuleName, alert.RuleType),
+		fmt.Sprintf("Fired at: %s", alert.FiredAt.UTC().Format("2006-01-02 15:04:05 MST")),
+		"",
+		alert.Details,
+	}, "\r\n")
+
+	chErr := make(chan error, 1)
+	go func() {
+		chErr <- smtp.SendMail(addr, auth, s.cfg.From, s.cfg.To, []byte(msg))
+	}()
+	select {
+	case err := <-chErr:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
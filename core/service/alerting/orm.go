@@ -0,0 +1,165 @@
+package alerting
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type ORM interface {
+	CreateNotifier(ctx context.Context, n *Notifier) error
+	DeleteNotifier(ctx context.Context, id int64) error
+	FindNotifier(ctx context.Context, id int64) (*Notifier, error)
+	FindNotifiers(ctx context.Context, ids []int64) ([]Notifier, error)
+	ListNotifiers(ctx context.Context) ([]Notifier, error)
+
+	CreateRule(ctx context.Context, r *Rule) error
+	DeleteRule(ctx context.Context, id int64) error
+	ListRules(ctx context.Context) ([]Rule, error)
+	ListEnabledRules(ctx context.Context) ([]Rule, error)
+	MarkRuleFired(ctx context.Context, id int64, at time.Time) error
+
+	CountErroredRuns(ctx context.Context, since time.Time, jobID *int32) (int64, error)
+	OldestUnconfirmedBroadcastBlock(ctx context.Context) (*int64, error)
+}
+
+type orm struct {
+	db *gorm.DB
+}
+
+var _ ORM = (*orm)(nil)
+
+func NewORM(db *gorm.DB) *orm {
+	return &orm{db: db}
+}
+
+func (o *orm) CreateNotifier(ctx context.Context, n *Notifier) error {
+	switch n.Type {
+	case NotifierTypeWebhook, NotifierTypeSMTP, NotifierTypeSlack:
+	default:
+		return errors.Errorf("unknown notifier type %q", n.Type)
+	}
+	if _, err := newNotifierBackend(*n); err != nil {
+		return errors.Wrap(err, "invalid notifier config")
+	}
+	return o.db.WithContext(ctx).Create(n).Error
+}
+
+func (o *orm) DeleteNotifier(ctx context.Context, id int64) error {
+	var inUse int64
+	err := o.db.WithContext(ctx).Raw(`SELECT count(*) FROM alert_rules WHERE ? = ANY(notifier_ids)`, id).Scan(&inUse).Error
+	if err != nil {
+		return err
+	}
+	if inUse > 0 {
+		return errors.Errorf("notifier %d is still referenced by %d rule(s)", id, inUse)
+	}
+	result := o.db.WithContext(ctx).Exec(`DELETE FROM alert_notifiers WHERE id = ?`, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (o *orm) FindNotifier(ctx context.Context, id int64) (*Notifier, error) {
+	var n Notifier
+	err := o.db.WithContext(ctx).First(&n, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, sql.ErrNoRows
+	}
+	return &n, err
+}
+
+func (o *orm) FindNotifiers(ctx context.Context, ids []int64) ([]Notifier, error) {
+	ns := []Notifier{}
+	if len(ids) == 0 {
+		return ns, nil
+	}
+	err := o.db.WithContext(ctx).Where("id IN (?)", ids).Order("id asc").Find(&ns).Error
+	return ns, err
+}
+
+func (o *orm) ListNotifiers(ctx context.Context) ([]Notifier, error) {
+	ns := []Notifier{}
+	err := o.db.WithContext(ctx).Order("id asc").Find(&ns).Error
+	return ns, err
+}
+
+func (o *orm) CreateRule(ctx context.Context, r *Rule) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	var found int64
+	err := o.db.WithContext(ctx).Raw(`SELECT count(*) FROM alert_notifiers WHERE id = ANY(?)`, r.NotifierIDs).Scan(&found).Error
+	if err != nil {
+		return err
+	}
+	if found != int64(len(r.NotifierIDs)) {
+		return errors.New("rule references unknown notifier")
+	}
+	return o.db.WithContext(ctx).Create(r).Error
+}
+
+func (o *orm) DeleteRule(ctx context.Context, id int64) error {
+	result := o.db.WithContext(ctx).Exec(`DELETE FROM alert_rules WHERE id = ?`, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (o *orm) ListRules(ctx context.Context) ([]Rule, error) {
+	rs := []Rule{}
+	err := o.db.WithContext(ctx).Order("id asc").Find(&rs).Error
+	return rs, err
+}
+
+func (o *orm) ListEnabledRules(ctx context.Context) ([]Rule, error) {
+	rs := []Rule{}
+	err := o.db.WithContext(ctx).Where("enabled").Order("id asc").Find(&rs).Error
+	return rs, err
+}
+
+func (o *orm) MarkRuleFired(ctx context.Context, id int64, at time.Time) error {
+	return o.db.WithContext(ctx).Exec(`UPDATE alert_rules SET last_fired_at = ? WHERE id = ?`, at, id).Error
+}
+
+func (o *orm) CountErroredRuns(ctx context.Context, since time.Time, jobID *int32) (count int64, err error) {
+	if jobID == nil {
+		err = o.db.WithContext(ctx).Raw(`
+SELECT count(*) FROM pipeline_runs
+WHERE state = 'errored' AND finished_at >= ?
+`, since).Scan(&count).Error
+		return
+	}
+	err = o.db.WithContext(ctx).Raw(`
+SELECT count(*) FROM pipeline_runs
+JOIN jobs ON jobs.pipeline_spec_id = pipeline_runs.pipeline_spec_id
+WHERE pipeline_runs.state = 'errored' AND pipeline_runs.finished_at >= ? AND jobs.id = ?
+`, since, *jobID).Scan(&count).Error
+	return
+}
+
+// OldestUnconfirmedBroadcastBlock returns the lowest block number before which
+// a still-unconfirmed transaction was broadcast, or nil if there are none.
+func (o *orm) OldestUnconfirmedBroadcastBlock(ctx context.Context) (*int64, error) {
+	var blockNum sql.NullInt64
+	err := o.db.WithContext(ctx).Raw(`
+SELECT min(eth_tx_attempts.broadcast_before_block_num) FROM eth_tx_attempts
+JOIN eth_txes ON eth_txes.id = eth_tx_attempts.eth_tx_id
+WHERE eth_txes.state = 'unconfirmed' AND eth_tx_attempts.state = 'broadcast'
+`).Scan(&blockNum).Error
+	if err != nil || !blockNum.Valid {
+		return nil, err
+	}
+	return &blockNum.Int64, nil
+}
@@ -0,0 +1,377 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"PhoenixOracle/core/assets"
+	"PhoenixOracle/core/keystore"
+	"PhoenixOracle/core/service/balancemonitor"
+	"PhoenixOracle/core/service/ethereum"
+	"PhoenixOracle/db/models"
+	httypes "PhoenixOracle/lib/headtracker/types"
+	"PhoenixOracle/lib/logger"
+	"PhoenixOracle/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/shopspring/decimal"
+	"go.uber.org/multierr"
+)
+
+const (
+	defaultCooldown = 15 * time.Minute
+	notifyTimeout   = 30 * time.Second
+)
+
+var (
+	promAlertsFired = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "alerting_alerts_fired",
+		Help: "The number of alerts fired, by rule type",
+	}, []string{"rule_type"})
+	promNotifyErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "alerting_notify_errors",
+		Help: "The number of failed notification deliveries, by notifier type",
+	}, []string{"notifier_type"})
+
+	weiPerUnit = decimal.NewFromBigInt(big.NewInt(1), 18)
+)
+
+type Config interface {
+	AlertingCheckInterval() time.Duration
+	PhbContractAddress() string
+}
+
+type Service interface {
+	httypes.HeadTrackable
+	Start() error
+	Close() error
+	Ready() error
+	Healthy() error
+
+	ORM() ORM
+	TestNotifier(ctx context.Context, id int64) error
+}
+
+type alertingService struct {
+	utils.StartStopOnce
+
+	orm            ORM
+	ethClient      ethereum.Client
+	ethKeyStore    keystore.Eth
+	balanceMonitor balancemonitor.BalanceMonitor
+	config         Config
+	logger         *logger.Logger
+
+	headMu     sync.RWMutex
+	lastHead   *models.Head
+	lastHeadAt time.Time
+
+	chStop chan struct{}
+	wg     sync.WaitGroup
+}
+
+var _ Service = (*alertingService)(nil)
+
+func NewService(
+	orm ORM,
+	ethClient ethereum.Client,
+	ethKeyStore keystore.Eth,
+	balanceMonitor balancemonitor.BalanceMonitor,
+	config Config,
+	lggr *logger.Logger,
+) *alertingService {
+	return &alertingService{
+		orm:            orm,
+		ethClient:      ethClient,
+		ethKeyStore:    ethKeyStore,
+		balanceMonitor: balanceMonitor,
+		config:         config,
+		logger:         lggr.Named("Alerting"),
+		chStop:         make(chan struct{}),
+	}
+}
+
+func (s *alertingService) Start() error {
+	return s.StartOnce("Alerting", func() error {
+		s.headMu.Lock()
+		s.lastHeadAt = time.Now()
+		s.headMu.Unlock()
+
+		s.wg.Add(1)
+		go s.run()
+		return nil
+	})
+}
+
+func (s *alertingService) Close() error {
+	return s.StopOnce("Alerting", func() error {
+		close(s.chStop)
+		s.wg.Wait()
+		return nil
+	})
+}
+
+func (s *alertingService) ORM() ORM {
+	return s.orm
+}
+
+func (s *alertingService) OnNewLongestChain(_ context.Context, head models.Head) {
+	s.headMu.Lock()
+	s.lastHead = &head
+	s.lastHeadAt = time.Now()
+	s.headMu.Unlock()
+}
+
+func (s *alertingService) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.AlertingCheckInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.chStop:
+			return
+		case <-ticker.C:
+		}
+		s.evaluateRules()
+	}
+}
+
+func (s *alertingService) evaluateRules() {
+	ctx, cancel := utils.ContextFromChan(s.chStop)
+	defer cancel()
+
+	rules, err := s.orm.ListEnabledRules(ctx)
+	if err != nil {
+		s.logger.Errorw("Alerting: failed to load rules", "err", err)
+		return
+	}
+
+	now := time.Now()
+	for _, rule := range rules {
+		cooldown := rule.Cooldown.Duration()
+		if cooldown <= 0 {
+			cooldown = defaultCooldown
+		}
+		if rule.LastFiredAt.Valid && now.Sub(rule.LastFiredAt.Time) < cooldown {
+			continue
+		}
+
+		alert, err := s.check(ctx, rule)
+		if err != nil {
+			s.logger.Errorw("Alerting: failed to evaluate rule", "rule", rule.Name, "err", err)
+			continue
+		}
+		if alert == nil {
+			continue
+		}
+		alert.FiredAt = now
+
+		promAlertsFired.WithLabelValues(string(rule.Type)).Inc()
+		s.logger.Warnw(fmt.Sprintf("Alerting: %s", alert.Summary), "rule", rule.Name, "details", alert.Details)
+		delivered, err := s.notify(ctx, rule.NotifierIDs, *alert)
+		if err != nil {
+			s.logger.Errorw("Alerting: failed to deliver alert", "rule", rule.Name, "delivered", delivered, "err", err)
+		}
+		if delivered == 0 {
+			// The cooldown only starts once someone was notified, so that the
+			// alert is retried on the next tick
+			continue
+		}
+		if err := s.orm.MarkRuleFired(ctx, rule.ID, now); err != nil {
+			s.logger.Errorw("Alerting: failed to mark rule fired", "rule", rule.Name, "err", err)
+		}
+	}
+}
+
+// check returns a non-nil Alert if the rule's condition currently holds.
+func (s *alertingService) check(ctx context.Context, rule Rule) (*Alert, error) {
+	switch rule.Type {
+	case RuleTypeEthBalanceBelow:
+		return s.checkEthBalance(rule)
+	case RuleTypePhbBalanceBelow:
+		return s.checkPhbBalance(rule)
+	case RuleTypeHeadStalled:
+		return s.checkHeadStalled(rule), nil
+	case RuleTypeTxUnconfirmed:
+		return s.checkTxUnconfirmed(ctx, rule)
+	case RuleTypeJobErrorSpike:
+		return s.checkJobErrors(ctx, rule)
+	default:
+		return nil, errors.Errorf("unknown rule type %q", rule.Type)
+	}
+}
+
+func (s *alertingService) checkEthBalance(rule Rule) (*Alert, error) {
+	keys, err := s.ethKeyStore.SendingKeys()
+	if err != nil {
+		return nil, err
+	}
+	threshold := assets.Eth(*rule.Threshold.Mul(weiPerUnit).BigInt())
+	for _, k := range keys {
+		bal := s.balanceMonitor.GetEthBalance(k.Address.Address())
+		if bal == nil || bal.Cmp(&threshold) >= 0 {
+			continue
+		}
+		return &Alert{
+			RuleName: rule.Name,
+			RuleType: rule.Type,
+			Summary:  fmt.Sprintf("ETH balance of %s is below %s", k.Address.Hex(), threshold.String()),
+			Details:  fmt.Sprintf("Current balance: %s", bal.String()),
+		}, nil
+	}
+	return nil, nil
+}
+
+func (s *alertingService) checkPhbBalance(rule Rule) (*Alert, error) {
+	phbAddress := s.config.PhbContractAddress()
+	if phbAddress == "" {
+		return nil, errors.New("PHB_CONTRACT_ADDRESS is not set")
+	}
+	keys, err := s.ethKeyStore.SendingKeys()
+	if err != nil {
+		return nil, err
+	}
+	threshold := assets.Phb(*rule.Threshold.Mul(weiPerUnit).BigInt())
+	var merr error
+	for _, k := range keys {
+		bal, err := s.ethClient.GetPHBBalance(common.HexToAddress(phbAddress), k.Address.Address())
+		if err != nil {
+			merr = multierr.Append(merr, err)
+			continue
+		}
+		if bal.Cmp(&threshold) >= 0 {
+			continue
+		}
+		return &Alert{
+			RuleName: rule.Name,
+			RuleType: rule.Type,
+			Summary:  fmt.Sprintf("PHB balance of %s is below %s", k.Address.Hex(), threshold.String()),
+			Details:  fmt.Sprintf("Current balance: %s", bal.String()),
+		}, nil
+	}
+	return nil, merr
+}
+
+func (s *alertingService) checkHeadStalled(rule Rule) *Alert {
+	s.headMu.RLock()
+	lastHead, lastHeadAt := s.lastHead, s.lastHeadAt
+	s.headMu.RUnlock()
+
+	maxAge := time.Duration(rule.Threshold.IntPart()) * time.Second
+	age := time.Since(lastHeadAt)
+	if age < maxAge {
+		return nil
+	}
+	details := "No head has been received since the node started"
+	if lastHead != nil {
+		details = fmt.Sprintf("Last head was block %d (%s)", lastHead.Number, lastHead.Hash.Hex())
+	}
+	return &Alert{
+		RuleName: rule.Name,
+		RuleType: rule.Type,
+		Summary:  fmt.Sprintf("Head has not advanced for %s", age.Round(time.Second)),
+		Details:  details,
+	}
+}
+
+func (s *alertingService) checkTxUnconfirmed(ctx context.Context, rule Rule) (*Alert, error) {
+	s.headMu.RLock()
+	lastHead := s.lastHead
+	s.headMu.RUnlock()
+	if lastHead == nil {
+		return nil, nil
+	}
+
+	oldest, err := s.orm.OldestUnconfirmedBroadcastBlock(ctx)
+	if err != nil || oldest == nil {
+		return nil, err
+	}
+	blocks := lastHead.Number - *oldest
+	if blocks < rule.Threshold.IntPart() {
+		return nil, nil
+	}
+	return &Alert{
+		RuleName: rule.Name,
+		RuleType: rule.Type,
+		Summary:  fmt.Sprintf("Transaction unconfirmed for %d blocks", blocks),
+		Details:  fmt.Sprintf("Oldest unconfirmed transaction was broadcast before block %d; current head is %d", *oldest, lastHead.Number),
+	}, nil
+}
+
+func (s *alertingService) checkJobErrors(ctx context.Context, rule Rule) (*Alert, error) {
+	var jobID *int32
+	if rule.JobID.Valid {
+		jobID = &rule.JobID.Int32
+	}
+	count, err := s.orm.CountErroredRuns(ctx, time.Now().Add(-rule.Window.Duration()), jobID)
+	if err != nil {
+		return nil, err
+	}
+	if count < rule.Threshold.IntPart() {
+		return nil, nil
+	}
+	scope := "all jobs"
+	if jobID != nil {
+		scope = fmt.Sprintf("job %d", *jobID)
+	}
+	return &Alert{
+		RuleName: rule.Name,
+		RuleType: rule.Type,
+		Summary:  fmt.Sprintf("%d errored runs in the last %s", count, rule.Window.Duration()),
+		Details:  fmt.Sprintf("Errored pipeline runs for %s exceeded the threshold of %s", scope, rule.Threshold.String()),
+	}, nil
+}
+
+// notify delivers the alert through each notifier, and returns how many of
+// them delivered it along with the errors of the others
+func (s *alertingService) notify(ctx context.Context, notifierIDs []int64, alert Alert) (delivered int, merr error) {
+	notifiers, err := s.orm.FindNotifiers(ctx, notifierIDs)
+	if err != nil {
+		return 0, err
+	}
+	for _, n := range notifiers {
+		if err := s.deliver(ctx, n, alert); err != nil {
+			merr = multierr.Append(merr, errors.Wrapf(err, "notifier %s", n.Name))
+			continue
+		}
+		delivered++
+	}
+	return delivered, merr
+}
+
+func (s *alertingService) deliver(ctx context.Context, n Notifier, alert Alert) error {
+	backend, err := newNotifierBackend(n)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, notifyTimeout)
+	defer cancel()
+	if err := backend.Notify(ctx, alert); err != nil {
+		promNotifyErrors.WithLabelValues(string(n.Type)).Inc()
+		return err
+	}
+	return nil
+}
+
+// TestNotifier sends a synthetic alert through the given notifier so that
+// operators can verify delivery end to end.
+func (s *alertingService) TestNotifier(ctx context.Context, id int64) error {
+	n, err := s.orm.FindNotifier(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.deliver(ctx, *n, Alert{
+		RuleName: "test",
+		RuleType: "test",
+		Summary:  fmt.Sprintf("Test notification from notifier %s", n.Name),
+		Details:  "This is a test alert fired manually; no action is required.",
+		FiredAt:  time.Now(),
+	})
+}
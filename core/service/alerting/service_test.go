@@ -0,0 +1,129 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"PhoenixOracle/db/models"
+	"PhoenixOracle/lib/logger"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/guregu/null.v4"
+	"gorm.io/datatypes"
+)
+
+type testConfig struct{}
+
+func (testConfig) AlertingCheckInterval() time.Duration { return time.Hour }
+func (testConfig) PhbContractAddress() string           { return "" }
+
+// fakeORM serves a single rule and its notifiers, and records when the rule
+// is marked fired. The methods evaluateRules does not use are left to the
+// nil ORM and panic if called.
+type fakeORM struct {
+	ORM
+	rule      Rule
+	notifiers []Notifier
+	firedAt   []time.Time
+}
+
+func (o *fakeORM) ListEnabledRules(context.Context) ([]Rule, error) {
+	return []Rule{o.rule}, nil
+}
+
+func (o *fakeORM) FindNotifiers(context.Context, []int64) ([]Notifier, error) {
+	return o.notifiers, nil
+}
+
+func (o *fakeORM) MarkRuleFired(_ context.Context, _ int64, at time.Time) error {
+	o.firedAt = append(o.firedAt, at)
+	o.rule.LastFiredAt = null.TimeFrom(at)
+	return nil
+}
+
+// newTestNotifier returns a webhook notifier whose endpoint answers with
+// status, and a pointer to the number of alerts it received
+func newTestNotifier(t *testing.T, id int64, status int) (Notifier, *int32) {
+	t.Helper()
+	received := new(int32)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(received, 1)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return Notifier{
+		ID:     id,
+		Name:   fmt.Sprintf("notifier %d", id),
+		Type:   NotifierTypeWebhook,
+		Config: datatypes.JSON(fmt.Sprintf(`{"url": %q}`, srv.URL)),
+	}, received
+}
+
+func TestAlertingService_EvaluateRules(t *testing.T) {
+	tests := []struct {
+		name       string
+		lastFired  time.Duration
+		statuses   []int
+		received   int32
+		fired      bool
+		firedAgain bool
+	}{
+		{"fires", 0, []int{http.StatusOK}, 1, true, false},
+		{"in cooldown", time.Minute, []int{http.StatusOK}, 0, false, false},
+		{"cooldown expired", 2 * time.Hour, []int{http.StatusOK}, 1, true, false},
+		{"every notifier fails", 0, []int{http.StatusInternalServerError}, 1, false, true},
+		{"one notifier fails", 0, []int{http.StatusInternalServerError, http.StatusOK}, 2, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A head_stalled rule with a zero threshold fires whenever it
+			// is evaluated
+			orm := &fakeORM{rule: Rule{
+				ID:       1,
+				Name:     "stalled",
+				Type:     RuleTypeHeadStalled,
+				Cooldown: models.Interval(time.Hour),
+			}}
+			if tt.lastFired > 0 {
+				orm.rule.LastFiredAt = null.TimeFrom(time.Now().Add(-tt.lastFired))
+			}
+			var received []*int32
+			for i, status := range tt.statuses {
+				n, r := newTestNotifier(t, int64(i+1), status)
+				orm.notifiers = append(orm.notifiers, n)
+				received = append(received, r)
+			}
+			s := NewService(orm, nil, nil, nil, testConfig{}, logger.CreateTestLogger(zapcore.FatalLevel))
+
+			s.evaluateRules()
+
+			total := countReceived(received)
+			if total != tt.received {
+				t.Errorf("expected %d notifications, got %d", tt.received, total)
+			}
+			if fired := len(orm.firedAt) == 1; fired != tt.fired {
+				t.Errorf("expected rule fired %t, got %d marks", tt.fired, len(orm.firedAt))
+			}
+
+			// A rule marked fired is in cooldown on the next tick, while one
+			// that notified no one is retried
+			s.evaluateRules()
+
+			total = countReceived(received)
+			if retried := total > tt.received; retried != tt.firedAgain {
+				t.Errorf("expected retry %t, got %d notifications after the first %d", tt.firedAgain, total-tt.received, tt.received)
+			}
+		})
+	}
+}
+
+func countReceived(received []*int32) (total int32) {
+	for _, r := range received {
+		total += atomic.LoadInt32(r)
+	}
+	return total
+}
@@ -0,0 +1,83 @@
+package ha
+
+import (
+	"context"
+
+	"PhoenixOracle/core/service/ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+)
+
+// sendMethods are the RPC methods that broadcast transactions
+var sendMethods = map[string]struct{}{
+	"eth_sendRawTransaction": {},
+	"eth_sendTransaction":    {},
+}
+
+// fencedClient is an ethereum.Client that only broadcasts transactions while
+// the node holds the leader lease, so that a standby or a leader that lost its
+// lease never broadcasts from the same keys as the new leader. Everything else
+// goes through, which keeps a standby connected to its chains.
+type fencedClient struct {
+	ethereum.Client
+	lease Lease
+}
+
+var _ ethereum.Client = (*fencedClient)(nil)
+
+func NewFencedClient(client ethereum.Client, lease Lease) ethereum.Client {
+	return &fencedClient{client, lease}
+}
+
+func (c *fencedClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := c.lease.CheckFence(ctx); err != nil {
+		return errors.Wrapf(err, "refusing to send transaction %s", tx.Hash().Hex())
+	}
+	return c.Client.SendTransaction(ctx, tx)
+}
+
+func (c *fencedClient) Call(result interface{}, method string, args ...interface{}) error {
+	if err := c.checkMethods(context.Background(), method); err != nil {
+		return err
+	}
+	return c.Client.Call(result, method, args...)
+}
+
+func (c *fencedClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if err := c.checkMethods(ctx, method); err != nil {
+		return err
+	}
+	return c.Client.CallContext(ctx, result, method, args...)
+}
+
+func (c *fencedClient) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	if err := c.checkBatch(ctx, b); err != nil {
+		return err
+	}
+	return c.Client.BatchCallContext(ctx, b)
+}
+
+func (c *fencedClient) RoundRobinBatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	if err := c.checkBatch(ctx, b); err != nil {
+		return err
+	}
+	return c.Client.RoundRobinBatchCallContext(ctx, b)
+}
+
+func (c *fencedClient) checkBatch(ctx context.Context, b []rpc.BatchElem) error {
+	methods := make([]string, len(b))
+	for i := range b {
+		methods[i] = b[i].Method
+	}
+	return c.checkMethods(ctx, methods...)
+}
+
+func (c *fencedClient) checkMethods(ctx context.Context, methods ...string) error {
+	for _, method := range methods {
+		if _, sends := sendMethods[method]; sends {
+			return errors.Wrapf(c.lease.CheckFence(ctx), "refusing to call %s", method)
+		}
+	}
+	return nil
+}
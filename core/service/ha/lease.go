@@ -0,0 +1,283 @@
+package ha
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"PhoenixOracle/lib/logger"
+	"PhoenixOracle/lib/postgres"
+	"PhoenixOracle/util"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+)
+
+// LeaderLeaseName is the name of the lease held by the active node
+const LeaderLeaseName = "leader"
+
+var (
+	// ErrNotLeader is returned by Lease#CheckFence when the node does not hold
+	// the leader lease
+	ErrNotLeader = errors.New("HA: this node does not hold the leader lease")
+
+	promLeader = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ha_leader",
+		Help: "1 if this node holds the leader lease, 0 if it is a standby",
+	})
+	promFencingToken = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ha_fencing_token",
+		Help: "Fencing token of the leader lease held by this node",
+	})
+)
+
+type (
+	// Lease elects one leader among the nodes sharing a database. The lease
+	// lasts for the lease duration and is renewed by its holder every refresh
+	// interval; other nodes take it over once it has lapsed.
+	Lease interface {
+		Start() error
+		Close() error
+		Ready() error
+		Healthy() error
+
+		// Acquired is closed once this node holds the lease
+		Acquired() <-chan struct{}
+		// Lost is closed if this node loses the lease after acquiring it
+		Lost() <-chan struct{}
+		// Status returns whether this node holds the lease, and with which
+		// fencing token
+		Status() Status
+		// CheckFence returns ErrNotLeader unless this node holds the lease in
+		// the database, with its fencing token, for at least half the refresh
+		// interval to come. Anything with external effects that another node
+		// must not duplicate checks it first.
+		CheckFence(ctx context.Context) error
+	}
+
+	Config interface {
+		HALeaseDuration() time.Duration
+		HALeaseRefreshInterval() time.Duration
+	}
+
+	Status struct {
+		Leader       bool
+		HolderID     uuid.UUID
+		FencingToken int64
+	}
+
+	lease struct {
+		db       *gorm.DB
+		config   Config
+		logger   *logger.Logger
+		holderID uuid.UUID
+
+		statusMu sync.RWMutex
+		status   Status
+		// validUntil is when the lease lapses by the local clock, if it is not
+		// renewed
+		validUntil time.Time
+
+		chAcquired chan struct{}
+		chLost     chan struct{}
+		chStop     chan struct{}
+		wgDone     sync.WaitGroup
+		utils.StartStopOnce
+	}
+)
+
+var _ Lease = (*lease)(nil)
+
+func NewLease(db *gorm.DB, config Config, holderID uuid.UUID, lggr *logger.Logger) Lease {
+	return &lease{
+		db:         db,
+		config:     config,
+		logger:     lggr.Named("HA").With("holderID", holderID),
+		holderID:   holderID,
+		status:     Status{HolderID: holderID},
+		chAcquired: make(chan struct{}),
+		chLost:     make(chan struct{}),
+		chStop:     make(chan struct{}),
+	}
+}
+
+func (l *lease) Start() error {
+	return l.StartOnce("HALease", func() error {
+		l.logger.Infow("HA: starting as standby, waiting for the leader lease",
+			"leaseDuration", l.config.HALeaseDuration(), "refreshInterval", l.config.HALeaseRefreshInterval())
+		l.wgDone.Add(1)
+		go l.run()
+		return nil
+	})
+}
+
+// Close releases the lease if this node holds it, so that the standby can
+// take over without waiting for it to lapse
+func (l *lease) Close() error {
+	return l.StopOnce("HALease", func() error {
+		close(l.chStop)
+		l.wgDone.Wait()
+
+		if !l.Status().Leader {
+			return nil
+		}
+		ctx, cancel := postgres.DefaultQueryCtx()
+		defer cancel()
+		err := l.db.WithContext(ctx).Exec(`
+UPDATE node_leases SET expires_at = now(), updated_at = now()
+WHERE name = ? AND holder_id = ?`, LeaderLeaseName, l.holderID).Error
+		l.setLeader(false, 0)
+		return errors.Wrap(err, "HA: could not release the leader lease")
+	})
+}
+
+func (l *lease) Acquired() <-chan struct{} {
+	return l.chAcquired
+}
+
+func (l *lease) Lost() <-chan struct{} {
+	return l.chLost
+}
+
+func (l *lease) Status() Status {
+	l.statusMu.RLock()
+	defer l.statusMu.RUnlock()
+	return l.status
+}
+
+func (l *lease) CheckFence(ctx context.Context) error {
+	status := l.Status()
+	if !status.Leader {
+		return ErrNotLeader
+	}
+	var valid bool
+	err := l.db.WithContext(ctx).Raw(`
+SELECT EXISTS (
+	SELECT 1 FROM node_leases
+	WHERE name = ? AND holder_id = ? AND fencing_token = ?
+	AND expires_at > now() + ? * interval '1 millisecond'
+)`, LeaderLeaseName, l.holderID, status.FencingToken, (l.config.HALeaseRefreshInterval() / 2).Milliseconds()).Scan(&valid).Error
+	if err != nil {
+		return errors.Wrap(err, "HA: could not check the leader lease")
+	}
+	if !valid {
+		return ErrNotLeader
+	}
+	return nil
+}
+
+func (l *lease) run() {
+	defer l.wgDone.Done()
+
+	ticker := time.NewTicker(l.config.HALeaseRefreshInterval())
+	defer ticker.Stop()
+	for {
+		l.refresh()
+
+		select {
+		case <-l.chStop:
+			return
+		case <-l.chLost:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh acquires or renews the lease
+func (l *lease) refresh() {
+	wasLeader := l.Status().Leader
+
+	ctx, cancel := utils.ContextFromChan(l.chStop)
+	defer cancel()
+	ctx, cancelQuery := context.WithTimeout(ctx, l.config.HALeaseRefreshInterval())
+	defer cancelQuery()
+
+	requestedAt := time.Now()
+	token, err := l.tryAcquire(ctx)
+	switch {
+	case err != nil:
+		l.logger.Errorw("HA: could not refresh the leader lease", "error", err)
+		if wasLeader && time.Now().After(l.lastValidUntil()) {
+			l.lose("the leader lease lapsed without being renewed")
+		}
+	case token == 0:
+		if wasLeader {
+			l.lose("the leader lease was taken over by another node")
+		}
+	default:
+		l.statusMu.Lock()
+		l.validUntil = requestedAt.Add(l.config.HALeaseDuration())
+		l.statusMu.Unlock()
+		if !wasLeader {
+			l.logger.Infow("HA: acquired the leader lease", "fencingToken", token)
+			l.setLeader(true, token)
+			close(l.chAcquired)
+		}
+	}
+}
+
+// tryAcquire takes the lease if it is free or has lapsed, or renews it if
+// this node holds it, and returns the fencing token. It returns 0 if another
+// node holds the lease.
+func (l *lease) tryAcquire(ctx context.Context) (int64, error) {
+	var token sql.NullInt64
+	err := l.db.WithContext(ctx).Raw(`
+INSERT INTO node_leases (name, holder_id, fencing_token, expires_at, updated_at)
+VALUES (?, ?, 1, now() + ? * interval '1 millisecond', now())
+ON CONFLICT (name) DO UPDATE SET
+	fencing_token = CASE WHEN node_leases.holder_id = EXCLUDED.holder_id
+		THEN node_leases.fencing_token ELSE node_leases.fencing_token + 1 END,
+	holder_id = EXCLUDED.holder_id,
+	expires_at = EXCLUDED.expires_at,
+	updated_at = now()
+WHERE node_leases.holder_id = EXCLUDED.holder_id OR node_leases.expires_at < now()
+RETURNING fencing_token`, LeaderLeaseName, l.holderID, l.config.HALeaseDuration().Milliseconds()).Scan(&token).Error
+	if err != nil {
+		return 0, err
+	}
+	return token.Int64, nil
+}
+
+func (l *lease) lastValidUntil() time.Time {
+	l.statusMu.RLock()
+	defer l.statusMu.RUnlock()
+	return l.validUntil
+}
+
+func (l *lease) lose(reason string) {
+	l.logger.Errorw(fmt.Sprintf("HA: lost the leader lease: %s", reason), "fencingToken", l.Status().FencingToken)
+	l.setLeader(false, 0)
+	close(l.chLost)
+}
+
+func (l *lease) setLeader(leader bool, token int64) {
+	l.statusMu.Lock()
+	l.status.Leader = leader
+	l.status.FencingToken = token
+	l.statusMu.Unlock()
+
+	if leader {
+		promLeader.Set(1)
+	} else {
+		promLeader.Set(0)
+	}
+	promFencingToken.Set(float64(token))
+}
+
+// Ready reports a standby as not ready, so that load balancers route API
+// traffic to the leader
+func (l *lease) Ready() error {
+	if !l.Status().Leader {
+		return errors.New("HA: standby, not holding the leader lease")
+	}
+	return nil
+}
+
+func (l *lease) Healthy() error {
+	return nil
+}
@@ -0,0 +1,133 @@
+package ha
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"PhoenixOracle/lib/logger"
+	uuid "github.com/satori/go.uuid"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type testConfig struct {
+	leaseDuration   time.Duration
+	refreshInterval time.Duration
+}
+
+func (c testConfig) HALeaseDuration() time.Duration        { return c.leaseDuration }
+func (c testConfig) HALeaseRefreshInterval() time.Duration { return c.refreshInterval }
+
+// newTestDB connects to DATABASE_URL and shadows node_leases with an empty
+// temporary table, so that tests neither see nor touch real leases. The pool
+// is limited to one connection, the one the temporary table lives on.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	url := os.Getenv("DATABASE_URL")
+	if url == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	err = db.Exec(`
+CREATE TEMPORARY TABLE node_leases (
+	name text PRIMARY KEY,
+	holder_id uuid NOT NULL,
+	fencing_token bigint NOT NULL,
+	expires_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL
+)`).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func newTestLease(db *gorm.DB, cfg testConfig) *lease {
+	return NewLease(db, cfg, uuid.NewV4(), logger.CreateTestLogger(zapcore.ErrorLevel)).(*lease)
+}
+
+func mustAcquire(t *testing.T, l *lease) int64 {
+	t.Helper()
+	token, err := l.tryAcquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestLease_TryAcquire(t *testing.T) {
+	db := newTestDB(t)
+	cfg := testConfig{leaseDuration: 500 * time.Millisecond, refreshInterval: 100 * time.Millisecond}
+	a, b := newTestLease(db, cfg), newTestLease(db, cfg)
+
+	if token := mustAcquire(t, a); token != 1 {
+		t.Fatalf("expected the first holder to get token 1, got %d", token)
+	}
+	if token := mustAcquire(t, a); token != 1 {
+		t.Errorf("expected the holder to keep token 1 on renewal, got %d", token)
+	}
+	if token := mustAcquire(t, b); token != 0 {
+		t.Errorf("expected no takeover before expiry, got token %d", token)
+	}
+
+	time.Sleep(cfg.leaseDuration + 100*time.Millisecond)
+
+	if token := mustAcquire(t, b); token != 2 {
+		t.Errorf("expected the token to increase to 2 on takeover, got %d", token)
+	}
+	if token := mustAcquire(t, a); token != 0 {
+		t.Errorf("expected the previous holder not to renew, got token %d", token)
+	}
+	if token := mustAcquire(t, b); token != 2 {
+		t.Errorf("expected the new holder to keep token 2 on renewal, got %d", token)
+	}
+}
+
+func TestLease_CheckFence(t *testing.T) {
+	db := newTestDB(t)
+	cfg := testConfig{leaseDuration: 500 * time.Millisecond, refreshInterval: 100 * time.Millisecond}
+	a, b := newTestLease(db, cfg), newTestLease(db, cfg)
+	ctx := context.Background()
+
+	if err := a.CheckFence(ctx); err != ErrNotLeader {
+		t.Errorf("expected ErrNotLeader before acquiring, got %v", err)
+	}
+
+	a.refresh()
+	if !a.Status().Leader {
+		t.Fatal("expected a to hold the lease")
+	}
+	if err := a.CheckFence(ctx); err != nil {
+		t.Errorf("expected the holder to pass the fence, got %v", err)
+	}
+
+	time.Sleep(cfg.leaseDuration + 100*time.Millisecond)
+	b.refresh()
+	if !b.Status().Leader || b.Status().FencingToken <= a.Status().FencingToken {
+		t.Fatalf("expected b to take over with a higher token, got %+v after %+v", b.Status(), a.Status())
+	}
+
+	// a has not refreshed yet, so it still believes it is the leader
+	if !a.Status().Leader {
+		t.Fatal("expected a to still believe it holds the lease")
+	}
+	if err := a.CheckFence(ctx); err != ErrNotLeader {
+		t.Errorf("expected a stale token to be rejected, got %v", err)
+	}
+	if err := b.CheckFence(ctx); err != nil {
+		t.Errorf("expected the new holder to pass the fence, got %v", err)
+	}
+}
@@ -1,6 +1,8 @@
 package job
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -30,6 +32,8 @@ const (
 	Keeper            Type = "keeper"
 	VRF               Type = "vrf"
 	Webhook           Type = "webhook"
+	EventTrigger      Type = "eventtrigger"
+	BlockTrigger      Type = "blocktrigger"
 )
 
 type Type string
@@ -55,6 +59,8 @@ var (
 		Keeper:            false,
 		VRF:               true,
 		Webhook:           true,
+		EventTrigger:      true,
+		BlockTrigger:      true,
 	}
 	supportsAsync = map[Type]bool{
 		Cron:              true,
@@ -64,6 +70,8 @@ var (
 		Keeper:            false,
 		VRF:               true,
 		Webhook:           true,
+		EventTrigger:      true,
+		BlockTrigger:      true,
 	}
 )
 
@@ -84,6 +92,10 @@ type Job struct {
 	VRFSpec                       *VRFSpec
 	WebhookSpecID                 *int32
 	WebhookSpec                   *WebhookSpec
+	EventTriggerSpecID            *int32
+	EventTriggerSpec              *EventTriggerSpec
+	BlockTriggerSpecID            *int32
+	BlockTriggerSpec              *BlockTriggerSpec
 	PipelineSpecID                int32
 	PipelineSpec                  *pipeline.Spec
 	JobSpecErrors                 []SpecError `gorm:"foreignKey:JobID"`
@@ -158,14 +170,22 @@ type OffchainReportingOracleSpec struct {
 	ContractAddress                        ethkey.EIP55Address  `toml:"contractAddress"`
 	P2PPeerID                              *p2pkey.PeerID       `toml:"p2pPeerID" gorm:"column:p2p_peer_id;default:null"`
 	P2PBootstrapPeers                      pq.StringArray       `toml:"p2pBootstrapPeers" gorm:"column:p2p_bootstrap_peers;type:text[]"`
+	P2PBootstrapPeerList                   *string              `toml:"p2pBootstrapPeerList" gorm:"column:p2p_bootstrap_peer_list;default:null"`
+	P2PBootstrapDNSName                    *string              `toml:"p2pBootstrapDNSName" gorm:"column:p2p_bootstrap_dns_name;default:null"`
 	IsBootstrapPeer                        bool                 `toml:"isBootstrapPeer"`
 	EncryptedOCRKeyBundleID                *models.Sha256Hash   `toml:"keyBundleID" gorm:"type:bytea"`
 	TransmitterAddress                     *ethkey.EIP55Address `toml:"transmitterAddress"`
 	ObservationTimeout                     models.Interval      `toml:"observationTimeout" gorm:"type:bigint;default:null"`
+	ObservationFallbackStaleness           models.Interval      `toml:"observationFallbackStaleness" gorm:"type:bigint;default:null"`
 	BlockchainTimeout                      models.Interval      `toml:"blockchainTimeout" gorm:"type:bigint;default:null"`
 	ContractConfigTrackerSubscribeInterval models.Interval      `toml:"contractConfigTrackerSubscribeInterval" gorm:"default:null"`
 	ContractConfigTrackerPollInterval      models.Interval      `toml:"contractConfigTrackerPollInterval" gorm:"type:bigint;default:null"`
 	ContractConfigConfirmations            uint16               `toml:"contractConfigConfirmations"`
+	TransmissionMaintenanceWindows         pq.StringArray       `toml:"transmissionMaintenanceWindows" gorm:"type:text[]"`
+	MaxTransmissionsPerHour                uint32               `toml:"maxTransmissionsPerHour" gorm:"default:null"`
+	GasSpikeGasPriceWei                    *utils.Big           `toml:"gasSpikeGasPriceWei" gorm:"type:numeric(78,0);default:null"`
+	GasSpikeAlphaPPB                       uint64               `toml:"gasSpikeAlphaPPB" gorm:"column:gas_spike_alpha_ppb;type:numeric(20,0);default:null"`
+	GasSpikeDeltaC                         models.Interval      `toml:"gasSpikeDeltaC" gorm:"type:bigint;default:null"`
 	CreatedAt                              time.Time            `toml:"-"`
 	UpdatedAt                              time.Time            `toml:"-"`
 }
@@ -209,8 +229,11 @@ type ExternalInitiatorWebhookSpec struct {
 type WebhookSpec struct {
 	ID                            int32 `toml:"-" gorm:"primary_key"`
 	ExternalInitiatorWebhookSpecs []ExternalInitiatorWebhookSpec
-	CreatedAt                     time.Time `json:"createdAt" toml:"-"`
-	UpdatedAt                     time.Time `json:"updatedAt" toml:"-"`
+	// SigningSecret allows callers holding it to run the job through the
+	// public signed webhook route
+	SigningSecret null.String `json:"-" toml:"-"`
+	CreatedAt     time.Time   `json:"createdAt" toml:"-"`
+	UpdatedAt     time.Time   `json:"updatedAt" toml:"-"`
 }
 
 func (w WebhookSpec) GetID() string {
@@ -244,11 +267,83 @@ func (DirectRequestSpec) TableName() string {
 	return "direct_request_specs"
 }
 
+// EventTopicFilters maps the names of indexed event arguments to the values
+// they are allowed to take. A log matches if, for every filtered argument, it
+// has one of the listed values.
+type EventTopicFilters map[string][]string
+
+func (f EventTopicFilters) Value() (driver.Value, error) {
+	if len(f) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(f)
+}
+
+func (f *EventTopicFilters) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*f = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), f)
+	case []byte:
+		return json.Unmarshal(v, f)
+	default:
+		return fmt.Errorf("unable to convert %v of %T to EventTopicFilters", value, value)
+	}
+}
+
+type EventTriggerSpec struct {
+	ID                       int32               `toml:"-" gorm:"primary_key"`
+	ContractAddress          ethkey.EIP55Address `toml:"contractAddress"`
+	Event                    string              `toml:"event"`
+	TopicFilters             EventTopicFilters   `toml:"topicFilters" gorm:"type:jsonb"`
+	MinIncomingConfirmations clnull.Uint32       `toml:"minIncomingConfirmations"`
+	CreatedAt                time.Time           `toml:"-"`
+	UpdatedAt                time.Time           `toml:"-"`
+}
+
+func (EventTriggerSpec) TableName() string {
+	return "event_trigger_specs"
+}
+
+// BlockTriggerSpec runs a job at blocks StartOffset + k*BlockInterval, once
+// they have Confirmations blocks on top of them
+type BlockTriggerSpec struct {
+	ID            int32     `toml:"-" gorm:"primary_key"`
+	BlockInterval uint32    `toml:"blockInterval"`
+	StartOffset   uint64    `toml:"startOffset"`
+	Confirmations uint32    `toml:"confirmations"`
+	CreatedAt     time.Time `toml:"-"`
+	UpdatedAt     time.Time `toml:"-"`
+}
+
+func (BlockTriggerSpec) TableName() string {
+	return "block_trigger_specs"
+}
+
+// CronMisfirePolicy decides what a cron job does about the executions it
+// missed while the node was down or the job was being restarted
+type CronMisfirePolicy string
+
+const (
+	// CronMisfireSkip drops missed executions
+	CronMisfireSkip CronMisfirePolicy = "skip"
+	// CronMisfireRunOnce runs once for all missed executions
+	CronMisfireRunOnce CronMisfirePolicy = "runOnce"
+	// CronMisfireCatchUp runs once for every missed execution
+	CronMisfireCatchUp CronMisfirePolicy = "catchUp"
+)
+
 type CronSpec struct {
-	ID           int32     `toml:"-" gorm:"primary_key"`
-	CronSchedule string    `toml:"schedule"`
-	CreatedAt    time.Time `toml:"-"`
-	UpdatedAt    time.Time `toml:"-"`
+	ID              int32             `toml:"-" gorm:"primary_key"`
+	CronSchedule    string            `toml:"schedule"`
+	MisfirePolicy   CronMisfirePolicy `toml:"misfirePolicy" gorm:"default:skip"`
+	Jitter          models.Interval   `toml:"jitter" gorm:"type:bigint;default:0"`
+	LastExecutionAt *time.Time        `toml:"-"`
+	NextExecutionAt *time.Time        `toml:"-"`
+	CreatedAt       time.Time         `toml:"-"`
+	UpdatedAt       time.Time         `toml:"-"`
 }
 
 func (s CronSpec) GetID() string {
@@ -331,6 +426,16 @@ type VRFSpec struct {
 	CoordinatorAddress ethkey.EIP55Address `toml:"coordinatorAddress"`
 	PublicKey          secp256k1.PublicKey `toml:"publicKey"`
 	Confirmations      uint32              `toml:"confirmations"`
-	CreatedAt          time.Time           `toml:"-"`
-	UpdatedAt          time.Time           `toml:"-"`
+
+	// Batch fulfillment only applies to VRF v2 jobs. Confirmed requests are
+	// accumulated for up to BatchFulfillmentSize requests or
+	// BatchFulfillmentTimeout, whichever comes first, and fulfilled in one
+	// transaction through the batch coordinator.
+	BatchFulfillmentEnabled bool                 `toml:"batchFulfillmentEnabled"`
+	BatchCoordinatorAddress *ethkey.EIP55Address `toml:"batchCoordinatorAddress"`
+	BatchFulfillmentSize    uint32               `toml:"batchFulfillmentSize"`
+	BatchFulfillmentTimeout models.Interval      `toml:"batchFulfillmentTimeout" gorm:"type:bigint;default:0"`
+
+	CreatedAt time.Time `toml:"-"`
+	UpdatedAt time.Time `toml:"-"`
 }
@@ -97,7 +97,9 @@ func PreloadAllJobTypes(db *gorm.DB) *gorm.DB {
 		Preload("PipelineSpec").
 		Preload("CronSpec").
 		Preload("WebhookSpec").
-		Preload("VRFSpec")
+		Preload("VRFSpec").
+		Preload("EventTriggerSpec").
+		Preload("BlockTriggerSpec")
 }
 
 func (o *orm) Close() error {
@@ -259,6 +261,18 @@ func (o *orm) CreateJob(ctx context.Context, jobSpec *Job, p pipeline.Pipeline)
 				return jb, errors.Wrapf(err, "failed to create ExternalInitiatorWebhookSpec for WebhookSpec: %#v", eiWS)
 			}
 		}
+	case EventTrigger:
+		err := tx.Create(&jobSpec.EventTriggerSpec).Error
+		if err != nil {
+			return jb, errors.Wrap(err, "failed to create EventTriggerSpec for jobSpec")
+		}
+		jobSpec.EventTriggerSpecID = &jobSpec.EventTriggerSpec.ID
+	case BlockTrigger:
+		err := tx.Create(&jobSpec.BlockTriggerSpec).Error
+		if err != nil {
+			return jb, errors.Wrap(err, "failed to create BlockTriggerSpec for jobSpec")
+		}
+		jobSpec.BlockTriggerSpecID = &jobSpec.BlockTriggerSpec.ID
 	default:
 		logger.Fatalf("Unsupported jobSpec.Type: %v", jobSpec.Type)
 	}
@@ -290,7 +304,9 @@ func (o *orm) DeleteJob(ctx context.Context, id int32) error {
 				flux_monitor_spec_id,
 				vrf_spec_id,
 				webhook_spec_id,
-				direct_request_spec_id
+				direct_request_spec_id,
+				event_trigger_spec_id,
+				block_trigger_spec_id
 		),
 		deleted_oracle_specs AS (
 			DELETE FROM offchainreporting_oracle_specs WHERE id IN (SELECT offchainreporting_oracle_spec_id FROM deleted_jobs)
@@ -312,6 +328,12 @@ func (o *orm) DeleteJob(ctx context.Context, id int32) error {
 		),
 		deleted_dr_specs AS (
 			DELETE FROM direct_request_specs WHERE id IN (SELECT direct_request_spec_id FROM deleted_jobs)
+		),
+		deleted_event_trigger_specs AS (
+			DELETE FROM event_trigger_specs WHERE id IN (SELECT event_trigger_spec_id FROM deleted_jobs)
+		),
+		deleted_block_trigger_specs AS (
+			DELETE FROM block_trigger_specs WHERE id IN (SELECT block_trigger_spec_id FROM deleted_jobs)
 		)
 		DELETE FROM pipeline_specs WHERE id IN (SELECT pipeline_spec_id FROM deleted_jobs)
 	`, id).Error
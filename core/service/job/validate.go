@@ -17,6 +17,8 @@ var (
 		Keeper:            {},
 		VRF:               {},
 		Webhook:           {},
+		EventTrigger:      {},
+		BlockTrigger:      {},
 	}
 )
 
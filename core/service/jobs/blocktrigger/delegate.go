@@ -0,0 +1,274 @@
+package blocktrigger
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"PhoenixOracle/core/service/job"
+	"PhoenixOracle/core/service/pipeline"
+	"PhoenixOracle/db/models"
+	httypes "PhoenixOracle/lib/headtracker/types"
+	"PhoenixOracle/lib/logger"
+	"PhoenixOracle/lib/postgres"
+	"PhoenixOracle/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type Delegate struct {
+	logger          *logger.Logger
+	headBroadcaster httypes.HeadBroadcaster
+	pipelineRunner  pipeline.Runner
+	db              *gorm.DB
+}
+
+var _ job.Delegate = (*Delegate)(nil)
+
+func NewDelegate(
+	logger *logger.Logger,
+	headBroadcaster httypes.HeadBroadcaster,
+	pipelineRunner pipeline.Runner,
+	db *gorm.DB,
+) *Delegate {
+	return &Delegate{
+		logger,
+		headBroadcaster,
+		pipelineRunner,
+		db,
+	}
+}
+
+func (d *Delegate) JobType() job.Type {
+	return job.BlockTrigger
+}
+
+func (Delegate) AfterJobCreated(spec job.Job)  {}
+func (Delegate) BeforeJobDeleted(spec job.Job) {}
+
+// ServicesForSpec returns the head listener service for a blocktrigger job
+func (d *Delegate) ServicesForSpec(jb job.Job) ([]job.Service, error) {
+	if jb.BlockTriggerSpec == nil {
+		return nil, errors.Errorf("BlockTrigger: blocktrigger.Delegate expects a *job.BlockTriggerSpec to be present, got %v", jb)
+	}
+	if jb.BlockTriggerSpec.BlockInterval == 0 {
+		return nil, errors.New("BlockTrigger: blockInterval must be at least 1")
+	}
+	jb.PipelineSpec.JobName = jb.Name.ValueOrZero()
+	jb.PipelineSpec.JobID = jb.ID
+
+	svcLogger := d.logger.
+		Named("BlockTrigger").
+		With(
+			"jobName", jb.Name.ValueOrZero(),
+			"jobID", jb.ID,
+			"externalJobID", jb.ExternalJobID,
+		)
+
+	return []job.Service{&trigger{
+		logger:          svcLogger,
+		headBroadcaster: d.headBroadcaster,
+		pipelineRunner:  d.pipelineRunner,
+		db:              d.db,
+		job:             jb,
+		mbHeads:         utils.NewMailbox(1),
+		chStop:          make(chan struct{}),
+		chDone:          make(chan struct{}),
+	}}, nil
+}
+
+var (
+	_ httypes.HeadTrackable = &trigger{}
+	_ job.Service           = &trigger{}
+)
+
+// errAlreadyTriggered rolls back a run for a block height that has already
+// been run for
+var errAlreadyTriggered = errors.New("block height already triggered")
+
+// trigger runs the pipeline of a job at every block height matching its spec.
+// Heights are run in order, at most once each: a height is recorded in
+// block_trigger_runs, and committed, before its pipeline executes, so a
+// height whose run is interrupted by a crash is not run again. Heights
+// skipped by a jump in the head are caught up as long as they are in the
+// head's chain.
+type trigger struct {
+	logger          *logger.Logger
+	headBroadcaster httypes.HeadBroadcaster
+	pipelineRunner  pipeline.Runner
+	db              *gorm.DB
+	job             job.Job
+	mbHeads         *utils.Mailbox
+
+	// lastTriggered is the last height run for, or -1 if there was none
+	lastTriggered int64
+
+	chStop chan struct{}
+	chDone chan struct{}
+	utils.StartStopOnce
+}
+
+func (t *trigger) Start() error {
+	return t.StartOnce("BlockTrigger", func() error {
+		lastTriggered, err := t.loadLastTriggered()
+		if err != nil {
+			return err
+		}
+		t.lastTriggered = lastTriggered
+
+		latestHead, unsubscribe := t.headBroadcaster.Subscribe(t)
+		if latestHead != nil {
+			t.mbHeads.Deliver(*latestHead)
+		}
+		go func() {
+			defer close(t.chDone)
+			defer unsubscribe()
+			t.run()
+		}()
+		return nil
+	})
+}
+
+func (t *trigger) Close() error {
+	return t.StopOnce("BlockTrigger", func() error {
+		close(t.chStop)
+		<-t.chDone
+		return nil
+	})
+}
+
+// OnNewLongestChain implements httypes.HeadTrackable
+func (t *trigger) OnNewLongestChain(_ context.Context, head models.Head) {
+	t.mbHeads.Deliver(head)
+}
+
+func (t *trigger) loadLastTriggered() (int64, error) {
+	ctx, cancel := postgres.DefaultQueryCtx()
+	defer cancel()
+	var last sql.NullInt64
+	err := t.db.WithContext(ctx).
+		Raw(`SELECT max(block_number) FROM block_trigger_runs WHERE job_id = ?`, t.job.ID).
+		Scan(&last).Error
+	if err != nil {
+		return 0, errors.Wrap(err, "BlockTrigger: could not load last triggered block")
+	}
+	if !last.Valid {
+		return -1, nil
+	}
+	return last.Int64, nil
+}
+
+func (t *trigger) run() {
+	for {
+		select {
+		case <-t.chStop:
+			return
+		case <-t.mbHeads.Notify():
+			item, exists := t.mbHeads.Retrieve()
+			if !exists {
+				continue
+			}
+			head, ok := item.(models.Head)
+			if !ok {
+				panic(errors.Errorf("BlockTrigger: invariant violation, expected models.Head but got %T", item))
+			}
+			t.processHead(head)
+		}
+	}
+}
+
+// nextHeight returns the first height at or above from that the job runs at
+func (t *trigger) nextHeight(from int64) int64 {
+	spec := t.job.BlockTriggerSpec
+	offset := int64(spec.StartOffset)
+	interval := int64(spec.BlockInterval)
+	if from <= offset {
+		return offset
+	}
+	return offset + (from-offset+interval-1)/interval*interval
+}
+
+func (t *trigger) processHead(head models.Head) {
+	spec := t.job.BlockTriggerSpec
+	confirmed := head.Number - int64(spec.Confirmations)
+
+	// Without history, start at the current confirmed height rather than
+	// running for every past height
+	from := t.lastTriggered + 1
+	if t.lastTriggered < 0 {
+		from = confirmed
+	}
+	// After an outage the heights we missed may have fallen out of the head
+	// chain. Skip them in one go rather than warning about each of them.
+	if earliest := head.EarliestInChain().Number; from < earliest {
+		skipTo := earliest - 1
+		if skipTo > confirmed {
+			skipTo = confirmed
+		}
+		if t.nextHeight(from) <= skipTo {
+			t.logger.Warnw(fmt.Sprintf("BlockTrigger: blocks %d to %d are no longer in the head chain, skipping them", from, skipTo),
+				"head", head.Number, "chainLength", head.ChainLength())
+		}
+		t.lastTriggered = skipTo
+		from = skipTo + 1
+	}
+	for height := t.nextHeight(from); height <= confirmed; height += int64(spec.BlockInterval) {
+		select {
+		case <-t.chStop:
+			return
+		default:
+		}
+		hash := head.HashAtHeight(height)
+		if hash == (common.Hash{}) {
+			t.logger.Warnw(fmt.Sprintf("BlockTrigger: block %d is no longer in the head chain, skipping it", height),
+				"head", head.Number, "chainLength", head.ChainLength())
+			t.lastTriggered = height
+			continue
+		}
+		if err := t.runPipeline(height, hash); err != nil {
+			// Retried on the next head
+			t.logger.Errorw(fmt.Sprintf("BlockTrigger: failed executing run for block %d", height), "err", err)
+			return
+		}
+		t.lastTriggered = height
+	}
+}
+
+func (t *trigger) runPipeline(height int64, hash common.Hash) error {
+	ctx, cancel := utils.ContextFromChan(t.chStop)
+	defer cancel()
+
+	vars := pipeline.NewVarsFrom(map[string]interface{}{
+		"jobSpec": map[string]interface{}{
+			"databaseID":    t.job.ID,
+			"externalJobID": t.job.ExternalJobID,
+			"name":          t.job.Name.ValueOrZero(),
+		},
+		"jobRun": map[string]interface{}{
+			"blockNumber": height,
+			"blockHash":   hash,
+		},
+	})
+	run := pipeline.NewRun(*t.job.PipelineSpec, vars)
+	// The runner commits this callback before executing the pipeline, which
+	// makes runs at-most-once per height
+	_, err := t.pipelineRunner.Run(ctx, &run, *t.logger, false, func(tx *gorm.DB) error {
+		result := tx.Exec(`
+INSERT INTO block_trigger_runs (job_id, block_number, block_hash, created_at)
+VALUES (?, ?, ?, ?) ON CONFLICT DO NOTHING`, t.job.ID, height, hash, time.Now())
+		if result.Error != nil {
+			return errors.Wrap(result.Error, "could not record block trigger run")
+		}
+		if result.RowsAffected == 0 {
+			return errAlreadyTriggered
+		}
+		return nil
+	})
+	if errors.Cause(err) == errAlreadyTriggered {
+		t.logger.Debugw("BlockTrigger: block already triggered, skipping", "blockNumber", height)
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package blocktrigger
+
+import (
+	"github.com/pelletier/go-toml"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"PhoenixOracle/core/service/job"
+)
+
+// ValidationConfig is the node config a blocktrigger spec is checked against
+type ValidationConfig interface {
+	EvmFinalityDepth() uint
+}
+
+func ValidatedBlockTriggerSpec(config ValidationConfig, tomlString string) (job.Job, error) {
+	var jb = job.Job{
+		ExternalJobID: uuid.NewV4(), // Default to generating a uuid, can be overwritten by the specified one in tomlString.
+	}
+
+	tree, err := toml.Load(tomlString)
+	if err != nil {
+		return jb, errors.Wrap(err, "toml error on load")
+	}
+
+	err = tree.Unmarshal(&jb)
+	if err != nil {
+		return jb, errors.Wrap(err, "toml unmarshal error on spec")
+	}
+	if jb.Type != job.BlockTrigger {
+		return jb, errors.Errorf("unsupported type %s", jb.Type)
+	}
+
+	var spec job.BlockTriggerSpec
+	err = tree.Unmarshal(&spec)
+	if err != nil {
+		return jb, errors.Wrap(err, "toml unmarshal error on job")
+	}
+	jb.BlockTriggerSpec = &spec
+
+	if spec.BlockInterval == 0 {
+		return jb, errors.New("blockInterval must be at least 1")
+	}
+	// Heads are only kept back to the finality depth, so deeper heights
+	// could never be looked up and the job would never run
+	if spec.Confirmations >= uint32(config.EvmFinalityDepth()) {
+		return jb, errors.Errorf("confirmations must be less than ETH_FINALITY_DEPTH (%d)", config.EvmFinalityDepth())
+	}
+
+	return jb, nil
+}
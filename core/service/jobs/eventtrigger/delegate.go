@@ -0,0 +1,234 @@
+package eventtrigger
+
+import (
+	"sync"
+
+	"PhoenixOracle/core/log"
+	"PhoenixOracle/core/service/job"
+	"PhoenixOracle/core/service/pipeline"
+	"PhoenixOracle/lib/logger"
+	"PhoenixOracle/lib/postgres"
+	"PhoenixOracle/util"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type (
+	Delegate struct {
+		logger         *logger.Logger
+		logBroadcaster log.Broadcaster
+		pipelineRunner pipeline.Runner
+		db             *gorm.DB
+		config         Config
+	}
+
+	Config interface {
+		MinIncomingConfirmations() uint32
+	}
+)
+
+var _ job.Delegate = (*Delegate)(nil)
+
+func NewDelegate(
+	logger *logger.Logger,
+	logBroadcaster log.Broadcaster,
+	pipelineRunner pipeline.Runner,
+	db *gorm.DB,
+	config Config,
+) *Delegate {
+	return &Delegate{
+		logger,
+		logBroadcaster,
+		pipelineRunner,
+		db,
+		config,
+	}
+}
+
+func (d *Delegate) JobType() job.Type {
+	return job.EventTrigger
+}
+
+func (Delegate) AfterJobCreated(spec job.Job)  {}
+func (Delegate) BeforeJobDeleted(spec job.Job) {}
+
+// ServicesForSpec returns the log listener service for an eventtrigger job
+func (d *Delegate) ServicesForSpec(jb job.Job) ([]job.Service, error) {
+	if jb.EventTriggerSpec == nil {
+		return nil, errors.Errorf("EventTrigger: eventtrigger.Delegate expects a *job.EventTriggerSpec to be present, got %v", jb)
+	}
+	spec := jb.EventTriggerSpec
+
+	event, err := ParseEvent(spec.Event)
+	if err != nil {
+		return nil, errors.Wrap(err, "EventTrigger: invalid event")
+	}
+	filters, err := TopicFilters(event, spec.TopicFilters)
+	if err != nil {
+		return nil, errors.Wrap(err, "EventTrigger: invalid topic filters")
+	}
+
+	// The node-wide minimum applies unless the job asks for more
+	// confirmations, as for directrequest jobs
+	minIncomingConfirmations := d.config.MinIncomingConfirmations()
+	if spec.MinIncomingConfirmations.Uint32 > minIncomingConfirmations {
+		minIncomingConfirmations = spec.MinIncomingConfirmations.Uint32
+	}
+
+	svcLogger := d.logger.
+		Named("EventTrigger").
+		With(
+			"contract", spec.ContractAddress.Address().String(),
+			"event", event.Sig,
+			"jobName", jb.Name.ValueOrZero(),
+			"jobID", jb.ID,
+			"externalJobID", jb.ExternalJobID,
+		)
+
+	return []job.Service{&listener{
+		logger:                   svcLogger,
+		logBroadcaster:           d.logBroadcaster,
+		pipelineRunner:           d.pipelineRunner,
+		db:                       d.db,
+		job:                      jb,
+		event:                    event,
+		filters:                  filters,
+		minIncomingConfirmations: uint64(minIncomingConfirmations),
+		mbLogs:                   utils.NewHighCapacityMailbox(),
+		chStop:                   make(chan struct{}),
+	}}, nil
+}
+
+var (
+	_ log.Listener = &listener{}
+	_ job.Service  = &listener{}
+)
+
+type listener struct {
+	logger                   *logger.Logger
+	logBroadcaster           log.Broadcaster
+	pipelineRunner           pipeline.Runner
+	db                       *gorm.DB
+	job                      job.Job
+	event                    abi.Event
+	filters                  [][]log.Topic
+	minIncomingConfirmations uint64
+	mbLogs                   *utils.Mailbox
+	wgDone                   sync.WaitGroup
+	chStop                   chan struct{}
+	utils.StartStopOnce
+}
+
+func (l *listener) Start() error {
+	return l.StartOnce("EventTriggerListener", func() error {
+		unsubscribeLogs := l.logBroadcaster.Register(l, log.ListenerOpts{
+			Contract: l.job.EventTriggerSpec.ContractAddress.Address(),
+			ParseLog: parseLog(l.event),
+			LogsWithTopics: map[common.Hash][][]log.Topic{
+				l.event.ID: l.filters,
+			},
+			NumConfirmations: l.minIncomingConfirmations,
+		})
+		l.wgDone.Add(1)
+		go func() {
+			defer l.wgDone.Done()
+			defer unsubscribeLogs()
+			l.processLogs()
+		}()
+		return nil
+	})
+}
+
+func (l *listener) Close() error {
+	return l.StopOnce("EventTriggerListener", func() error {
+		close(l.chStop)
+		l.wgDone.Wait()
+		return nil
+	})
+}
+
+func (l *listener) HandleLog(lb log.Broadcast) {
+	if wasOverCapacity := l.mbLogs.Deliver(lb); wasOverCapacity {
+		l.logger.Error("EventTrigger: log mailbox is over capacity - dropped the oldest log")
+	}
+}
+
+// JobID complies with log.Listener
+func (l *listener) JobID() int32 {
+	return l.job.ID
+}
+
+func (l *listener) processLogs() {
+	for {
+		select {
+		case <-l.chStop:
+			return
+		case <-l.mbLogs.Notify():
+			for {
+				i, exists := l.mbLogs.Retrieve()
+				if !exists {
+					break
+				}
+				lb, ok := i.(log.Broadcast)
+				if !ok {
+					panic(errors.Errorf("EventTrigger: invariant violation, expected log.Broadcast but got %T", i))
+				}
+				l.handleLog(lb)
+			}
+		}
+	}
+}
+
+func (l *listener) handleLog(lb log.Broadcast) {
+	ctx, cancel := postgres.DefaultQueryCtx()
+	was, err := l.logBroadcaster.WasAlreadyConsumed(l.db.WithContext(ctx), lb)
+	cancel()
+	if err != nil {
+		l.logger.Errorw("EventTrigger: could not determine if log was already consumed", "error", err)
+		return
+	} else if was {
+		return
+	}
+
+	event, ok := lb.DecodedLog().(*decodedEvent)
+	if !ok || event == nil {
+		l.logger.Errorw("EventTrigger: ignoring log that could not be decoded", "log", lb.String())
+		return
+	}
+	rawLog := lb.RawLog()
+
+	vars := pipeline.NewVarsFrom(map[string]interface{}{
+		"jobSpec": map[string]interface{}{
+			"databaseID":    l.job.ID,
+			"externalJobID": l.job.ExternalJobID,
+			"name":          l.job.Name.ValueOrZero(),
+		},
+		"jobRun": map[string]interface{}{
+			"logBlockHash":   rawLog.BlockHash,
+			"logBlockNumber": rawLog.BlockNumber,
+			"logTxHash":      rawLog.TxHash,
+			"logIndex":       rawLog.Index,
+			"logAddress":     rawLog.Address,
+			"logTopics":      rawLog.Topics,
+			"logData":        rawLog.Data,
+			"event":          event.values,
+		},
+	})
+
+	runCtx, cancelRun := utils.ContextFromChan(l.chStop)
+	defer cancelRun()
+	run := pipeline.NewRun(*l.job.PipelineSpec, vars)
+	// The log is marked consumed in a transaction that commits before the
+	// pipeline executes, so a run interrupted by a restart is not retried:
+	// each log triggers at most one run
+	_, err = l.pipelineRunner.Run(runCtx, &run, *l.logger, true, func(tx *gorm.DB) error {
+		return l.logBroadcaster.MarkConsumed(tx, lb)
+	})
+	if runCtx.Err() != nil {
+		return
+	} else if err != nil {
+		l.logger.Errorw("EventTrigger: failed executing run", "err", err, "txHash", rawLog.TxHash, "logIndex", rawLog.Index)
+	}
+}
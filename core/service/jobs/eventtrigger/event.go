@@ -0,0 +1,219 @@
+package eventtrigger
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+
+	"PhoenixOracle/core/log"
+	"PhoenixOracle/core/service/job"
+	"PhoenixOracle/internal/gethwrappers/generated"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+// ParseEvent parses the event of an eventtrigger spec. The event is given
+// either as a JSON ABI fragment of a single event, or as a Solidity-style
+// signature with optional argument names, e.g.
+// "Transfer(address indexed from, address indexed to, uint256 value)".
+// Unnamed arguments are named arg0, arg1, ...
+func ParseEvent(s string) (abi.Event, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[") {
+		return parseEventJSON(s)
+	}
+	return parseEventSignature(s)
+}
+
+func parseEventJSON(s string) (abi.Event, error) {
+	if strings.HasPrefix(s, "{") {
+		s = "[" + s + "]"
+	}
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		return abi.Event{}, errors.Wrap(err, "invalid event ABI")
+	}
+	if len(parsed.Events) != 1 {
+		return abi.Event{}, errors.Errorf("event ABI must contain exactly one event, got %d", len(parsed.Events))
+	}
+	var event abi.Event
+	for _, e := range parsed.Events {
+		event = e
+	}
+	if event.Anonymous {
+		return abi.Event{}, errors.New("anonymous events are not supported")
+	}
+	return event, nil
+}
+
+func parseEventSignature(s string) (abi.Event, error) {
+	open := strings.Index(s, "(")
+	if open <= 0 || !strings.HasSuffix(s, ")") {
+		return abi.Event{}, errors.Errorf("invalid event signature %q, expected e.g. Name(address indexed from, uint256 value)", s)
+	}
+	name := strings.TrimSpace(s[:open])
+	body := strings.TrimSpace(s[open+1 : len(s)-1])
+
+	var args abi.Arguments
+	names := make(map[string]struct{})
+	if body != "" {
+		for i, part := range strings.Split(body, ",") {
+			fields := strings.Fields(part)
+			if len(fields) == 0 {
+				return abi.Event{}, errors.Errorf("invalid event signature %q: empty argument %d", s, i)
+			}
+			typ, err := abi.NewType(fields[0], "", nil)
+			if err != nil {
+				return abi.Event{}, errors.Wrapf(err, "invalid event signature %q: argument %d", s, i)
+			}
+			arg := abi.Argument{Name: "arg" + strconv.Itoa(i), Type: typ}
+			rest := fields[1:]
+			if len(rest) > 0 && rest[0] == "indexed" {
+				arg.Indexed = true
+				rest = rest[1:]
+			}
+			switch len(rest) {
+			case 0:
+			case 1:
+				arg.Name = rest[0]
+			default:
+				return abi.Event{}, errors.Errorf("invalid event signature %q: argument %d", s, i)
+			}
+			if _, exists := names[arg.Name]; exists {
+				return abi.Event{}, errors.Errorf("invalid event signature %q: duplicate argument name %s", s, arg.Name)
+			}
+			names[arg.Name] = struct{}{}
+			args = append(args, arg)
+		}
+	}
+	return abi.NewEvent(name, name, false, args), nil
+}
+
+// TopicFilters converts filters keyed by indexed argument name into the
+// per-topic filters of log.ListenerOpts
+func TopicFilters(event abi.Event, filters job.EventTopicFilters) ([][]log.Topic, error) {
+	indexed := make(map[string]struct{})
+	var result [][]log.Topic
+	var filtered bool
+	for _, arg := range event.Inputs {
+		if !arg.Indexed {
+			continue
+		}
+		indexed[arg.Name] = struct{}{}
+		topics := []log.Topic{}
+		for _, value := range filters[arg.Name] {
+			topic, err := topicValue(arg.Type, value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid filter value %q for argument %s", value, arg.Name)
+			}
+			topics = append(topics, log.Topic(topic))
+		}
+		filtered = filtered || len(topics) > 0
+		result = append(result, topics)
+	}
+	for name := range filters {
+		if _, exists := indexed[name]; !exists {
+			return nil, errors.Errorf("cannot filter on %s, it is not an indexed argument of %s", name, event.Sig)
+		}
+	}
+	if !filtered {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// topicValue encodes a filter value the way the EVM encodes an indexed
+// argument of type typ
+func topicValue(typ abi.Type, value string) (common.Hash, error) {
+	switch typ.T {
+	case abi.AddressTy:
+		if !common.IsHexAddress(value) {
+			return common.Hash{}, errors.New("not an address")
+		}
+		return common.BytesToHash(common.HexToAddress(value).Bytes()), nil
+	case abi.IntTy, abi.UintTy:
+		n, ok := new(big.Int).SetString(value, 0)
+		if !ok {
+			return common.Hash{}, errors.New("not an integer")
+		}
+		if typ.T == abi.UintTy && n.Sign() < 0 {
+			return common.Hash{}, errors.New("negative value for unsigned integer")
+		}
+		return common.BytesToHash(math.U256Bytes(n)), nil
+	case abi.BoolTy:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		if b {
+			return common.BigToHash(big.NewInt(1)), nil
+		}
+		return common.Hash{}, nil
+	case abi.FixedBytesTy:
+		b, err := hexutil.Decode(value)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		if len(b) != typ.Size {
+			return common.Hash{}, errors.Errorf("expected %d bytes, got %d", typ.Size, len(b))
+		}
+		var h common.Hash
+		copy(h[:], b)
+		return h, nil
+	case abi.StringTy:
+		return crypto.Keccak256Hash([]byte(value)), nil
+	case abi.BytesTy:
+		b, err := hexutil.Decode(value)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		return crypto.Keccak256Hash(b), nil
+	default:
+		return common.Hash{}, errors.Errorf("filtering on %s arguments is not supported", typ.String())
+	}
+}
+
+// decodedEvent is the log decoded by parseLog
+type decodedEvent struct {
+	id     common.Hash
+	values map[string]interface{}
+}
+
+var _ generated.AbigenLog = (*decodedEvent)(nil)
+
+func (e *decodedEvent) Topic() common.Hash {
+	return e.id
+}
+
+// parseLog returns a log.ParseLogFunc decoding the arguments of event. Indexed
+// arguments of dynamic types are only available as their hash.
+func parseLog(event abi.Event) log.ParseLogFunc {
+	var indexed abi.Arguments
+	for _, arg := range event.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	nonIndexed := event.Inputs.NonIndexed()
+
+	return func(lg types.Log) (generated.AbigenLog, error) {
+		if len(lg.Topics) != len(indexed)+1 || lg.Topics[0] != event.ID {
+			return nil, errors.Errorf("log does not match event %s", event.Sig)
+		}
+		values := make(map[string]interface{})
+		if len(nonIndexed) > 0 {
+			if err := nonIndexed.UnpackIntoMap(values, lg.Data); err != nil {
+				return nil, errors.Wrapf(err, "could not decode data of event %s", event.Sig)
+			}
+		}
+		if err := abi.ParseTopicsIntoMap(values, indexed, lg.Topics[1:]); err != nil {
+			return nil, errors.Wrapf(err, "could not decode topics of event %s", event.Sig)
+		}
+		return &decodedEvent{id: event.ID, values: values}, nil
+	}
+}
@@ -0,0 +1,233 @@
+package eventtrigger
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"PhoenixOracle/core/log"
+	"PhoenixOracle/core/service/job"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const transferSignature = "Transfer(address indexed from, address indexed to, uint256 value)"
+
+var transferTopic = common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+
+func TestParseEvent(t *testing.T) {
+	type arg struct {
+		name    string
+		typ     string
+		indexed bool
+	}
+	tests := []struct {
+		name  string
+		event string
+		sig   string
+		args  []arg
+	}{
+		{
+			"signature with names",
+			transferSignature,
+			"Transfer(address,address,uint256)",
+			[]arg{{"from", "address", true}, {"to", "address", true}, {"value", "uint256", false}},
+		},
+		{
+			"signature without names",
+			" Ping(uint256, bytes32 indexed) ",
+			"Ping(uint256,bytes32)",
+			[]arg{{"arg0", "uint256", false}, {"arg1", "bytes32", true}},
+		},
+		{
+			"signature without arguments",
+			"Poke()",
+			"Poke()",
+			nil,
+		},
+		{
+			"JSON fragment",
+			`{"type":"event","name":"Ping","inputs":[{"name":"id","type":"uint256","indexed":true}]}`,
+			"Ping(uint256)",
+			[]arg{{"id", "uint256", true}},
+		},
+		{
+			"JSON ABI with one event",
+			`[{"type":"event","name":"Ping","inputs":[{"name":"id","type":"uint256","indexed":false}]}]`,
+			"Ping(uint256)",
+			[]arg{{"id", "uint256", false}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := ParseEvent(tt.event)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if event.Sig != tt.sig {
+				t.Errorf("expected signature %s, got %s", tt.sig, event.Sig)
+			}
+			if event.ID != crypto.Keccak256Hash([]byte(tt.sig)) {
+				t.Errorf("expected ID keccak256(%s), got %s", tt.sig, event.ID.Hex())
+			}
+			if len(event.Inputs) != len(tt.args) {
+				t.Fatalf("expected %d inputs, got %d", len(tt.args), len(event.Inputs))
+			}
+			for i, a := range tt.args {
+				in := event.Inputs[i]
+				if in.Name != a.name || in.Type.String() != a.typ || in.Indexed != a.indexed {
+					t.Errorf("input %d: expected %+v, got {name:%s typ:%s indexed:%t}", i, a, in.Name, in.Type, in.Indexed)
+				}
+			}
+		})
+	}
+
+	errorTests := []struct {
+		name  string
+		event string
+	}{
+		{"no parentheses", "Transfer"},
+		{"no name", "(uint256)"},
+		{"unterminated", "Transfer(uint256"},
+		{"empty argument", "Transfer(uint256, )"},
+		{"unknown type", "Transfer(foo)"},
+		{"too many words", "Transfer(uint256 indexed value extra)"},
+		{"duplicate name", "Transfer(uint256 value, uint256 value)"},
+		{"invalid JSON", `{"type":"event"`},
+		{"no event in JSON", `[{"type":"function","name":"f","inputs":[]}]`},
+		{"two events in JSON", `[{"type":"event","name":"A","inputs":[]},{"type":"event","name":"B","inputs":[]}]`},
+		{"anonymous event", `{"type":"event","name":"A","anonymous":true,"inputs":[]}`},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseEvent(tt.event); err == nil {
+				t.Errorf("expected error parsing %q", tt.event)
+			}
+		})
+	}
+}
+
+func TestParseEvent_TransferTopic(t *testing.T) {
+	event, err := ParseEvent(transferSignature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.ID != transferTopic {
+		t.Errorf("expected %s, got %s", transferTopic.Hex(), event.ID.Hex())
+	}
+}
+
+func TestTopicFilters(t *testing.T) {
+	event, err := ParseEvent(transferSignature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := "0x00000000000000000000000000000000000000aa"
+	b := "0x00000000000000000000000000000000000000bb"
+	topicA := log.Topic(common.HexToHash(a))
+	topicB := log.Topic(common.HexToHash(b))
+
+	tests := []struct {
+		name     string
+		filters  job.EventTopicFilters
+		expected [][]log.Topic
+	}{
+		{"no filters", nil, nil},
+		{"empty value list", job.EventTopicFilters{"to": {}}, nil},
+		{"first indexed argument", job.EventTopicFilters{"from": {a, b}}, [][]log.Topic{{topicA, topicB}, {}}},
+		{"second indexed argument", job.EventTopicFilters{"to": {b}}, [][]log.Topic{{}, {topicB}}},
+		{"both indexed arguments", job.EventTopicFilters{"from": {a}, "to": {b}}, [][]log.Topic{{topicA}, {topicB}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TopicFilters(event, tt.filters)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	errorTests := []struct {
+		name    string
+		filters job.EventTopicFilters
+	}{
+		{"non-indexed argument", job.EventTopicFilters{"value": {"1"}}},
+		{"unknown argument", job.EventTopicFilters{"spender": {a}}},
+		{"invalid value", job.EventTopicFilters{"from": {"not an address"}}},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := TopicFilters(event, tt.filters); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestTopicValue(t *testing.T) {
+	newType := func(s string) abi.Type {
+		typ, err := abi.NewType(s, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return typ
+	}
+	var bytes4 common.Hash
+	copy(bytes4[:], []byte{0xde, 0xad, 0xbe, 0xef})
+	minusOne := common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+
+	tests := []struct {
+		name     string
+		typ      string
+		value    string
+		expected common.Hash
+	}{
+		{"address", "address", "0x00000000000000000000000000000000000000aa", common.HexToHash("0xaa")},
+		{"decimal uint", "uint256", "42", common.BigToHash(big.NewInt(42))},
+		{"hex uint", "uint64", "0x2a", common.BigToHash(big.NewInt(42))},
+		{"negative int", "int256", "-1", minusOne},
+		{"positive int", "int8", "5", common.BigToHash(big.NewInt(5))},
+		{"true", "bool", "true", common.BigToHash(big.NewInt(1))},
+		{"false", "bool", "false", common.Hash{}},
+		{"bytes4 is left aligned", "bytes4", "0xdeadbeef", bytes4},
+		{"string is hashed", "string", "hello", crypto.Keccak256Hash([]byte("hello"))},
+		{"bytes are hashed", "bytes", "0x0102", crypto.Keccak256Hash([]byte{1, 2})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := topicValue(newType(tt.typ), tt.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected.Hex(), got.Hex())
+			}
+		})
+	}
+
+	errorTests := []struct {
+		name  string
+		typ   string
+		value string
+	}{
+		{"invalid address", "address", "0x1234"},
+		{"not an integer", "uint256", "forty-two"},
+		{"negative uint", "uint256", "-1"},
+		{"invalid bool", "bool", "maybe"},
+		{"bytes4 too long", "bytes4", "0xdeadbeef00"},
+		{"bytes4 not hex", "bytes4", "deadbeef"},
+		{"bytes not hex", "bytes", "0xzz"},
+		{"array", "uint256[]", "1"},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := topicValue(newType(tt.typ), tt.value); err == nil {
+				t.Errorf("expected error encoding %q as %s", tt.value, tt.typ)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package eventtrigger
+
+import (
+	"github.com/pelletier/go-toml"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+
+	"PhoenixOracle/core/service/job"
+)
+
+func ValidatedEventTriggerSpec(tomlString string) (job.Job, error) {
+	var jb = job.Job{
+		ExternalJobID: uuid.NewV4(), // Default to generating a uuid, can be overwritten by the specified one in tomlString.
+	}
+
+	tree, err := toml.Load(tomlString)
+	if err != nil {
+		return jb, errors.Wrap(err, "toml error on load")
+	}
+
+	err = tree.Unmarshal(&jb)
+	if err != nil {
+		return jb, errors.Wrap(err, "toml unmarshal error on spec")
+	}
+	if jb.Type != job.EventTrigger {
+		return jb, errors.Errorf("unsupported type %s", jb.Type)
+	}
+
+	var spec job.EventTriggerSpec
+	err = tree.Unmarshal(&spec)
+	if err != nil {
+		return jb, errors.Wrap(err, "toml unmarshal error on job")
+	}
+	jb.EventTriggerSpec = &spec
+
+	if !tree.Has("contractAddress") {
+		return jb, errors.New("contractAddress is required")
+	}
+	if spec.Event == "" {
+		return jb, errors.New("event is required")
+	}
+	event, err := ParseEvent(spec.Event)
+	if err != nil {
+		return jb, err
+	}
+	if _, err := TopicFilters(event, spec.TopicFilters); err != nil {
+		return jb, err
+	}
+
+	return jb, nil
+}
@@ -0,0 +1,361 @@
+package offchainreporting
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"PhoenixOracle/core/service/job"
+	"PhoenixOracle/lib/logger"
+	"PhoenixOracle/util"
+	"github.com/lib/pq"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BootstrapPeersPollInterval is how often jobs using a shared bootstrap peer
+// list or a DNS name re-resolve their bootstrap peers
+const BootstrapPeersPollInterval = time.Minute
+
+// dnsaddrPrefix prefixes multiaddrs in DNS TXT records, following the libp2p
+// dnsaddr convention
+const dnsaddrPrefix = "dnsaddr="
+
+var (
+	// ErrBootstrapPeerListNotFound is returned when a named bootstrap peer
+	// list does not exist
+	ErrBootstrapPeerListNotFound = errors.New("bootstrap peer list not found")
+	// ErrBootstrapPeerListInUse is returned when deleting a bootstrap peer
+	// list that jobs still reference
+	ErrBootstrapPeerListInUse = errors.New("bootstrap peer list is referenced by jobs")
+)
+
+// BootstrapPeerList is a named, node-level list of bootstrap peers that OCR
+// jobs can reference instead of embedding the peers in their spec.
+//
+// A node has a single P2P peer, so it runs at most one job per contract. For
+// redundant bootstrap nodes, run the bootstrap job of a contract on several
+// nodes and list all of them: oracles connect to every listed peer.
+type BootstrapPeerList struct {
+	Name      string         `gorm:"primary_key"`
+	Peers     pq.StringArray `gorm:"type:text[]"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (BootstrapPeerList) TableName() string {
+	return "p2p_bootstrap_peer_lists"
+}
+
+// ValidateBootstrapPeers checks that every peer is a valid multiaddr
+func ValidateBootstrapPeers(peers []string) error {
+	for _, peer := range peers {
+		if _, err := multiaddr.NewMultiaddr(peer); err != nil {
+			return errors.Wrapf(err, "p2p bootstrap peer %v is invalid", peer)
+		}
+	}
+	return nil
+}
+
+// BootstrapPeerLists returns all shared bootstrap peer lists, ordered by name
+func BootstrapPeerLists(db *gorm.DB) ([]BootstrapPeerList, error) {
+	var lists []BootstrapPeerList
+	err := db.Order("name ASC").Find(&lists).Error
+	return lists, errors.Wrap(err, "BootstrapPeerLists failed")
+}
+
+// FindBootstrapPeerList returns the shared bootstrap peer list with the given
+// name
+func FindBootstrapPeerList(db *gorm.DB, name string) (BootstrapPeerList, error) {
+	var list BootstrapPeerList
+	err := db.Where("name = ?", name).First(&list).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return list, errors.Wrapf(ErrBootstrapPeerListNotFound, "%q", name)
+	}
+	return list, errors.Wrap(err, "FindBootstrapPeerList failed")
+}
+
+// UpsertBootstrapPeerList creates the named list or replaces its peers. Jobs
+// referencing the list pick up the change within BootstrapPeersPollInterval.
+func UpsertBootstrapPeerList(db *gorm.DB, name string, peers []string) (BootstrapPeerList, error) {
+	if name == "" {
+		return BootstrapPeerList{}, errors.New("bootstrap peer list name is required")
+	}
+	if len(peers) == 0 {
+		return BootstrapPeerList{}, errors.New("bootstrap peer list must contain at least one peer")
+	}
+	if err := ValidateBootstrapPeers(peers); err != nil {
+		return BootstrapPeerList{}, err
+	}
+	now := time.Now()
+	list := BootstrapPeerList{Name: name, Peers: peers, CreatedAt: now, UpdatedAt: now}
+	err := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"peers", "updated_at"}),
+	}).Create(&list).Error
+	if err != nil {
+		return list, errors.Wrap(err, "UpsertBootstrapPeerList failed")
+	}
+	return FindBootstrapPeerList(db, name)
+}
+
+// DeleteBootstrapPeerList deletes the named list. Lists referenced by jobs
+// cannot be deleted.
+func DeleteBootstrapPeerList(db *gorm.DB, name string) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var inUse int64
+		err := tx.Model(&job.OffchainReportingOracleSpec{}).Where("p2p_bootstrap_peer_list = ?", name).Count(&inUse).Error
+		if err != nil {
+			return errors.Wrap(err, "DeleteBootstrapPeerList failed to count referencing jobs")
+		}
+		if inUse > 0 {
+			return errors.Wrapf(ErrBootstrapPeerListInUse, "%q is used by %d job(s)", name, inUse)
+		}
+		result := tx.Where("name = ?", name).Delete(&BootstrapPeerList{})
+		if result.Error != nil {
+			return errors.Wrap(result.Error, "DeleteBootstrapPeerList failed")
+		}
+		if result.RowsAffected == 0 {
+			return errors.Wrapf(ErrBootstrapPeerListNotFound, "%q", name)
+		}
+		return nil
+	})
+}
+
+type txtResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// bootstrapPeerResolver resolves the V1 bootstrap peers of a job. The static
+// peers, the shared list and the DNS name of the spec are combined; if the
+// spec sets none of them the node-wide default is used.
+type bootstrapPeerResolver struct {
+	db           *gorm.DB
+	dns          txtResolver
+	staticPeers  []string
+	listName     string
+	dnsName      string
+	defaultPeers func() ([]string, error)
+}
+
+func newBootstrapPeerResolver(db *gorm.DB, dns txtResolver, spec job.OffchainReportingOracleSpec, defaultPeers func() ([]string, error)) *bootstrapPeerResolver {
+	r := &bootstrapPeerResolver{
+		db:           db,
+		dns:          dns,
+		staticPeers:  spec.P2PBootstrapPeers,
+		defaultPeers: defaultPeers,
+	}
+	if spec.P2PBootstrapPeerList != nil {
+		r.listName = *spec.P2PBootstrapPeerList
+	}
+	if spec.P2PBootstrapDNSName != nil {
+		r.dnsName = *spec.P2PBootstrapDNSName
+	}
+	return r
+}
+
+// dynamic reports whether the resolved peers can change while the job runs
+func (r *bootstrapPeerResolver) dynamic() bool {
+	return r.listName != "" || r.dnsName != ""
+}
+
+// resolve returns the deduplicated, sorted bootstrap peers
+func (r *bootstrapPeerResolver) resolve(ctx context.Context) ([]string, error) {
+	if r.staticPeers == nil && !r.dynamic() {
+		return r.defaultPeers()
+	}
+	peers := append([]string{}, r.staticPeers...)
+	if r.listName != "" {
+		list, err := FindBootstrapPeerList(r.db.WithContext(ctx), r.listName)
+		if err != nil {
+			return nil, err
+		}
+		peers = append(peers, list.Peers...)
+	}
+	if r.dnsName != "" {
+		records, err := r.dns.LookupTXT(ctx, r.dnsName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not look up bootstrap peers of %s", r.dnsName)
+		}
+		for _, record := range records {
+			peer := strings.TrimPrefix(record, dnsaddrPrefix)
+			if err := ValidateBootstrapPeers([]string{peer}); err != nil {
+				return nil, errors.Wrapf(err, "invalid TXT record of %s", r.dnsName)
+			}
+			peers = append(peers, peer)
+		}
+	}
+	return dedupeSorted(peers), nil
+}
+
+func dedupeSorted(peers []string) []string {
+	seen := make(map[string]struct{}, len(peers))
+	result := make([]string, 0, len(peers))
+	for _, peer := range peers {
+		if _, ok := seen[peer]; ok {
+			continue
+		}
+		seen[peer] = struct{}{}
+		result = append(result, peer)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func equalPeers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// bootstrapPeerWatcher runs an oracle or bootstrap node built from a set of
+// bootstrap peers, and replaces it with a new one whenever the peers resolved
+// for the job change. libocr nodes cannot be reconfigured or restarted, so a
+// change briefly interrupts the node; its persistent state is kept in the
+// database and survives the replacement.
+//
+// The peers are first resolved when the watcher starts, without blocking job
+// startup. If that fails, the node starts with the fallback peers, i.e. the
+// static peers of the spec, and resolution is retried on every poll. An
+// oracle, which requires a bootstrap peer, is not created until there is one.
+type bootstrapPeerWatcher struct {
+	utils.StartStopOnce
+	logger        *logger.Logger
+	resolver      *bootstrapPeerResolver
+	pollTicker    utils.TickerBase
+	newNode       func(v1Bootstrappers []string) (job.Service, error)
+	fallbackPeers []string
+	requireOne    bool
+
+	// Only accessed from the event loop, and from Close once it has exited
+	peers []string
+	node  job.Service
+
+	chStop chan struct{}
+	chDone chan struct{}
+}
+
+func newBootstrapPeerWatcher(
+	logger *logger.Logger,
+	resolver *bootstrapPeerResolver,
+	pollTicker utils.TickerBase,
+	fallbackPeers []string,
+	requireOne bool,
+	newNode func(v1Bootstrappers []string) (job.Service, error),
+) *bootstrapPeerWatcher {
+	return &bootstrapPeerWatcher{
+		logger:        logger,
+		resolver:      resolver,
+		pollTicker:    pollTicker,
+		newNode:       newNode,
+		fallbackPeers: fallbackPeers,
+		requireOne:    requireOne,
+		chStop:        make(chan struct{}),
+		chDone:        make(chan struct{}),
+	}
+}
+
+func (w *bootstrapPeerWatcher) Start() error {
+	return w.StartOnce("OCRBootstrapPeerWatcher", func() error {
+		go w.eventLoop()
+		return nil
+	})
+}
+
+func (w *bootstrapPeerWatcher) Close() error {
+	return w.StopOnce("OCRBootstrapPeerWatcher", func() error {
+		close(w.chStop)
+		<-w.chDone
+		if w.node == nil {
+			return nil
+		}
+		return w.node.Close()
+	})
+}
+
+func (w *bootstrapPeerWatcher) eventLoop() {
+	defer close(w.chDone)
+	w.pollTicker.Resume()
+	defer w.pollTicker.Destroy()
+
+	w.refresh()
+	if w.node == nil {
+		w.startFallback()
+	}
+	for {
+		select {
+		case <-w.chStop:
+			return
+		case <-w.pollTicker.Ticks():
+			w.refresh()
+		}
+	}
+}
+
+// startFallback starts the node with the fallback peers, after the first
+// resolution failed
+func (w *bootstrapPeerWatcher) startFallback() {
+	if w.requireOne && len(w.fallbackPeers) == 0 {
+		w.logger.Errorw("OCRBootstrapPeerWatcher: no bootstrap peers yet, waiting for them to resolve")
+		return
+	}
+	w.logger.Warnw(fmt.Sprintf("OCRBootstrapPeerWatcher: starting node with %d static bootstrap peer(s) until the others resolve", len(w.fallbackPeers)),
+		"peers", w.fallbackPeers)
+	w.replaceNode(w.fallbackPeers)
+}
+
+func (w *bootstrapPeerWatcher) refresh() {
+	ctx, cancel := utils.ContextFromChan(w.chStop)
+	defer cancel()
+	ctx, cancelTimeout := context.WithTimeout(ctx, BootstrapPeersPollInterval)
+	defer cancelTimeout()
+	peers, err := w.resolver.resolve(ctx)
+	if err != nil {
+		w.logger.Errorw("OCRBootstrapPeerWatcher: could not resolve bootstrap peers, keeping the previous ones", "err", err, "previous", w.peers)
+		return
+	}
+	if w.node != nil && equalPeers(peers, w.peers) {
+		return
+	}
+	if w.requireOne && len(peers) == 0 {
+		w.logger.Errorw("OCRBootstrapPeerWatcher: resolved no bootstrap peers, keeping the previous ones", "previous", w.peers)
+		return
+	}
+	if w.node != nil {
+		w.logger.Infow(fmt.Sprintf("OCRBootstrapPeerWatcher: bootstrap peers changed, restarting node with %d peer(s)", len(peers)),
+			"previous", w.peers, "peers", peers)
+	}
+	w.replaceNode(peers)
+}
+
+// replaceNode closes the running node, if any, and starts one with the peers
+func (w *bootstrapPeerWatcher) replaceNode(peers []string) {
+	node, err := w.newNode(peers)
+	if err != nil {
+		w.logger.Errorw("OCRBootstrapPeerWatcher: could not create node with new bootstrap peers, keeping the previous ones", "err", err, "peers", peers)
+		return
+	}
+	if w.node != nil {
+		if err := w.node.Close(); err != nil {
+			w.logger.Errorw("OCRBootstrapPeerWatcher: error closing node", "err", err)
+		}
+	}
+	if err := node.Start(); err != nil {
+		// The old node is gone; retry with a fresh node on the next tick
+		w.logger.Errorw("OCRBootstrapPeerWatcher: could not start node with new bootstrap peers", "err", err)
+		w.peers = nil
+	} else {
+		w.peers = peers
+	}
+	w.node = node
+}
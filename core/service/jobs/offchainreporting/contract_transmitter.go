@@ -2,10 +2,12 @@ package offchainreporting
 
 import (
 	"context"
+	"encoding/binary"
 	"math/big"
 	"time"
 
 	"PhoenixOracle/core/log"
+	"PhoenixOracle/core/service/txmanager"
 	"PhoenixOracle/lib/libocr/gethwrappers/offchainaggregator"
 	ocrtypes "PhoenixOracle/lib/libocr/offchainreporting/types"
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -26,10 +28,13 @@ type (
 		contractCaller  *offchainaggregator.OffchainAggregatorCaller
 		tracker         *OCRContractTracker
 		chainID         *big.Int
+		jobID           int32
+		policy          *TransmissionPolicy
+		status          *jobStatus
 	}
 
 	Transmitter interface {
-		CreateEthTransaction(ctx context.Context, toAddress gethCommon.Address, payload []byte) error
+		CreateEthTransaction(ctx context.Context, toAddress gethCommon.Address, payload []byte, meta *txmanager.EthTxMeta) (txmanager.EthTx, error)
 		FromAddress() gethCommon.Address
 	}
 )
@@ -42,6 +47,9 @@ func NewOCRContractTransmitter(
 	logBroadcaster log.Broadcaster,
 	tracker *OCRContractTracker,
 	chainID *big.Int,
+	jobID int32,
+	policy *TransmissionPolicy,
+	status *jobStatus,
 ) *OCRContractTransmitter {
 	return &OCRContractTransmitter{
 		contractAddress: address,
@@ -50,16 +58,51 @@ func NewOCRContractTransmitter(
 		contractCaller:  contractCaller,
 		tracker:         tracker,
 		chainID:         chainID,
+		jobID:           jobID,
+		policy:          policy,
+		status:          status,
 	}
 }
 
 func (oc *OCRContractTransmitter) Transmit(ctx context.Context, report []byte, rs, ss [][32]byte, vs [32]byte) error {
+	if oc.policy != nil {
+		decision, err := oc.policy.Allow(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		oc.status.policyDecided(decision)
+		if decision != PolicyAllowed {
+			err = errors.Wrap(ErrTransmissionSuppressed, decision)
+			oc.status.transmitted(report, 0, err)
+			return err
+		}
+	}
+
 	payload, err := oc.contractABI.Pack("transmit", report, rs, ss, vs)
 	if err != nil {
 		return errors.Wrap(err, "abi.Pack failed")
 	}
 
-	return errors.Wrap(oc.transmitter.CreateEthTransaction(ctx, oc.contractAddress, payload), "failed to send Eth transaction")
+	meta := &txmanager.EthTxMeta{JobID: oc.jobID}
+	if configDigest, epoch, round, ok := reportContext(report); ok {
+		meta.OCRConfigDigest = configDigest.Hex()
+		meta.OCREpoch = epoch
+		meta.OCRRound = round
+	}
+
+	etx, err := oc.transmitter.CreateEthTransaction(ctx, oc.contractAddress, payload, meta)
+	oc.status.transmitted(report, etx.ID, err)
+	return errors.Wrap(err, "failed to send Eth transaction")
+}
+
+// reportContext decodes the first word of a report, the domain separation
+// tag: 11 zero bytes, the config digest, the epoch and the round
+func reportContext(report []byte) (configDigest ocrtypes.ConfigDigest, epoch uint32, round uint8, ok bool) {
+	if len(report) < 32 {
+		return configDigest, 0, 0, false
+	}
+	copy(configDigest[:], report[11:27])
+	return configDigest, binary.BigEndian.Uint32(report[27:31]), report[31], true
 }
 
 func (oc *OCRContractTransmitter) LatestTransmissionDetails(ctx context.Context) (configDigest ocrtypes.ConfigDigest, epoch uint32, round uint8, latestAnswer ocrtypes.Observation, latestTimestamp time.Time, err error) {
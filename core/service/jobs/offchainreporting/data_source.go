@@ -3,6 +3,7 @@ package offchainreporting
 import (
 	"context"
 	"math/big"
+	"sync"
 	"time"
 
 	"PhoenixOracle/db/models"
@@ -22,12 +23,81 @@ type dataSource struct {
 	ocrLogger             logger.Logger
 	runResults            chan<- pipeline.Run
 	currentBridgeMetadata models.BridgeMetaData
+	status                *jobStatus
+
+	// fallbackStaleness is how old the last good observation may be to be
+	// returned in place of a failed one. Zero disables the fallback.
+	fallbackStaleness time.Duration
+	lastGoodMu        sync.Mutex
+	lastGood          *big.Int
+	lastGoodAt        time.Time
+}
+
+// ObservationSource is the path through which the data source produced an
+// observation
+type ObservationSource string
+
+const (
+	// ObservationSourceComplete means every task of the pipeline succeeded
+	ObservationSourceComplete ObservationSource = "complete"
+	// ObservationSourcePartial means the pipeline produced a result although
+	// some tasks errored or did not finish before the observation deadline,
+	// e.g. a median over the sources that responded in time
+	ObservationSourcePartial ObservationSource = "partial"
+	// ObservationSourceFallback means the pipeline failed and the last good
+	// observation was returned instead
+	ObservationSourceFallback ObservationSource = "fallback"
+)
+
+type observation struct {
+	value       *big.Int
+	source      ObservationSource
+	failedTasks int
 }
 
 var _ ocrtypes.DataSource = (*dataSource)(nil)
 
+// Observe runs the pipeline until the deadline of ctx. Tasks that have not
+// finished by then are cancelled, so aggregation tasks with enough allowed
+// faults still yield a value from the sources that did respond. If the
+// pipeline fails, the last good observation is returned as long as it is
+// within fallbackStaleness.
 func (ds *dataSource) Observe(ctx context.Context) (ocrtypes.Observation, error) {
-	var observation ocrtypes.Observation
+	run, obs, err := ds.observe(ctx)
+	if err == nil {
+		ds.lastGoodMu.Lock()
+		ds.lastGood, ds.lastGoodAt = obs.value, time.Now()
+		ds.lastGoodMu.Unlock()
+		ds.status.observed(run, obs, nil)
+		return obs.value, nil
+	}
+
+	value, observedAt, ok := ds.fallback()
+	if !ok {
+		ds.status.observed(run, obs, err)
+		return nil, err
+	}
+	ds.ocrLogger.Warnw("Observation failed, falling back to last good observation",
+		"err", err,
+		"value", value,
+		"observedAt", observedAt,
+	)
+	obs.value, obs.source = value, ObservationSourceFallback
+	ds.status.observed(run, obs, err)
+	return value, nil
+}
+
+func (ds *dataSource) fallback() (*big.Int, time.Time, bool) {
+	ds.lastGoodMu.Lock()
+	defer ds.lastGoodMu.Unlock()
+	if ds.fallbackStaleness == 0 || ds.lastGood == nil || time.Since(ds.lastGoodAt) > ds.fallbackStaleness {
+		return nil, time.Time{}, false
+	}
+	return new(big.Int).Set(ds.lastGood), ds.lastGoodAt, true
+}
+
+func (ds *dataSource) observe(ctx context.Context) (pipeline.Run, observation, error) {
+	var obs observation
 	md, err := models.MarshalBridgeMetaData(ds.currentBridgeMetadata.LatestAnswer, ds.currentBridgeMetadata.UpdatedAt)
 	if err != nil {
 		logger.Warnw("unable to attach metadata for run", "err", err)
@@ -46,7 +116,12 @@ func (ds *dataSource) Observe(ctx context.Context) (ocrtypes.Observation, error)
 
 	run, trrs, err := ds.pipelineRunner.ExecuteRun(ctx, ds.spec, vars, ds.ocrLogger)
 	if err != nil {
-		return observation, errors.Wrapf(err, "error executing run for spec ID %v", ds.spec.ID)
+		return run, obs, errors.Wrapf(err, "error executing run for spec ID %v", ds.spec.ID)
+	}
+	for _, trr := range trrs {
+		if trr.Result.Error != nil {
+			obs.failedTasks++
+		}
 	}
 	finalResult := trrs.FinalResult()
 
@@ -59,25 +134,29 @@ func (ds *dataSource) Observe(ctx context.Context) (ocrtypes.Observation, error)
 	select {
 	case ds.runResults <- run:
 	default:
-		return nil, errors.Errorf("unable to enqueue run save for job ID %v, buffer full", ds.spec.JobID)
+		return run, obs, errors.Errorf("unable to enqueue run save for job ID %v, buffer full", ds.spec.JobID)
 	}
 
 	result, err := finalResult.SingularResult()
 	if err != nil {
-		return nil, errors.Wrapf(err, "error getting singular result for job ID %v", ds.spec.JobID)
+		return run, obs, errors.Wrapf(err, "error getting singular result for job ID %v", ds.spec.JobID)
 	}
 
 	if result.Error != nil {
-		return nil, result.Error
+		return run, obs, result.Error
 	}
 
 	asDecimal, err := utils.ToDecimal(result.Value)
 	if err != nil {
-		return nil, errors.Wrap(err, "cannot convert observation to decimal")
+		return run, obs, errors.Wrap(err, "cannot convert observation to decimal")
 	}
 	ds.currentBridgeMetadata = models.BridgeMetaData{
 		LatestAnswer: asDecimal.BigInt(),
 		UpdatedAt:    big.NewInt(time.Now().Unix()),
 	}
-	return asDecimal.BigInt(), nil
+	obs.value, obs.source = asDecimal.BigInt(), ObservationSourceComplete
+	if obs.failedTasks > 0 {
+		obs.source = ObservationSourcePartial
+	}
+	return run, obs, nil
 }
@@ -56,6 +56,33 @@ LIMIT 1`, d.oracleSpecID, cd)
 	return ps, nil
 }
 
+// latestPersistentState returns the most recently written persistent state
+// across all config digests, or nil if there is none
+func (d *db) latestPersistentState(ctx context.Context) (*PersistentStateStatus, error) {
+	q := d.QueryRowContext(ctx, `
+SELECT config_digest, epoch, highest_sent_epoch, highest_received_epoch, updated_at
+FROM offchainreporting_persistent_states
+WHERE offchainreporting_oracle_spec_id = $1
+ORDER BY updated_at DESC
+LIMIT 1`, d.oracleSpecID)
+
+	ps := new(PersistentStateStatus)
+
+	var tmp []int64
+	err := q.Scan(&ps.ConfigDigest, &ps.Epoch, &ps.HighestSentEpoch, pq.Array(&tmp), &ps.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "latestPersistentState failed")
+	}
+
+	for _, v := range tmp {
+		ps.HighestReceivedEpoch = append(ps.HighestReceivedEpoch, uint32(v))
+	}
+
+	return ps, nil
+}
+
 func (d *db) WriteState(ctx context.Context, cd ocrtypes.ConfigDigest, state ocrtypes.PersistentState) error {
 	var highestReceivedEpoch []int64
 	for _, v := range state.HighestReceivedEpoch {
@@ -236,6 +263,34 @@ WHERE offchainreporting_oracle_spec_id = $1 AND config_digest = $2
 	return m, nil
 }
 
+// pendingTransmissionStatuses returns every pending transmission of the
+// oracle, oldest first
+func (d *db) pendingTransmissionStatuses(ctx context.Context) ([]PendingTransmissionStatus, error) {
+	rows, err := d.QueryContext(ctx, `
+SELECT config_digest, epoch, round, time, median
+FROM offchainreporting_pending_transmissions
+WHERE offchainreporting_oracle_spec_id = $1
+ORDER BY time ASC
+`, d.oracleSpecID)
+	if err != nil {
+		return nil, errors.Wrap(err, "pendingTransmissionStatuses failed to query rows")
+	}
+	defer logger.ErrorIfCalling(rows.Close)
+
+	var pts []PendingTransmissionStatus
+	for rows.Next() {
+		var pt PendingTransmissionStatus
+		var median utils.Big
+		if err := rows.Scan(&pt.ConfigDigest, &pt.Epoch, &pt.Round, &pt.Time, &median); err != nil {
+			return nil, errors.Wrap(err, "pendingTransmissionStatuses failed to scan row")
+		}
+		pt.Median = median.ToInt()
+		pts = append(pts, pt)
+	}
+
+	return pts, rows.Err()
+}
+
 func (d *db) DeletePendingTransmission(ctx context.Context, k ocrtypes.PendingTransmissionKey) (err error) {
 	_, err = d.ExecContext(ctx, `
 DELETE FROM offchainreporting_pending_transmissions
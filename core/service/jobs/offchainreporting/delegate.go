@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"net"
 	"strings"
 	"time"
 
@@ -68,6 +69,7 @@ type Delegate struct {
 	monitoringEndpointGen telemetry.MonitoringEndpointGenerator
 	chain                 *chain.Chain
 	headBroadcaster       httypes.HeadBroadcaster
+	statusRegistry        *StatusRegistry
 }
 
 var _ job.Delegate = (*Delegate)(nil)
@@ -87,6 +89,7 @@ func NewDelegate(
 	monitoringEndpointGen telemetry.MonitoringEndpointGenerator,
 	chain *chain.Chain,
 	headBroadcaster httypes.HeadBroadcaster,
+	statusRegistry *StatusRegistry,
 ) *Delegate {
 	return &Delegate{
 		db,
@@ -101,6 +104,7 @@ func NewDelegate(
 		monitoringEndpointGen,
 		chain,
 		headBroadcaster,
+		statusRegistry,
 	}
 }
 
@@ -108,8 +112,11 @@ func (d Delegate) JobType() job.Type {
 	return job.OffchainReporting
 }
 
-func (Delegate) AfterJobCreated(spec job.Job)  {}
-func (Delegate) BeforeJobDeleted(spec job.Job) {}
+func (Delegate) AfterJobCreated(spec job.Job) {}
+
+func (d Delegate) BeforeJobDeleted(spec job.Job) {
+	d.statusRegistry.unregister(spec.ID)
+}
 
 func (d Delegate) ServicesForSpec(jobSpec job.Job) (services []job.Service, err error) {
 	if jobSpec.OffchainreportingOracleSpec == nil {
@@ -172,13 +179,15 @@ func (d Delegate) ServicesForSpec(jobSpec job.Job) (services []job.Service, err
 	} else if peerWrapper.PeerID != peerID {
 		return nil, errors.Errorf("given peer with ID '%s' does not match OCR configured peer with ID: %s", peerWrapper.PeerID.String(), peerID.String())
 	}
-	var bootstrapPeers []string
-	if concreteSpec.P2PBootstrapPeers != nil {
-		bootstrapPeers = concreteSpec.P2PBootstrapPeers
-	} else {
-		bootstrapPeers, err = d.config.P2PBootstrapPeers()
+	peerResolver := newBootstrapPeerResolver(d.db, net.DefaultResolver, concreteSpec, d.config.P2PBootstrapPeers)
+	// Shared lists and DNS names are resolved by the bootstrapPeerWatcher
+	// once the job has started, so that a failed lookup neither blocks nor
+	// fails job startup. The static peers are the fallback.
+	bootstrapPeers := concreteSpec.P2PBootstrapPeers
+	if !peerResolver.dynamic() {
+		bootstrapPeers, err = peerResolver.resolve(context.Background())
 		if err != nil {
-			return nil, err
+			return nil, errors.Wrap(err, "could not resolve bootstrap peers")
 		}
 	}
 	v2BootstrapPeers := d.config.P2PV2Bootstrappers()
@@ -188,7 +197,9 @@ func (d Delegate) ServicesForSpec(jobSpec job.Job) (services []job.Service, err
 		"jobName", jobSpec.Name.ValueOrZero(),
 		"jobID", jobSpec.ID,
 	)
+	status := d.statusRegistry.register(jobSpec.ID, concreteSpec.ContractAddress.Address(), d.config.ChainID(), peerWrapper)
 	ocrLogger := NewLogger(loggerWith, d.config.OCRTraceLogging(), func(msg string) {
+		status.protocolError(msg)
 		d.jobORM.RecordError(context.Background(), jobSpec.ID, msg)
 	})
 
@@ -199,21 +210,24 @@ func (d Delegate) ServicesForSpec(jobSpec job.Job) (services []job.Service, err
 	logger.Info(fmt.Sprintf("OCR job using local config %+v", lc))
 
 	if concreteSpec.IsBootstrapPeer {
-		var bootstrapper *ocr.BootstrapNode
-		bootstrapper, err = ocr.NewBootstrapNode(ocr.BootstrapNodeArgs{
-			BootstrapperFactory:   peerWrapper.Peer,
-			V1Bootstrappers:       bootstrapPeers,
-			ContractConfigTracker: tracker,
-			Database:              ocrdb,
-			LocalConfig:           lc,
-			Logger:                ocrLogger,
-		})
-		if err != nil {
-			return nil, errors.Wrap(err, "error calling NewBootstrapNode")
+		newBootstrapNode := func(v1Bootstrappers []string) (job.Service, error) {
+			bootstrapper, err2 := ocr.NewBootstrapNode(ocr.BootstrapNodeArgs{
+				BootstrapperFactory:   peerWrapper.Peer,
+				V1Bootstrappers:       v1Bootstrappers,
+				ContractConfigTracker: tracker,
+				Database:              ocrdb,
+				LocalConfig:           lc,
+				Logger:                ocrLogger,
+			})
+			return bootstrapper, errors.Wrap(err2, "error calling NewBootstrapNode")
+		}
+		bootstrapper, err2 := d.maybeWatchBootstrapPeers(loggerWith, peerResolver, bootstrapPeers, false, newBootstrapNode)
+		if err2 != nil {
+			return nil, err2
 		}
 		services = append(services, bootstrapper)
 	} else {
-		if len(bootstrapPeers) < 1 {
+		if len(bootstrapPeers) < 1 && !peerResolver.dynamic() {
 			return nil, errors.New("need at least one bootstrap peer")
 		}
 		var kb string
@@ -246,6 +260,30 @@ func (d Delegate) ServicesForSpec(jobSpec job.Job) (services []job.Service, err
 
 		strategy := txmanager.NewQueueingTxStrategy(jobSpec.ExternalJobID, d.config.OCRDefaultTransactionQueueDepth())
 
+		var configOverrider ocrtypes.ConfigOverrider
+		configOverriderService, err := d.maybeCreateConfigOverrider(loggerWith, concreteSpec.ContractAddress)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to create ConfigOverrider")
+		}
+
+		// NOTE: conditional assigning to `configOverrider` is necessary due to the unfortunate fact that assigning `nil` to an
+		// interface variable causes `x == nil` checks to always return false, so methods on the interface cannot be safely called then.
+		//
+		if configOverriderService != nil {
+			services = append(services, configOverriderService)
+			configOverrider = configOverriderService
+		}
+
+		ticker := utils.NewPausableTicker(TransmissionPolicyPollInterval)
+		policy, err := NewTransmissionPolicy(loggerWith, d.db, jobSpec.ID, concreteSpec, d.ethClient, configOverrider, status, &ticker)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to create transmission policy")
+		}
+		if policy != nil {
+			services = append(services, policy)
+			configOverrider = policy
+		}
+
 		contractTransmitter := NewOCRContractTransmitter(
 			concreteSpec.ContractAddress.Address(),
 			contractCaller,
@@ -254,48 +292,46 @@ func (d Delegate) ServicesForSpec(jobSpec job.Job) (services []job.Service, err
 			d.logBroadcaster,
 			tracker,
 			d.config.ChainID(),
+			jobSpec.ID,
+			policy,
+			status,
 		)
 
 		runResults := make(chan pipeline.Run, d.config.JobPipelineResultWriteQueueDepth())
 		jobSpec.PipelineSpec.JobName = jobSpec.Name.ValueOrZero()
 		jobSpec.PipelineSpec.JobID = jobSpec.ID
 
-		var configOverrider ocrtypes.ConfigOverrider
-		configOverriderService, err := d.maybeCreateConfigOverrider(loggerWith, concreteSpec.ContractAddress)
-		if err != nil {
-			return nil, errors.Wrap(err, "Failed to create ConfigOverrider")
+		newOracle := func(v1Bootstrappers []string) (job.Service, error) {
+			oracle, err2 := ocr.NewOracle(ocr.OracleArgs{
+				Database: ocrdb,
+				Datasource: &dataSource{
+					pipelineRunner: d.pipelineRunner,
+					ocrLogger:      *loggerWith,
+					jobSpec:        jobSpec,
+					spec:           *jobSpec.PipelineSpec,
+					runResults:     runResults,
+					status:         status,
+
+					fallbackStaleness: concreteSpec.ObservationFallbackStaleness.Duration(),
+				},
+				LocalConfig:                  lc,
+				ContractTransmitter:          contractTransmitter,
+				ContractConfigTracker:        tracker,
+				PrivateKeys:                  ocrkey,
+				BinaryNetworkEndpointFactory: peerWrapper.Peer,
+				Logger:                       ocrLogger,
+				V1Bootstrappers:              v1Bootstrappers,
+				V2Bootstrappers:              v2BootstrapPeers,
+				MonitoringEndpoint:           d.monitoringEndpointGen.GenMonitoringEndpoint(concreteSpec.ContractAddress.Address()),
+				ConfigOverrider:              configOverrider,
+				LeaderSelectionObserver:      status,
+				RoundObserver:                status,
+			})
+			return oracle, errors.Wrap(err2, "error calling NewOracle")
 		}
-
-		// NOTE: conditional assigning to `configOverrider` is necessary due to the unfortunate fact that assigning `nil` to an
-		// interface variable causes `x == nil` checks to always return false, so methods on the interface cannot be safely called then.
-		//
-		if configOverriderService != nil {
-			services = append(services, configOverriderService)
-			configOverrider = configOverriderService
-		}
-
-		oracle, err := ocr.NewOracle(ocr.OracleArgs{
-			Database: ocrdb,
-			Datasource: &dataSource{
-				pipelineRunner: d.pipelineRunner,
-				ocrLogger:      *loggerWith,
-				jobSpec:        jobSpec,
-				spec:           *jobSpec.PipelineSpec,
-				runResults:     runResults,
-			},
-			LocalConfig:                  lc,
-			ContractTransmitter:          contractTransmitter,
-			ContractConfigTracker:        tracker,
-			PrivateKeys:                  ocrkey,
-			BinaryNetworkEndpointFactory: peerWrapper.Peer,
-			Logger:                       ocrLogger,
-			V1Bootstrappers:              bootstrapPeers,
-			V2Bootstrappers:              v2BootstrapPeers,
-			MonitoringEndpoint:           d.monitoringEndpointGen.GenMonitoringEndpoint(concreteSpec.ContractAddress.Address()),
-			ConfigOverrider:              configOverrider,
-		})
+		oracle, err := d.maybeWatchBootstrapPeers(loggerWith, peerResolver, bootstrapPeers, true, newOracle)
 		if err != nil {
-			return nil, errors.Wrap(err, "error calling NewOracle")
+			return nil, err
 		}
 		services = append(services, oracle)
 
@@ -305,12 +341,30 @@ func (d Delegate) ServicesForSpec(jobSpec job.Job) (services []job.Service, err
 			d.pipelineRunner,
 			make(chan struct{}),
 			*loggerWith,
+			status,
 		)}, services...)
 	}
 
 	return services, nil
 }
 
+// maybeWatchBootstrapPeers returns the node built by newNode, wrapped in a
+// watcher that rebuilds it on changes if the bootstrap peers of the job are
+// resolved dynamically
+func (d *Delegate) maybeWatchBootstrapPeers(
+	logger *logger.Logger,
+	resolver *bootstrapPeerResolver,
+	peers []string,
+	requireOne bool,
+	newNode func(v1Bootstrappers []string) (job.Service, error),
+) (job.Service, error) {
+	if !resolver.dynamic() {
+		return newNode(peers)
+	}
+	ticker := utils.NewPausableTicker(BootstrapPeersPollInterval)
+	return newBootstrapPeerWatcher(logger, resolver, &ticker, peers, requireOne, newNode), nil
+}
+
 func (d *Delegate) maybeCreateConfigOverrider(logger *logger.Logger, contractAddress ethkey.EIP55Address) (*ConfigOverriderImpl, error) {
 	flagsContractAddress := d.config.FlagsContractAddress()
 	if flagsContractAddress != "" {
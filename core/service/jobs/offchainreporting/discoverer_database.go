@@ -3,6 +3,7 @@ package offchainreporting
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	ocrnetworking "PhoenixOracle/lib/libocr/networking"
 	"github.com/lib/pq"
@@ -59,3 +60,35 @@ SELECT remote_peer_id, ann FROM offchainreporting_discoverer_announcements WHERE
 	}
 	return results, nil
 }
+
+// DiscoveredAnnouncement is an announcement of a remote peer's addresses
+// received by the v2 networking stack
+type DiscoveredAnnouncement struct {
+	PeerID       string
+	Announcement []byte
+	UpdatedAt    time.Time
+}
+
+// Announcements returns every announcement stored for the local peer
+func (d *DiscovererDatabase) Announcements(ctx context.Context) ([]DiscoveredAnnouncement, error) {
+	rows, err := d.db.QueryContext(ctx, `
+SELECT remote_peer_id, ann, updated_at FROM offchainreporting_discoverer_announcements WHERE local_peer_id = $1 ORDER BY remote_peer_id`, d.peerID)
+	if err != nil {
+		return nil, errors.Wrap(err, "DiscovererDatabase failed to read Announcements")
+	}
+	var results []DiscoveredAnnouncement
+	for rows.Next() {
+		var a DiscoveredAnnouncement
+		if err := rows.Scan(&a.PeerID, &a.Announcement, &a.UpdatedAt); err != nil {
+			return nil, multierr.Combine(err, rows.Close())
+		}
+		results = append(results, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if err := rows.Close(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return results, nil
+}
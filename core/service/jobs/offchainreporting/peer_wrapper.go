@@ -1,6 +1,7 @@
 package offchainreporting
 
 import (
+	"context"
 	"net"
 	"time"
 
@@ -10,7 +11,9 @@ import (
 	ocrnetworking "PhoenixOracle/lib/libocr/networking"
 	ocrtypes "PhoenixOracle/lib/libocr/offchainreporting/types"
 	"PhoenixOracle/lib/logger"
+	"PhoenixOracle/lib/postgres"
 	"PhoenixOracle/util"
+	p2pnetwork "github.com/libp2p/go-libp2p-core/network"
 	p2ppeer "github.com/libp2p/go-libp2p-core/peer"
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
@@ -44,6 +47,7 @@ type (
 	peer interface {
 		ocrtypes.BootstrapperFactory
 		ocrtypes.BinaryNetworkEndpointFactory
+		ocrnetworking.Diagnostics
 		Close() error
 	}
 
@@ -53,6 +57,7 @@ type (
 		db       *gorm.DB
 
 		pstoreWrapper *Pstorewrapper
+		discovererDB  *DiscovererDatabase
 		PeerID        p2pkey.PeerID
 		Peer          peer
 
@@ -105,7 +110,7 @@ func (p *SingletonPeerWrapper) Start() error {
 		if err != nil {
 			return err
 		}
-		discovererDB := NewDiscovererDatabase(sqlDB, p2ppeer.ID(p.PeerID))
+		p.discovererDB = NewDiscovererDatabase(sqlDB, p2ppeer.ID(p.PeerID))
 
 		// If the P2PAnnounceIP is set we must also set the P2PAnnouncePort
 		// Fallback to P2PListenPort if it wasn't made explicit
@@ -131,7 +136,7 @@ func (p *SingletonPeerWrapper) Start() error {
 			V2AnnounceAddresses:  p.config.P2PV2AnnounceAddresses(),
 			V2DeltaReconcile:     p.config.P2PV2DeltaReconcile().Duration(),
 			V2DeltaDial:          p.config.P2PV2DeltaDial().Duration(),
-			V2DiscovererDatabase: discovererDB,
+			V2DiscovererDatabase: p.discovererDB,
 			EndpointConfig: ocrnetworking.EndpointConfig{
 				IncomingMessageBufferSize: p.config.OCRIncomingMessageBufferSize(),
 				OutgoingMessageBufferSize: p.config.OCROutgoingMessageBufferSize(),
@@ -144,10 +149,119 @@ func (p *SingletonPeerWrapper) Start() error {
 		if err != nil {
 			return errors.Wrap(err, "error calling NewPeer")
 		}
+		if err = p.loadBannedPeers(); err != nil {
+			return errors.Wrap(err, "could not load banned peers")
+		}
 		return p.pstoreWrapper.Start()
 	})
 }
 
+func (p *SingletonPeerWrapper) loadBannedPeers() error {
+	var banned []string
+	err := p.db.Raw(`SELECT remote_peer_id FROM p2p_banned_peers WHERE local_peer_id = ?`, p.PeerID.Raw()).Scan(&banned).Error
+	if err != nil {
+		return err
+	}
+	for _, remotePeerID := range banned {
+		err := p.Peer.BanPeer(remotePeerID)
+		if errors.Is(err, ocrnetworking.ErrV2Unsupported) {
+			logger.Warnw("Bans are not enforced with the V2 networking stack, ignoring banned peers", "bannedPeers", banned)
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ErrPeerNotStarted is returned by the peer management methods when this
+// node has no P2P peer, either because it has no P2P key or because off-chain
+// reporting is disabled
+var ErrPeerNotStarted = errors.New("P2P peer is not started")
+
+// Diagnostics returns the connectivity diagnostics of the running peer
+func (p *SingletonPeerWrapper) Diagnostics() (ocrnetworking.Diagnostics, error) {
+	if p == nil || !p.IsStarted() || p.Peer == nil {
+		return nil, ErrPeerNotStarted
+	}
+	return p.Peer, nil
+}
+
+// Announcements returns the addresses of remote peers discovered by the v2
+// networking stack
+func (p *SingletonPeerWrapper) Announcements(ctx context.Context) ([]DiscoveredAnnouncement, error) {
+	if p == nil || !p.IsStarted() || p.discovererDB == nil {
+		return nil, ErrPeerNotStarted
+	}
+	return p.discovererDB.Announcements(ctx)
+}
+
+// AddPeerAddress adds a static address for a remote peer and dials it
+func (p *SingletonPeerWrapper) AddPeerAddress(ctx context.Context, peerID string, addr string) error {
+	diagnostics, err := p.Diagnostics()
+	if err != nil {
+		return err
+	}
+	return diagnostics.AddPeerAddress(ctx, peerID, addr)
+}
+
+// BanPeer disconnects from a remote peer and refuses connections with it,
+// including after a restart, until UnbanPeer is called. The ban is saved
+// before it is applied, and not saved if the peer rejects it.
+func (p *SingletonPeerWrapper) BanPeer(peerID string) error {
+	diagnostics, err := p.Diagnostics()
+	if err != nil {
+		return err
+	}
+	return postgres.GormTransactionWithDefaultContext(p.db, func(tx *gorm.DB) error {
+		err := tx.Exec(`
+INSERT INTO p2p_banned_peers (local_peer_id, remote_peer_id, created_at) VALUES (?, ?, NOW())
+ON CONFLICT (local_peer_id, remote_peer_id) DO NOTHING`, p.PeerID.Raw(), peerID).Error
+		if err != nil {
+			return err
+		}
+		return diagnostics.BanPeer(peerID)
+	})
+}
+
+// UnbanPeer lifts a ban placed with BanPeer
+func (p *SingletonPeerWrapper) UnbanPeer(peerID string) error {
+	diagnostics, err := p.Diagnostics()
+	if err != nil {
+		return err
+	}
+	return postgres.GormTransactionWithDefaultContext(p.db, func(tx *gorm.DB) error {
+		err := tx.Exec(`DELETE FROM p2p_banned_peers WHERE local_peer_id = ? AND remote_peer_id = ?`, p.PeerID.Raw(), peerID).Error
+		if err != nil {
+			return err
+		}
+		return diagnostics.UnbanPeer(peerID)
+	})
+}
+
+// Connectedness reports whether the libp2p host of this node is connected to
+// the peer with the given raw peer ID
+func (p *SingletonPeerWrapper) Connectedness(peerID string) string {
+	host, ok := p.Peer.(interface{ Network() p2pnetwork.Network })
+	if !ok || !p.IsStarted() {
+		return "Unknown"
+	}
+	id, err := p2ppeer.Decode(peerID)
+	if err != nil {
+		return "Unknown"
+	}
+	switch host.Network().Connectedness(id) {
+	case p2pnetwork.Connected:
+		return "Connected"
+	case p2pnetwork.CanConnect:
+		return "CanConnect"
+	case p2pnetwork.CannotConnect:
+		return "CannotConnect"
+	default:
+		return "NotConnected"
+	}
+}
+
 func (p *SingletonPeerWrapper) Close() error {
 	return p.StopOnce("SingletonPeerWrapper", func() (err error) {
 		if p.Peer != nil {
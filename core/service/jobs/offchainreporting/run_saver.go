@@ -17,10 +17,11 @@ type RunResultSaver struct {
 	pipelineRunner pipeline.Runner
 	done           chan struct{}
 	logger         logger.Logger
+	status         *jobStatus
 }
 
 func NewResultRunSaver(db *sqlx.DB, runResults <-chan pipeline.Run, pipelineRunner pipeline.Runner, done chan struct{},
-	logger logger.Logger,
+	logger logger.Logger, status *jobStatus,
 ) *RunResultSaver {
 	return &RunResultSaver{
 		db:             db,
@@ -28,6 +29,7 @@ func NewResultRunSaver(db *sqlx.DB, runResults <-chan pipeline.Run, pipelineRunn
 		pipelineRunner: pipelineRunner,
 		done:           done,
 		logger:         logger,
+		status:         status,
 	}
 }
 
@@ -39,10 +41,7 @@ func (r *RunResultSaver) Start() error {
 				case run := <-r.runResults:
 					r.logger.Infow("RunSaver: saving job run", "run", run)
 
-					_, err := r.pipelineRunner.InsertFinishedRun(r.db, run, false)
-					if err != nil {
-						r.logger.Errorw("error inserting finished results", "err", err)
-					}
+					r.saveRun(run)
 				case <-r.done:
 					return
 				}
@@ -60,13 +59,19 @@ func (r *RunResultSaver) Close() error {
 			select {
 			case run := <-r.runResults:
 				r.logger.Infow("RunSaver: saving job run before exiting", "run", run, "task results")
-				_, err := r.pipelineRunner.InsertFinishedRun(r.db, run, false)
-				if err != nil {
-					r.logger.Errorw("error inserting finished results", "err", err)
-				}
+				r.saveRun(run)
 			default:
 				return nil
 			}
 		}
 	})
 }
+
+func (r *RunResultSaver) saveRun(run pipeline.Run) {
+	runID, err := r.pipelineRunner.InsertFinishedRun(r.db, run, false)
+	if err != nil {
+		r.logger.Errorw("error inserting finished results", "err", err)
+		return
+	}
+	r.status.runSaved(run, runID)
+}
@@ -0,0 +1,122 @@
+package offchainreporting
+
+import (
+	"math/big"
+	"strings"
+
+	"PhoenixOracle/core/keystore/keys/ethkey"
+	"PhoenixOracle/core/keystore/keys/ocrkey"
+	"PhoenixOracle/core/keystore/keys/p2pkey"
+	"PhoenixOracle/db/models"
+	"PhoenixOracle/lib/libocr/gethwrappers/offchainaggregator"
+	"PhoenixOracle/lib/libocr/offchainreporting/confighelper"
+	ocrtypes "PhoenixOracle/lib/libocr/offchainreporting/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// SetConfigOracle identifies an oracle of a new config. The keys use the
+// same format as `keys ocr list` and `keys p2p list`.
+type SetConfigOracle struct {
+	OnChainSigningAddress ocrkey.OnChainSigningAddress `json:"onChainSigningAddress"`
+	OffChainPublicKey     ocrkey.OffChainPublicKey     `json:"offChainPublicKey"`
+	ConfigPublicKey       ocrkey.ConfigPublicKey       `json:"configPublicKey"`
+	PeerID                p2pkey.PeerID                `json:"peerID"`
+	TransmitterAddress    ethkey.EIP55Address          `json:"transmitterAddress"`
+}
+
+// SetConfigRequest holds everything needed to build a setConfig call for an
+// OffchainAggregator contract
+type SetConfigRequest struct {
+	ContractAddress ethkey.EIP55Address `json:"contractAddress"`
+	Oracles         []SetConfigOracle   `json:"oracles"`
+	DeltaProgress   models.Duration     `json:"deltaProgress"`
+	DeltaResend     models.Duration     `json:"deltaResend"`
+	DeltaRound      models.Duration     `json:"deltaRound"`
+	DeltaGrace      models.Duration     `json:"deltaGrace"`
+	DeltaC          models.Duration     `json:"deltaC"`
+	AlphaPPB        uint64              `json:"alphaPPB"`
+	DeltaStage      models.Duration     `json:"deltaStage"`
+	RMax            uint8               `json:"rMax"`
+	// S is the transmission schedule. It defaults to one oracle per stage.
+	S []int `json:"s"`
+	F int   `json:"f"`
+	// SkipChainSpecificChecks disables the minimum timings enforced for
+	// well-known chains
+	SkipChainSpecificChecks bool `json:"skipChainSpecificChecks"`
+
+	// Send submits the setConfig transaction through the txmanager from
+	// FromAddress instead of only returning the calldata
+	Send        bool                 `json:"send"`
+	FromAddress *ethkey.EIP55Address `json:"fromAddress,omitempty"`
+	GasLimit    uint64               `json:"gasLimit"`
+}
+
+// SetConfigArgs are the arguments and ABI encoded calldata of a setConfig
+// call
+type SetConfigArgs struct {
+	Signers              []common.Address
+	Transmitters         []common.Address
+	Threshold            uint8
+	EncodedConfigVersion uint64
+	EncodedConfig        []byte
+	Calldata             []byte
+}
+
+// BuildSetConfig generates a config for the oracles in req, encrypting a
+// fresh shared secret to each oracle's config public key, and encodes the
+// setConfig call for it
+func BuildSetConfig(chainID *big.Int, req SetConfigRequest) (args SetConfigArgs, err error) {
+	if len(req.Oracles) == 0 {
+		return args, errors.New("at least one oracle is required")
+	}
+
+	var oracles []confighelper.OracleIdentityExtra
+	for _, o := range req.Oracles {
+		oracles = append(oracles, confighelper.OracleIdentityExtra{
+			OracleIdentity: confighelper.OracleIdentity{
+				OnChainSigningAddress: ocrtypes.OnChainSigningAddress(o.OnChainSigningAddress),
+				TransmitAddress:       o.TransmitterAddress.Address(),
+				OffchainPublicKey:     ocrtypes.OffchainPublicKey(o.OffChainPublicKey),
+				PeerID:                o.PeerID.Raw(),
+			},
+			SharedSecretEncryptionPublicKey: ocrtypes.SharedSecretEncryptionPublicKey(o.ConfigPublicKey),
+		})
+	}
+
+	s := req.S
+	if len(s) == 0 {
+		for range req.Oracles {
+			s = append(s, 1)
+		}
+	}
+
+	args.Signers, args.Transmitters, args.Threshold, args.EncodedConfigVersion, args.EncodedConfig, err = confighelper.CheckedContractSetConfigArgs(
+		chainID,
+		req.SkipChainSpecificChecks,
+		confighelper.ConfigParameters{
+			DeltaProgress: req.DeltaProgress.Duration(),
+			DeltaResend:   req.DeltaResend.Duration(),
+			DeltaRound:    req.DeltaRound.Duration(),
+			DeltaGrace:    req.DeltaGrace.Duration(),
+			DeltaC:        req.DeltaC.Duration(),
+			AlphaPPB:      req.AlphaPPB,
+			DeltaStage:    req.DeltaStage.Duration(),
+			RMax:          req.RMax,
+			S:             s,
+			F:             req.F,
+		},
+		oracles,
+	)
+	if err != nil {
+		return args, err
+	}
+
+	contractABI, err := abi.JSON(strings.NewReader(offchainaggregator.OffchainAggregatorABI))
+	if err != nil {
+		return args, errors.Wrap(err, "could not get contract ABI JSON")
+	}
+	args.Calldata, err = contractABI.Pack("setConfig", args.Signers, args.Transmitters, args.Threshold, args.EncodedConfigVersion, args.EncodedConfig)
+	return args, errors.Wrap(err, "abi.Pack failed")
+}
@@ -0,0 +1,498 @@
+package offchainreporting
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"PhoenixOracle/core/service/job"
+	"PhoenixOracle/core/service/pipeline"
+	"PhoenixOracle/lib/libocr/offchainreporting/confighelper"
+	ocrtypes "PhoenixOracle/lib/libocr/offchainreporting/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// maxRecentLeaderSelections bounds how many overrides and fallbacks are
+	// kept per job
+	maxRecentLeaderSelections = 20
+	// maxRecentProtocolErrors bounds how many protocol errors are kept per job
+	maxRecentProtocolErrors = 20
+)
+
+var (
+	promActiveOracleSetSize = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ocr_active_oracle_set_size",
+		Help: "Number of oracles in the active set reported by the contract's getIndexes",
+	},
+		[]string{"job_id", "contract_address"},
+	)
+	promEpochLeader = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ocr_epoch_leader",
+		Help: "Oracle ID of the leader of the latest epoch",
+	},
+		[]string{"job_id", "contract_address"},
+	)
+	promLeaderOverrides = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ocr_leader_overrides",
+		Help: "Number of epochs whose default leader was replaced by an oracle from the active set",
+	},
+		[]string{"job_id", "contract_address"},
+	)
+	promLeaderSelectionFallbacks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ocr_leader_selection_fallbacks",
+		Help: "Number of epochs whose leader was selected without an active set, by reason",
+	},
+		[]string{"job_id", "contract_address", "reason"},
+	)
+	promObservations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ocr_observations",
+		Help: "Number of observations made by the data source, by the path that produced them",
+	},
+		[]string{"job_id", "contract_address", "source"},
+	)
+	promTransmissionPolicyDecisions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ocr_transmission_policy_decisions",
+		Help: "Number of transmissions evaluated by the node-side transmission policy, by decision",
+	},
+		[]string{"job_id", "contract_address", "decision"},
+	)
+	promTransmissionPolicyGasSpike = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ocr_transmission_policy_gas_spike",
+		Help: "1 while the gas price is above the gas spike threshold of the transmission policy",
+	},
+		[]string{"job_id", "contract_address"},
+	)
+)
+
+// OracleStatus is a snapshot of the protocol state of an OCR job
+type OracleStatus struct {
+	JobID           int32
+	ContractAddress common.Address
+	// Running is false when the job has no oracle on this node, in which
+	// case only the persisted state is filled in
+	Running bool
+
+	// Round is the latest round started by this oracle
+	Round *RoundStatus
+	// ActiveIndexes is the latest active oracle set seen by the pacemaker
+	ActiveIndexes   []int
+	LatestSelection *ocrtypes.LeaderSelection
+	RecentOverrides []ocrtypes.LeaderSelection
+	RecentFallbacks []ocrtypes.LeaderSelection
+	OverrideCount   uint64
+	FallbackCount   uint64
+
+	LastObservation  *ObservationStatus
+	LastTransmission *TransmissionStatus
+	Peers            []PeerStatus
+	RecentErrors     []ProtocolError
+
+	PersistentState      *PersistentStateStatus
+	PendingTransmissions []PendingTransmissionStatus
+
+	// GasSpike is true while the transmission policy applies its stricter
+	// deviation threshold and heartbeat
+	GasSpike bool
+}
+
+// RoundStatus identifies a round of the OCR protocol
+type RoundStatus struct {
+	ConfigDigest ocrtypes.ConfigDigest
+	Epoch        uint32
+	Round        uint8
+	Leader       ocrtypes.OracleID
+	StartedAt    time.Time
+}
+
+// ObservationStatus is the outcome of the latest call to the data source
+type ObservationStatus struct {
+	Value *big.Int
+	Error string
+	// Source is the path that produced Value, empty if there is none
+	Source ObservationSource
+	// FailedTasks is the number of pipeline tasks that errored or did not
+	// finish before the deadline
+	FailedTasks int
+	// PipelineRunID is zero until the run has been saved
+	PipelineRunID int64
+	ObservedAt    time.Time
+
+	runCreatedAt time.Time
+}
+
+// TransmissionStatus is the latest report handed to the transmitter
+type TransmissionStatus struct {
+	ConfigDigest  ocrtypes.ConfigDigest
+	Epoch         uint32
+	Round         uint8
+	EthTxID       int64
+	Error         string
+	TransmittedAt time.Time
+}
+
+// PeerStatus is the connectivity of this node to a member of the oracle set
+type PeerStatus struct {
+	OracleID      ocrtypes.OracleID
+	PeerID        string
+	Self          bool
+	Connectedness string
+}
+
+// ProtocolError is an error logged by the OCR protocol
+type ProtocolError struct {
+	Message string
+	Time    time.Time
+}
+
+// PersistentStateStatus is the latest protocol state written to
+// offchainreporting_persistent_states
+type PersistentStateStatus struct {
+	ConfigDigest         ocrtypes.ConfigDigest
+	Epoch                uint32
+	HighestSentEpoch     uint32
+	HighestReceivedEpoch []uint32
+	UpdatedAt            time.Time
+}
+
+// PendingTransmissionStatus is a report waiting to be confirmed on chain
+type PendingTransmissionStatus struct {
+	ConfigDigest ocrtypes.ConfigDigest
+	Epoch        uint32
+	Round        uint8
+	Median       *big.Int
+	Time         time.Time
+}
+
+// StatusRegistry keeps the live status of every OCR oracle running on this
+// node, keyed by job ID
+type StatusRegistry struct {
+	db   *sql.DB
+	mu   sync.RWMutex
+	jobs map[int32]*jobStatus
+}
+
+func NewStatusRegistry(db *sql.DB) *StatusRegistry {
+	return &StatusRegistry{db: db, jobs: make(map[int32]*jobStatus)}
+}
+
+// Status returns the status of the OCR job jb, combining the live state of
+// its oracle, if one is running, with the state persisted in the database
+func (r *StatusRegistry) Status(ctx context.Context, jb job.Job) (OracleStatus, error) {
+	spec := jb.OffchainreportingOracleSpec
+	if spec == nil {
+		return OracleStatus{}, errors.Errorf("job %v is not an OCR job", jb.ID)
+	}
+
+	r.mu.RLock()
+	js, running := r.jobs[jb.ID]
+	r.mu.RUnlock()
+
+	status := OracleStatus{JobID: jb.ID, ContractAddress: spec.ContractAddress.Address()}
+	if running {
+		status = js.snapshot()
+		status.Running = true
+	}
+
+	ocrdb := NewDB(r.db, spec.ID)
+	ps, err := ocrdb.latestPersistentState(ctx)
+	if err != nil {
+		return status, err
+	}
+	status.PersistentState = ps
+	status.PendingTransmissions, err = ocrdb.pendingTransmissionStatuses(ctx)
+	if err != nil {
+		return status, err
+	}
+
+	if running && !spec.IsBootstrapPeer {
+		cc, err := ocrdb.ReadConfig(ctx)
+		if err != nil {
+			return status, err
+		}
+		if cc != nil {
+			status.Peers, err = js.peerStatuses(*cc)
+			if err != nil {
+				return status, err
+			}
+		}
+	}
+	return status, nil
+}
+
+// Get returns the status of the OCR oracle for jobID, if one is running
+func (r *StatusRegistry) Get(jobID int32) (OracleStatus, bool) {
+	r.mu.RLock()
+	js, ok := r.jobs[jobID]
+	r.mu.RUnlock()
+	if !ok {
+		return OracleStatus{}, false
+	}
+	status := js.snapshot()
+	status.Running = true
+	return status, true
+}
+
+func (r *StatusRegistry) register(jobID int32, contractAddress common.Address, chainID *big.Int, peerWrapper *SingletonPeerWrapper) *jobStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if js, ok := r.jobs[jobID]; ok && js.status.ContractAddress == contractAddress {
+		return js
+	}
+	js := &jobStatus{
+		status: OracleStatus{JobID: jobID, ContractAddress: contractAddress},
+		labels: prometheus.Labels{
+			"job_id":           fmt.Sprintf("%d", jobID),
+			"contract_address": contractAddress.Hex(),
+		},
+		chainID:     chainID,
+		peerWrapper: peerWrapper,
+	}
+	r.jobs[jobID] = js
+	return js
+}
+
+func (r *StatusRegistry) unregister(jobID int32) {
+	r.mu.Lock()
+	js, ok := r.jobs[jobID]
+	delete(r.jobs, jobID)
+	r.mu.Unlock()
+	if !ok {
+		return
+	}
+	promActiveOracleSetSize.Delete(js.labels)
+	promEpochLeader.Delete(js.labels)
+	promLeaderOverrides.Delete(js.labels)
+	for _, reason := range []ocrtypes.LeaderSelectionReason{
+		ocrtypes.LeaderSelectionFallbackTimeout,
+		ocrtypes.LeaderSelectionFallbackError,
+		ocrtypes.LeaderSelectionFallbackEmpty,
+	} {
+		promLeaderSelectionFallbacks.Delete(js.fallbackLabels(reason))
+	}
+	for _, source := range []ObservationSource{ObservationSourceComplete, ObservationSourcePartial, ObservationSourceFallback} {
+		promObservations.Delete(js.observationLabels(source))
+	}
+	for _, decision := range []string{PolicyAllowed, PolicyMaintenanceWindow, PolicyRateLimited} {
+		promTransmissionPolicyDecisions.Delete(js.decisionLabels(decision))
+	}
+	promTransmissionPolicyGasSpike.Delete(js.labels)
+}
+
+// jobStatus tracks a single OCR job and is handed to libocr as its
+// LeaderSelectionObserver and RoundObserver. A nil *jobStatus records nothing.
+type jobStatus struct {
+	mu     sync.RWMutex
+	status OracleStatus
+	labels prometheus.Labels
+
+	chainID     *big.Int
+	peerWrapper *SingletonPeerWrapper
+}
+
+var (
+	_ ocrtypes.LeaderSelectionObserver = (*jobStatus)(nil)
+	_ ocrtypes.RoundObserver           = (*jobStatus)(nil)
+)
+
+func (js *jobStatus) RoundStarted(configDigest ocrtypes.ConfigDigest, epoch uint32, round uint8, leader ocrtypes.OracleID) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	js.status.Round = &RoundStatus{
+		ConfigDigest: configDigest,
+		Epoch:        epoch,
+		Round:        round,
+		Leader:       leader,
+		StartedAt:    time.Now(),
+	}
+}
+
+func (js *jobStatus) LeaderSelected(selection ocrtypes.LeaderSelection) {
+	js.mu.Lock()
+	defer js.mu.Unlock()
+
+	js.status.LatestSelection = &selection
+	promEpochLeader.With(js.labels).Set(float64(selection.Leader))
+
+	switch {
+	case selection.Reason.IsFallback():
+		js.status.FallbackCount++
+		js.status.RecentFallbacks = appendBounded(js.status.RecentFallbacks, selection)
+		promLeaderSelectionFallbacks.With(js.fallbackLabels(selection.Reason)).Inc()
+		return
+	case selection.Reason == ocrtypes.LeaderSelectionOverride:
+		js.status.OverrideCount++
+		js.status.RecentOverrides = appendBounded(js.status.RecentOverrides, selection)
+		promLeaderOverrides.With(js.labels).Inc()
+	}
+	js.status.ActiveIndexes = selection.ActiveIndexes
+	promActiveOracleSetSize.With(js.labels).Set(float64(len(selection.ActiveIndexes)))
+}
+
+// observed records the outcome of dataSource.Observe
+func (js *jobStatus) observed(run pipeline.Run, obs observation, err error) {
+	if js == nil {
+		return
+	}
+	o := &ObservationStatus{ObservedAt: time.Now(), FailedTasks: obs.failedTasks, runCreatedAt: run.CreatedAt}
+	if obs.source != "" {
+		// A fallback value may come with the error of the run it replaced
+		o.Value = obs.value
+		o.Source = obs.source
+		promObservations.With(js.observationLabels(obs.source)).Inc()
+	}
+	if err != nil {
+		o.Error = err.Error()
+	}
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	js.status.LastObservation = o
+}
+
+// runSaved attaches the ID of a saved pipeline run to the latest
+// observation, if that observation came from it
+func (js *jobStatus) runSaved(run pipeline.Run, runID int64) {
+	if js == nil {
+		return
+	}
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	o := js.status.LastObservation
+	if o != nil && !run.CreatedAt.IsZero() && o.runCreatedAt.Equal(run.CreatedAt) {
+		o.PipelineRunID = runID
+	}
+}
+
+// transmitted records a report handed to the transmitter
+func (js *jobStatus) transmitted(report []byte, ethTxID int64, err error) {
+	if js == nil {
+		return
+	}
+	t := &TransmissionStatus{EthTxID: ethTxID, TransmittedAt: time.Now()}
+	t.ConfigDigest, t.Epoch, t.Round, _ = reportContext(report)
+	if err != nil {
+		t.Error = err.Error()
+	}
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	js.status.LastTransmission = t
+}
+
+// policyDecided records a decision of the transmission policy
+func (js *jobStatus) policyDecided(decision string) {
+	if js == nil {
+		return
+	}
+	promTransmissionPolicyDecisions.With(js.decisionLabels(decision)).Inc()
+}
+
+// gasSpikeChanged records whether the transmission policy is in gas spike
+// mode
+func (js *jobStatus) gasSpikeChanged(spike bool) {
+	if js == nil {
+		return
+	}
+	if spike {
+		promTransmissionPolicyGasSpike.With(js.labels).Set(1)
+	} else {
+		promTransmissionPolicyGasSpike.With(js.labels).Set(0)
+	}
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	js.status.GasSpike = spike
+}
+
+// protocolError records an error logged by libocr
+func (js *jobStatus) protocolError(msg string) {
+	if js == nil {
+		return
+	}
+	js.mu.Lock()
+	defer js.mu.Unlock()
+	js.status.RecentErrors = append(js.status.RecentErrors, ProtocolError{Message: msg, Time: time.Now()})
+	if len(js.status.RecentErrors) > maxRecentProtocolErrors {
+		js.status.RecentErrors = js.status.RecentErrors[len(js.status.RecentErrors)-maxRecentProtocolErrors:]
+	}
+}
+
+func (js *jobStatus) peerStatuses(cc ocrtypes.ContractConfig) ([]PeerStatus, error) {
+	pc, err := confighelper.PublicConfigFromContractConfig(js.chainID, true, cc)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode contract config")
+	}
+	var peers []PeerStatus
+	for i, identity := range pc.OracleIdentities {
+		peers = append(peers, PeerStatus{
+			OracleID:      ocrtypes.OracleID(i),
+			PeerID:        identity.PeerID,
+			Self:          identity.PeerID == js.peerWrapper.PeerID.Raw(),
+			Connectedness: js.peerWrapper.Connectedness(identity.PeerID),
+		})
+	}
+	return peers, nil
+}
+
+func (js *jobStatus) snapshot() OracleStatus {
+	js.mu.RLock()
+	defer js.mu.RUnlock()
+	s := js.status
+	s.ActiveIndexes = append([]int(nil), s.ActiveIndexes...)
+	s.RecentOverrides = append([]ocrtypes.LeaderSelection(nil), s.RecentOverrides...)
+	s.RecentFallbacks = append([]ocrtypes.LeaderSelection(nil), s.RecentFallbacks...)
+	s.RecentErrors = append([]ProtocolError(nil), s.RecentErrors...)
+	if s.LatestSelection != nil {
+		latest := *s.LatestSelection
+		s.LatestSelection = &latest
+	}
+	if s.Round != nil {
+		round := *s.Round
+		s.Round = &round
+	}
+	if s.LastObservation != nil {
+		observation := *s.LastObservation
+		s.LastObservation = &observation
+	}
+	if s.LastTransmission != nil {
+		transmission := *s.LastTransmission
+		s.LastTransmission = &transmission
+	}
+	return s
+}
+
+func (js *jobStatus) fallbackLabels(reason ocrtypes.LeaderSelectionReason) prometheus.Labels {
+	return prometheus.Labels{
+		"job_id":           js.labels["job_id"],
+		"contract_address": js.labels["contract_address"],
+		"reason":           string(reason),
+	}
+}
+
+func (js *jobStatus) observationLabels(source ObservationSource) prometheus.Labels {
+	return prometheus.Labels{
+		"job_id":           js.labels["job_id"],
+		"contract_address": js.labels["contract_address"],
+		"source":           string(source),
+	}
+}
+
+func (js *jobStatus) decisionLabels(decision string) prometheus.Labels {
+	return prometheus.Labels{
+		"job_id":           js.labels["job_id"],
+		"contract_address": js.labels["contract_address"],
+		"decision":         decision,
+	}
+}
+
+func appendBounded(selections []ocrtypes.LeaderSelection, selection ocrtypes.LeaderSelection) []ocrtypes.LeaderSelection {
+	selections = append(selections, selection)
+	if len(selections) > maxRecentLeaderSelections {
+		selections = selections[len(selections)-maxRecentLeaderSelections:]
+	}
+	return selections
+}
@@ -0,0 +1,151 @@
+package offchainreporting
+
+import (
+	"encoding/json"
+	"math/big"
+	"sort"
+	"time"
+
+	"PhoenixOracle/core/assets"
+	"PhoenixOracle/core/service/txmanager"
+	"PhoenixOracle/util"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// CostPeriod is the length of the periods transmission costs are aggregated
+// over
+type CostPeriod string
+
+const (
+	CostPeriodDay   CostPeriod = "day"
+	CostPeriodMonth CostPeriod = "month"
+)
+
+// ParseCostPeriod validates a period passed to TransmissionCosts, defaulting
+// to CostPeriodDay
+func ParseCostPeriod(s string) (CostPeriod, error) {
+	switch CostPeriod(s) {
+	case "", CostPeriodDay:
+		return CostPeriodDay, nil
+	case CostPeriodMonth:
+		return CostPeriodMonth, nil
+	default:
+		return "", errors.Errorf("invalid period %q, must be %q or %q", s, CostPeriodDay, CostPeriodMonth)
+	}
+}
+
+// start returns the start of the period containing t, in UTC
+func (p CostPeriod) start(t time.Time) time.Time {
+	t = t.UTC()
+	if p == CostPeriodMonth {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// TransmissionCost aggregates the OCR transmissions of a job created during
+// one period. Reverted transmissions are mined and cost gas like confirmed
+// ones; failed transmissions never made it on chain.
+type TransmissionCost struct {
+	JobID         int32
+	PeriodStart   time.Time
+	Transmissions int
+	Confirmed     int
+	Reverted      int
+	Failed        int
+	Pending       int
+	GasUsed       uint64
+	EthSpent      assets.Eth
+}
+
+// TransmissionCostsQuery selects the transmissions to aggregate. JobID may be
+// zero to include all jobs.
+type TransmissionCostsQuery struct {
+	JobID  int32
+	From   time.Time
+	To     time.Time
+	Period CostPeriod
+}
+
+type transmissionCostRow struct {
+	JobID     int32
+	State     txmanager.EthTxState
+	CreatedAt time.Time
+	GasPrice  *utils.Big
+	Receipt   []byte
+}
+
+// TransmissionCosts returns the gas used and ETH spent on OCR transmissions
+// per job and period, ordered by job and period. Transmissions are attributed
+// to the period in which they were created, using the job ID recorded in the
+// eth_tx meta.
+func TransmissionCosts(db *gorm.DB, q TransmissionCostsQuery) ([]TransmissionCost, error) {
+	var rows []transmissionCostRow
+	// Only one attempt per transaction can have a receipt; prefer that one
+	err := db.Raw(`
+SELECT DISTINCT ON (eth_txes.id)
+	(eth_txes.meta->>'JobID')::int AS job_id, eth_txes.state, eth_txes.created_at,
+	eth_tx_attempts.gas_price, eth_receipts.receipt
+FROM eth_txes
+LEFT JOIN eth_tx_attempts ON eth_tx_attempts.eth_tx_id = eth_txes.id
+LEFT JOIN eth_receipts ON eth_receipts.tx_hash = eth_tx_attempts.hash
+WHERE eth_txes.meta->>'OCRConfigDigest' IS NOT NULL
+AND eth_txes.created_at >= ? AND eth_txes.created_at < ?
+AND (? = 0 OR (eth_txes.meta->>'JobID')::int = ?)
+ORDER BY eth_txes.id, eth_receipts.receipt IS NULL, eth_tx_attempts.id DESC
+`, q.From, q.To, q.JobID, q.JobID).Scan(&rows).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "TransmissionCosts failed to load transmissions")
+	}
+
+	type key struct {
+		jobID int32
+		start time.Time
+	}
+	costs := make(map[key]*TransmissionCost)
+	for _, row := range rows {
+		k := key{row.JobID, q.Period.start(row.CreatedAt)}
+		cost, ok := costs[k]
+		if !ok {
+			cost = &TransmissionCost{JobID: k.jobID, PeriodStart: k.start, EthSpent: assets.NewEthValue(0)}
+			costs[k] = cost
+		}
+		cost.Transmissions++
+
+		if row.Receipt == nil {
+			if row.State == txmanager.EthTxFatalError {
+				cost.Failed++
+			} else {
+				cost.Pending++
+			}
+			continue
+		}
+		var receipt txmanager.Receipt
+		if err := json.Unmarshal(row.Receipt, &receipt); err != nil {
+			return nil, errors.Wrap(err, "TransmissionCosts failed to unmarshal receipt")
+		}
+		if receipt.Status == 0 {
+			cost.Reverted++
+		} else {
+			cost.Confirmed++
+		}
+		cost.GasUsed += receipt.GasUsed
+		if row.GasPrice != nil {
+			spent := new(big.Int).Mul(row.GasPrice.ToInt(), new(big.Int).SetUint64(receipt.GasUsed))
+			cost.EthSpent.ToInt().Add(cost.EthSpent.ToInt(), spent)
+		}
+	}
+
+	result := make([]TransmissionCost, 0, len(costs))
+	for _, cost := range costs {
+		result = append(result, *cost)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].JobID != result[j].JobID {
+			return result[i].JobID < result[j].JobID
+		}
+		return result[i].PeriodStart.Before(result[j].PeriodStart)
+	})
+	return result, nil
+}
@@ -0,0 +1,263 @@
+package offchainreporting
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"PhoenixOracle/core/service/job"
+	ocrtypes "PhoenixOracle/lib/libocr/offchainreporting/types"
+	"PhoenixOracle/lib/logger"
+	"PhoenixOracle/util"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// TransmissionPolicyPollInterval is how often the gas price is checked for
+// spikes
+const TransmissionPolicyPollInterval = 30 * time.Second
+
+// Transmission policy decisions, as reported in status and metrics
+const (
+	PolicyAllowed           = "allowed"
+	PolicyMaintenanceWindow = "maintenance_window"
+	PolicyRateLimited       = "rate_limited"
+)
+
+// ErrTransmissionSuppressed is returned by Transmit when the node-side
+// transmission policy of the job holds a report back
+var ErrTransmissionSuppressed = errors.New("transmission suppressed by policy")
+
+// MaintenanceWindow is a daily window, in UTC, during which transmissions are
+// suppressed. End may be before Start for windows spanning midnight.
+type MaintenanceWindow struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// ParseMaintenanceWindow parses a window of the form "HH:MM-HH:MM"
+func ParseMaintenanceWindow(s string) (MaintenanceWindow, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return MaintenanceWindow{}, errors.Errorf("maintenance window %q must be of the form HH:MM-HH:MM", s)
+	}
+	var bounds [2]time.Duration
+	for i, part := range parts {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return MaintenanceWindow{}, errors.Wrapf(err, "invalid maintenance window %q", s)
+		}
+		bounds[i] = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	if bounds[0] == bounds[1] {
+		return MaintenanceWindow{}, errors.Errorf("maintenance window %q is empty", s)
+	}
+	return MaintenanceWindow{Start: bounds[0], End: bounds[1]}, nil
+}
+
+// Contains reports whether t falls within the window
+func (w MaintenanceWindow) Contains(t time.Time) bool {
+	t = t.UTC()
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if w.Start < w.End {
+		return offset >= w.Start && offset < w.End
+	}
+	return offset >= w.Start || offset < w.End
+}
+
+// ValidateTransmissionPolicy checks the transmission policy fields of a spec
+func ValidateTransmissionPolicy(spec job.OffchainReportingOracleSpec) error {
+	for _, w := range spec.TransmissionMaintenanceWindows {
+		if _, err := ParseMaintenanceWindow(w); err != nil {
+			return err
+		}
+	}
+	gasSpikeSet := spec.GasSpikeGasPriceWei != nil || spec.GasSpikeAlphaPPB != 0 || spec.GasSpikeDeltaC != 0
+	if gasSpikeSet && (spec.GasSpikeGasPriceWei == nil || spec.GasSpikeAlphaPPB == 0 || spec.GasSpikeDeltaC == 0) {
+		return errors.New("gasSpikeGasPriceWei, gasSpikeAlphaPPB and gasSpikeDeltaC must be set together")
+	}
+	return nil
+}
+
+type gasPricer interface {
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// TransmissionPolicy applies node-side rules on top of the on-chain config of
+// an OCR job. It suppresses transmissions during maintenance windows and
+// beyond an hourly cap, and while the gas price is above a threshold it
+// overrides alphaPPB and deltaC with stricter values. It wraps the
+// hibernation ConfigOverrider, if there is one, which takes precedence.
+type TransmissionPolicy struct {
+	utils.StartStopOnce
+	logger           *logger.Logger
+	db               *gorm.DB
+	jobID            int32
+	gasPricer        gasPricer
+	hibernation      ocrtypes.ConfigOverrider
+	status           *jobStatus
+	pollTicker       utils.TickerBase
+	windows          []MaintenanceWindow
+	maxPerHour       uint32
+	gasSpikePrice    *big.Int
+	gasSpikeOverride ocrtypes.ConfigOverride
+
+	chStop chan struct{}
+	chDone chan struct{}
+
+	mu       sync.RWMutex
+	gasSpike bool
+}
+
+var _ ocrtypes.ConfigOverrider = (*TransmissionPolicy)(nil)
+
+// NewTransmissionPolicy returns a policy for spec, or nil if the spec does
+// not configure any. hibernation may be nil.
+func NewTransmissionPolicy(
+	logger *logger.Logger,
+	db *gorm.DB,
+	jobID int32,
+	spec job.OffchainReportingOracleSpec,
+	gasPricer gasPricer,
+	hibernation ocrtypes.ConfigOverrider,
+	status *jobStatus,
+	pollTicker utils.TickerBase,
+) (*TransmissionPolicy, error) {
+	if len(spec.TransmissionMaintenanceWindows) == 0 && spec.MaxTransmissionsPerHour == 0 && spec.GasSpikeGasPriceWei == nil {
+		return nil, nil
+	}
+	if err := ValidateTransmissionPolicy(spec); err != nil {
+		return nil, err
+	}
+	p := &TransmissionPolicy{
+		logger:      logger,
+		db:          db,
+		jobID:       jobID,
+		gasPricer:   gasPricer,
+		hibernation: hibernation,
+		status:      status,
+		pollTicker:  pollTicker,
+		maxPerHour:  spec.MaxTransmissionsPerHour,
+		chStop:      make(chan struct{}),
+		chDone:      make(chan struct{}),
+	}
+	for _, s := range spec.TransmissionMaintenanceWindows {
+		w, err := ParseMaintenanceWindow(s)
+		if err != nil {
+			return nil, err
+		}
+		p.windows = append(p.windows, w)
+	}
+	if spec.GasSpikeGasPriceWei != nil {
+		p.gasSpikePrice = spec.GasSpikeGasPriceWei.ToInt()
+		p.gasSpikeOverride = ocrtypes.ConfigOverride{
+			AlphaPPB: spec.GasSpikeAlphaPPB,
+			DeltaC:   spec.GasSpikeDeltaC.Duration(),
+		}
+	}
+	return p, nil
+}
+
+func (p *TransmissionPolicy) Start() error {
+	return p.StartOnce("OCRTransmissionPolicy", func() error {
+		if p.gasSpikePrice == nil {
+			close(p.chDone)
+			return nil
+		}
+		go p.eventLoop()
+		return nil
+	})
+}
+
+func (p *TransmissionPolicy) Close() error {
+	return p.StopOnce("OCRTransmissionPolicy", func() error {
+		close(p.chStop)
+		<-p.chDone
+		p.status.gasSpikeChanged(false)
+		return nil
+	})
+}
+
+func (p *TransmissionPolicy) eventLoop() {
+	defer close(p.chDone)
+	p.updateGasSpike()
+	p.pollTicker.Resume()
+	defer p.pollTicker.Destroy()
+	for {
+		select {
+		case <-p.chStop:
+			return
+		case <-p.pollTicker.Ticks():
+			p.updateGasSpike()
+		}
+	}
+}
+
+func (p *TransmissionPolicy) updateGasSpike() {
+	ctx, cancel := utils.ContextFromChan(p.chStop)
+	defer cancel()
+	ctx, cancelTimeout := context.WithTimeout(ctx, TransmissionPolicyPollInterval)
+	defer cancelTimeout()
+	gasPrice, err := p.gasPricer.SuggestGasPrice(ctx)
+	if err != nil {
+		p.logger.Errorw("OCRTransmissionPolicy: could not get gas price, keeping the previous gas spike state", "err", err)
+		return
+	}
+	spike := gasPrice.Cmp(p.gasSpikePrice) > 0
+
+	p.mu.Lock()
+	changed := spike != p.gasSpike
+	p.gasSpike = spike
+	p.mu.Unlock()
+
+	if changed {
+		p.logger.Infow(fmt.Sprintf("OCRTransmissionPolicy: setting gas spike state to '%v'", spike),
+			"gasPrice", gasPrice, "threshold", p.gasSpikePrice)
+		p.status.gasSpikeChanged(spike)
+	}
+}
+
+// ConfigOverride implements ocrtypes.ConfigOverrider
+func (p *TransmissionPolicy) ConfigOverride() *ocrtypes.ConfigOverride {
+	if p.hibernation != nil {
+		if override := p.hibernation.ConfigOverride(); override != nil {
+			return override
+		}
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.gasSpike {
+		override := p.gasSpikeOverride
+		return &override
+	}
+	return nil
+}
+
+// Allow decides whether a transmission may be sent now. It returns
+// PolicyAllowed or the reason the transmission is suppressed.
+func (p *TransmissionPolicy) Allow(ctx context.Context, now time.Time) (string, error) {
+	for _, w := range p.windows {
+		if w.Contains(now) {
+			return PolicyMaintenanceWindow, nil
+		}
+	}
+	if p.maxPerHour > 0 {
+		var count int64
+		err := p.db.WithContext(ctx).Raw(`
+SELECT count(*) FROM eth_txes
+WHERE eth_txes.meta->>'OCRConfigDigest' IS NOT NULL
+AND (eth_txes.meta->>'JobID')::int = ?
+AND eth_txes.created_at > ?
+`, p.jobID, now.Add(-time.Hour)).Scan(&count).Error
+		if err != nil {
+			return "", errors.Wrap(err, "OCRTransmissionPolicy: could not count recent transmissions")
+		}
+		if count >= int64(p.maxPerHour) {
+			return PolicyRateLimited, nil
+		}
+	}
+	return PolicyAllowed, nil
+}
@@ -31,17 +31,17 @@ func NewTransmitter(txm txManager, db *gorm.DB, fromAddress common.Address, gasL
 	}
 }
 
-func (t *transmitter) CreateEthTransaction(ctx context.Context, toAddress common.Address, payload []byte) error {
+func (t *transmitter) CreateEthTransaction(ctx context.Context, toAddress common.Address, payload []byte, meta *txmanager.EthTxMeta) (txmanager.EthTx, error) {
 	db := t.db.WithContext(ctx)
-	_, err := t.txm.CreateEthTransaction(db, txmanager.NewTx{
+	etx, err := t.txm.CreateEthTransaction(db, txmanager.NewTx{
 		FromAddress:    t.fromAddress,
 		ToAddress:      toAddress,
 		EncodedPayload: payload,
 		GasLimit:       t.gasLimit,
-		Meta:           nil,
+		Meta:           meta,
 		Strategy:       t.strategy,
 	})
-	return errors.Wrap(err, "Skipped OCR transmission")
+	return etx, errors.Wrap(err, "Skipped OCR transmission")
 }
 
 func (t *transmitter) FromAddress() common.Address {
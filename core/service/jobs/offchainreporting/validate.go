@@ -6,7 +6,6 @@ import (
 	"PhoenixOracle/core/chain"
 	"PhoenixOracle/core/service/job"
 	"PhoenixOracle/lib/libocr/offchainreporting"
-	"github.com/multiformats/go-multiaddr"
 	"github.com/pelletier/go-toml"
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
@@ -50,10 +49,14 @@ func ValidatedOracleSpecToml(config ValidationConfig, tomlString string) (job.Jo
 	if !tree.Has("isBootstrapPeer") {
 		return jb, errors.New("isBootstrapPeer is not defined")
 	}
-	for i := range spec.P2PBootstrapPeers {
-		if _, err := multiaddr.NewMultiaddr(spec.P2PBootstrapPeers[i]); err != nil {
-			return jb, errors.Wrapf(err, "p2p bootstrap peer %v is invalid", spec.P2PBootstrapPeers[i])
-		}
+	if err := ValidateBootstrapPeers(spec.P2PBootstrapPeers); err != nil {
+		return jb, err
+	}
+	if spec.P2PBootstrapPeerList != nil && *spec.P2PBootstrapPeerList == "" {
+		return jb, errors.New("p2pBootstrapPeerList must not be empty")
+	}
+	if spec.P2PBootstrapDNSName != nil && *spec.P2PBootstrapDNSName == "" {
+		return jb, errors.New("p2pBootstrapDNSName must not be empty")
 	}
 	if spec.IsBootstrapPeer {
 		if err := validateBootstrapSpec(tree, jb); err != nil {
@@ -65,6 +68,9 @@ func ValidatedOracleSpecToml(config ValidationConfig, tomlString string) (job.Jo
 	if err := validateTimingParameters(config, spec); err != nil {
 		return jb, err
 	}
+	if err := ValidateTransmissionPolicy(spec); err != nil {
+		return jb, err
+	}
 	return jb, nil
 }
 
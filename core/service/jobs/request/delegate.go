@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"time"
 
 	"PhoenixOracle/lib/postgres"
 
@@ -13,15 +14,26 @@ import (
 	"PhoenixOracle/core/service/ethereum"
 	"PhoenixOracle/core/service/job"
 	"PhoenixOracle/core/service/pipeline"
+	"PhoenixOracle/core/service/txmanager"
 	"PhoenixOracle/db/models"
 	"PhoenixOracle/internal/gethwrappers/generated/operator_wrapper"
+	"PhoenixOracle/lib/gas"
 	"PhoenixOracle/lib/logger"
 	"PhoenixOracle/util"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"gorm.io/gorm"
 )
 
+var promRejectedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "direct_request_rejected_requests",
+	Help: "Number of oracle requests rejected by the job's requesters and minimum payment or by the node's requester policies",
+},
+	[]string{"job_id", "job_name", "reason"},
+)
+
 type (
 	Delegate struct {
 		logger         *logger.Logger
@@ -30,6 +42,7 @@ type (
 		pipelineORM    pipeline.ORM
 		db             *gorm.DB
 		ethClient      ethereum.Client
+		txm            txmanager.TxManager
 		chHeads        chan models.Head
 		config         Config
 	}
@@ -48,6 +61,7 @@ func NewDelegate(
 	pipelineRunner pipeline.Runner,
 	pipelineORM pipeline.ORM,
 	ethClient ethereum.Client,
+	txm txmanager.TxManager,
 	db *gorm.DB,
 	config Config,
 ) *Delegate {
@@ -58,6 +72,7 @@ func NewDelegate(
 		pipelineORM,
 		db,
 		ethClient,
+		txm,
 		make(chan models.Head, 1),
 		config,
 	}
@@ -102,12 +117,14 @@ func (d *Delegate) ServicesForSpec(jobObj job.Job) ([]job.Service, error) {
 		config:                   d.config,
 		logBroadcaster:           d.logBroadcaster,
 		oracle:                   oracle,
+		gasEstimator:             d.txm.GetGasEstimator(),
 		pipelineRunner:           d.pipelineRunner,
 		db:                       d.db,
 		pipelineORM:              d.pipelineORM,
 		job:                      jobObj,
 		mbOracleRequests:         utils.NewHighCapacityMailbox(),
 		mbOracleCancelRequests:   utils.NewMailbox(50),
+		mbOracleResponses:        utils.NewHighCapacityMailbox(),
 		minIncomingConfirmations: uint64(minIncomingConfirmations),
 		requesters:               concreteSpec.Requesters,
 		minContractPayment:       concreteSpec.MinContractPayment,
@@ -129,6 +146,7 @@ type listener struct {
 	config                   Config
 	logBroadcaster           log.Broadcaster
 	oracle                   operator_wrapper.OperatorInterface
+	gasEstimator             gas.Estimator
 	pipelineRunner           pipeline.Runner
 	db                       *gorm.DB
 	pipelineORM              pipeline.ORM
@@ -137,6 +155,7 @@ type listener struct {
 	shutdownWaitGroup        sync.WaitGroup
 	mbOracleRequests         *utils.Mailbox
 	mbOracleCancelRequests   *utils.Mailbox
+	mbOracleResponses        *utils.Mailbox
 	minIncomingConfirmations uint64
 	requesters               models.AddressCollection
 	minContractPayment       *assets.Phb
@@ -152,12 +171,16 @@ func (l *listener) Start() error {
 			LogsWithTopics: map[common.Hash][][]log.Topic{
 				operator_wrapper.OperatorOracleRequest{}.Topic():       {{log.Topic(l.job.ExternalIDEncodeBytesToTopic()), log.Topic(l.job.ExternalIDEncodeStringToTopic())}},
 				operator_wrapper.OperatorCancelOracleRequest{}.Topic(): {{log.Topic(l.job.ExternalIDEncodeBytesToTopic()), log.Topic(l.job.ExternalIDEncodeStringToTopic())}},
+				// Responses are not indexed by job, the earnings ledger only
+				// matches those to requests of this job
+				operator_wrapper.OperatorOracleResponse{}.Topic(): nil,
 			},
 			NumConfirmations: l.minIncomingConfirmations,
 		})
-		l.shutdownWaitGroup.Add(3)
+		l.shutdownWaitGroup.Add(4)
 		go l.processOracleRequests()
 		go l.processCancelOracleRequests()
+		go l.processOracleResponses()
 
 		go func() {
 			<-l.chStop
@@ -203,6 +226,11 @@ func (l *listener) HandleLog(lb log.Broadcast) {
 		if wasOverCapacity {
 			l.logger.Error("DirectRequest: CancelOracleRequest log mailbox is over capacity - dropped the oldest log")
 		}
+	case *operator_wrapper.OperatorOracleResponse:
+		wasOverCapacity := l.mbOracleResponses.Deliver(lb)
+		if wasOverCapacity {
+			l.logger.Error("DirectRequest: OracleResponse log mailbox is over capacity - dropped the oldest log")
+		}
 	default:
 		l.logger.Warnf("DirectRequest: unexpected log type %T", log)
 	}
@@ -232,6 +260,18 @@ func (l *listener) processCancelOracleRequests() {
 	}
 }
 
+func (l *listener) processOracleResponses() {
+	for {
+		select {
+		case <-l.chStop:
+			l.shutdownWaitGroup.Done()
+			return
+		case <-l.mbOracleResponses.Notify():
+			l.handleReceivedLogs(l.mbOracleResponses)
+		}
+	}
+}
+
 func (l *listener) handleReceivedLogs(mailbox *utils.Mailbox) {
 	for {
 		i, exists := mailbox.Retrieve()
@@ -252,6 +292,12 @@ func (l *listener) handleReceivedLogs(mailbox *utils.Mailbox) {
 			return
 		}
 
+		// The first topic of a response is the request ID, not the job ID
+		if response, ok := lb.DecodedLog().(*operator_wrapper.OperatorOracleResponse); ok {
+			l.handleOracleResponse(response, lb)
+			continue
+		}
+
 		logJobSpecID := lb.RawLog().Topics[1]
 		if logJobSpecID == (common.Hash{}) || (logJobSpecID != l.job.ExternalIDEncodeStringToTopic() && logJobSpecID != l.job.ExternalIDEncodeBytesToTopic()) {
 			l.logger.Debugw("DirectRequest: Skipping Run for Log with wrong Job ID", "logJobSpecID", logJobSpecID)
@@ -303,33 +349,22 @@ func (l *listener) handleOracleRequest(request *operator_wrapper.OperatorOracleR
 		"data", fmt.Sprintf("%0x", request.Data),
 	)
 
-	if !l.allowRequester(request.Requester) {
-		l.logger.Infow("DirectRequest: Rejected run for invalid requester",
-			"requester", request.Requester,
-			"allowedRequesters", l.requesters.ToStrings(),
+	policy, decision, fields, err := l.checkRequest(request)
+	if err != nil {
+		// Not consumed, so that the request is considered again when the log
+		// is redelivered
+		l.logger.Errorw("DirectRequest: could not evaluate requester policy", "err", err, "requestId", formatRequestId(request.RequestId))
+		return
+	}
+	if decision != PolicyAllowed {
+		l.logger.Infow(fmt.Sprintf("DirectRequest: Rejected run: %s", decision),
+			append([]interface{}{"requester", request.Requester, "requestId", formatRequestId(request.RequestId)}, fields...)...,
 		)
+		promRejectedRequests.WithLabelValues(fmt.Sprintf("%d", l.job.ID), l.job.Name.ValueOrZero(), decision).Inc()
 		l.markLogConsumed(nil, lb)
 		return
 	}
 
-	var minContractPayment *assets.Phb
-	if l.minContractPayment != nil {
-		minContractPayment = l.minContractPayment
-	} else {
-		minContractPayment = l.config.MinimumContractPayment()
-	}
-	if minContractPayment != nil {
-		requestPayment := assets.Phb(*request.Payment)
-		if minContractPayment.Cmp(&requestPayment) > 0 {
-			l.logger.Infow("DirectRequest: Rejected run for insufficient payment",
-				"minContractPayment", minContractPayment.String(),
-				"requestPayment", requestPayment.String(),
-			)
-			l.markLogConsumed(nil, lb)
-			return
-		}
-	}
-
 	meta := make(map[string]interface{})
 	meta["oracleRequest"] = oracleRequestToMap(request)
 
@@ -358,8 +393,21 @@ func (l *listener) handleOracleRequest(request *operator_wrapper.OperatorOracleR
 		},
 	})
 	run := pipeline.NewRun(*l.job.PipelineSpec, vars)
-	_, err := l.pipelineRunner.Run(ctx, &run, *l.logger, true, func(tx *gorm.DB) error {
+	_, err = l.pipelineRunner.Run(ctx, &run, *l.logger, true, func(tx *gorm.DB) error {
 		l.markLogConsumed(tx, lb)
+		// The earnings ledger is bookkeeping, so failing to record the
+		// request must not stop the run. The savepoint keeps the run's
+		// transaction usable if it does fail. The rate limit counts the
+		// requests in the ledger though, so a rate limited request is not
+		// run, nor consumed, unless it is recorded.
+		err := tx.Transaction(func(tx *gorm.DB) error {
+			return recordRequest(tx, l.job.ID, request)
+		})
+		if err != nil && policy.MaxRequests > 0 {
+			return errors.Wrap(err, "unable to record rate limited request")
+		} else if err != nil {
+			l.logger.Errorw("DirectRequest: unable to record request", "err", err, "requestId", formatRequestId(request.RequestId))
+		}
 		return nil
 	})
 	if ctx.Err() != nil {
@@ -369,6 +417,62 @@ func (l *listener) handleOracleRequest(request *operator_wrapper.OperatorOracleR
 	}
 }
 
+// checkRequest applies the requesters and minimum payment of the job and the
+// node's requester policies to a request. It returns the effective policy of
+// the requester, and PolicyAllowed or the reason the request is rejected,
+// with fields to log.
+func (l *listener) checkRequest(request *operator_wrapper.OperatorOracleRequest) (policy RequesterPolicy, decision string, fields []interface{}, err error) {
+	if !l.allowRequester(request.Requester) {
+		return policy, PolicyNotAllowed, []interface{}{"allowedRequesters", l.requesters.ToStrings()}, nil
+	}
+
+	ctx, cancel := postgres.DefaultQueryCtx()
+	defer cancel()
+	policy, err = effectiveRequesterPolicy(l.db.WithContext(ctx), request.Requester)
+	if err != nil {
+		return policy, "", nil, err
+	}
+	if policy.Denied {
+		return policy, PolicyDenied, nil, nil
+	}
+
+	minContractPayment := l.config.MinimumContractPayment()
+	if l.minContractPayment != nil {
+		minContractPayment = l.minContractPayment
+	}
+	if policy.MinContractPayment != nil {
+		minContractPayment = policy.MinContractPayment
+	}
+	if policy.MinPaymentPerGwei != nil {
+		// The gas price the node would pay to fulfill the request now
+		if l.gasEstimator == nil {
+			return policy, "", nil, errors.New("could not get gas price: no gas estimator")
+		}
+		gasPrice, _, err := l.gasEstimator.EstimateGas(nil, 0)
+		if err != nil {
+			return policy, "", nil, errors.Wrap(err, "could not get gas price")
+		}
+		if gasMin := policy.gasPriceMinimumPayment(gasPrice); minContractPayment == nil || gasMin.Cmp(minContractPayment) > 0 {
+			minContractPayment = gasMin
+		}
+	}
+	requestPayment := assets.Phb(*request.Payment)
+	if minContractPayment != nil && minContractPayment.Cmp(&requestPayment) > 0 {
+		return policy, PolicyInsufficientPayment, []interface{}{"minContractPayment", minContractPayment.String(), "requestPayment", requestPayment.String()}, nil
+	}
+
+	if policy.MaxRequests > 0 {
+		count, err := countAcceptedRequests(l.db.WithContext(ctx), request.Requester, time.Now().Add(-policy.RateLimitPeriod.Duration()))
+		if err != nil {
+			return policy, "", nil, err
+		}
+		if count >= int64(policy.MaxRequests) {
+			return policy, PolicyRateLimited, []interface{}{"maxRequests", policy.MaxRequests, "rateLimitPeriod", policy.RateLimitPeriod.Duration()}, nil
+		}
+	}
+	return policy, PolicyAllowed, nil, nil
+}
+
 func (l *listener) allowRequester(requester common.Address) bool {
 	if len(l.requesters) == 0 {
 		return true
@@ -387,7 +491,28 @@ func (l *listener) handleCancelOracleRequest(request *operator_wrapper.OperatorC
 	if loaded {
 		close(runCloserChannelIf.(chan struct{}))
 	}
-	l.markLogConsumed(nil, lb)
+	ctx, cancel := postgres.DefaultQueryCtx()
+	defer cancel()
+	err := postgres.GormTransactionWithDefaultContext(l.db.WithContext(ctx), func(tx *gorm.DB) error {
+		l.markLogConsumed(tx, lb)
+		return recordCancellation(tx, l.job.ID, request)
+	})
+	if err != nil {
+		l.logger.Errorw("DirectRequest: unable to record cancelled request", "err", err, "requestId", formatRequestId(request.RequestId))
+	}
+}
+
+// Records the fulfillment of a request in the earnings ledger
+func (l *listener) handleOracleResponse(response *operator_wrapper.OperatorOracleResponse, lb log.Broadcast) {
+	ctx, cancel := postgres.DefaultQueryCtx()
+	defer cancel()
+	err := postgres.GormTransactionWithDefaultContext(l.db.WithContext(ctx), func(tx *gorm.DB) error {
+		l.markLogConsumed(tx, lb)
+		return recordFulfillment(tx, l.job.ID, response)
+	})
+	if err != nil {
+		l.logger.Errorw("DirectRequest: unable to record fulfilled request", "err", err, "requestId", formatRequestId(response.RequestId))
+	}
 }
 
 func (l *listener) markLogConsumed(db *gorm.DB, lb log.Broadcast) {
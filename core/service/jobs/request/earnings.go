@@ -0,0 +1,160 @@
+package request
+
+import (
+	"fmt"
+	"time"
+
+	"PhoenixOracle/core/assets"
+	"PhoenixOracle/internal/gethwrappers/generated/operator_wrapper"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// EarningsGroupBy is what direct request earnings are aggregated per, besides
+// the period
+type EarningsGroupBy string
+
+const (
+	EarningsGroupByJob       EarningsGroupBy = "job"
+	EarningsGroupByRequester EarningsGroupBy = "requester"
+)
+
+// ParseEarningsGroupBy validates a grouping passed to Earnings, defaulting to
+// EarningsGroupByJob
+func ParseEarningsGroupBy(s string) (EarningsGroupBy, error) {
+	switch EarningsGroupBy(s) {
+	case "", EarningsGroupByJob:
+		return EarningsGroupByJob, nil
+	case EarningsGroupByRequester:
+		return EarningsGroupByRequester, nil
+	default:
+		return "", errors.Errorf("invalid groupBy %q, must be %q or %q", s, EarningsGroupByJob, EarningsGroupByRequester)
+	}
+}
+
+// EarningsPeriod is the length of the periods earnings are aggregated over
+type EarningsPeriod string
+
+const (
+	EarningsPeriodDay   EarningsPeriod = "day"
+	EarningsPeriodMonth EarningsPeriod = "month"
+)
+
+// ParseEarningsPeriod validates a period passed to Earnings, defaulting to
+// EarningsPeriodDay
+func ParseEarningsPeriod(s string) (EarningsPeriod, error) {
+	switch EarningsPeriod(s) {
+	case "", EarningsPeriodDay:
+		return EarningsPeriodDay, nil
+	case EarningsPeriodMonth:
+		return EarningsPeriodMonth, nil
+	default:
+		return "", errors.Errorf("invalid period %q, must be %q or %q", s, EarningsPeriodDay, EarningsPeriodMonth)
+	}
+}
+
+// Earning aggregates the payments of the requests fulfilled during one
+// period, for one job or one requester depending on the grouping
+type Earning struct {
+	JobID       *int32
+	Requester   *common.Address
+	PeriodStart time.Time
+	Fulfilled   int
+	Earned      assets.Phb
+}
+
+// EarningsQuery selects the fulfilled requests to aggregate. JobID may be
+// zero to include all jobs, and Requester nil to include all requesters.
+type EarningsQuery struct {
+	JobID     int32
+	Requester *common.Address
+	From      time.Time
+	To        time.Time
+	GroupBy   EarningsGroupBy
+	Period    EarningsPeriod
+}
+
+type earningRow struct {
+	JobID       *int32
+	Requester   []byte
+	PeriodStart time.Time
+	Fulfilled   int
+	Earned      assets.Phb
+}
+
+// Earnings returns the number of requests fulfilled and the PHB earned per
+// job or requester and period, ordered by job or requester and period.
+// Requests are attributed to the period in which their fulfillment was seen.
+func Earnings(db *gorm.DB, q EarningsQuery) ([]Earning, error) {
+	column := "job_id"
+	if q.GroupBy == EarningsGroupByRequester {
+		column = "requester"
+	}
+	var requester []byte
+	if q.Requester != nil {
+		requester = q.Requester.Bytes()
+	}
+
+	var rows []earningRow
+	err := db.Raw(fmt.Sprintf(`
+SELECT %[1]s, date_trunc(?, fulfilled_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS period_start,
+	count(*) AS fulfilled, sum(payment) AS earned
+FROM direct_request_earnings
+WHERE fulfilled_at >= ? AND fulfilled_at < ?
+AND (? = 0 OR job_id = ?)
+AND (?::bytea IS NULL OR requester = ?)
+GROUP BY %[1]s, period_start
+ORDER BY %[1]s, period_start
+`, column), string(q.Period), q.From, q.To, q.JobID, q.JobID, requester, requester).Scan(&rows).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "Earnings failed to load earnings")
+	}
+
+	earnings := make([]Earning, len(rows))
+	for i, row := range rows {
+		earnings[i] = Earning{
+			PeriodStart: row.PeriodStart.UTC(),
+			Fulfilled:   row.Fulfilled,
+			Earned:      row.Earned,
+		}
+		if q.GroupBy == EarningsGroupByRequester {
+			addr := common.BytesToAddress(row.Requester)
+			earnings[i].Requester = &addr
+		} else {
+			earnings[i].JobID = row.JobID
+		}
+	}
+	return earnings, nil
+}
+
+// recordRequest adds a request to the ledger when its run starts. It earns
+// nothing until its fulfillment is seen.
+func recordRequest(tx *gorm.DB, jobID int32, request *operator_wrapper.OperatorOracleRequest) error {
+	err := tx.Exec(`
+INSERT INTO direct_request_earnings (job_id, contract_address, request_id, requester, payment, request_tx_hash, created_at)
+VALUES (?, ?, ?, ?, ?, ?, now())
+ON CONFLICT ON CONSTRAINT direct_request_earnings_request_key DO NOTHING`,
+		jobID, request.Raw.Address, request.RequestId[:], request.Requester, assets.Phb(*request.Payment), request.Raw.TxHash).Error
+	return errors.Wrap(err, "failed to record request")
+}
+
+// recordFulfillment marks a request of the job as fulfilled by the
+// transaction that emitted the OracleResponse
+func recordFulfillment(tx *gorm.DB, jobID int32, response *operator_wrapper.OperatorOracleResponse) error {
+	err := tx.Exec(`
+UPDATE direct_request_earnings SET fulfillment_tx_hash = ?, fulfilled_at = now()
+WHERE job_id = ? AND contract_address = ? AND request_id = ? AND fulfilled_at IS NULL AND cancelled_at IS NULL`,
+		response.Raw.TxHash, jobID, response.Raw.Address, response.RequestId[:]).Error
+	return errors.Wrap(err, "failed to record fulfillment")
+}
+
+// recordCancellation marks a request of the job as cancelled, so that it
+// never earns anything
+func recordCancellation(tx *gorm.DB, jobID int32, request *operator_wrapper.OperatorCancelOracleRequest) error {
+	err := tx.Exec(`
+UPDATE direct_request_earnings SET cancelled_at = now()
+WHERE job_id = ? AND contract_address = ? AND request_id = ? AND fulfilled_at IS NULL`,
+		jobID, request.Raw.Address, request.RequestId[:]).Error
+	return errors.Wrap(err, "failed to record cancellation")
+}
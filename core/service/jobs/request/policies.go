@@ -0,0 +1,185 @@
+package request
+
+import (
+	"database/sql"
+	"math/big"
+	"time"
+
+	"PhoenixOracle/core/assets"
+	"PhoenixOracle/core/keystore/keys/ethkey"
+	"PhoenixOracle/db/models"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// Requester policy decisions, as logged and reported in metrics
+const (
+	PolicyAllowed             = "allowed"
+	PolicyNotAllowed          = "not_allowed"
+	PolicyDenied              = "denied"
+	PolicyInsufficientPayment = "insufficient_payment"
+	PolicyRateLimited         = "rate_limited"
+)
+
+// RequesterPolicy is a node-level rule for the requests of one requester,
+// applied by every direct request job. The policy without a requester is the
+// default policy, which applies to all requesters. Denying by default and
+// adding policies for some requesters makes an allow-list.
+//
+// A requester's policy takes precedence over the default one: Denied always,
+// the minimum payments when set, and the rate limit when MaxRequests is not
+// zero or NoRateLimit is set. MinContractPayment overrides the minimum
+// payment of the job and of the node config, MinPaymentPerGwei raises the
+// minimum to that many juels per gwei of the current gas price, and at most
+// MaxRequests requests are accepted from the requester per RateLimitPeriod,
+// across all jobs. NoRateLimit exempts a requester from the default rate
+// limit.
+type RequesterPolicy struct {
+	ID                 int64
+	Requester          *ethkey.EIP55Address
+	Denied             bool
+	MinContractPayment *assets.Phb
+	MinPaymentPerGwei  *assets.Phb
+	MaxRequests        uint32
+	RateLimitPeriod    models.Interval
+	NoRateLimit        bool
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+func (RequesterPolicy) TableName() string {
+	return "direct_request_requester_policies"
+}
+
+// ValidateRequesterPolicy checks the fields of a policy are consistent
+func ValidateRequesterPolicy(p RequesterPolicy) error {
+	if p.Requester != nil && p.Requester.Address() == (common.Address{}) {
+		return errors.New("requester must not be the zero address, omit it for the default policy")
+	}
+	if (p.MaxRequests == 0) != (p.RateLimitPeriod.IsZero()) {
+		return errors.New("maxRequests and rateLimitPeriod must be set together")
+	}
+	if p.NoRateLimit && p.MaxRequests != 0 {
+		return errors.New("noRateLimit and maxRequests are mutually exclusive")
+	}
+	if p.RateLimitPeriod.Duration() < 0 {
+		return errors.New("rateLimitPeriod must not be negative")
+	}
+	for name, payment := range map[string]*assets.Phb{"minContractPayment": p.MinContractPayment, "minPaymentPerGwei": p.MinPaymentPerGwei} {
+		if payment != nil && payment.ToInt().Sign() < 0 {
+			return errors.Errorf("%s must not be negative", name)
+		}
+	}
+	return nil
+}
+
+// RequesterPolicies returns all policies, the default one first
+func RequesterPolicies(db *gorm.DB) ([]RequesterPolicy, error) {
+	var policies []RequesterPolicy
+	err := db.Raw(`SELECT * FROM direct_request_requester_policies ORDER BY requester NULLS FIRST`).Scan(&policies).Error
+	return policies, errors.Wrap(err, "RequesterPolicies failed to load policies")
+}
+
+// UpsertRequesterPolicy creates the policy of p.Requester, or the default
+// policy if p.Requester is nil, replacing any existing one
+func UpsertRequesterPolicy(db *gorm.DB, p *RequesterPolicy) error {
+	if err := ValidateRequesterPolicy(*p); err != nil {
+		return err
+	}
+	var requester, minContractPayment, minPaymentPerGwei interface{}
+	if p.Requester != nil {
+		requester = p.Requester.Address().Bytes()
+	}
+	if p.MinContractPayment != nil {
+		minContractPayment = *p.MinContractPayment
+	}
+	if p.MinPaymentPerGwei != nil {
+		minPaymentPerGwei = *p.MinPaymentPerGwei
+	}
+	err := db.Raw(`
+INSERT INTO direct_request_requester_policies (requester, denied, min_contract_payment, min_payment_per_gwei, max_requests, rate_limit_period, no_rate_limit, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, now(), now())
+ON CONFLICT ((coalesce(requester, '\x'::bytea))) DO UPDATE SET
+	denied = EXCLUDED.denied,
+	min_contract_payment = EXCLUDED.min_contract_payment,
+	min_payment_per_gwei = EXCLUDED.min_payment_per_gwei,
+	max_requests = EXCLUDED.max_requests,
+	rate_limit_period = EXCLUDED.rate_limit_period,
+	no_rate_limit = EXCLUDED.no_rate_limit,
+	updated_at = now()
+RETURNING *`,
+		requester, p.Denied, minContractPayment, minPaymentPerGwei, p.MaxRequests, p.RateLimitPeriod, p.NoRateLimit).Scan(p).Error
+	return errors.Wrap(err, "UpsertRequesterPolicy failed")
+}
+
+// DeleteRequesterPolicy deletes a policy by ID, returning sql.ErrNoRows if
+// there is none
+func DeleteRequesterPolicy(db *gorm.DB, id int64) error {
+	result := db.Exec(`DELETE FROM direct_request_requester_policies WHERE id = ?`, id)
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "DeleteRequesterPolicy failed")
+	}
+	if result.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// effectiveRequesterPolicy merges the policy of the requester, if there is
+// one, over the default policy
+func effectiveRequesterPolicy(db *gorm.DB, requester common.Address) (RequesterPolicy, error) {
+	var policies []RequesterPolicy
+	err := db.Raw(`SELECT * FROM direct_request_requester_policies WHERE requester = ? OR requester IS NULL ORDER BY requester NULLS FIRST`, requester.Bytes()).Scan(&policies).Error
+	if err != nil {
+		return RequesterPolicy{}, errors.Wrap(err, "failed to load requester policies")
+	}
+	return mergeRequesterPolicies(policies), nil
+}
+
+// mergeRequesterPolicies merges the requester policies over the default
+// policy, which is the one without a requester
+func mergeRequesterPolicies(policies []RequesterPolicy) RequesterPolicy {
+	var policy RequesterPolicy
+	for _, p := range policies {
+		if p.Requester == nil {
+			policy = p
+		}
+	}
+	for _, p := range policies {
+		if p.Requester == nil {
+			continue
+		}
+		policy.ID = p.ID
+		policy.Requester = p.Requester
+		policy.Denied = p.Denied
+		if p.MinContractPayment != nil {
+			policy.MinContractPayment = p.MinContractPayment
+		}
+		if p.MinPaymentPerGwei != nil {
+			policy.MinPaymentPerGwei = p.MinPaymentPerGwei
+		}
+		if p.MaxRequests != 0 || p.NoRateLimit {
+			policy.MaxRequests = p.MaxRequests
+			policy.RateLimitPeriod = p.RateLimitPeriod
+			policy.NoRateLimit = p.NoRateLimit
+		}
+	}
+	return policy
+}
+
+// gasPriceMinimumPayment is the minimum payment the policy requires at the
+// given gas price, in wei
+func (p RequesterPolicy) gasPriceMinimumPayment(gasPrice *big.Int) *assets.Phb {
+	min := new(big.Int).Mul(p.MinPaymentPerGwei.ToInt(), gasPrice)
+	return (*assets.Phb)(min.Quo(min, big.NewInt(1e9)))
+}
+
+// countAcceptedRequests counts the requests accepted from the requester by
+// any job since the given time, from the earnings ledger. Requests subject to
+// a rate limit are only accepted if they are recorded there, see
+// handleOracleRequest.
+func countAcceptedRequests(db *gorm.DB, requester common.Address, since time.Time) (count int64, err error) {
+	err = db.Raw(`SELECT count(*) FROM direct_request_earnings WHERE requester = ? AND created_at > ?`, requester.Bytes(), since).Scan(&count).Error
+	return count, errors.Wrap(err, "failed to count accepted requests")
+}
@@ -0,0 +1,172 @@
+package request
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"PhoenixOracle/core/assets"
+	"PhoenixOracle/core/keystore/keys/ethkey"
+	"PhoenixOracle/db/models"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestMergeRequesterPolicies(t *testing.T) {
+	requester := ethkey.EIP55AddressFromAddress(common.HexToAddress("0xaa"))
+	defaultPolicy := RequesterPolicy{
+		ID:                 1,
+		MinContractPayment: assets.NewPhb(100),
+		MinPaymentPerGwei:  assets.NewPhb(10),
+		MaxRequests:        5,
+		RateLimitPeriod:    models.Interval(time.Minute),
+	}
+	denyByDefault := RequesterPolicy{ID: 1, Denied: true}
+
+	tests := []struct {
+		name     string
+		policies []RequesterPolicy
+		expected RequesterPolicy
+	}{
+		{"no policies", nil, RequesterPolicy{}},
+		{"default only", []RequesterPolicy{defaultPolicy}, defaultPolicy},
+		{
+			"requester only",
+			[]RequesterPolicy{{ID: 2, Requester: &requester, MaxRequests: 1, RateLimitPeriod: models.Interval(time.Hour)}},
+			RequesterPolicy{ID: 2, Requester: &requester, MaxRequests: 1, RateLimitPeriod: models.Interval(time.Hour)},
+		},
+		{
+			"requester inherits unset fields",
+			[]RequesterPolicy{defaultPolicy, {ID: 2, Requester: &requester}},
+			RequesterPolicy{
+				ID:                 2,
+				Requester:          &requester,
+				MinContractPayment: assets.NewPhb(100),
+				MinPaymentPerGwei:  assets.NewPhb(10),
+				MaxRequests:        5,
+				RateLimitPeriod:    models.Interval(time.Minute),
+			},
+		},
+		{
+			"requester overrides set fields",
+			[]RequesterPolicy{defaultPolicy, {
+				ID:                 2,
+				Requester:          &requester,
+				MinContractPayment: assets.NewPhb(0),
+				MinPaymentPerGwei:  assets.NewPhb(20),
+				MaxRequests:        50,
+				RateLimitPeriod:    models.Interval(time.Hour),
+			}},
+			RequesterPolicy{
+				ID:                 2,
+				Requester:          &requester,
+				MinContractPayment: assets.NewPhb(0),
+				MinPaymentPerGwei:  assets.NewPhb(20),
+				MaxRequests:        50,
+				RateLimitPeriod:    models.Interval(time.Hour),
+			},
+		},
+		{
+			"requester exempted from default rate limit",
+			[]RequesterPolicy{defaultPolicy, {ID: 2, Requester: &requester, NoRateLimit: true}},
+			RequesterPolicy{
+				ID:                 2,
+				Requester:          &requester,
+				MinContractPayment: assets.NewPhb(100),
+				MinPaymentPerGwei:  assets.NewPhb(10),
+				NoRateLimit:        true,
+			},
+		},
+		{
+			"allow-list lets listed requester through",
+			[]RequesterPolicy{denyByDefault, {ID: 2, Requester: &requester}},
+			RequesterPolicy{ID: 2, Requester: &requester},
+		},
+		{
+			"requester denied over default",
+			[]RequesterPolicy{defaultPolicy, {ID: 2, Requester: &requester, Denied: true}},
+			RequesterPolicy{
+				ID:                 2,
+				Requester:          &requester,
+				Denied:             true,
+				MinContractPayment: assets.NewPhb(100),
+				MinPaymentPerGwei:  assets.NewPhb(10),
+				MaxRequests:        5,
+				RateLimitPeriod:    models.Interval(time.Minute),
+			},
+		},
+		{
+			"default after requester",
+			[]RequesterPolicy{{ID: 2, Requester: &requester}, denyByDefault},
+			RequesterPolicy{ID: 2, Requester: &requester},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeRequesterPolicies(tt.policies)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+
+	t.Run("unlisted requester is denied by default", func(t *testing.T) {
+		if got := mergeRequesterPolicies([]RequesterPolicy{denyByDefault}); !got.Denied {
+			t.Errorf("expected unlisted requester to be denied, got %+v", got)
+		}
+	})
+}
+
+func TestValidateRequesterPolicy(t *testing.T) {
+	requester := ethkey.EIP55AddressFromAddress(common.HexToAddress("0xaa"))
+	zero := ethkey.EIP55AddressFromAddress(common.Address{})
+	tests := []struct {
+		name   string
+		policy RequesterPolicy
+		valid  bool
+	}{
+		{"default policy", RequesterPolicy{MaxRequests: 5, RateLimitPeriod: models.Interval(time.Minute)}, true},
+		{"no rate limit", RequesterPolicy{Requester: &requester, NoRateLimit: true}, true},
+		{"zero requester", RequesterPolicy{Requester: &zero}, false},
+		{"max requests without period", RequesterPolicy{MaxRequests: 5}, false},
+		{"period without max requests", RequesterPolicy{RateLimitPeriod: models.Interval(time.Minute)}, false},
+		{"no rate limit with max requests", RequesterPolicy{Requester: &requester, NoRateLimit: true, MaxRequests: 5, RateLimitPeriod: models.Interval(time.Minute)}, false},
+		{"negative payment", RequesterPolicy{MinContractPayment: assets.NewPhb(-1)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRequesterPolicy(tt.policy)
+			if tt.valid && err != nil {
+				t.Errorf("expected policy to be valid, got %v", err)
+			} else if !tt.valid && err == nil {
+				t.Error("expected policy to be rejected")
+			}
+		})
+	}
+}
+
+func TestRequesterPolicy_GasPriceMinimumPayment(t *testing.T) {
+	gwei := big.NewInt(1e9)
+	tests := []struct {
+		name              string
+		minPaymentPerGwei int64
+		gasPrice          *big.Int
+		expected          int64
+	}{
+		{"one gwei", 10, gwei, 10},
+		{"many gwei", 10, new(big.Int).Mul(big.NewInt(150), gwei), 1500},
+		{"fraction of a gwei rounds down", 10, big.NewInt(1.5e9), 15},
+		{"below one juel", 1, big.NewInt(5e8), 0},
+		{"zero gas price", 10, big.NewInt(0), 0},
+		{"zero minimum", 0, gwei, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := RequesterPolicy{MinPaymentPerGwei: assets.NewPhb(tt.minPaymentPerGwei)}
+			got := p.gasPriceMinimumPayment(tt.gasPrice)
+			if got.Cmp(assets.NewPhb(tt.expected)) != 0 {
+				t.Errorf("expected %d, got %s", tt.expected, got)
+			}
+		})
+	}
+}
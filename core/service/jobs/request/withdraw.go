@@ -0,0 +1,70 @@
+package request
+
+import (
+	"context"
+
+	"PhoenixOracle/core/assets"
+	"PhoenixOracle/core/keystore/keys/ethkey"
+	"PhoenixOracle/core/service/ethereum"
+	"PhoenixOracle/internal/gethwrappers/generated/operator_wrapper"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+var operatorABI = ethereum.MustGetABI(operator_wrapper.OperatorABI)
+
+// WithdrawRequest withdraws PHB earned by an Operator contract. Amount
+// defaults to everything withdrawable and FromAddress to the operator's
+// owner, which must be one of the node's keys.
+type WithdrawRequest struct {
+	ContractAddress ethkey.EIP55Address  `json:"contractAddress"`
+	Recipient       ethkey.EIP55Address  `json:"recipient"`
+	Amount          *assets.Phb          `json:"amount,omitempty"`
+	FromAddress     *ethkey.EIP55Address `json:"fromAddress,omitempty"`
+	GasLimit        uint64               `json:"gasLimit"`
+}
+
+// WithdrawArgs are the checked arguments and ABI encoded calldata of a
+// withdraw call
+type WithdrawArgs struct {
+	Owner        common.Address
+	Withdrawable assets.Phb
+	Amount       assets.Phb
+	Calldata     []byte
+}
+
+// BuildWithdraw checks the amount of a withdrawal against what the operator
+// contract reports as withdrawable, and encodes the withdraw call
+func BuildWithdraw(ctx context.Context, client ethereum.Client, req WithdrawRequest) (args WithdrawArgs, err error) {
+	if req.Recipient.Address() == (common.Address{}) {
+		return args, errors.New("recipient is required")
+	}
+	operator, err := operator_wrapper.NewOperator(req.ContractAddress.Address(), client)
+	if err != nil {
+		return args, errors.Wrapf(err, "failed to create an operator wrapper for address %s", req.ContractAddress)
+	}
+	opts := &bind.CallOpts{Context: ctx}
+	withdrawable, err := operator.Withdrawable(opts)
+	if err != nil {
+		return args, errors.Wrap(err, "failed to get withdrawable PHB")
+	}
+	args.Withdrawable = assets.Phb(*withdrawable)
+	if args.Owner, err = operator.Owner(opts); err != nil {
+		return args, errors.Wrap(err, "failed to get operator owner")
+	}
+
+	args.Amount = args.Withdrawable
+	if req.Amount != nil {
+		args.Amount = *req.Amount
+	}
+	if args.Amount.ToInt().Sign() <= 0 {
+		return args, errors.New("nothing to withdraw")
+	}
+	if args.Amount.Cmp(&args.Withdrawable) > 0 {
+		return args, errors.Errorf("amount %s exceeds withdrawable %s", args.Amount.String(), args.Withdrawable.String())
+	}
+
+	args.Calldata, err = operatorABI.Pack("withdraw", req.Recipient.Address(), args.Amount.ToInt())
+	return args, errors.Wrap(err, "failed to encode withdraw call")
+}
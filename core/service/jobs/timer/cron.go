@@ -2,60 +2,193 @@ package timer
 
 import (
 	"fmt"
+	mrand "math/rand"
+	"sync"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
 
 	"PhoenixOracle/core/service/job"
 	"PhoenixOracle/core/service/pipeline"
 	"PhoenixOracle/lib/logger"
+	"PhoenixOracle/lib/postgres"
 	"PhoenixOracle/util"
 )
 
+// MaxCatchUpExecutions bounds the number of missed executions a job with the
+// catchUp misfire policy runs on start. The most recent ones are kept.
+const MaxCatchUpExecutions = 100
+
+// Cron runs the pipeline of a cron job on its schedule. Execution times are
+// persisted on the spec, so that executions missed while the node was down
+// or the job was being restarted are handled by the job's misfire policy.
 type Cron struct {
-	cronRunner     *cron.Cron
 	logger         *logger.Logger
 	jobSpec        job.Job
+	schedule       cron.Schedule
 	pipelineRunner pipeline.Runner
+	db             *gorm.DB
 	chStop         chan struct{}
+	wgDone         sync.WaitGroup
+	utils.StartStopOnce
 }
 
 func NewCronFromJobSpec(
 	jobSpec job.Job,
 	pipelineRunner pipeline.Runner,
+	db *gorm.DB,
 ) (*Cron, error) {
 	cronLogger := logger.Default.With(
 		"jobID", jobSpec.ID,
 		"schedule", jobSpec.CronSpec.CronSchedule,
 	)
 
+	schedule, err := ParseSchedule(jobSpec.CronSpec.CronSchedule)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Cron{
-		cronRunner:     cronRunner(),
 		logger:         cronLogger,
 		jobSpec:        jobSpec,
+		schedule:       schedule,
 		pipelineRunner: pipelineRunner,
+		db:             db,
 		chStop:         make(chan struct{}),
 	}, nil
 }
 
+// ParseSchedule parses a cron schedule with an optional seconds field
+func ParseSchedule(schedule string) (cron.Schedule, error) {
+	parser := cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+	return parser.Parse(schedule)
+}
+
 func (cr *Cron) Start() error {
-	cr.logger.Debug("Cron: Starting")
+	return cr.StartOnce("Cron", func() error {
+		cr.logger.Debug("Cron: Starting")
+
+		from, err := cr.loadMisfireStart()
+		if err != nil {
+			return err
+		}
+
+		cr.wgDone.Add(1)
+		go cr.run(from)
+		return nil
+	})
+}
 
-	_, err := cr.cronRunner.AddFunc(cr.jobSpec.CronSpec.CronSchedule, cr.runPipeline)
+func (cr *Cron) Close() error {
+	return cr.StopOnce("Cron", func() error {
+		cr.logger.Debug("Cron: Closing")
+		close(cr.chStop)
+		cr.wgDone.Wait()
+		return nil
+	})
+}
+
+// loadMisfireStart returns the time after which executions count as missed:
+// the last execution, or just before the next planned one if the job never
+// ran. Jobs that have neither start without misfires.
+func (cr *Cron) loadMisfireStart() (time.Time, error) {
+	ctx, cancel := postgres.DefaultQueryCtx()
+	defer cancel()
+	var spec job.CronSpec
+	err := cr.db.WithContext(ctx).
+		Select("last_execution_at", "next_execution_at").
+		Where("id = ?", cr.jobSpec.CronSpec.ID).
+		First(&spec).Error
 	if err != nil {
-		cr.logger.Errorw(fmt.Sprintf("Error running cron job %d", cr.jobSpec.ID), "error", err, "schedule", cr.jobSpec.CronSpec.CronSchedule, "jobID", cr.jobSpec.ID)
-		return err
+		return time.Time{}, errors.Wrap(err, "Cron: could not load execution times")
+	}
+	switch {
+	case spec.LastExecutionAt != nil:
+		return *spec.LastExecutionAt, nil
+	case spec.NextExecutionAt != nil:
+		return spec.NextExecutionAt.Add(-time.Nanosecond), nil
+	default:
+		return time.Now(), nil
 	}
-	cr.cronRunner.Start()
-	return nil
 }
 
-func (cr *Cron) Close() error {
-	cr.logger.Debug("Cron: Closing")
-	cr.cronRunner.Stop()
-	return nil
+func (cr *Cron) run(from time.Time) {
+	defer cr.wgDone.Done()
+
+	for _, scheduledAt := range cr.misfires(from, time.Now()) {
+		select {
+		case <-cr.chStop:
+			return
+		default:
+		}
+		cr.runPipeline(scheduledAt)
+	}
+
+	for {
+		next := cr.schedule.Next(time.Now())
+		cr.saveNextExecution(next)
+
+		timer := time.NewTimer(time.Until(next) + cr.jitter())
+		select {
+		case <-cr.chStop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		cr.runPipeline(next)
+	}
+}
+
+// misfires returns the executions scheduled after from and up to now that
+// should still run according to the job's misfire policy
+func (cr *Cron) misfires(from, now time.Time) []time.Time {
+	var missed []time.Time
+	for t := cr.schedule.Next(from); !t.IsZero() && !t.After(now); t = cr.schedule.Next(t) {
+		missed = append(missed, t)
+		if len(missed) > MaxCatchUpExecutions {
+			missed = missed[1:]
+		}
+	}
+	if len(missed) == 0 {
+		return nil
+	}
+
+	switch cr.jobSpec.CronSpec.MisfirePolicy {
+	case job.CronMisfireCatchUp:
+		cr.logger.Infow(fmt.Sprintf("Cron: catching up %d missed executions", len(missed)), "since", from)
+		return missed
+	case job.CronMisfireRunOnce:
+		cr.logger.Infow("Cron: running once for missed executions", "since", from, "missed", len(missed))
+		return missed[len(missed)-1:]
+	default:
+		cr.logger.Infow("Cron: skipping missed executions", "since", from, "missed", len(missed))
+		return nil
+	}
 }
 
-func (cr *Cron) runPipeline() {
+// jitter returns a random delay of less than the job's jitter
+func (cr *Cron) jitter() time.Duration {
+	jitter := cr.jobSpec.CronSpec.Jitter.Duration()
+	if jitter <= 0 {
+		return 0
+	}
+	return time.Duration(mrand.Int63n(int64(jitter)))
+}
+
+func (cr *Cron) saveNextExecution(next time.Time) {
+	ctx, cancel := postgres.DefaultQueryCtx()
+	defer cancel()
+	err := cr.db.WithContext(ctx).
+		Exec(`UPDATE cron_specs SET next_execution_at = ? WHERE id = ?`, next, cr.jobSpec.CronSpec.ID).
+		Error
+	if err != nil {
+		cr.logger.Errorw("Cron: could not save next execution time", "error", err)
+	}
+}
+
+func (cr *Cron) runPipeline(scheduledAt time.Time) {
 	ctx, cancel := utils.ContextFromChan(cr.chStop)
 	defer cancel()
 
@@ -66,18 +199,19 @@ func (cr *Cron) runPipeline() {
 			"name":          cr.jobSpec.Name.ValueOrZero(),
 		},
 		"jobRun": map[string]interface{}{
-			"meta": map[string]interface{}{},
+			"meta":        map[string]interface{}{},
+			"scheduledAt": scheduledAt,
 		},
 	})
 
 	run := pipeline.NewRun(*cr.jobSpec.PipelineSpec, vars)
 
-	_, err := cr.pipelineRunner.Run(ctx, &run, *cr.logger, false, nil)
+	// The execution is recorded with the run, so that it is not run again as a
+	// misfire after a restart
+	_, err := cr.pipelineRunner.Run(ctx, &run, *cr.logger, false, func(tx *gorm.DB) error {
+		return tx.Exec(`UPDATE cron_specs SET last_execution_at = ? WHERE id = ?`, scheduledAt, cr.jobSpec.CronSpec.ID).Error
+	})
 	if err != nil {
-		cr.logger.Errorf("Error executing new run for jobSpec ID %v", cr.jobSpec.ID)
+		cr.logger.Errorw(fmt.Sprintf("Error executing new run for jobSpec ID %v", cr.jobSpec.ID), "error", err, "scheduledAt", scheduledAt)
 	}
 }
-
-func cronRunner() *cron.Cron {
-	return cron.New(cron.WithSeconds())
-}
@@ -2,6 +2,7 @@ package timer
 
 import (
 	"github.com/pkg/errors"
+	"gorm.io/gorm"
 
 	"PhoenixOracle/core/service/job"
 	"PhoenixOracle/core/service/pipeline"
@@ -9,13 +10,15 @@ import (
 
 type Delegate struct {
 	pipelineRunner pipeline.Runner
+	db             *gorm.DB
 }
 
 var _ job.Delegate = (*Delegate)(nil)
 
-func NewDelegate(pipelineRunner pipeline.Runner) *Delegate {
+func NewDelegate(pipelineRunner pipeline.Runner, db *gorm.DB) *Delegate {
 	return &Delegate{
 		pipelineRunner: pipelineRunner,
+		db:             db,
 	}
 }
 
@@ -34,7 +37,7 @@ func (d *Delegate) ServicesForSpec(spec job.Job) (services []job.Service, err er
 		return nil, errors.Errorf("services.Delegate expects a *jobSpec.CronSpec to be present, got %v", spec)
 	}
 
-	cron, err := NewCronFromJobSpec(spec, d.pipelineRunner)
+	cron, err := NewCronFromJobSpec(spec, d.pipelineRunner, d.db)
 	if err != nil {
 		return nil, err
 	}
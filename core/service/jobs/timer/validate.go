@@ -1,6 +1,9 @@
 package timer
 
 import (
+	"strings"
+	"time"
+
 	"github.com/pelletier/go-toml"
 	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
@@ -37,6 +40,59 @@ func ValidatedCronSpec(tomlString string) (job.Job, error) {
 	if err := utils.ValidateCronSchedule(spec.CronSchedule); err != nil {
 		return jb, errors.Wrapf(err, "while validating cron schedule '%v'", spec.CronSchedule)
 	}
+	if err := validateTimeZone(spec.CronSchedule); err != nil {
+		return jb, err
+	}
+
+	switch spec.MisfirePolicy {
+	case "":
+		spec.MisfirePolicy = job.CronMisfireSkip
+	case job.CronMisfireSkip, job.CronMisfireRunOnce, job.CronMisfireCatchUp:
+	default:
+		return jb, errors.Errorf("invalid misfirePolicy %q, expected one of %s, %s or %s",
+			spec.MisfirePolicy, job.CronMisfireSkip, job.CronMisfireRunOnce, job.CronMisfireCatchUp)
+	}
+
+	if err := validateJitter(spec); err != nil {
+		return jb, err
+	}
 
 	return jb, nil
 }
+
+// validateTimeZone checks the CRON_TZ prefix of a schedule names a time zone
+// known to the node, e.g. CRON_TZ=Europe/Berlin
+func validateTimeZone(schedule string) error {
+	if !strings.HasPrefix(schedule, "CRON_TZ=") {
+		return nil
+	}
+	fields := strings.Fields(strings.TrimPrefix(schedule, "CRON_TZ="))
+	if len(fields) == 0 {
+		return errors.New("cron schedule is missing a time zone after CRON_TZ=")
+	}
+	if _, err := time.LoadLocation(fields[0]); err != nil {
+		return errors.Wrapf(err, "invalid time zone %q in cron schedule", fields[0])
+	}
+	return nil
+}
+
+// validateJitter checks the jitter is shorter than the time between two
+// executions, so that jittered executions do not overlap
+func validateJitter(spec job.CronSpec) error {
+	jitter := spec.Jitter.Duration()
+	if jitter < 0 {
+		return errors.New("jitter must not be negative")
+	}
+	if jitter == 0 {
+		return nil
+	}
+	schedule, err := ParseSchedule(spec.CronSchedule)
+	if err != nil {
+		return err
+	}
+	next := schedule.Next(time.Now())
+	if interval := schedule.Next(next).Sub(next); jitter >= interval {
+		return errors.Errorf("jitter of %s must be shorter than the %s between executions", jitter, interval)
+	}
+	return nil
+}
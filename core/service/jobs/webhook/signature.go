@@ -0,0 +1,122 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// SignatureHeader carries the hex encoded HMAC-SHA256 of a signed webhook
+	// request, keyed by the job's signing secret, over the timestamp, a dot
+	// and the body. It may be prefixed by "sha256=".
+	SignatureHeader = "X-Webhook-Signature"
+	// TimestampHeader carries the unix time in seconds at which a signed
+	// webhook request was signed
+	TimestampHeader = "X-Webhook-Timestamp"
+
+	// MinSigningSecretLength is the minimum length of a webhook job's signing
+	// secret
+	MinSigningSecretLength = 32
+	// SignatureTolerance is how far the timestamp of a signed webhook request
+	// may be from the node's clock
+	SignatureTolerance = 5 * time.Minute
+)
+
+var (
+	ErrJobNotSigned              = errors.New("job does not accept signed requests")
+	ErrSignatureMissing          = errors.Errorf("missing %s or %s header", SignatureHeader, TimestampHeader)
+	ErrSignatureInvalid          = errors.New("invalid signature")
+	ErrSignatureTimestampExpired = errors.New("signature timestamp is outside the tolerance")
+	ErrSignatureReplayed         = errors.New("signature has already been used")
+)
+
+// SignedRequest is a request to run a webhook job authenticated by the job's
+// signing secret rather than by a user or an external initiator
+type SignedRequest struct {
+	Timestamp string
+	Signature string
+	Body      []byte
+}
+
+// Sign returns the signature of a request body at the given time, as expected
+// in the SignatureHeader
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	return "sha256=" + hex.EncodeToString(mac(secret, strconv.FormatInt(timestamp.Unix(), 10), body))
+}
+
+func mac(secret, timestamp string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(timestamp))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// VerifySignedRequest checks a signed request was signed with the signing
+// secret of the webhook job, within SignatureTolerance of now, and has not
+// been seen before. The signature is recorded, so a request only verifies
+// once.
+func VerifySignedRequest(ctx context.Context, db *gorm.DB, jobUUID uuid.UUID, req SignedRequest, now time.Time) error {
+	if req.Timestamp == "" || req.Signature == "" {
+		return ErrSignatureMissing
+	}
+	ts, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		return errors.Wrap(ErrSignatureInvalid, "timestamp is not a unix time")
+	}
+	if skew := now.Sub(time.Unix(ts, 0)); skew > SignatureTolerance || skew < -SignatureTolerance {
+		return ErrSignatureTimestampExpired
+	}
+	signature, err := hex.DecodeString(strings.TrimPrefix(req.Signature, "sha256="))
+	if err != nil {
+		return errors.Wrap(ErrSignatureInvalid, "signature is not hex encoded")
+	}
+
+	var spec struct {
+		ID            int32
+		SigningSecret sql.NullString
+	}
+	err = db.WithContext(ctx).Raw(`
+SELECT webhook_specs.id, webhook_specs.signing_secret FROM jobs
+JOIN webhook_specs ON jobs.webhook_spec_id = webhook_specs.id
+WHERE jobs.external_job_id = ?`, jobUUID).Scan(&spec).Error
+	if err != nil {
+		return errors.Wrap(err, "could not load webhook spec")
+	}
+	if spec.ID == 0 || !spec.SigningSecret.Valid {
+		return ErrJobNotSigned
+	}
+	if !hmac.Equal(signature, mac(spec.SigningSecret.String, req.Timestamp, req.Body)) {
+		return ErrSignatureInvalid
+	}
+
+	// A signature stays valid until its timestamp is SignatureTolerance in the
+	// past, which is at most twice the tolerance after it was received
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`DELETE FROM webhook_signed_requests WHERE webhook_spec_id = ? AND received_at < ?`,
+			spec.ID, now.Add(-2*SignatureTolerance)).Error
+		if err != nil {
+			return errors.Wrap(err, "could not prune signed requests")
+		}
+		result := tx.Exec(`
+INSERT INTO webhook_signed_requests (webhook_spec_id, signature, received_at)
+VALUES (?, ?, ?) ON CONFLICT DO NOTHING`, spec.ID, signature, now)
+		if result.Error != nil {
+			return errors.Wrap(result.Error, "could not record signed request")
+		}
+		if result.RowsAffected == 0 {
+			return ErrSignatureReplayed
+		}
+		return nil
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/pelletier/go-toml"
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
+	"gopkg.in/guregu/null.v4"
 
 	"PhoenixOracle/core/service/job"
 	"PhoenixOracle/db/models"
@@ -18,6 +19,7 @@ type TOMLWebhookSpecExternalInitiator struct {
 
 type TOMLWebhookSpec struct {
 	ExternalInitiators []TOMLWebhookSpecExternalInitiator `toml:"externalInitiators"`
+	SigningSecret      string                             `toml:"signingSecret"`
 }
 
 func ValidatedWebhookSpec(tomlString string, externalInitiatorManager ExternalInitiatorManager) (jb job.Job, err error) {
@@ -59,8 +61,17 @@ func ValidatedWebhookSpec(tomlString string, externalInitiatorManager ExternalIn
 		return jb, err
 	}
 
+	var signingSecret null.String
+	if tree.Has("signingSecret") {
+		if len(tomlSpec.SigningSecret) < MinSigningSecretLength {
+			return jb, errors.Errorf("signingSecret must be at least %d characters long", MinSigningSecretLength)
+		}
+		signingSecret = null.StringFrom(tomlSpec.SigningSecret)
+	}
+
 	jb.WebhookSpec = &job.WebhookSpec{
 		ExternalInitiatorWebhookSpecs: externalInitiatorWebhookSpecs,
+		SigningSecret:                 signingSecret,
 	}
 
 	return jb, nil
@@ -12,14 +12,20 @@ import (
 	"sync"
 	"syscall"
 
+	"PhoenixOracle/build/static"
 	"PhoenixOracle/core/chain/evm"
 	"PhoenixOracle/core/keystore"
+	"PhoenixOracle/core/keystore/signer"
 	"PhoenixOracle/core/log"
 	"PhoenixOracle/core/service"
+	"PhoenixOracle/core/service/alerting"
 	"PhoenixOracle/core/service/balancemonitor"
 	"PhoenixOracle/core/service/ethereum"
 	"PhoenixOracle/core/service/feedmanager"
+	"PhoenixOracle/core/service/ha"
 	"PhoenixOracle/core/service/job"
+	"PhoenixOracle/core/service/jobs/blocktrigger"
+	"PhoenixOracle/core/service/jobs/eventtrigger"
 	"PhoenixOracle/core/service/jobs/fluxmonitor"
 	"PhoenixOracle/core/service/jobs/offchainreporting"
 	"PhoenixOracle/core/service/jobs/request"
@@ -57,6 +63,7 @@ type Application interface {
 	Stop() error
 	GetLogger() *loggerPkg.Logger
 	GetHealthChecker() health.Checker
+	GetLease() ha.Lease
 	GetStore() *strpkg.Store
 	GetEthClient() ethereum.Client
 	GetConfig() config.GeneralConfig
@@ -82,6 +89,10 @@ type Application interface {
 	SetServiceLogger(ctx context.Context, service string, level zapcore.Level) error
 
 	GetFeedsService() feedmanager.Service
+	GetAlertingService() alerting.Service
+	GetOCRStatusRegistry() *offchainreporting.StatusRegistry
+	GetTxManager() txmanager.TxManager
+	GetPeerWrapper() *offchainreporting.SingletonPeerWrapper
 
 	ReplayFromBlock(number uint64) error
 }
@@ -110,13 +121,19 @@ type PhoenixApplication struct {
 	shutdownOnce             sync.Once
 	shutdownSignal           gracefulpanic.Signal
 	balanceMonitor           balancemonitor.BalanceMonitor
+	alertingService          alerting.Service
+	ocrStatusRegistry        *offchainreporting.StatusRegistry
+	peerWrapper              *offchainreporting.SingletonPeerWrapper
 	explorerClient           synchronization.ExplorerClient
 	subservices              []service.Service
 	HealthChecker            health.Checker
+	Lease                    ha.Lease
 	logger                   *loggerPkg.Logger
 
-	started     bool
-	startStopMu sync.Mutex
+	started         bool
+	servicesStarted bool
+	startStopMu     sync.Mutex
+	chStop          chan struct{}
 }
 
 func NewApplication(logger *loggerPkg.Logger, cfg config.EVMConfig, ethClient ethereum.Client, advisoryLocker postgres.AdvisoryLocker) (Application, error) {
@@ -130,9 +147,27 @@ func NewApplication(logger *loggerPkg.Logger, cfg config.EVMConfig, ethClient et
 
 	scryptParams := utils.GetScryptParams(cfg)
 	keyStore := keystore.New(store.DB, scryptParams)
+	if url := cfg.ExternalSignerClefURL(); url != "" {
+		keyStore.Eth().SetExternalSigner(signer.TypeClef, signer.NewClefSigner(url))
+	}
+	if module := cfg.ExternalSignerPKCS11Module(); module != "" {
+		hsm, err := signer.NewPKCS11Signer(module, cfg.ExternalSignerPKCS11TokenLabel(), cfg.ExternalSignerPKCS11Pin())
+		if err != nil {
+			return nil, err
+		}
+		keyStore.Eth().SetExternalSigner(signer.TypePKCS11, hsm)
+	}
 
 	setupConfig(cfg, store.DB, keyStore)
 
+	// In HA mode, services only start once this node holds the leader lease,
+	// and transactions are only broadcast while it does
+	var lease ha.Lease
+	if cfg.HAEnabled() {
+		lease = ha.NewLease(store.DB, cfg, static.InstanceUUID, logger)
+		ethClient = ha.NewFencedClient(ethClient, lease)
+	}
+
 	var subservices []service.Service
 
 	telemetryIngressClient := synchronization.TelemetryIngressClient(&synchronization.NoopTelemetryIngressClient{})
@@ -210,6 +245,9 @@ func NewApplication(logger *loggerPkg.Logger, cfg config.EVMConfig, ethClient et
 	}
 	subservices = append(subservices, balanceMonitor)
 
+	alertingService := alerting.NewService(alerting.NewORM(store.DB), ethClient, keyStore.Eth(), balanceMonitor, cfg, logger)
+	subservices = append(subservices, alertingService)
+
 	promReporter := service.NewPromReporter(store.MustSQLDB())
 	subservices = append(subservices, promReporter)
 
@@ -230,9 +268,23 @@ func NewApplication(logger *loggerPkg.Logger, cfg config.EVMConfig, ethClient et
 				pipelineRunner,
 				pipelineORM,
 				ethClient,
+				txManager,
 				store.DB,
 				cfg,
 			),
+			job.EventTrigger: eventtrigger.NewDelegate(
+				logger,
+				logBroadcaster,
+				pipelineRunner,
+				store.DB,
+				cfg,
+			),
+			job.BlockTrigger: blocktrigger.NewDelegate(
+				logger,
+				headBroadcaster,
+				pipelineRunner,
+				store.DB,
+			),
 			job.VRF: vrf.NewDelegate(
 				store.DB,
 				txManager,
@@ -271,10 +323,13 @@ func NewApplication(logger *loggerPkg.Logger, cfg config.EVMConfig, ethClient et
 		)
 	}
 
+	ocrStatusRegistry := offchainreporting.NewStatusRegistry(sqlxDB.DB)
+	var peerWrapper *offchainreporting.SingletonPeerWrapper
 	if (cfg.Dev() && cfg.P2PListenPort() > 0) || cfg.FeatureOffchainReporting() {
 		logger.Debug("Off-chain reporting enabled")
 		concretePW := offchainreporting.NewSingletonPeerWrapper(keyStore, cfg, store.DB)
 		subservices = append(subservices, concretePW)
+		peerWrapper = concretePW
 		delegates[job.OffchainReporting] = offchainreporting.NewDelegate(
 			store.DB,
 			txManager,
@@ -288,6 +343,7 @@ func NewApplication(logger *loggerPkg.Logger, cfg config.EVMConfig, ethClient et
 			monitoringEndpointGen,
 			cfg.Chain(),
 			headBroadcaster,
+			ocrStatusRegistry,
 		)
 	} else {
 		logger.Debug("Off-chain reporting disabled")
@@ -303,7 +359,7 @@ func NewApplication(logger *loggerPkg.Logger, cfg config.EVMConfig, ethClient et
 	}
 
 	if cfg.Dev() || cfg.FeatureCronV2() {
-		delegates[job.Cron] = timer.NewDelegate(pipelineRunner)
+		delegates[job.Cron] = timer.NewDelegate(pipelineRunner, store.DB)
 	}
 
 	jobSpawner := job.NewSpawner(jobORM, cfg, delegates, gormTxm)
@@ -339,31 +395,33 @@ func NewApplication(logger *loggerPkg.Logger, cfg config.EVMConfig, ethClient et
 		ExternalInitiatorManager: externalInitiatorManager,
 		shutdownSignal:           shutdownSignal,
 		balanceMonitor:           balanceMonitor,
+		alertingService:          alertingService,
+		ocrStatusRegistry:        ocrStatusRegistry,
+		peerWrapper:              peerWrapper,
 		explorerClient:           explorerClient,
 		HealthChecker:            healthChecker,
+		Lease:                    lease,
 		HeadTracker:              headTracker,
 		logger:                   globalLogger,
 
 		subservices: subservices,
+		chStop:      make(chan struct{}),
 	}
 
 	headBroadcaster.Subscribe(logBroadcaster)
 	headBroadcaster.Subscribe(txManager)
 	headBroadcaster.Subscribe(promReporter)
 	headBroadcaster.Subscribe(balanceMonitor)
+	headBroadcaster.Subscribe(alertingService)
 
 	logBroadcaster.AddDependents(1)
 
-	for _, service := range app.subservices {
-		if err = app.HealthChecker.Register(reflect.TypeOf(service).String(), service); err != nil {
+	if lease != nil {
+		if err = app.HealthChecker.Register(reflect.TypeOf(lease).String(), lease); err != nil {
 			return nil, err
 		}
 	}
 
-	if err = app.HealthChecker.Register(reflect.TypeOf(headTracker).String(), headTracker); err != nil {
-		return nil, err
-	}
-
 	return app, nil
 }
 
@@ -398,13 +456,23 @@ func (app *PhoenixApplication) Start() error {
 
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	var chLeaseLost <-chan struct{}
+	if app.Lease != nil {
+		chLeaseLost = app.Lease.Lost()
+	}
 	go func() {
+		exitCode := 0
 		select {
 		case <-sigs:
 		case <-app.shutdownSignal.Wait():
+		case <-chLeaseLost:
+			// Exit non-zero so that supervisors treat it as a failure and
+			// restart the node as a standby
+			app.logger.Error("HA: lost the leader lease, shutting down")
+			exitCode = 1
 		}
 		app.logger.ErrorIf(app.Stop())
-		app.Exiter(0)
+		app.Exiter(exitCode)
 	}()
 
 	// EthClient must be dialed first because it is required in subtasks
@@ -416,10 +484,43 @@ func (app *PhoenixApplication) Start() error {
 		return err
 	}
 
+	if app.Lease != nil {
+		if err := app.Lease.Start(); err != nil {
+			return err
+		}
+		go app.startServicesWhenLeader()
+	} else if err := app.startServices(); err != nil {
+		return err
+	}
+
+	if err := app.HealthChecker.Start(); err != nil {
+		return err
+	}
+
+	app.started = true
+
+	return nil
+}
+
+func (app *PhoenixApplication) startServices() error {
 	if err := app.FeedsService.Start(); err != nil {
 		app.logger.Infof("[Feeds Service] %v", err)
 	}
 
+	// Services that started are stopped with the application even if a later
+	// one fails to start
+	app.servicesStarted = true
+
+	// Services are only checked once started, so that a standby is healthy
+	for _, service := range app.subservices {
+		if err := app.HealthChecker.Register(reflect.TypeOf(service).String(), service); err != nil {
+			return err
+		}
+	}
+	if err := app.HealthChecker.Register(reflect.TypeOf(app.HeadTracker).String(), app.HeadTracker); err != nil {
+		return err
+	}
+
 	for _, subservice := range app.subservices {
 		app.logger.Debugw("Starting service...", "serviceType", reflect.TypeOf(subservice))
 		if err := subservice.Start(); err != nil {
@@ -429,17 +530,33 @@ func (app *PhoenixApplication) Start() error {
 
 	app.LogBroadcaster.DependentReady()
 
-	if err := app.HeadTracker.Start(); err != nil {
-		return err
-	}
+	return app.HeadTracker.Start()
+}
 
-	if err := app.HealthChecker.Start(); err != nil {
-		return err
+// startServicesWhenLeader keeps the node a warm standby, with its keys
+// unlocked and its chains connected, until it acquires the leader lease and
+// then starts its services. A node that loses the lease exits with a non-zero
+// code (see Start), as its services cannot be restarted in place; it comes
+// back as a standby.
+func (app *PhoenixApplication) startServicesWhenLeader() {
+	select {
+	case <-app.Lease.Acquired():
+	case <-app.chStop:
+		return
 	}
 
-	app.started = true
-
-	return nil
+	app.startStopMu.Lock()
+	if !app.started {
+		app.startStopMu.Unlock()
+		return
+	}
+	app.logger.Info("HA: this node is now the leader, starting services")
+	err := app.startServices()
+	app.startStopMu.Unlock()
+	if err != nil {
+		app.logger.Errorw("HA: failed to start services after acquiring the leader lease", "error", err)
+		app.shutdownSignal.Panic()
+	}
 }
 
 func (app *PhoenixApplication) StopIfStarted() error {
@@ -474,25 +591,37 @@ func (app *PhoenixApplication) stop() error {
 		}()
 		app.logger.Info("Gracefully exiting...")
 
+		close(app.chStop)
+
 		// Stop services in the reverse order from which they were started
 
-		app.logger.Debug("Stopping HeadTracker...")
-		merr = multierr.Append(merr, app.HeadTracker.Stop())
+		if app.servicesStarted {
+			app.logger.Debug("Stopping HeadTracker...")
+			merr = multierr.Append(merr, app.HeadTracker.Stop())
 
-		for i := len(app.subservices) - 1; i >= 0; i-- {
-			service := app.subservices[i]
-			app.logger.Debugw("Closing service...", "serviceType", reflect.TypeOf(service))
-			merr = multierr.Append(merr, service.Close())
+			for i := len(app.subservices) - 1; i >= 0; i-- {
+				service := app.subservices[i]
+				app.logger.Debugw("Closing service...", "serviceType", reflect.TypeOf(service))
+				merr = multierr.Append(merr, service.Close())
+			}
 		}
 
 		app.logger.Debug("Stopping SessionReaper...")
 		merr = multierr.Append(merr, app.SessionReaper.Stop())
+		if app.Lease != nil {
+			// Released once services are stopped, so the standby takes over
+			// without waiting for the lease to lapse
+			app.logger.Debug("Closing HA Lease...")
+			merr = multierr.Append(merr, app.Lease.Close())
+		}
 		app.logger.Debug("Closing Store...")
 		merr = multierr.Append(merr, app.Store.Close())
 		app.logger.Debug("Closing HealthChecker...")
 		merr = multierr.Append(merr, app.HealthChecker.Close())
-		app.logger.Debug("Closing Feeds Service...")
-		merr = multierr.Append(merr, app.FeedsService.Close())
+		if app.servicesStarted {
+			app.logger.Debug("Closing Feeds Service...")
+			merr = multierr.Append(merr, app.FeedsService.Close())
+		}
 
 		app.logger.Info("Exited all services")
 
@@ -530,6 +659,11 @@ func (app *PhoenixApplication) GetHealthChecker() health.Checker {
 	return app.HealthChecker
 }
 
+// GetLease returns the HA leader lease, or nil if HA is disabled
+func (app *PhoenixApplication) GetLease() ha.Lease {
+	return app.Lease
+}
+
 func (app *PhoenixApplication) JobSpawner() job.Spawner {
 	return app.jobSpawner
 }
@@ -647,6 +781,24 @@ func (app *PhoenixApplication) GetFeedsService() feedmanager.Service {
 	return app.FeedsService
 }
 
+func (app *PhoenixApplication) GetAlertingService() alerting.Service {
+	return app.alertingService
+}
+
+func (app *PhoenixApplication) GetOCRStatusRegistry() *offchainreporting.StatusRegistry {
+	return app.ocrStatusRegistry
+}
+
+func (app *PhoenixApplication) GetTxManager() txmanager.TxManager {
+	return app.TxManager
+}
+
+// GetPeerWrapper returns the P2P peer of this node, or nil if off-chain
+// reporting is disabled
+func (app *PhoenixApplication) GetPeerWrapper() *offchainreporting.SingletonPeerWrapper {
+	return app.peerWrapper
+}
+
 // NewBox returns the packr.Box instance that holds the static assets to
 // be delivered by the router.
 func (app *PhoenixApplication) NewBox() packr.Box {
@@ -364,14 +364,17 @@ func (r *runner) executeTaskRun(ctx context.Context, spec Spec, taskRun *memoryT
 		"attempt", taskRun.attempts,
 	}
 
+	// The task timeouts shorten, but never extend, the deadline of the run
+	// so that callers such as OCR can collect partial results at their
+	// deadline
 	taskTimeout, isSet := taskRun.task.TaskTimeout()
 	if isSet {
 		var cancel context.CancelFunc
-		ctx, cancel = utils.CombinedContext(r.chStop, taskTimeout)
+		ctx, cancel = utils.CombinedContext(r.chStop, ctx, taskTimeout)
 		defer cancel()
 	} else if spec.MaxTaskDuration != models.Interval(time.Duration(0)) {
 		var cancel context.CancelFunc
-		ctx, cancel = utils.CombinedContext(r.chStop, time.Duration(spec.MaxTaskDuration))
+		ctx, cancel = utils.CombinedContext(r.chStop, ctx, time.Duration(spec.MaxTaskDuration))
 		defer cancel()
 	}
 
@@ -55,6 +55,8 @@ type Config interface {
 	EthTxReaperInterval() time.Duration
 	EthTxReaperThreshold() time.Duration
 	EthTxResendAfterThreshold() time.Duration
+	FeeHistoryEstimatorBlockCount() uint16
+	FeeHistoryEstimatorRewardPercentile() uint16
 	GasEstimatorMode() string
 	TriggerFallbackDBPollInterval() time.Duration
 }
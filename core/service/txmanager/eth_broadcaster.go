@@ -421,7 +421,7 @@ func saveAttempt(db *gorm.DB, etx *EthTx, attempt EthTxAttempt, newAttemptState
 }
 
 func (eb *EthBroadcaster) tryAgainBumpingGas(sendError *ethereum.SendError, etx EthTx, attempt EthTxAttempt, initialBroadcastAt time.Time) error {
-	bumpedGasPrice, bumpedGasLimit, err := eb.estimator.BumpGas(attempt.GasPrice.ToInt(), etx.GasLimit)
+	bumpedGasPrice, bumpedGasLimit, err := eb.estimator.BumpGas(etx.EncodedPayload, attempt.GasPrice.ToInt(), etx.GasLimit)
 	if err != nil {
 		return errors.Wrap(err, "tryAgainWithHigherGasPrice failed")
 	}
@@ -710,7 +710,7 @@ func (ec *EthConfirmer) attemptForRebroadcast(ctx context.Context, etx EthTx) (a
 			// TODO: Handle optimism case here
 			return previousAttempt, nil
 		}
-		bumpedGasPrice, bumpedGasLimit, err = ec.estimator.BumpGas(previousAttempt.GasPrice.ToInt(), etx.GasLimit)
+		bumpedGasPrice, bumpedGasLimit, err = ec.estimator.BumpGas(etx.EncodedPayload, previousAttempt.GasPrice.ToInt(), etx.GasLimit)
 		logFields := []interface{}{
 			"etxID", etx.ID,
 			"txHash", attempt.Hash,
@@ -756,7 +756,7 @@ func (ec *EthConfirmer) handleInProgressAttempt(ctx context.Context, etx EthTx,
 	sendError := sendTransaction(ctx, ec.ethClient, attempt, etx, ec.logger)
 
 	if sendError.IsTerminallyUnderpriced() {
-		bumpedGasPrice, bumpedGasLimit, err := ec.estimator.BumpGas(attempt.GasPrice.ToInt(), etx.GasLimit)
+		bumpedGasPrice, bumpedGasLimit, err := ec.estimator.BumpGas(etx.EncodedPayload, attempt.GasPrice.ToInt(), etx.GasLimit)
 		if err != nil {
 			return errors.Wrap(err, "could not bump gas for terminally underpriced transaction")
 		}
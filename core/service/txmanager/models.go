@@ -21,6 +21,15 @@ type EthTxMeta struct {
 	JobID         int32
 	RequestID     common.Hash
 	RequestTxHash common.Hash
+
+	// Set on VRF v2 batch fulfillments, which fulfill several requests
+	RequestIDs []common.Hash `json:",omitempty"`
+
+	// Set on OCR transmissions, so that their cost can be attributed to the
+	// round they reported on
+	OCRConfigDigest string `json:",omitempty"`
+	OCREpoch        uint32 `json:",omitempty"`
+	OCRRound        uint8  `json:",omitempty"`
 }
 
 func (EthTxMeta) GormDataType() string {
@@ -200,10 +200,17 @@ func GetStartingResponseCountsV2(db *gorm.DB, l *logger.Logger) map[string]uint6
 	}
 	// Allow any state, not just confirmed, on purpose.
 	// We assume once a ethtx is queued it will go through.
-	err := db.Raw(`SELECT meta->'RequestID' AS request_id, count(meta->'RequestID') as count
-			FROM eth_txes
-			WHERE meta->'RequestID' IS NOT NULL
-		    GROUP BY meta->'RequestID'`).Scan(&counts).Error
+	// Batch fulfillments count once for each of their requests.
+	err := db.Raw(`SELECT request_id, count(*) as count FROM (
+				SELECT meta->'RequestID' AS request_id
+				FROM eth_txes
+				WHERE meta->'RequestID' IS NOT NULL
+			UNION ALL
+				SELECT jsonb_array_elements(meta->'RequestIDs') AS request_id
+				FROM eth_txes
+				WHERE meta->'RequestIDs' IS NOT NULL
+			) AS request_ids
+		    GROUP BY request_id`).Scan(&counts).Error
 	if err != nil {
 		// Continue with an empty map, do not block job on this.
 		l.Errorw("VRFListenerV2: unable to read previous fulfillments", "err", err)
@@ -80,6 +80,9 @@ type listenerV2 struct {
 	respCountMu sync.Mutex
 	respCount   map[string]uint64
 	blockNumberToReqID *pairing.PairHeap
+
+	// batcher is nil unless batch fulfillment is enabled
+	batcher *requestBatcher
 }
 
 func (lsn *listenerV2) Start() error {
@@ -88,6 +91,11 @@ func (lsn *listenerV2) Start() error {
 		if lsn.job.VRFSpec.Confirmations > lsn.cfg.MinIncomingConfirmations() {
 			minConfs = lsn.job.VRFSpec.Confirmations
 		}
+		if lsn.batchFulfillmentEnabled() {
+			lsn.batcher = newRequestBatcher(lsn, lsn.l, lsn.job.VRFSpec.BatchFulfillmentSize, lsn.job.VRFSpec.BatchFulfillmentTimeout.Duration())
+			lsn.batcher.resumeSubmittedBatches()
+		}
+
 		unsubscribeLogs := lsn.logBroadcaster.Register(lsn, log.ListenerOpts{
 			Contract: lsn.coordinator.Address(),
 			ParseLog: lsn.coordinator.ParseLog,
@@ -200,10 +208,17 @@ func (lsn *listenerV2) runHeadListener(unsubscribe func()) {
 			return
 		case <-lsn.newHead:
 			toProcess := lsn.extractConfirmedLogs()
-			for _, r := range toProcess {
-				lsn.ProcessV2VRFRequest(r.req, r.lb)
+			if lsn.batcher != nil {
+				lsn.batcher.batchRequests(toProcess)
+				lsn.batcher.recheckBatchedRequests()
+			} else {
+				for _, r := range toProcess {
+					lsn.ProcessV2VRFRequest(r.req, r.lb)
+				}
 			}
 			lsn.pruneConfirmedRequestCounts()
+		case <-lsn.batcher.batchTimeout():
+			lsn.batcher.flushBatch()
 		}
 	}
 }
@@ -311,7 +326,15 @@ func (lsn *listenerV2) markLogAsConsumed(lb log.Broadcast) {
 }
 
 func (lsn *listenerV2) ProcessV2VRFRequest(req *vrf_coordinator_v2.VRFCoordinatorV2RandomWordsRequested, lb log.Broadcast) {
-	// Check if the vrf req has already been fulfilled
+	if lsn.alreadyFulfilled(req, lb) {
+		return
+	}
+	lsn.runPipeline(req, lb)
+}
+
+// alreadyFulfilled checks if the vrf req has already been fulfilled, and
+// marks its log consumed if so
+func (lsn *listenerV2) alreadyFulfilled(req *vrf_coordinator_v2.VRFCoordinatorV2RandomWordsRequested, lb log.Broadcast) bool {
 	callback, err := lsn.coordinator.GetCommitment(nil, req.RequestId)
 	if err != nil {
 		lsn.l.Errorw("VRFListenerV2: unable to check if already fulfilled, processing anyways", "err", err, "txHash", req.Raw.TxHash)
@@ -320,9 +343,13 @@ func (lsn *listenerV2) ProcessV2VRFRequest(req *vrf_coordinator_v2.VRFCoordinato
 		// and we should skip it
 		lsn.l.Infow("VRFListenerV2: request already fulfilled", "txHash", req.Raw.TxHash, "subID", req.SubId, "callback", callback)
 		lsn.markLogAsConsumed(lb)
-		return
+		return true
 	}
+	return false
+}
 
+// runPipeline fulfills the request individually through the job pipeline
+func (lsn *listenerV2) runPipeline(req *vrf_coordinator_v2.VRFCoordinatorV2RandomWordsRequested, lb log.Broadcast) {
 	lsn.l.Infow("VRFListenerV2: received log request",
 		"log", lb.String(),
 		"reqID", req.RequestId.String(),
@@ -347,9 +374,9 @@ func (lsn *listenerV2) ProcessV2VRFRequest(req *vrf_coordinator_v2.VRFCoordinato
 		},
 	})
 	run := pipeline.NewRun(*lsn.job.PipelineSpec, vars)
-	if _, err = lsn.pipelineRunner.Run(context.Background(), &run, lsn.l, true, func(tx *gorm.DB) error {
+	if _, err := lsn.pipelineRunner.Run(context.Background(), &run, lsn.l, true, func(tx *gorm.DB) error {
 		// Always mark consumed regardless of whether the proof failed or not.
-		if err = lsn.logBroadcaster.MarkConsumed(tx, lb); err != nil {
+		if err := lsn.logBroadcaster.MarkConsumed(tx, lb); err != nil {
 			logger.Errorw("VRFListenerV2: failed mark consumed", "err", err)
 		}
 		return nil
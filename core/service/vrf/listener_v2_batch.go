@@ -0,0 +1,397 @@
+package vrf
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"PhoenixOracle/core/log"
+	"PhoenixOracle/core/service/ethereum"
+	"PhoenixOracle/core/service/txmanager"
+	"PhoenixOracle/core/service/vrf/proof"
+	"PhoenixOracle/internal/gethwrappers/generated/vrf_coordinator_v2"
+	"PhoenixOracle/lib/logger"
+	"PhoenixOracle/lib/postgres"
+	"PhoenixOracle/util"
+	geth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultBatchFulfillmentSize is the number of requests fulfilled in one
+	// batch, unless the job spec sets batchFulfillmentSize
+	DefaultBatchFulfillmentSize = 10
+	// MaxBatchFulfillmentSize bounds batchFulfillmentSize, so that a batch
+	// stays well within the block gas limit
+	MaxBatchFulfillmentSize = 100
+	// DefaultBatchFulfillmentTimeout is how long a batch waits for more
+	// requests, unless the job spec sets batchFulfillmentTimeout
+	DefaultBatchFulfillmentTimeout = 10 * time.Second
+
+	// batchFulfillmentGasOverhead is the gas used to verify the proof and
+	// pay for one request in a batch, on top of its callback gas limit
+	batchFulfillmentGasOverhead = 100000
+	// txBaseGas is the intrinsic gas of a transaction, saved for every
+	// request but one in a batch
+	txBaseGas = 21000
+)
+
+// batchCoordinatorV2ABI is the ABI of BatchVRFCoordinatorV2#fulfillRandomWords,
+// which calls VRFCoordinatorV2#fulfillRandomWords for each proof in turn and
+// does not revert if one of them fails
+const batchCoordinatorV2ABI = `[{"inputs":[{"components":[{"internalType":"uint256[2]","name":"pk","type":"uint256[2]"},{"internalType":"uint256[2]","name":"gamma","type":"uint256[2]"},{"internalType":"uint256","name":"c","type":"uint256"},{"internalType":"uint256","name":"s","type":"uint256"},{"internalType":"uint256","name":"seed","type":"uint256"},{"internalType":"address","name":"uWitness","type":"address"},{"internalType":"uint256[2]","name":"cGammaWitness","type":"uint256[2]"},{"internalType":"uint256[2]","name":"sHashWitness","type":"uint256[2]"},{"internalType":"uint256","name":"zInv","type":"uint256"}],"internalType":"struct VRFTypes.Proof[]","name":"proofs","type":"tuple[]"},{"components":[{"internalType":"uint64","name":"blockNum","type":"uint64"},{"internalType":"uint64","name":"subId","type":"uint64"},{"internalType":"uint32","name":"callbackGasLimit","type":"uint32"},{"internalType":"uint32","name":"numWords","type":"uint32"},{"internalType":"address","name":"sender","type":"address"}],"internalType":"struct VRFTypes.RequestCommitment[]","name":"rcs","type":"tuple[]"}],"name":"fulfillRandomWords","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+
+var (
+	batchCoordinatorV2 = ethereum.MustGetABI(batchCoordinatorV2ABI)
+
+	promBatchSize = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "vrf_batch_fulfillment_size",
+		Help:    "Number of VRF v2 requests fulfilled per batch transaction",
+		Buckets: []float64{2, 5, 10, 20, 50, 100},
+	},
+		[]string{"job_id", "job_name"},
+	)
+	promBatchGasSaved = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "vrf_batch_fulfillment_gas_saved",
+		Help: "Estimated gas saved by fulfilling VRF v2 requests in batches rather than in individual transactions",
+	},
+		[]string{"job_id", "job_name"},
+	)
+	promBatchFallbacks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "vrf_batch_fulfillment_fallbacks",
+		Help: "Number of VRF v2 requests fulfilled individually because they could not be fulfilled in a batch",
+	},
+		[]string{"job_id", "job_name", "reason"},
+	)
+)
+
+// batchedRequest is a confirmed request with its proof, waiting to be
+// fulfilled in a batch
+type batchedRequest struct {
+	req   *vrf_coordinator_v2.VRFCoordinatorV2RandomWordsRequested
+	lb    log.Broadcast
+	proof vrf_coordinator_v2.VRFProof
+	rc    vrf_coordinator_v2.VRFCoordinatorV2RequestCommitment
+}
+
+// submittedRequest is a request fulfilled in the batch transaction ethTxID
+type submittedRequest struct {
+	req     *vrf_coordinator_v2.VRFCoordinatorV2RandomWordsRequested
+	lb      log.Broadcast
+	ethTxID int64
+}
+
+// batchFulfiller is what the requestBatcher needs from the listener
+type batchFulfiller interface {
+	alreadyFulfilled(req *vrf_coordinator_v2.VRFCoordinatorV2RandomWordsRequested, lb log.Broadcast) bool
+	generateProofV2(req *vrf_coordinator_v2.VRFCoordinatorV2RandomWordsRequested) (vrf_coordinator_v2.VRFProof, vrf_coordinator_v2.VRFCoordinatorV2RequestCommitment, error)
+	// submitBatch creates the batch transaction and returns the ID of its
+	// eth_tx. The logs of its requests are left unconsumed until the batch
+	// is known to have fulfilled them.
+	submitBatch(batch []batchedRequest) (int64, error)
+	// submittedBatches returns the ID of the latest batch transaction of
+	// each request batched by this job, by request ID
+	submittedBatches() (map[common.Hash]int64, error)
+	// batchTxState returns the state of the batch transaction
+	batchTxState(ethTxID int64) (txmanager.EthTxState, error)
+	// commitmentPending returns whether the request is still waiting to be
+	// fulfilled on chain
+	commitmentPending(req *vrf_coordinator_v2.VRFCoordinatorV2RandomWordsRequested) (bool, error)
+	fulfillIndividually(req *vrf_coordinator_v2.VRFCoordinatorV2RandomWordsRequested, lb log.Broadcast, reason string)
+	markLogAsConsumed(lb log.Broadcast)
+}
+
+var _ batchFulfiller = (*listenerV2)(nil)
+
+// requestBatcher accumulates confirmed requests into batches, and fulfills
+// individually the requests that a batch could not fulfill. It is only
+// accessed from the head listener.
+type requestBatcher struct {
+	f       batchFulfiller
+	l       logger.Logger
+	size    int
+	timeout time.Duration
+
+	batch     []batchedRequest
+	timer     *time.Timer
+	submitted []submittedRequest
+	// resumed holds the batch transactions submitted before the node
+	// restarted, until the log broadcaster replays their requests
+	resumed map[common.Hash]int64
+}
+
+func newRequestBatcher(f batchFulfiller, l logger.Logger, size uint32, timeout time.Duration) *requestBatcher {
+	return &requestBatcher{f: f, l: l, size: int(size), timeout: timeout}
+}
+
+// resumeSubmittedBatches loads the batch transactions submitted before the
+// node restarted, so that their requests, replayed by the log broadcaster,
+// are rechecked rather than batched again
+func (b *requestBatcher) resumeSubmittedBatches() {
+	resumed, err := b.f.submittedBatches()
+	if err != nil {
+		b.l.Errorw("VRFListenerV2: unable to load submitted batches, their pending requests will be batched again", "err", err)
+		return
+	}
+	b.resumed = resumed
+}
+
+func (lsn *listenerV2) batchFulfillmentEnabled() bool {
+	return lsn.job.VRFSpec.BatchFulfillmentEnabled && lsn.job.VRFSpec.BatchCoordinatorAddress != nil
+}
+
+// batchRequests generates the proofs of confirmed requests and adds them to
+// the batch, which is submitted once full. Requests whose proof cannot be
+// generated run through the job pipeline, which records the error.
+//
+// Requests are not marked consumed until they are fulfilled, so the log
+// broadcaster replays them if the node stops first. Replayed requests of a
+// batch submitted before the restart are rechecked with that batch.
+func (b *requestBatcher) batchRequests(reqs []pendingRequest) {
+	for _, r := range reqs {
+		if b.f.alreadyFulfilled(r.req, r.lb) {
+			continue
+		}
+		reqID := common.BytesToHash(r.req.RequestId.Bytes())
+		if ethTxID, ok := b.resumed[reqID]; ok {
+			delete(b.resumed, reqID)
+			b.submitted = append(b.submitted, submittedRequest{req: r.req, lb: r.lb, ethTxID: ethTxID})
+			continue
+		}
+		p, rc, err := b.f.generateProofV2(r.req)
+		if err != nil {
+			b.l.Errorw("VRFListenerV2: unable to generate proof for batch, fulfilling individually", "err", err, "reqID", r.req.RequestId.String(), "txHash", r.req.Raw.TxHash)
+			b.f.fulfillIndividually(r.req, r.lb, "proof")
+			continue
+		}
+		if len(b.batch) == 0 {
+			b.timer = time.NewTimer(b.timeout)
+		}
+		b.batch = append(b.batch, batchedRequest{req: r.req, lb: r.lb, proof: p, rc: rc})
+		if len(b.batch) >= b.size {
+			b.flushBatch()
+		}
+	}
+}
+
+// batchTimeout fires once the oldest request in the batch has waited for
+// the batch fulfillment timeout
+func (b *requestBatcher) batchTimeout() <-chan time.Time {
+	if b == nil || b.timer == nil {
+		return nil
+	}
+	return b.timer.C
+}
+
+func (lsn *listenerV2) generateProofV2(req *vrf_coordinator_v2.VRFCoordinatorV2RandomWordsRequested) (vrf_coordinator_v2.VRFProof, vrf_coordinator_v2.VRFCoordinatorV2RequestCommitment, error) {
+	preSeed, err := proof.BigToSeed(req.PreSeed)
+	if err != nil {
+		return vrf_coordinator_v2.VRFProof{}, vrf_coordinator_v2.VRFCoordinatorV2RequestCommitment{}, errors.Wrapf(err, "unable to parse preseed %v", req.PreSeed)
+	}
+	preSeedData := proof.PreSeedDataV2{
+		PreSeed:          preSeed,
+		BlockHash:        req.Raw.BlockHash,
+		BlockNum:         req.Raw.BlockNumber,
+		SubId:            req.SubId,
+		CallbackGasLimit: req.CallbackGasLimit,
+		NumWords:         req.NumWords,
+		Sender:           req.Sender,
+	}
+	pk := lsn.job.VRFSpec.PublicKey
+	p, err := lsn.vrfks.GenerateProof(hexutil.Encode(pk[:]), proof.FinalSeedV2(preSeedData))
+	if err != nil {
+		return vrf_coordinator_v2.VRFProof{}, vrf_coordinator_v2.VRFCoordinatorV2RequestCommitment{}, err
+	}
+	return proof.GenerateProofResponseFromProofV2(p, preSeedData)
+}
+
+// flushBatch submits the batch in one transaction to the batch coordinator.
+// If the batch cannot be submitted, its requests are fulfilled individually.
+func (b *requestBatcher) flushBatch() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	batch := b.batch
+	b.batch = nil
+	switch len(batch) {
+	case 0:
+		return
+	case 1:
+		// Nothing to save by batching a single request
+		b.f.fulfillIndividually(batch[0].req, batch[0].lb, "single")
+		return
+	}
+
+	ethTxID, err := b.f.submitBatch(batch)
+	if err != nil {
+		b.l.Errorw("VRFListenerV2: unable to submit batch, fulfilling requests individually", "err", err, "batchSize", len(batch))
+		for _, r := range batch {
+			b.f.fulfillIndividually(r.req, r.lb, "submit")
+		}
+		return
+	}
+	for _, r := range batch {
+		b.submitted = append(b.submitted, submittedRequest{req: r.req, lb: r.lb, ethTxID: ethTxID})
+	}
+}
+
+func (lsn *listenerV2) submitBatch(batch []batchedRequest) (int64, error) {
+	proofs := make([]vrf_coordinator_v2.VRFProof, len(batch))
+	rcs := make([]vrf_coordinator_v2.VRFCoordinatorV2RequestCommitment, len(batch))
+	reqIDs := make([]common.Hash, len(batch))
+	gasLimit := uint64(txBaseGas)
+	for i, r := range batch {
+		proofs[i] = r.proof
+		rcs[i] = r.rc
+		reqIDs[i] = common.BytesToHash(r.req.RequestId.Bytes())
+		gasLimit += uint64(r.rc.CallbackGasLimit) + batchFulfillmentGasOverhead
+	}
+	payload, err := batchCoordinatorV2.Pack("fulfillRandomWords", proofs, rcs)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to encode batch")
+	}
+	fromAddress, err := lsn.gethks.GetRoundRobinAddress()
+	if err != nil {
+		return 0, err
+	}
+	batchCoordinatorAddress := lsn.job.VRFSpec.BatchCoordinatorAddress.Address()
+
+	// A batch that reverts as a whole, e.g. if the batch coordinator is
+	// misconfigured, would waste the gas of every request in it
+	ctx, cancel := utils.ContextFromChan(lsn.chStop)
+	defer cancel()
+	ctx, cancelCall := context.WithTimeout(ctx, postgres.DefaultQueryTimeout)
+	defer cancelCall()
+	_, err = lsn.ethClient.CallContract(ctx, geth.CallMsg{
+		From: fromAddress,
+		To:   &batchCoordinatorAddress,
+		Gas:  gasLimit,
+		Data: payload,
+	}, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "batch fulfillment reverted")
+	}
+
+	dbCtx, cancelDB := postgres.DefaultQueryCtx()
+	defer cancelDB()
+	etx, err := lsn.txm.CreateEthTransaction(lsn.db.WithContext(dbCtx), txmanager.NewTx{
+		FromAddress:    fromAddress,
+		ToAddress:      batchCoordinatorAddress,
+		EncodedPayload: payload,
+		GasLimit:       gasLimit,
+		Meta: &txmanager.EthTxMeta{
+			JobID:      lsn.job.ID,
+			RequestIDs: reqIDs,
+		},
+		Strategy: txmanager.SendEveryStrategy{},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	jobID := strconv.Itoa(int(lsn.job.ID))
+	promBatchSize.WithLabelValues(jobID, lsn.job.Name.ValueOrZero()).Observe(float64(len(batch)))
+	promBatchGasSaved.WithLabelValues(jobID, lsn.job.Name.ValueOrZero()).Add(float64((len(batch) - 1) * txBaseGas))
+	return etx.ID, nil
+}
+
+func (lsn *listenerV2) submittedBatches() (map[common.Hash]int64, error) {
+	ctx, cancel := postgres.DefaultQueryCtx()
+	defer cancel()
+	var etxs []txmanager.EthTx
+	err := lsn.db.WithContext(ctx).
+		Select("id", "meta").
+		Where("to_address = ? AND meta->>'JobID' = ?", lsn.job.VRFSpec.BatchCoordinatorAddress.Address(), strconv.Itoa(int(lsn.job.ID))).
+		Order("id ASC").
+		Find(&etxs).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load batch transactions")
+	}
+	batches := make(map[common.Hash]int64)
+	for _, etx := range etxs {
+		var meta txmanager.EthTxMeta
+		if err := json.Unmarshal(etx.Meta, &meta); err != nil {
+			return nil, errors.Wrapf(err, "unable to parse meta of eth_tx %d", etx.ID)
+		}
+		for _, reqID := range meta.RequestIDs {
+			batches[reqID] = etx.ID
+		}
+	}
+	return batches, nil
+}
+
+func (lsn *listenerV2) batchTxState(ethTxID int64) (txmanager.EthTxState, error) {
+	ctx, cancel := postgres.DefaultQueryCtx()
+	defer cancel()
+	var etx txmanager.EthTx
+	err := lsn.db.WithContext(ctx).Select("state").Where("id = ?", ethTxID).Take(&etx).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// The reaper only deletes confirmed and fatally errored transactions
+		return txmanager.EthTxConfirmed, nil
+	}
+	return etx.State, err
+}
+
+func (lsn *listenerV2) commitmentPending(req *vrf_coordinator_v2.VRFCoordinatorV2RandomWordsRequested) (bool, error) {
+	callback, err := lsn.coordinator.GetCommitment(nil, req.RequestId)
+	if err != nil {
+		return false, err
+	}
+	return !utils.IsEmpty(callback[:]), nil
+}
+
+// recheckBatchedRequests marks consumed the requests of submitted batches
+// that were fulfilled once their batch transaction is confirmed or has
+// fatally errored, and fulfills individually those still pending, e.g.
+// because their callback ran out of gas within the batch. Requests of
+// batches still in flight are checked again on the next head.
+func (b *requestBatcher) recheckBatchedRequests() {
+	states := make(map[int64]txmanager.EthTxState)
+	var toKeep []submittedRequest
+	for _, r := range b.submitted {
+		state, ok := states[r.ethTxID]
+		if !ok {
+			var err error
+			state, err = b.f.batchTxState(r.ethTxID)
+			if err != nil {
+				b.l.Errorw("VRFListenerV2: unable to load batch transaction, checking again on next head", "err", err, "ethTxID", r.ethTxID)
+				toKeep = append(toKeep, r)
+				continue
+			}
+			states[r.ethTxID] = state
+		}
+		if state != txmanager.EthTxConfirmed && state != txmanager.EthTxFatalError {
+			toKeep = append(toKeep, r)
+			continue
+		}
+		pending, err := b.f.commitmentPending(r.req)
+		if err != nil {
+			b.l.Errorw("VRFListenerV2: unable to check if batched request was fulfilled, checking again on next head", "err", err, "reqID", r.req.RequestId.String(), "txHash", r.req.Raw.TxHash)
+			toKeep = append(toKeep, r)
+			continue
+		}
+		if !pending {
+			b.f.markLogAsConsumed(r.lb)
+			continue
+		}
+		reason := "unfulfilled"
+		if state == txmanager.EthTxFatalError {
+			reason = "fatal"
+		}
+		b.l.Warnw("VRFListenerV2: request not fulfilled by batch, fulfilling individually", "ethTxID", r.ethTxID, "ethTxState", state, "reqID", r.req.RequestId.String(), "txHash", r.req.Raw.TxHash)
+		b.f.fulfillIndividually(r.req, r.lb, true, reason)
+	}
+	b.submitted = toKeep
+}
+
+func (lsn *listenerV2) fulfillIndividually(req *vrf_coordinator_v2.VRFCoordinatorV2RandomWordsRequested, lb log.Broadcast, reason string) {
+	promBatchFallbacks.WithLabelValues(strconv.Itoa(int(lsn.job.ID)), lsn.job.Name.ValueOrZero(), reason).Inc()
+	lsn.runPipeline(req, lb)
+}
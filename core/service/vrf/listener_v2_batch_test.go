@@ -0,0 +1,278 @@
+package vrf
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"PhoenixOracle/core/log"
+	"PhoenixOracle/core/service/txmanager"
+	"PhoenixOracle/internal/gethwrappers/generated/vrf_coordinator_v2"
+	"PhoenixOracle/lib/logger"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap/zapcore"
+)
+
+type individualFulfillment struct {
+	reqID  int64
+	reason string
+}
+
+// fakeFulfiller records what the batcher asks of the listener. Requests are
+// identified by their request ID.
+type fakeFulfiller struct {
+	fulfilled  map[int64]bool
+	proofFails map[int64]bool
+	submitErr  error
+	txStates   map[int64]txmanager.EthTxState
+	txStateErr error
+	submitted  map[common.Hash]int64
+
+	batches    [][]int64
+	individual []individualFulfillment
+	consumed   []int64
+}
+
+var _ batchFulfiller = (*fakeFulfiller)(nil)
+
+func newFakeFulfiller() *fakeFulfiller {
+	return &fakeFulfiller{
+		fulfilled:  make(map[int64]bool),
+		proofFails: make(map[int64]bool),
+		txStates:   make(map[int64]txmanager.EthTxState),
+	}
+}
+
+func (f *fakeFulfiller) alreadyFulfilled(req *vrf_coordinator_v2.VRFCoordinatorV2RandomWordsRequested, _ log.Broadcast) bool {
+	return f.fulfilled[req.RequestId.Int64()]
+}
+
+func (f *fakeFulfiller) generateProofV2(req *vrf_coordinator_v2.VRFCoordinatorV2RandomWordsRequested) (vrf_coordinator_v2.VRFProof, vrf_coordinator_v2.VRFCoordinatorV2RequestCommitment, error) {
+	if f.proofFails[req.RequestId.Int64()] {
+		return vrf_coordinator_v2.VRFProof{}, vrf_coordinator_v2.VRFCoordinatorV2RequestCommitment{}, errors.New("no proof")
+	}
+	return vrf_coordinator_v2.VRFProof{Seed: req.RequestId}, vrf_coordinator_v2.VRFCoordinatorV2RequestCommitment{}, nil
+}
+
+func (f *fakeFulfiller) submitBatch(batch []batchedRequest) (int64, error) {
+	if f.submitErr != nil {
+		return 0, f.submitErr
+	}
+	var ids []int64
+	for _, r := range batch {
+		ids = append(ids, r.req.RequestId.Int64())
+	}
+	f.batches = append(f.batches, ids)
+	return int64(len(f.batches)), nil
+}
+
+func (f *fakeFulfiller) submittedBatches() (map[common.Hash]int64, error) {
+	return f.submitted, nil
+}
+
+func (f *fakeFulfiller) batchTxState(ethTxID int64) (txmanager.EthTxState, error) {
+	return f.txStates[ethTxID], f.txStateErr
+}
+
+func (f *fakeFulfiller) commitmentPending(req *vrf_coordinator_v2.VRFCoordinatorV2RandomWordsRequested) (bool, error) {
+	return !f.fulfilled[req.RequestId.Int64()], nil
+}
+
+func (f *fakeFulfiller) fulfillIndividually(req *vrf_coordinator_v2.VRFCoordinatorV2RandomWordsRequested, _ log.Broadcast, reason string) {
+	f.individual = append(f.individual, individualFulfillment{req.RequestId.Int64(), reason})
+}
+
+// markLogAsConsumed records the block number of the log, which pendingRequests
+// sets to the request ID
+func (f *fakeFulfiller) markLogAsConsumed(lb log.Broadcast) {
+	f.consumed = append(f.consumed, int64(lb.RawLog().BlockNumber))
+}
+
+func newTestBatcher(f *fakeFulfiller, size uint32, timeout time.Duration) *requestBatcher {
+	return newRequestBatcher(f, *logger.CreateTestLogger(zapcore.ErrorLevel), size, timeout)
+}
+
+func pendingRequests(ids ...int64) []pendingRequest {
+	var reqs []pendingRequest
+	for _, id := range ids {
+		reqs = append(reqs, pendingRequest{
+			req: &vrf_coordinator_v2.VRFCoordinatorV2RandomWordsRequested{RequestId: big.NewInt(id)},
+			lb:  log.NewLogBroadcast(types.Log{BlockNumber: uint64(id)}, nil),
+		})
+	}
+	return reqs
+}
+
+func TestRequestBatcher_FlushesWhenFull(t *testing.T) {
+	f := newFakeFulfiller()
+	b := newTestBatcher(f, 3, time.Hour)
+
+	b.batchRequests(pendingRequests(1, 2, 3, 4, 5, 6, 7))
+
+	expected := [][]int64{{1, 2, 3}, {4, 5, 6}}
+	if !reflect.DeepEqual(f.batches, expected) {
+		t.Errorf("expected batches %v, got %v", expected, f.batches)
+	}
+	if len(b.batch) != 1 || b.batchTimeout() == nil {
+		t.Errorf("expected request 7 to wait for the timeout, got batch of %d", len(b.batch))
+	}
+	if len(b.submitted) != 6 {
+		t.Errorf("expected 6 submitted requests, got %d", len(b.submitted))
+	}
+	if len(f.individual) != 0 {
+		t.Errorf("expected no individual fulfillments, got %v", f.individual)
+	}
+}
+
+func TestRequestBatcher_FlushesOnTimeout(t *testing.T) {
+	f := newFakeFulfiller()
+	b := newTestBatcher(f, 10, 10*time.Millisecond)
+
+	if b.batchTimeout() != nil {
+		t.Fatal("expected no timeout without a batch")
+	}
+	b.batchRequests(pendingRequests(1))
+	b.batchRequests(pendingRequests(2))
+
+	select {
+	case <-b.batchTimeout():
+		b.flushBatch()
+	case <-time.After(5 * time.Second):
+		t.Fatal("batch timeout did not fire")
+	}
+
+	expected := [][]int64{{1, 2}}
+	if !reflect.DeepEqual(f.batches, expected) {
+		t.Errorf("expected batches %v, got %v", expected, f.batches)
+	}
+	if len(b.batch) != 0 || b.batchTimeout() != nil {
+		t.Error("expected the batch and its timer to be reset")
+	}
+}
+
+func TestRequestBatcher_FlushesSingleRequestIndividually(t *testing.T) {
+	f := newFakeFulfiller()
+	b := newTestBatcher(f, 10, time.Hour)
+
+	b.batchRequests(pendingRequests(1))
+	b.flushBatch()
+
+	if len(f.batches) != 0 {
+		t.Errorf("expected no batch, got %v", f.batches)
+	}
+	expected := []individualFulfillment{{1, "single"}}
+	if !reflect.DeepEqual(f.individual, expected) {
+		t.Errorf("expected %v, got %v", expected, f.individual)
+	}
+}
+
+func TestRequestBatcher_SkipsFulfilledAndUnprovableRequests(t *testing.T) {
+	f := newFakeFulfiller()
+	f.fulfilled[2] = true
+	f.proofFails[3] = true
+	b := newTestBatcher(f, 2, time.Hour)
+
+	b.batchRequests(pendingRequests(1, 2, 3, 4))
+
+	if expected := [][]int64{{1, 4}}; !reflect.DeepEqual(f.batches, expected) {
+		t.Errorf("expected batches %v, got %v", expected, f.batches)
+	}
+	if expected := []individualFulfillment{{3, "proof"}}; !reflect.DeepEqual(f.individual, expected) {
+		t.Errorf("expected %v, got %v", expected, f.individual)
+	}
+}
+
+func TestRequestBatcher_FallsBackWhenSubmitFails(t *testing.T) {
+	f := newFakeFulfiller()
+	f.submitErr = errors.New("batch fulfillment reverted")
+	b := newTestBatcher(f, 2, time.Hour)
+
+	b.batchRequests(pendingRequests(1, 2))
+
+	expected := []individualFulfillment{{1, "submit"}, {2, "submit"}}
+	if !reflect.DeepEqual(f.individual, expected) {
+		t.Errorf("expected %v, got %v", expected, f.individual)
+	}
+	if len(b.submitted) != 0 {
+		t.Errorf("expected no submitted requests, got %d", len(b.submitted))
+	}
+}
+
+func TestRequestBatcher_RecheckBatchedRequests(t *testing.T) {
+	tests := []struct {
+		name       string
+		state      txmanager.EthTxState
+		stateErr   error
+		individual []individualFulfillment
+		consumed   []int64
+		kept       int
+	}{
+		{"in flight", txmanager.EthTxUnconfirmed, nil, nil, nil, 2},
+		{"missing receipt", txmanager.EthTxConfirmedMissingReceipt, nil, nil, nil, 2},
+		{"state unavailable", "", errors.New("connection refused"), nil, nil, 2},
+		{"confirmed", txmanager.EthTxConfirmed, nil, []individualFulfillment{{2, "unfulfilled"}}, []int64{1}, 0},
+		{"fatal error", txmanager.EthTxFatalError, nil, []individualFulfillment{{2, "fatal"}}, []int64{1}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeFulfiller()
+			b := newTestBatcher(f, 2, time.Hour)
+			b.batchRequests(pendingRequests(1, 2))
+			if len(b.submitted) != 2 {
+				t.Fatalf("expected 2 submitted requests, got %d", len(b.submitted))
+			}
+
+			// The batch fulfilled request 1 only, e.g. request 2 ran out of gas
+			f.fulfilled[1] = true
+			f.txStates[1] = tt.state
+			f.txStateErr = tt.stateErr
+			b.recheckBatchedRequests()
+
+			if !reflect.DeepEqual(f.individual, tt.individual) {
+				t.Errorf("expected %v, got %v", tt.individual, f.individual)
+			}
+			if !reflect.DeepEqual(f.consumed, tt.consumed) {
+				t.Errorf("expected logs %v to be consumed, got %v", tt.consumed, f.consumed)
+			}
+			if len(b.submitted) != tt.kept {
+				t.Errorf("expected %d requests to be checked again, got %d", tt.kept, len(b.submitted))
+			}
+		})
+	}
+}
+
+func TestRequestBatcher_ResumesSubmittedBatches(t *testing.T) {
+	f := newFakeFulfiller()
+	f.submitted = map[common.Hash]int64{
+		common.BigToHash(big.NewInt(1)): 7,
+		common.BigToHash(big.NewInt(2)): 7,
+	}
+	b := newTestBatcher(f, 2, time.Hour)
+	b.resumeSubmittedBatches()
+
+	// The log broadcaster replays the requests, which were not consumed
+	// before the restart
+	b.batchRequests(pendingRequests(1, 2, 3))
+
+	if len(f.batches) != 0 {
+		t.Errorf("expected no new batch, got %v", f.batches)
+	}
+	if len(b.submitted) != 2 || b.submitted[0].ethTxID != 7 || b.submitted[1].ethTxID != 7 {
+		t.Fatalf("expected requests 1 and 2 to be rechecked with eth_tx 7, got %+v", b.submitted)
+	}
+	if len(b.batch) != 1 {
+		t.Errorf("expected request 3 to be batched, got batch of %d", len(b.batch))
+	}
+
+	// The batch fatally errored before the restart
+	f.txStates[7] = txmanager.EthTxFatalError
+	b.recheckBatchedRequests()
+
+	expected := []individualFulfillment{{1, "fatal"}, {2, "fatal"}}
+	if !reflect.DeepEqual(f.individual, expected) {
+		t.Errorf("expected %v, got %v", expected, f.individual)
+	}
+}
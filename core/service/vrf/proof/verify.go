@@ -0,0 +1,118 @@
+package proof
+
+import (
+	"bytes"
+
+	"PhoenixOracle/core/keystore/keys/vrfkey"
+	"PhoenixOracle/internal/gethwrappers/generated/vrf_coordinator_v2"
+	"PhoenixOracle/lib/signatures/secp256k1"
+	"PhoenixOracle/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// VerifyProofResponse checks an on-chain response to a VRFCoordinator
+// randomness request is a valid proof by the public key, for the request
+// made in the block with the given hash. It returns the proof, with its
+// final seed and output.
+//
+// Besides the proof itself, the witnesses the solidity verifier relies on
+// must be those the node would have computed, so a response which verifies
+// here would also verify on-chain.
+func VerifyProofResponse(pk secp256k1.PublicKey, m MarshaledOnChainResponse, blockHash common.Hash) (vrfkey.Proof, error) {
+	response, err := UnmarshalProofResponse(m)
+	if err != nil {
+		return vrfkey.Proof{}, err
+	}
+	s := PreSeedData{PreSeed: response.PreSeed, BlockHash: blockHash, BlockNum: response.BlockNum}
+	p, err := response.CryptoProof(s)
+	if err != nil {
+		return vrfkey.Proof{}, err
+	}
+	if err = checkPublicKey(pk, p); err != nil {
+		return vrfkey.Proof{}, err
+	}
+	expected, err := GenerateProofResponseFromProof(p, s)
+	if err != nil {
+		return vrfkey.Proof{}, err
+	}
+	if expected != m {
+		return vrfkey.Proof{}, errors.New("proof witnesses do not match the proof")
+	}
+	return p, nil
+}
+
+// VerifyProofResponseV2 checks the arguments of a call to
+// VRFCoordinatorV2#fulfillRandomWords are a valid proof by the public key,
+// for the request made in the block with the given hash. It returns the
+// proof, with its final seed and output.
+func VerifyProofResponseV2(pk secp256k1.PublicKey, vp vrf_coordinator_v2.VRFProof, rc vrf_coordinator_v2.VRFCoordinatorV2RequestCommitment, blockHash common.Hash) (vrfkey.Proof, error) {
+	marshaled := marshalProofV2(vp)
+	p, err := UnmarshalSolidityProof(marshaled)
+	if err != nil {
+		return vrfkey.Proof{}, err
+	}
+	preSeed, err := BigToSeed(vp.Seed)
+	if err != nil {
+		return vrfkey.Proof{}, errors.Wrap(err, "while converting seed to bytes representation")
+	}
+	s := PreSeedDataV2{
+		PreSeed:          preSeed,
+		BlockHash:        blockHash,
+		BlockNum:         rc.BlockNum,
+		SubId:            rc.SubId,
+		CallbackGasLimit: rc.CallbackGasLimit,
+		NumWords:         rc.NumWords,
+		Sender:           rc.Sender,
+	}
+	p.Seed = FinalSeedV2(s)
+	valid, err := p.VerifyVRFProof()
+	if err != nil {
+		return vrfkey.Proof{}, errors.Wrap(err, "could not validate proof")
+	}
+	if !valid {
+		return vrfkey.Proof{}, errors.New("proof is invalid")
+	}
+	if err = checkPublicKey(pk, p); err != nil {
+		return vrfkey.Proof{}, err
+	}
+	expected, _, err := GenerateProofResponseFromProofV2(p, s)
+	if err != nil {
+		return vrfkey.Proof{}, err
+	}
+	if !bytes.Equal(marshalProofV2(expected), marshaled) {
+		return vrfkey.Proof{}, errors.New("proof witnesses do not match the proof")
+	}
+	return p, nil
+}
+
+func checkPublicKey(pk secp256k1.PublicKey, p vrfkey.Proof) error {
+	point, err := pk.Point()
+	if err != nil {
+		return errors.Wrap(err, "invalid public key")
+	}
+	if !point.Equal(p.PublicKey) {
+		return errors.Errorf("proof is by %s, not by public key %s", p.PublicKey, pk)
+	}
+	return nil
+}
+
+// marshalProofV2 lays out a VRFCoordinatorV2 proof as the solidity verifier
+// expects it
+func marshalProofV2(vp vrf_coordinator_v2.VRFProof) []byte {
+	var b []byte
+	for _, n := range [][]byte{
+		utils.Uint256ToBytes32(vp.Pk[0]), utils.Uint256ToBytes32(vp.Pk[1]),
+		utils.Uint256ToBytes32(vp.Gamma[0]), utils.Uint256ToBytes32(vp.Gamma[1]),
+		utils.Uint256ToBytes32(vp.C),
+		utils.Uint256ToBytes32(vp.S),
+		utils.Uint256ToBytes32(vp.Seed),
+		common.LeftPadBytes(vp.UWitness[:], 32),
+		utils.Uint256ToBytes32(vp.CGammaWitness[0]), utils.Uint256ToBytes32(vp.CGammaWitness[1]),
+		utils.Uint256ToBytes32(vp.SHashWitness[0]), utils.Uint256ToBytes32(vp.SHashWitness[1]),
+		utils.Uint256ToBytes32(vp.ZInv),
+	} {
+		b = append(b, n...)
+	}
+	return b
+}
@@ -0,0 +1,136 @@
+package proof
+
+import (
+	"math/big"
+	"testing"
+
+	"PhoenixOracle/core/keystore/keys/vrfkey"
+	"PhoenixOracle/internal/gethwrappers/generated/vrf_coordinator_v2"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	testBlockHash  = common.HexToHash("0x2a")
+	otherBlockHash = common.HexToHash("0x2b")
+)
+
+func newTestKey(t *testing.T) vrfkey.KeyV2 {
+	t.Helper()
+	key, err := vrfkey.NewV2()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func testPreSeed(t *testing.T) Seed {
+	t.Helper()
+	seed, err := BigToSeed(big.NewInt(0x10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return seed
+}
+
+func TestVerifyProofResponse(t *testing.T) {
+	key, other := newTestKey(t), newTestKey(t)
+	s := PreSeedData{PreSeed: testPreSeed(t), BlockHash: testBlockHash, BlockNum: 10}
+	p, err := key.GenerateProof(FinalSeed(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := GenerateProofResponseFromProof(p, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verified, err := VerifyProofResponse(key.PublicKey, m, testBlockHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verified.Seed.Cmp(p.Seed) != 0 || verified.Output.Cmp(p.Output) != 0 {
+		t.Errorf("expected seed %v and output %v, got %v and %v", p.Seed, p.Output, verified.Seed, verified.Output)
+	}
+
+	// uWitness is the address in the word at offset 224, cGammaWitness
+	// follows it
+	tamperedUWitness := m
+	tamperedUWitness[255] ^= 1
+	tamperedCGammaWitness := m
+	tamperedCGammaWitness[287] ^= 1
+
+	tests := []struct {
+		name      string
+		pk        vrfkey.KeyV2
+		m         MarshaledOnChainResponse
+		blockHash common.Hash
+	}{
+		{"other public key", other, m, testBlockHash},
+		{"other block hash", key, m, otherBlockHash},
+		{"tampered uWitness", key, tamperedUWitness, testBlockHash},
+		{"tampered cGammaWitness", key, tamperedCGammaWitness, testBlockHash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := VerifyProofResponse(tt.pk.PublicKey, tt.m, tt.blockHash); err == nil {
+				t.Error("expected proof to be rejected")
+			}
+		})
+	}
+}
+
+func TestVerifyProofResponseV2(t *testing.T) {
+	key, other := newTestKey(t), newTestKey(t)
+	s := PreSeedDataV2{
+		PreSeed:          testPreSeed(t),
+		BlockHash:        testBlockHash,
+		BlockNum:         10,
+		SubId:            1,
+		CallbackGasLimit: 100000,
+		NumWords:         2,
+		Sender:           common.HexToAddress("0xaa"),
+	}
+	p, err := key.GenerateProof(FinalSeedV2(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vp, rc, err := GenerateProofResponseFromProofV2(p, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verified, err := VerifyProofResponseV2(key.PublicKey, vp, rc, testBlockHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verified.Seed.Cmp(p.Seed) != 0 || verified.Output.Cmp(p.Output) != 0 {
+		t.Errorf("expected seed %v and output %v, got %v and %v", p.Seed, p.Output, verified.Seed, verified.Output)
+	}
+
+	tamperedUWitness := vp
+	tamperedUWitness.UWitness[19] ^= 1
+	tamperedCGammaWitness := vp
+	tamperedCGammaWitness.CGammaWitness[0] = new(big.Int).Add(vp.CGammaWitness[0], big.NewInt(1))
+	tamperedZInv := vp
+	tamperedZInv.ZInv = new(big.Int).Add(vp.ZInv, big.NewInt(1))
+
+	tests := []struct {
+		name      string
+		pk        vrfkey.KeyV2
+		vp        vrf_coordinator_v2.VRFProof
+		blockHash common.Hash
+	}{
+		{"other public key", other, vp, testBlockHash},
+		{"other block hash", key, vp, otherBlockHash},
+		{"tampered uWitness", key, tamperedUWitness, testBlockHash},
+		{"tampered cGammaWitness", key, tamperedCGammaWitness, testBlockHash},
+		{"tampered zInv", key, tamperedZInv, testBlockHash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := VerifyProofResponseV2(tt.pk.PublicKey, tt.vp, rc, tt.blockHash); err == nil {
+				t.Error("expected proof to be rejected")
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	uuid "github.com/satori/go.uuid"
 
 	"PhoenixOracle/core/service/job"
+	"PhoenixOracle/db/models"
 	"PhoenixOracle/lib/signatures/secp256k1"
 	"github.com/pelletier/go-toml"
 	"github.com/pkg/errors"
@@ -50,15 +51,35 @@ func ValidatedVRFSpec(tomlString string) (job.Job, error) {
 	if spec.CoordinatorAddress.String() == "" {
 		return jb, errors.Wrap(ErrKeyNotSet, "coordinatorAddress")
 	}
-	var foundVRFTask bool
+	var foundVRFTask, foundVRFTaskV2 bool
 	for _, t := range jb.Pipeline.Tasks {
 		if t.Type() == pipeline.TaskTypeVRF || t.Type() == pipeline.TaskTypeVRFV2 {
 			foundVRFTask = true
 		}
+		if t.Type() == pipeline.TaskTypeVRFV2 {
+			foundVRFTaskV2 = true
+		}
 	}
 	if !foundVRFTask {
 		return jb, errors.Wrapf(ErrKeyNotSet, "invalid pipeline, expected a vrf task")
 	}
+	if spec.BatchFulfillmentEnabled {
+		if !foundVRFTaskV2 {
+			return jb, errors.New("batchFulfillmentEnabled is only supported by VRF v2 jobs")
+		}
+		if spec.BatchCoordinatorAddress == nil {
+			return jb, errors.Wrap(ErrKeyNotSet, "batchCoordinatorAddress")
+		}
+		if spec.BatchFulfillmentSize == 0 {
+			spec.BatchFulfillmentSize = DefaultBatchFulfillmentSize
+		}
+		if spec.BatchFulfillmentSize > MaxBatchFulfillmentSize {
+			return jb, errors.Errorf("batchFulfillmentSize must be at most %d", MaxBatchFulfillmentSize)
+		}
+		if spec.BatchFulfillmentTimeout.Duration() == 0 {
+			spec.BatchFulfillmentTimeout = models.Interval(DefaultBatchFulfillmentTimeout)
+		}
+	}
 
 	jb.VRFSpec = &spec
 
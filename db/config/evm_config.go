@@ -47,6 +47,8 @@ type EVMOnlyConfig interface {
 	EvmMinGasPriceWei() *big.Int
 	EvmNonceAutoSync() bool
 	EvmRPCDefaultBatchSize() uint32
+	FeeHistoryEstimatorBlockCount() uint16
+	FeeHistoryEstimatorRewardPercentile() uint16
 	FlagsContractAddress() string
 	GasEstimatorMode() string
 	PhbContractAddress() string
@@ -105,6 +107,14 @@ func (c *evmConfig) validate() (err error) {
 	if c.GasEstimatorMode() == "BlockHistory" && c.BlockHistoryEstimatorBlockHistorySize() <= 0 {
 		err = multierr.Combine(err, errors.New("GAS_UPDATER_BLOCK_HISTORY_SIZE must be greater than or equal to 1 if block history estimator is enabled"))
 	}
+	if c.GasEstimatorMode() == "FeeHistory" {
+		if c.FeeHistoryEstimatorBlockCount() <= 0 {
+			err = multierr.Combine(err, errors.New("FEE_HISTORY_ESTIMATOR_BLOCK_COUNT must be greater than or equal to 1 if fee history estimator is enabled"))
+		}
+		if c.FeeHistoryEstimatorRewardPercentile() > 100 {
+			err = multierr.Combine(err, errors.New("FEE_HISTORY_ESTIMATOR_REWARD_PERCENTILE must be less than or equal to 100"))
+		}
+	}
 	if c.EvmFinalityDepth() < 1 {
 		err = multierr.Combine(err, errors.New("ETH_FINALITY_DEPTH must be greater than or equal to 1"))
 	}
@@ -334,6 +344,26 @@ func (c *evmConfig) BlockHistoryEstimatorTransactionPercentile() uint16 {
 	return c.chainSpecificConfig.BlockHistoryEstimatorTransactionPercentile
 }
 
+// FeeHistoryEstimatorBlockCount is the number of recent blocks requested via
+// eth_feeHistory when GAS_ESTIMATOR_MODE=FeeHistory
+func (c *evmConfig) FeeHistoryEstimatorBlockCount() uint16 {
+	val, ok := lookupEnv("FEE_HISTORY_ESTIMATOR_BLOCK_COUNT", parseUint16)
+	if ok {
+		return val.(uint16)
+	}
+	return c.chainSpecificConfig.FeeHistoryEstimatorBlockCount
+}
+
+// FeeHistoryEstimatorRewardPercentile is the priority fee percentile
+// requested via eth_feeHistory when GAS_ESTIMATOR_MODE=FeeHistory
+func (c *evmConfig) FeeHistoryEstimatorRewardPercentile() uint16 {
+	val, ok := lookupEnv("FEE_HISTORY_ESTIMATOR_REWARD_PERCENTILE", parseUint16)
+	if ok {
+		return val.(uint16)
+	}
+	return c.chainSpecificConfig.FeeHistoryEstimatorRewardPercentile
+}
+
 func (c *evmConfig) GasEstimatorMode() string {
 	if c.EthereumDisabled() {
 		return "FixedPrice"
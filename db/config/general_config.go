@@ -49,6 +49,7 @@ var (
 
 type GeneralConfig interface {
 	AdminCredentialsFile() string
+	AlertingCheckInterval() time.Duration
 	AllowOrigins() string
 	AuthenticatedRateLimit() int64
 	AuthenticatedRateLimitPeriod() models.Duration
@@ -83,6 +84,10 @@ type GeneralConfig interface {
 	ExplorerAccessKey() string
 	ExplorerSecret() string
 	ExplorerURL() *url.URL
+	ExternalSignerClefURL() string
+	ExternalSignerPKCS11Module() string
+	ExternalSignerPKCS11Pin() string
+	ExternalSignerPKCS11TokenLabel() string
 	FMDefaultTransactionQueueDepth() uint32
 	FeatureCronV2() bool
 	FeatureUICSAKeys() bool
@@ -94,6 +99,9 @@ type GeneralConfig interface {
 	GetAdvisoryLockIDConfiguredOrDefault() int64
 	GetDatabaseDialectConfiguredOrDefault() dialects.DialectName
 	GlobalLockRetryInterval() models.Duration
+	HAEnabled() bool
+	HALeaseDuration() time.Duration
+	HALeaseRefreshInterval() time.Duration
 	InsecureFastScrypt() bool
 	InsecureSkipVerify() bool
 	JSONConsole() bool
@@ -263,6 +271,9 @@ func (c *generalConfig) Validate() error {
 			return errors.Wrapf(err, "invalid monitoring url: %s", me)
 		}
 	}
+	if c.HAEnabled() && c.HALeaseRefreshInterval()*2 > c.HALeaseDuration() {
+		return errors.Errorf("HA_LEASE_REFRESH_INTERVAL of %s must be at most half of HA_LEASE_DURATION of %s", c.HALeaseRefreshInterval(), c.HALeaseDuration())
+	}
 	return nil
 }
 
@@ -297,6 +308,10 @@ func (c *generalConfig) AdminCredentialsFile() string {
 	return file
 }
 
+func (c *generalConfig) AlertingCheckInterval() time.Duration {
+	return c.getWithFallback("AlertingCheckInterval", parseDuration).(time.Duration)
+}
+
 func (c *generalConfig) AuthenticatedRateLimit() int64 {
 	return c.viper.GetInt64(EnvVarName("AuthenticatedRateLimit"))
 }
@@ -386,6 +401,25 @@ func (c *generalConfig) GlobalLockRetryInterval() models.Duration {
 	return models.MustMakeDuration(c.getWithFallback("GlobalLockRetryInterval", parseDuration).(time.Duration))
 }
 
+// HAEnabled runs the node as one of an active/passive pair sharing a
+// database: only the holder of the leader lease runs its services, the other
+// stays warm and takes over when the lease lapses
+func (c *generalConfig) HAEnabled() bool {
+	return c.getWithFallback("HAEnabled", parseBool).(bool)
+}
+
+// HALeaseDuration is how long the leader lease lasts without being renewed,
+// and so roughly how long a failover takes
+func (c *generalConfig) HALeaseDuration() time.Duration {
+	return c.getWithFallback("HALeaseDuration", parseDuration).(time.Duration)
+}
+
+// HALeaseRefreshInterval is how often the leader renews its lease and the
+// standby checks whether it has lapsed
+func (c *generalConfig) HALeaseRefreshInterval() time.Duration {
+	return c.getWithFallback("HALeaseRefreshInterval", parseDuration).(time.Duration)
+}
+
 func (c *generalConfig) DatabaseURL() url.URL {
 	s := c.viper.GetString(EnvVarName("DatabaseURL"))
 	uri, err := url.Parse(s)
@@ -570,6 +604,26 @@ func (c *generalConfig) ExplorerSecret() string {
 	return c.viper.GetString(EnvVarName("ExplorerSecret"))
 }
 
+// ExternalSignerClefURL is the JSON-RPC endpoint of a Clef-compatible signer
+// used for eth keys with external_signer = 'clef'
+func (c *generalConfig) ExternalSignerClefURL() string {
+	return c.viper.GetString(EnvVarName("ExternalSignerClefURL"))
+}
+
+// ExternalSignerPKCS11Module is the path to the PKCS#11 library used for eth
+// keys with external_signer = 'pkcs11'
+func (c *generalConfig) ExternalSignerPKCS11Module() string {
+	return c.viper.GetString(EnvVarName("ExternalSignerPKCS11Module"))
+}
+
+func (c *generalConfig) ExternalSignerPKCS11Pin() string {
+	return c.viper.GetString(EnvVarName("ExternalSignerPKCS11Pin"))
+}
+
+func (c *generalConfig) ExternalSignerPKCS11TokenLabel() string {
+	return c.viper.GetString(EnvVarName("ExternalSignerPKCS11TokenLabel"))
+}
+
 func (c *generalConfig) TelemetryIngressURL() *url.URL {
 	rval := c.getWithFallback("TelemetryIngressURL", parseURL)
 	switch t := rval.(type) {
@@ -16,6 +16,7 @@ import (
 
 type ConfigSchema struct {
 	AdminCredentialsFile                       string          `env:"ADMIN_CREDENTIALS_FILE" default:"$ROOT/apicredentials"`
+	AlertingCheckInterval                      time.Duration   `env:"ALERTING_CHECK_INTERVAL" default:"15s"`
 	AllowOrigins                               string          `env:"ALLOW_ORIGINS" default:"http://localhost:3000,http://localhost:6688"`
 	AuthenticatedRateLimit                     int64           `env:"AUTHENTICATED_RATE_LIMIT" default:"1000"`
 	AuthenticatedRateLimitPeriod               time.Duration   `env:"AUTHENTICATED_RATE_LIMIT_PERIOD" default:"1m"`
@@ -52,6 +53,10 @@ type ConfigSchema struct {
 	ExplorerAccessKey                     string                        `env:"EXPLORER_ACCESS_KEY"`
 	ExplorerSecret                        string                        `env:"EXPLORER_SECRET"`
 	ExplorerURL                           *url.URL                      `env:"EXPLORER_URL"`
+	ExternalSignerClefURL                 string                        `env:"EXTERNAL_SIGNER_CLEF_URL"`
+	ExternalSignerPKCS11Module            string                        `env:"EXTERNAL_SIGNER_PKCS11_MODULE"`
+	ExternalSignerPKCS11Pin               string                        `env:"EXTERNAL_SIGNER_PKCS11_PIN"`
+	ExternalSignerPKCS11TokenLabel        string                        `env:"EXTERNAL_SIGNER_PKCS11_TOKEN_LABEL"`
 	FMDefaultTransactionQueueDepth        uint32                        `env:"FM_DEFAULT_TRANSACTION_QUEUE_DEPTH" default:"1"`
 	FeatureCronV2                         bool                          `env:"FEATURE_CRON_V2" default:"true"`
 	FeatureExternalInitiators             bool                          `env:"FEATURE_EXTERNAL_INITIATORS" default:"false"`
@@ -60,6 +65,8 @@ type ConfigSchema struct {
 	FeatureUICSAKeys                      bool                          `env:"FEATURE_UI_CSA_KEYS" default:"false"`
 	FeatureUIFeedsManager                 bool                          `env:"FEATURE_UI_FEEDS_MANAGER" default:"false"`
 	FeatureWebhookV2                      bool                          `env:"FEATURE_WEBHOOK_V2" default:"false"`
+	FeeHistoryEstimatorBlockCount         uint16                        `env:"FEE_HISTORY_ESTIMATOR_BLOCK_COUNT"`
+	FeeHistoryEstimatorRewardPercentile   uint16                        `env:"FEE_HISTORY_ESTIMATOR_REWARD_PERCENTILE"`
 	GasEstimatorMode                      string                        `env:"GAS_ESTIMATOR_MODE"`
 	GasUpdaterBatchSize                   uint32                        `env:"GAS_UPDATER_BATCH_SIZE"`
 	GasUpdaterBlockDelay                  uint16                        `env:"GAS_UPDATER_BLOCK_DELAY"`
@@ -67,6 +74,9 @@ type ConfigSchema struct {
 	GasUpdaterEnabled                     bool                          `env:"GAS_UPDATER_ENABLED"`
 	GasUpdaterTransactionPercentile       uint16                        `env:"GAS_UPDATER_TRANSACTION_PERCENTILE" default:"60"`
 	GlobalLockRetryInterval               models.Duration               `env:"GLOBAL_LOCK_RETRY_INTERVAL" default:"1s"`
+	HAEnabled                             bool                          `env:"HA_ENABLED" default:"false"`
+	HALeaseDuration                       time.Duration                 `env:"HA_LEASE_DURATION" default:"15s"`
+	HALeaseRefreshInterval                time.Duration                 `env:"HA_LEASE_REFRESH_INTERVAL" default:"5s"`
 	HTTPServerWriteTimeout                time.Duration                 `env:"HTTP_SERVER_WRITE_TIMEOUT" default:"10s"`
 	InsecureFastScrypt                    bool                          `env:"INSECURE_FAST_SCRYPT" default:"false"`
 	InsecureSkipVerify                    bool                          `env:"INSECURE_SKIP_VERIFY" default:"false"`
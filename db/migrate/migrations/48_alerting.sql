@@ -0,0 +1,32 @@
+-- +goose Up
+CREATE TABLE alert_notifiers (
+	id BIGSERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	type TEXT NOT NULL,
+	config jsonb NOT NULL DEFAULT '{}',
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	CONSTRAINT chk_alert_notifiers_type CHECK (type IN ('webhook', 'smtp', 'slack'))
+);
+CREATE UNIQUE INDEX idx_alert_notifiers_name ON alert_notifiers (name);
+
+CREATE TABLE alert_rules (
+	id BIGSERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	type TEXT NOT NULL,
+	threshold numeric(78,18) NOT NULL,
+	time_window bigint NOT NULL DEFAULT 0,
+	cooldown bigint NOT NULL DEFAULT 0,
+	job_id int REFERENCES jobs (id) ON DELETE CASCADE DEFERRABLE INITIALLY IMMEDIATE,
+	notifier_ids bigint[] NOT NULL DEFAULT '{}',
+	enabled boolean NOT NULL DEFAULT true,
+	last_fired_at timestamp with time zone,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	CONSTRAINT chk_alert_rules_type CHECK (type IN ('eth_balance_below', 'phb_balance_below', 'head_stalled', 'tx_unconfirmed', 'job_error_spike'))
+);
+CREATE UNIQUE INDEX idx_alert_rules_name ON alert_rules (name);
+
+-- +goose Down
+DROP TABLE alert_rules;
+DROP TABLE alert_notifiers;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE eth_key_states ADD COLUMN external_signer text, ADD COLUMN external_key_ref text;
+ALTER TABLE eth_key_states ADD CONSTRAINT chk_external_signer CHECK (external_signer IS NULL OR external_signer IN ('clef', 'pkcs11'));
+
+-- +goose Down
+ALTER TABLE eth_key_states DROP CONSTRAINT chk_external_signer;
+ALTER TABLE eth_key_states DROP COLUMN external_signer, DROP COLUMN external_key_ref;
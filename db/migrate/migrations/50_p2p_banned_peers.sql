@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE p2p_banned_peers (
+	local_peer_id text NOT NULL,
+	remote_peer_id text NOT NULL,
+	created_at timestamptz NOT NULL,
+	PRIMARY KEY(local_peer_id, remote_peer_id)
+);
+
+-- +goose Down
+DROP TABLE p2p_banned_peers;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE offchainreporting_oracle_specs ADD COLUMN observation_fallback_staleness bigint;
+
+-- +goose Down
+ALTER TABLE offchainreporting_oracle_specs DROP COLUMN observation_fallback_staleness;
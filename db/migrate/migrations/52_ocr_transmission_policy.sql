@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE offchainreporting_oracle_specs
+    ADD COLUMN transmission_maintenance_windows text[],
+    ADD COLUMN max_transmissions_per_hour integer,
+    ADD COLUMN gas_spike_gas_price_wei numeric(78,0),
+    ADD COLUMN gas_spike_alpha_ppb numeric(20,0),
+    ADD COLUMN gas_spike_delta_c bigint;
+
+-- +goose Down
+ALTER TABLE offchainreporting_oracle_specs
+    DROP COLUMN transmission_maintenance_windows,
+    DROP COLUMN max_transmissions_per_hour,
+    DROP COLUMN gas_spike_gas_price_wei,
+    DROP COLUMN gas_spike_alpha_ppb,
+    DROP COLUMN gas_spike_delta_c;
@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE p2p_bootstrap_peer_lists (
+	name text PRIMARY KEY,
+	peers text[] NOT NULL,
+	created_at timestamptz NOT NULL,
+	updated_at timestamptz NOT NULL
+);
+
+ALTER TABLE offchainreporting_oracle_specs
+    ADD COLUMN p2p_bootstrap_peer_list text REFERENCES p2p_bootstrap_peer_lists (name),
+    ADD COLUMN p2p_bootstrap_dns_name text;
+
+-- +goose Down
+ALTER TABLE offchainreporting_oracle_specs
+    DROP COLUMN p2p_bootstrap_peer_list,
+    DROP COLUMN p2p_bootstrap_dns_name;
+
+DROP TABLE p2p_bootstrap_peer_lists;
@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE event_trigger_specs (
+	id SERIAL PRIMARY KEY,
+	contract_address bytea NOT NULL,
+	event text NOT NULL,
+	topic_filters jsonb,
+	min_incoming_confirmations bigint,
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	CONSTRAINT contract_address_len_chk CHECK (octet_length(contract_address) = 20)
+);
+
+ALTER TABLE jobs ADD COLUMN event_trigger_spec_id INT REFERENCES event_trigger_specs(id) ON DELETE CASCADE,
+DROP CONSTRAINT chk_only_one_spec,
+ADD CONSTRAINT chk_only_one_spec CHECK (
+	num_nonnulls(offchainreporting_oracle_spec_id, direct_request_spec_id, flux_monitor_spec_id, keeper_spec_id, cron_spec_id, vrf_spec_id, webhook_spec_id, event_trigger_spec_id) = 1
+);
+CREATE UNIQUE INDEX idx_jobs_unique_event_trigger_spec_id ON jobs (event_trigger_spec_id);
+
+-- +goose Down
+ALTER TABLE jobs DROP CONSTRAINT chk_only_one_spec,
+ADD CONSTRAINT chk_only_one_spec CHECK (
+	num_nonnulls(offchainreporting_oracle_spec_id, direct_request_spec_id, flux_monitor_spec_id, keeper_spec_id, cron_spec_id, vrf_spec_id, webhook_spec_id) = 1
+);
+
+ALTER TABLE jobs DROP COLUMN event_trigger_spec_id;
+DROP TABLE IF EXISTS event_trigger_specs;
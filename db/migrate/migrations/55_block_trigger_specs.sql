@@ -0,0 +1,37 @@
+-- +goose Up
+CREATE TABLE block_trigger_specs (
+	id SERIAL PRIMARY KEY,
+	block_interval bigint NOT NULL CHECK (block_interval > 0),
+	start_offset bigint NOT NULL DEFAULT 0 CHECK (start_offset >= 0),
+	confirmations bigint NOT NULL DEFAULT 0 CHECK (confirmations >= 0),
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL
+);
+
+ALTER TABLE jobs ADD COLUMN block_trigger_spec_id INT REFERENCES block_trigger_specs(id) ON DELETE CASCADE,
+DROP CONSTRAINT chk_only_one_spec,
+ADD CONSTRAINT chk_only_one_spec CHECK (
+	num_nonnulls(offchainreporting_oracle_spec_id, direct_request_spec_id, flux_monitor_spec_id, keeper_spec_id, cron_spec_id, vrf_spec_id, webhook_spec_id, event_trigger_spec_id, block_trigger_spec_id) = 1
+);
+CREATE UNIQUE INDEX idx_jobs_unique_block_trigger_spec_id ON jobs (block_trigger_spec_id);
+
+-- Block heights a blocktrigger job has run for. A height runs at most once,
+-- even if a re-org replaces the block.
+CREATE TABLE block_trigger_runs (
+	job_id int NOT NULL REFERENCES jobs (id) ON DELETE CASCADE,
+	block_number bigint NOT NULL,
+	block_hash bytea NOT NULL,
+	created_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (job_id, block_number)
+);
+
+-- +goose Down
+DROP TABLE block_trigger_runs;
+
+ALTER TABLE jobs DROP CONSTRAINT chk_only_one_spec,
+ADD CONSTRAINT chk_only_one_spec CHECK (
+	num_nonnulls(offchainreporting_oracle_spec_id, direct_request_spec_id, flux_monitor_spec_id, keeper_spec_id, cron_spec_id, vrf_spec_id, webhook_spec_id, event_trigger_spec_id) = 1
+);
+
+ALTER TABLE jobs DROP COLUMN block_trigger_spec_id;
+DROP TABLE IF EXISTS block_trigger_specs;
@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE cron_specs
+	ADD COLUMN misfire_policy text NOT NULL DEFAULT 'skip' CHECK (misfire_policy IN ('skip', 'runOnce', 'catchUp')),
+	ADD COLUMN jitter bigint NOT NULL DEFAULT 0 CHECK (jitter >= 0),
+	ADD COLUMN last_execution_at timestamp with time zone,
+	ADD COLUMN next_execution_at timestamp with time zone;
+
+-- +goose Down
+ALTER TABLE cron_specs
+	DROP COLUMN misfire_policy,
+	DROP COLUMN jitter,
+	DROP COLUMN last_execution_at,
+	DROP COLUMN next_execution_at;
@@ -0,0 +1,16 @@
+-- +goose Up
+ALTER TABLE webhook_specs ADD COLUMN signing_secret text CHECK (signing_secret IS NULL OR length(signing_secret) >= 32);
+
+-- Signatures of accepted signed webhook requests, kept for as long as their
+-- timestamp is within tolerance so that requests cannot be replayed.
+CREATE TABLE webhook_signed_requests (
+	webhook_spec_id int NOT NULL REFERENCES webhook_specs (id) ON DELETE CASCADE,
+	signature bytea NOT NULL,
+	received_at timestamp with time zone NOT NULL,
+	PRIMARY KEY (webhook_spec_id, signature)
+);
+CREATE INDEX idx_webhook_signed_requests_received_at ON webhook_signed_requests (received_at);
+
+-- +goose Down
+DROP TABLE webhook_signed_requests;
+ALTER TABLE webhook_specs DROP COLUMN signing_secret;
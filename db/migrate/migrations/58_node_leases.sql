@@ -0,0 +1,13 @@
+-- +goose Up
+-- Leases held by one node of an active/passive pair. The fencing token
+-- increases every time the lease changes hands.
+CREATE TABLE node_leases (
+	name text PRIMARY KEY,
+	holder_id uuid NOT NULL,
+	fencing_token bigint NOT NULL,
+	expires_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL
+);
+
+-- +goose Down
+DROP TABLE node_leases;
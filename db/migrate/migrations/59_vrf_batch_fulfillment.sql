@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE vrf_specs
+	ADD COLUMN batch_fulfillment_enabled bool NOT NULL DEFAULT false,
+	ADD COLUMN batch_coordinator_address bytea CHECK (octet_length(batch_coordinator_address) = 20),
+	ADD COLUMN batch_fulfillment_size integer NOT NULL DEFAULT 0 CHECK (batch_fulfillment_size >= 0),
+	ADD COLUMN batch_fulfillment_timeout bigint NOT NULL DEFAULT 0 CHECK (batch_fulfillment_timeout >= 0),
+	ADD CONSTRAINT chk_batch_coordinator_address CHECK (NOT batch_fulfillment_enabled OR batch_coordinator_address IS NOT NULL);
+
+-- +goose Down
+ALTER TABLE vrf_specs
+	DROP CONSTRAINT chk_batch_coordinator_address,
+	DROP COLUMN batch_fulfillment_enabled,
+	DROP COLUMN batch_coordinator_address,
+	DROP COLUMN batch_fulfillment_size,
+	DROP COLUMN batch_fulfillment_timeout;
@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE direct_request_earnings (
+	id BIGSERIAL PRIMARY KEY,
+	job_id int REFERENCES jobs(id) ON DELETE SET NULL,
+	contract_address bytea NOT NULL CHECK (octet_length(contract_address) = 20),
+	request_id bytea NOT NULL CHECK (octet_length(request_id) = 32),
+	requester bytea NOT NULL CHECK (octet_length(requester) = 20),
+	payment numeric(78,0) NOT NULL,
+	request_tx_hash bytea NOT NULL,
+	fulfillment_tx_hash bytea,
+	fulfilled_at timestamp with time zone,
+	cancelled_at timestamp with time zone,
+	created_at timestamp with time zone NOT NULL,
+	CONSTRAINT direct_request_earnings_request_key UNIQUE (contract_address, request_id)
+);
+
+CREATE INDEX idx_direct_request_earnings_fulfilled_at ON direct_request_earnings (fulfilled_at) WHERE fulfilled_at IS NOT NULL;
+CREATE INDEX idx_direct_request_earnings_job_id ON direct_request_earnings (job_id);
+
+-- +goose Down
+DROP TABLE direct_request_earnings;
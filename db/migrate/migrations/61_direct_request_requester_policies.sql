@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE direct_request_requester_policies (
+	id BIGSERIAL PRIMARY KEY,
+	requester bytea CHECK (octet_length(requester) = 20),
+	denied boolean NOT NULL DEFAULT false,
+	min_contract_payment numeric(78,0) CHECK (min_contract_payment >= 0),
+	min_payment_per_gwei numeric(78,0) CHECK (min_payment_per_gwei >= 0),
+	max_requests bigint NOT NULL DEFAULT 0 CHECK (max_requests >= 0),
+	rate_limit_period bigint NOT NULL DEFAULT 0 CHECK (rate_limit_period >= 0),
+	created_at timestamp with time zone NOT NULL,
+	updated_at timestamp with time zone NOT NULL,
+	CONSTRAINT chk_rate_limit CHECK ((max_requests = 0) = (rate_limit_period = 0))
+);
+
+-- A NULL requester is the default policy, of which there is at most one
+CREATE UNIQUE INDEX idx_direct_request_requester_policies_requester ON direct_request_requester_policies ((coalesce(requester, '\x'::bytea)));
+
+CREATE INDEX idx_direct_request_earnings_requester_created_at ON direct_request_earnings (requester, created_at);
+
+-- +goose Down
+DROP INDEX idx_direct_request_earnings_requester_created_at;
+DROP TABLE direct_request_requester_policies;
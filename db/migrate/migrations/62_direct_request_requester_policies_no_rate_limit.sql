@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE direct_request_requester_policies ADD COLUMN no_rate_limit boolean NOT NULL DEFAULT false;
+ALTER TABLE direct_request_requester_policies ADD CONSTRAINT chk_no_rate_limit CHECK (NOT no_rate_limit OR max_requests = 0);
+
+-- +goose Down
+ALTER TABLE direct_request_requester_policies DROP CONSTRAINT chk_no_rate_limit;
+ALTER TABLE direct_request_requester_policies DROP COLUMN no_rate_limit;
@@ -29,6 +29,9 @@ func NewLockingStrategy(ct Connection) (LockingStrategy, error) {
 type LockingStrategy interface {
 	Lock(timeout models.Duration) error
 	Unlock(timeout models.Duration) error
+	// Release unlocks, and makes later calls to Lock succeed without taking
+	// the lock again
+	Release(timeout models.Duration) error
 }
 
 type PostgresLockingStrategy struct {
@@ -132,6 +135,13 @@ func logRetry(count int) {
 	}
 }
 
+func (s *PostgresLockingStrategy) Release(timeout models.Duration) error {
+	s.m.Lock()
+	s.config.locking = false
+	s.m.Unlock()
+	return s.Unlock(timeout)
+}
+
 func (s *PostgresLockingStrategy) Unlock(timeout models.Duration) error {
 	s.m.Lock()
 	defer s.m.Unlock()
@@ -93,6 +93,13 @@ func (orm *ORM) MustEnsureAdvisoryLock() error {
 	return nil
 }
 
+// ReleaseAdvisoryLock releases the advisory lock and stops taking it. Nodes
+// running with HA hold it only while migrating and setting up keys, after
+// which the leader lease decides which node is active.
+func (orm *ORM) ReleaseAdvisoryLock() error {
+	return orm.lockingStrategy.Release(orm.advisoryLockTimeout)
+}
+
 func displayTimeout(timeout models.Duration) string {
 	if timeout.IsInstant() {
 		return "indefinite"
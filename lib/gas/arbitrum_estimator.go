@@ -0,0 +1,224 @@
+package gas
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"PhoenixOracle/db/models"
+	"PhoenixOracle/lib/logger"
+	"PhoenixOracle/util"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+)
+
+var _ Estimator = &arbitrumEstimator{}
+
+var (
+	// ArbGasInfoAddress is the address of the ArbGasInfo precompile
+	ArbGasInfoAddress = common.HexToAddress("0x000000000000000000000000000000000000006C")
+	// getPricesInWei() returns (perL2Tx, perL1CalldataByte, perStorageAllocation,
+	// perArbGasBase, perArbGasCongestion, perArbGasTotal)
+	arbGasInfoGetPricesInWei = crypto.Keccak256([]byte("getPricesInWei()"))[:4]
+)
+
+type arbitrumRPCClient interface {
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// ArbitrumPrices is the subset of ArbGasInfo.getPricesInWei used for
+// estimation
+type ArbitrumPrices struct {
+	PerL2Tx           *big.Int
+	PerL1CalldataByte *big.Int
+	PerArbGasTotal    *big.Int
+}
+
+// L1Gas returns the number of L2 gas units that will be charged to cover the
+// L1 cost of posting a transaction with the given calldata.
+func (p ArbitrumPrices) L1Gas(calldata []byte) uint64 {
+	if p.PerArbGasTotal == nil || p.PerArbGasTotal.Sign() <= 0 {
+		return 0
+	}
+	l1Wei := new(big.Int).Mul(p.PerL1CalldataByte, big.NewInt(int64(len(calldata))))
+	l1Wei.Add(l1Wei, p.PerL2Tx)
+	// Round up so the limit always covers the full L1 component
+	l1Gas, rem := new(big.Int).QuoRem(l1Wei, p.PerArbGasTotal, new(big.Int))
+	if rem.Sign() > 0 {
+		l1Gas.Add(l1Gas, big.NewInt(1))
+	}
+	return l1Gas.Uint64()
+}
+
+// arbitrumEstimator prices transactions using the ArbGasInfo precompile. On
+// Arbitrum the cost of posting calldata to L1 is charged in L2 gas, so the
+// gas limit must include an L1 component on top of the execution gas.
+type arbitrumEstimator struct {
+	utils.StartStopOnce
+
+	config     Config
+	client     arbitrumRPCClient
+	pollPeriod time.Duration
+
+	pricesMu sync.RWMutex
+	prices   *ArbitrumPrices
+
+	chForceRefetch chan (chan struct{})
+	chInitialised  chan struct{}
+	chStop         chan struct{}
+	chDone         chan struct{}
+}
+
+// NewArbitrumEstimator returns a new arbitrum estimator
+func NewArbitrumEstimator(config Config, client arbitrumRPCClient) Estimator {
+	return &arbitrumEstimator{
+		config:         config,
+		client:         client,
+		pollPeriod:     10 * time.Second,
+		chForceRefetch: make(chan (chan struct{})),
+		chInitialised:  make(chan struct{}),
+		chStop:         make(chan struct{}),
+		chDone:         make(chan struct{}),
+	}
+}
+
+func (a *arbitrumEstimator) Start() error {
+	return a.StartOnce("ArbitrumEstimator", func() error {
+		go a.run()
+		<-a.chInitialised
+		return nil
+	})
+}
+func (a *arbitrumEstimator) Close() error {
+	return a.StopOnce("ArbitrumEstimator", func() error {
+		close(a.chStop)
+		<-a.chDone
+		return nil
+	})
+}
+
+func (a *arbitrumEstimator) run() {
+	defer close(a.chDone)
+
+	t := a.refreshPrices()
+	close(a.chInitialised)
+
+	for {
+		select {
+		case <-a.chStop:
+			return
+		case ch := <-a.chForceRefetch:
+			t.Stop()
+			t = a.refreshPrices()
+			close(ch)
+		case <-t.C:
+			t = a.refreshPrices()
+		}
+	}
+}
+
+func (a *arbitrumEstimator) refreshPrices() (t *time.Timer) {
+	t = time.NewTimer(utils.WithJitter(a.pollPeriod))
+
+	ctx, cancel := utils.ContextFromChan(a.chStop)
+	defer cancel()
+	ctx, cancel2 := context.WithTimeout(ctx, maxEthNodeRequestTime)
+	defer cancel2()
+
+	res, err := a.client.CallContract(ctx, ethereum.CallMsg{To: &ArbGasInfoAddress, Data: arbGasInfoGetPricesInWei}, nil)
+	if err != nil {
+		logger.Warnf("ArbitrumEstimator: Failed to refresh prices, got error: %s", err)
+		return
+	}
+	prices, err := parseArbGasInfoPrices(res)
+	if err != nil {
+		logger.Warnf("ArbitrumEstimator: Failed to parse prices, got error: %s", err)
+		return
+	}
+
+	logger.Debugw("ArbitrumEstimator#refreshPrices", "perL2Tx", prices.PerL2Tx, "perL1CalldataByte", prices.PerL1CalldataByte, "perArbGasTotal", prices.PerArbGasTotal)
+
+	a.pricesMu.Lock()
+	defer a.pricesMu.Unlock()
+	a.prices = &prices
+	return
+}
+
+func parseArbGasInfoPrices(b []byte) (prices ArbitrumPrices, err error) {
+	const word = 32
+	if len(b) != 6*word {
+		return prices, errors.Errorf("expected getPricesInWei to return 6 words, got %d bytes", len(b))
+	}
+	prices.PerL2Tx = new(big.Int).SetBytes(b[0:word])
+	prices.PerL1CalldataByte = new(big.Int).SetBytes(b[word : 2*word])
+	prices.PerArbGasTotal = new(big.Int).SetBytes(b[5*word : 6*word])
+	return prices, nil
+}
+
+func (a *arbitrumEstimator) OnNewLongestChain(_ context.Context, _ models.Head) {}
+
+func (a *arbitrumEstimator) EstimateGas(calldata []byte, l2GasLimit uint64, opts ...Opt) (gasPrice *big.Int, chainSpecificGasLimit uint64, err error) {
+	ok := a.IfStarted(func() {
+		var forceRefetch bool
+		for _, opt := range opts {
+			if opt == OptForceRefetch {
+				forceRefetch = true
+			}
+		}
+		if forceRefetch {
+			ch := make(chan struct{})
+			a.chForceRefetch <- ch
+			select {
+			case <-ch:
+			case <-a.chStop:
+				err = errors.New("estimator stopped")
+				return
+			}
+		}
+		prices := a.getPrices()
+		if prices == nil {
+			err = errors.New("failed to estimate arbitrum gas; prices not set")
+			return
+		}
+		gasPrice = prices.PerArbGasTotal
+		if max := a.config.EvmMaxGasPriceWei(); gasPrice.Cmp(max) > 0 {
+			err = errors.Errorf("arbitrum gas price of %s exceeds configured max gas price of %s", gasPrice.String(), max.String())
+			return
+		}
+		chainSpecificGasLimit = a.chainSpecificGasLimit(*prices, calldata, l2GasLimit)
+		logger.Debugw("ArbitrumEstimator#EstimateGas", "gasPrice", gasPrice, "l2GasLimit", l2GasLimit, "chainSpecificGasLimit", chainSpecificGasLimit)
+	})
+	if !ok {
+		return nil, 0, errors.New("estimator is not started")
+	}
+	return
+}
+
+// BumpGas bumps the price as usual but recomputes the L1 component of the
+// gas limit at current prices, since a spike in L1 prices is a common
+// reason for an arbitrum transaction to get stuck.
+func (a *arbitrumEstimator) BumpGas(calldata []byte, originalGasPrice *big.Int, l2GasLimit uint64) (bumpedGasPrice *big.Int, chainSpecificGasLimit uint64, err error) {
+	prices := a.getPrices()
+	if prices == nil {
+		return nil, 0, errors.New("failed to bump arbitrum gas; prices not set")
+	}
+	bumpedGasPrice, err = bumpGasPrice(a.config, prices.PerArbGasTotal, originalGasPrice)
+	if err != nil {
+		return nil, 0, err
+	}
+	chainSpecificGasLimit = a.chainSpecificGasLimit(*prices, calldata, l2GasLimit)
+	return
+}
+
+func (a *arbitrumEstimator) chainSpecificGasLimit(prices ArbitrumPrices, calldata []byte, l2GasLimit uint64) uint64 {
+	return applyMultiplier(l2GasLimit, a.config.EvmGasLimitMultiplier()) + prices.L1Gas(calldata)
+}
+
+func (a *arbitrumEstimator) getPrices() *ArbitrumPrices {
+	a.pricesMu.RLock()
+	defer a.pricesMu.RUnlock()
+	return a.prices
+}
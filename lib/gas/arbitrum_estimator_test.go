@@ -0,0 +1,85 @@
+package gas
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestArbitrumPrices_L1Gas(t *testing.T) {
+	prices := ArbitrumPrices{
+		PerL2Tx:           big.NewInt(1000),
+		PerL1CalldataByte: big.NewInt(10),
+		PerArbGasTotal:    big.NewInt(100),
+	}
+	tests := []struct {
+		name     string
+		prices   ArbitrumPrices
+		calldata []byte
+		expected uint64
+	}{
+		{"rounds up a partial unit of gas", prices, make([]byte, 5), 11},
+		{"exact multiple", prices, make([]byte, 10), 11},
+		{"no calldata still pays for the transaction", prices, nil, 10},
+		{"zero ArbGas price", ArbitrumPrices{PerL2Tx: big.NewInt(1000), PerL1CalldataByte: big.NewInt(10), PerArbGasTotal: big.NewInt(0)}, make([]byte, 5), 0},
+		{"no prices", ArbitrumPrices{}, make([]byte, 5), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.prices.L1Gas(tt.calldata); got != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseArbGasInfoPrices(t *testing.T) {
+	var words []byte
+	for i := int64(1); i <= 6; i++ {
+		words = append(words, common.LeftPadBytes(big.NewInt(i*1000).Bytes(), 32)...)
+	}
+
+	tests := []struct {
+		name        string
+		b           []byte
+		expected    ArbitrumPrices
+		expectedErr bool
+	}{
+		{
+			"reads perL2Tx, perL1CalldataByte and perArbGasTotal",
+			words,
+			ArbitrumPrices{PerL2Tx: big.NewInt(1000), PerL1CalldataByte: big.NewInt(2000), PerArbGasTotal: big.NewInt(6000)},
+			false,
+		},
+		{"too short", words[:5*32], ArbitrumPrices{}, true},
+		{"too long", append(append([]byte{}, words...), make([]byte, 32)...), ArbitrumPrices{}, true},
+		{"empty", nil, ArbitrumPrices{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prices, err := parseArbGasInfoPrices(tt.b)
+			if tt.expectedErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, p := range []struct {
+				name          string
+				got, expected *big.Int
+			}{
+				{"PerL2Tx", prices.PerL2Tx, tt.expected.PerL2Tx},
+				{"PerL1CalldataByte", prices.PerL1CalldataByte, tt.expected.PerL1CalldataByte},
+				{"PerArbGasTotal", prices.PerArbGasTotal, tt.expected.PerArbGasTotal},
+			} {
+				if p.got.Cmp(p.expected) != 0 {
+					t.Errorf("%s: expected %s, got %s", p.name, p.expected, p.got)
+				}
+			}
+		})
+	}
+}
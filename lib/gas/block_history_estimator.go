@@ -136,7 +136,7 @@ func (b *BlockHistoryEstimator) getGasPrice() *big.Int {
 	return b.gasPrice
 }
 
-func (b *BlockHistoryEstimator) BumpGas(originalGasPrice *big.Int, gasLimit uint64) (bumpedGasPrice *big.Int, chainSpecificGasLimit uint64, err error) {
+func (b *BlockHistoryEstimator) BumpGas(_ []byte, originalGasPrice *big.Int, gasLimit uint64) (bumpedGasPrice *big.Int, chainSpecificGasLimit uint64, err error) {
 	return BumpGasPriceOnly(b.config, b.getGasPrice(), originalGasPrice, gasLimit)
 }
 
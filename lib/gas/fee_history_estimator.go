@@ -0,0 +1,333 @@
+package gas
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"PhoenixOracle/core/service/ethereum"
+	"PhoenixOracle/db/models"
+	"PhoenixOracle/lib/logger"
+	"PhoenixOracle/util"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	promFeeHistoryEstimatorBaseFee = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gas_fee_history_predicted_base_fee",
+		Help: "Base fee predicted for the next block by the fee history estimator (in Wei)",
+	})
+	promFeeHistoryEstimatorTip = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gas_fee_history_priority_fee",
+		Help: "Priority fee at the configured reward percentile (in Wei)",
+	})
+	promFeeHistoryEstimatorSetGasPrice = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "gas_fee_history_set_gas_price",
+		Help: "Gas price set by the fee history estimator (in Wei)",
+	})
+)
+
+const (
+	// EIP-1559 constants used to project the base fee forward
+	baseFeeChangeDenominator = 8
+	elasticityMultiplier     = 2
+)
+
+var _ Estimator = &FeeHistoryEstimator{}
+
+// FeeHistoryEstimator derives a gas price from eth_feeHistory: the base fee
+// predicted for the next block plus the priority fee paid at the configured
+// reward percentile. This avoids downloading whole blocks like the
+// BlockHistoryEstimator does. If the node does not support eth_feeHistory the
+// estimator permanently delegates to a BlockHistoryEstimator.
+type FeeHistoryEstimator struct {
+	utils.StartStopOnce
+	ethClient ethereum.Client
+	config    Config
+	mb        *utils.Mailbox
+	wg        *sync.WaitGroup
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+
+	gasPrice   *big.Int
+	baseFee    *big.Int
+	gasPriceMu sync.RWMutex
+
+	fallbackMu sync.RWMutex
+	fallback   Estimator
+
+	logger *logger.Logger
+}
+
+func NewFeeHistoryEstimator(ethClient ethereum.Client, config Config) Estimator {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &FeeHistoryEstimator{
+		ethClient: ethClient,
+		config:    config,
+		mb:        utils.NewMailbox(1),
+		wg:        new(sync.WaitGroup),
+		ctx:       ctx,
+		ctxCancel: cancel,
+		logger:    logger.Default.With("id", "fee_history_estimator"),
+	}
+}
+
+func (f *FeeHistoryEstimator) OnNewLongestChain(ctx context.Context, head models.Head) {
+	if fb := f.getFallback(); fb != nil {
+		fb.OnNewLongestChain(ctx, head)
+		return
+	}
+	f.mb.Deliver(head)
+}
+
+func (f *FeeHistoryEstimator) Start() error {
+	return f.StartOnce("FeeHistoryEstimator", func() error {
+		f.logger.Debugw("FeeHistoryEstimator: starting")
+		ctx, cancel := context.WithTimeout(f.ctx, maxStartTime)
+		defer cancel()
+		if err := f.FetchAndRecalculate(ctx); err != nil {
+			if isFeeHistoryUnsupported(err) {
+				return f.startFallback(err)
+			}
+			f.logger.Warnw("FeeHistoryEstimator: initial fee history fetch failed", "err", err)
+		}
+		f.wg.Add(1)
+		go f.runLoop()
+		f.logger.Debugw("FeeHistoryEstimator: started")
+		return nil
+	})
+}
+
+func (f *FeeHistoryEstimator) Close() error {
+	return f.StopOnce("FeeHistoryEstimator", func() error {
+		f.ctxCancel()
+		f.wg.Wait()
+		if fb := f.getFallback(); fb != nil {
+			return fb.Close()
+		}
+		return nil
+	})
+}
+
+func (f *FeeHistoryEstimator) EstimateGas(calldata []byte, gasLimit uint64, opts ...Opt) (gasPrice *big.Int, chainSpecificGasLimit uint64, err error) {
+	if fb := f.getFallback(); fb != nil {
+		return fb.EstimateGas(calldata, gasLimit, opts...)
+	}
+	ok := f.IfStarted(func() {
+		chainSpecificGasLimit = applyMultiplier(gasLimit, f.config.EvmGasLimitMultiplier())
+		gasPrice = f.getGasPrice()
+	})
+	if !ok {
+		return nil, 0, errors.New("FeeHistoryEstimator is not started; cannot estimate gas")
+	}
+	if gasPrice == nil {
+		return nil, 0, errors.New("FeeHistoryEstimator has not finished the first gas estimation yet, likely because a failure on start")
+	}
+	return
+}
+
+func (f *FeeHistoryEstimator) BumpGas(calldata []byte, originalGasPrice *big.Int, gasLimit uint64) (bumpedGasPrice *big.Int, chainSpecificGasLimit uint64, err error) {
+	if fb := f.getFallback(); fb != nil {
+		return fb.BumpGas(calldata, originalGasPrice, gasLimit)
+	}
+	return BumpGasPriceOnly(f.config, f.getGasPrice(), originalGasPrice, gasLimit)
+}
+
+// PredictedBaseFee returns the base fee expected for the next block, or nil
+// if no fee history has been fetched yet.
+func (f *FeeHistoryEstimator) PredictedBaseFee() *big.Int {
+	f.gasPriceMu.RLock()
+	defer f.gasPriceMu.RUnlock()
+	return f.baseFee
+}
+
+func (f *FeeHistoryEstimator) getGasPrice() *big.Int {
+	f.gasPriceMu.RLock()
+	defer f.gasPriceMu.RUnlock()
+	return f.gasPrice
+}
+
+func (f *FeeHistoryEstimator) getFallback() Estimator {
+	f.fallbackMu.RLock()
+	defer f.fallbackMu.RUnlock()
+	return f.fallback
+}
+
+func (f *FeeHistoryEstimator) startFallback(cause error) error {
+	f.logger.Warnw("FeeHistoryEstimator: eth_feeHistory is not supported by the node, falling back to BlockHistoryEstimator", "err", cause)
+	fb := NewBlockHistoryEstimator(f.ethClient, f.config)
+	if err := fb.Start(); err != nil {
+		return err
+	}
+	f.fallbackMu.Lock()
+	f.fallback = fb
+	f.fallbackMu.Unlock()
+	return nil
+}
+
+func (f *FeeHistoryEstimator) runLoop() {
+	defer f.wg.Done()
+	for {
+		select {
+		case <-f.ctx.Done():
+			return
+		case <-f.mb.Notify():
+			if _, exists := f.mb.Retrieve(); !exists {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(f.ctx, maxEthNodeRequestTime)
+			err := f.FetchAndRecalculate(ctx)
+			cancel()
+			if err == nil {
+				continue
+			}
+			if isFeeHistoryUnsupported(err) {
+				if ferr := f.startFallback(err); ferr != nil {
+					f.logger.Errorw("FeeHistoryEstimator: failed to start fallback estimator", "err", ferr)
+					continue
+				}
+				return
+			}
+			f.logger.Warnw("FeeHistoryEstimator: error fetching fee history", "err", err)
+		}
+	}
+}
+
+// FeeHistory is the response of eth_feeHistory. BaseFeePerGas contains one
+// more element than GasUsedRatio: the base fee of the block after the newest
+// one in the range.
+type FeeHistory struct {
+	OldestBlock   *hexutil.Big     `json:"oldestBlock"`
+	BaseFeePerGas []*hexutil.Big   `json:"baseFeePerGas"`
+	GasUsedRatio  []float64        `json:"gasUsedRatio"`
+	Reward        [][]*hexutil.Big `json:"reward"`
+}
+
+func (f *FeeHistoryEstimator) FetchAndRecalculate(ctx context.Context) error {
+	blockCount := int64(f.config.FeeHistoryEstimatorBlockCount())
+	if blockCount <= 0 {
+		return errors.Errorf("FeeHistoryEstimator: block count must be > 0, got: %d", blockCount)
+	}
+	percentile := float64(f.config.FeeHistoryEstimatorRewardPercentile())
+
+	var history FeeHistory
+	if err := f.ethClient.CallContext(ctx, &history, "eth_feeHistory", Int64ToHex(blockCount), "latest", []float64{percentile}); err != nil {
+		return err
+	}
+	if len(history.BaseFeePerGas) == 0 {
+		return errors.New("FeeHistoryEstimator: eth_feeHistory returned no base fees")
+	}
+
+	baseFee := PredictNextBaseFee(history)
+	tip := percentileReward(history)
+	gasPrice := new(big.Int).Add(baseFee, tip)
+
+	f.logger.Debugw(fmt.Sprintf("FeeHistoryEstimator: setting new default gas price: %s Wei", gasPrice.String()),
+		"predictedBaseFeeWei", baseFee,
+		"priorityFeeWei", tip,
+		"rewardPercentile", percentile,
+		"blockCount", blockCount,
+	)
+	promFeeHistoryEstimatorBaseFee.Set(float64(baseFee.Int64()))
+	promFeeHistoryEstimatorTip.Set(float64(tip.Int64()))
+	f.setGasPrice(gasPrice, baseFee)
+	return nil
+}
+
+// PredictNextBaseFee returns the base fee expected for the block following the
+// fee history range. If the node already supplied it (as the final element of
+// baseFeePerGas) that value is used, otherwise it is computed from the newest
+// block with the EIP-1559 update rule. When the base fee has been rising over
+// the range, one further maximum increase is added to stay ahead of the trend.
+func PredictNextBaseFee(history FeeHistory) *big.Int {
+	fees := history.BaseFeePerGas
+	var next *big.Int
+	if len(fees) > len(history.GasUsedRatio) {
+		next = new(big.Int).Set(fees[len(fees)-1].ToInt())
+	} else {
+		last := fees[len(fees)-1].ToInt()
+		ratio := 0.5
+		if len(history.GasUsedRatio) > 0 {
+			ratio = history.GasUsedRatio[len(history.GasUsedRatio)-1]
+		}
+		next = nextBaseFee(last, ratio)
+	}
+
+	first := fees[0].ToInt()
+	if len(fees) > 1 && next.Cmp(first) > 0 {
+		next = nextBaseFee(next, 1)
+	}
+	return next
+}
+
+// nextBaseFee applies the EIP-1559 base fee update for a block that used the
+// given fraction of its gas limit.
+func nextBaseFee(baseFee *big.Int, gasUsedRatio float64) *big.Int {
+	target := 1.0 / elasticityMultiplier
+	delta := new(big.Float).Mul(
+		new(big.Float).SetInt(baseFee),
+		big.NewFloat((gasUsedRatio-target)/target/baseFeeChangeDenominator),
+	)
+	d, _ := delta.Int(nil)
+	next := new(big.Int).Add(baseFee, d)
+	if next.Sign() < 0 {
+		return big.NewInt(0)
+	}
+	return next
+}
+
+// percentileReward returns the median across blocks of the reward at the
+// requested percentile, ignoring empty blocks.
+func percentileReward(history FeeHistory) *big.Int {
+	rewards := make([]*big.Int, 0, len(history.Reward))
+	for i, r := range history.Reward {
+		if len(r) == 0 || r[0] == nil {
+			continue
+		}
+		if i < len(history.GasUsedRatio) && history.GasUsedRatio[i] == 0 {
+			continue
+		}
+		rewards = append(rewards, r[0].ToInt())
+	}
+	if len(rewards) == 0 {
+		return big.NewInt(0)
+	}
+	sort.Slice(rewards, func(i, j int) bool { return rewards[i].Cmp(rewards[j]) < 0 })
+	return rewards[len(rewards)/2]
+}
+
+func (f *FeeHistoryEstimator) setGasPrice(gasPrice, baseFee *big.Int) {
+	max := f.config.EvmMaxGasPriceWei()
+	min := f.config.EvmMinGasPriceWei()
+
+	f.gasPriceMu.Lock()
+	defer f.gasPriceMu.Unlock()
+	f.baseFee = baseFee
+	if gasPrice.Cmp(max) > 0 {
+		f.logger.Warnw(fmt.Sprintf("Calculated gas price of %s Wei exceeds ETH_MAX_GAS_PRICE_WEI=%[2]s, setting gas price to the maximum allowed value of %[2]s Wei instead", gasPrice.String(), max.String()), "gasPriceWei", gasPrice, "maxGasPriceWei", max)
+		f.gasPrice = max
+	} else if gasPrice.Cmp(min) < 0 {
+		f.logger.Warnw(fmt.Sprintf("Calculated gas price of %s Wei falls below ETH_MIN_GAS_PRICE_WEI=%[2]s, setting gas price to the minimum allowed value of %[2]s Wei instead", gasPrice.String(), min.String()), "gasPriceWei", gasPrice, "minGasPriceWei", min)
+		f.gasPrice = min
+	} else {
+		f.gasPrice = gasPrice
+	}
+	promFeeHistoryEstimatorSetGasPrice.Set(float64(f.gasPrice.Int64()))
+}
+
+// methodNotFoundErrorCode is the JSON-RPC error code for a call to a method
+// the server does not implement
+const methodNotFoundErrorCode = -32601
+
+// isFeeHistoryUnsupported reports whether err indicates that the RPC node
+// does not implement eth_feeHistory (e.g. pre-London clients).
+func isFeeHistoryUnsupported(err error) bool {
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr) && rpcErr.ErrorCode() == methodNotFoundErrorCode
+}
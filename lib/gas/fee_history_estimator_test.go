@@ -0,0 +1,152 @@
+package gas
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+func bigs(ns ...int64) []*hexutil.Big {
+	bs := make([]*hexutil.Big, len(ns))
+	for i, n := range ns {
+		bs[i] = (*hexutil.Big)(big.NewInt(n))
+	}
+	return bs
+}
+
+func TestNextBaseFee(t *testing.T) {
+	tests := []struct {
+		name         string
+		baseFee      int64
+		gasUsedRatio float64
+		expected     int64
+	}{
+		{"at target", 1000, 0.5, 1000},
+		{"full block", 1000, 1, 1125},
+		{"empty block", 1000, 0, 875},
+		{"above target rounds towards zero", 1000, 0.75, 1062},
+		{"below target rounds towards zero", 1000, 0.25, 938},
+		{"zero base fee", 0, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextBaseFee(big.NewInt(tt.baseFee), tt.gasUsedRatio); got.Cmp(big.NewInt(tt.expected)) != 0 {
+				t.Errorf("expected %d, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPredictNextBaseFee(t *testing.T) {
+	tests := []struct {
+		name     string
+		history  FeeHistory
+		expected int64
+	}{
+		{
+			"uses the next base fee supplied by the node",
+			FeeHistory{BaseFeePerGas: bigs(1000, 1000, 900), GasUsedRatio: []float64{0.5, 0.5}},
+			900,
+		},
+		{
+			"computes the next base fee from the newest block",
+			FeeHistory{BaseFeePerGas: bigs(1000), GasUsedRatio: []float64{0}},
+			875,
+		},
+		{
+			"assumes a block at target without gas used ratios",
+			FeeHistory{BaseFeePerGas: bigs(1000)},
+			1000,
+		},
+		{
+			"adds one maximum increase when the supplied base fee is rising",
+			FeeHistory{BaseFeePerGas: bigs(1000, 1100, 1200), GasUsedRatio: []float64{0.9, 0.9}},
+			1350,
+		},
+		{
+			"adds one maximum increase when the computed base fee is rising",
+			FeeHistory{BaseFeePerGas: bigs(1000, 1000), GasUsedRatio: []float64{0.5, 1}},
+			1265,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PredictNextBaseFee(tt.history); got.Cmp(big.NewInt(tt.expected)) != 0 {
+				t.Errorf("expected %d, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPercentileReward(t *testing.T) {
+	tests := []struct {
+		name     string
+		history  FeeHistory
+		expected int64
+	}{
+		{
+			"median of an odd number of blocks",
+			FeeHistory{Reward: [][]*hexutil.Big{bigs(10), bigs(30), bigs(20)}, GasUsedRatio: []float64{0.5, 0.5, 0.5}},
+			20,
+		},
+		{
+			"upper median of an even number of blocks",
+			FeeHistory{Reward: [][]*hexutil.Big{bigs(20), bigs(10)}, GasUsedRatio: []float64{0.5, 0.5}},
+			20,
+		},
+		{
+			"ignores empty blocks",
+			FeeHistory{Reward: [][]*hexutil.Big{bigs(10), bigs(1000), bigs(20), bigs(30)}, GasUsedRatio: []float64{0.5, 0, 0.5, 0.5}},
+			20,
+		},
+		{
+			"ignores missing rewards",
+			FeeHistory{Reward: [][]*hexutil.Big{{}, {nil}, bigs(5)}, GasUsedRatio: []float64{0.5, 0.5, 0.5}},
+			5,
+		},
+		{
+			"zero without rewards",
+			FeeHistory{},
+			0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentileReward(tt.history); got.Cmp(big.NewInt(tt.expected)) != 0 {
+				t.Errorf("expected %d, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+type testRPCError struct {
+	code int
+	msg  string
+}
+
+func (e testRPCError) Error() string  { return e.msg }
+func (e testRPCError) ErrorCode() int { return e.code }
+
+func TestIsFeeHistoryUnsupported(t *testing.T) {
+	methodNotFound := testRPCError{-32601, "the method eth_feeHistory does not exist/is not available"}
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"method not found", methodNotFound, true},
+		{"wrapped method not found", errors.Wrap(methodNotFound, "primary http (http://localhost:8545) call failed"), true},
+		{"other JSON-RPC error mentioning the method", testRPCError{-32000, "method not found"}, false},
+		{"plain error", errors.New("method not found"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isFeeHistoryUnsupported(tt.err); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -34,8 +34,12 @@ var (
 func NewEstimator(ethClient ethereum.Client, config Config) Estimator {
 	s := config.GasEstimatorMode()
 	switch s {
+	case "Arbitrum":
+		return NewArbitrumEstimator(config, ethClient)
 	case "BlockHistory":
 		return NewBlockHistoryEstimator(ethClient, config)
+	case "FeeHistory":
+		return NewFeeHistoryEstimator(ethClient, config)
 	case "FixedPrice":
 		return NewFixedPriceEstimator(config)
 	case "Optimism":
@@ -53,7 +57,7 @@ type Estimator interface {
 	Start() error
 	Close() error
 	EstimateGas(calldata []byte, gasLimit uint64, opts ...Opt) (gasPrice *big.Int, chainSpecificGasLimit uint64, err error)
-	BumpGas(originalGasPrice *big.Int, gasLimit uint64) (bumpedGasPrice *big.Int, chainSpecificGasLimit uint64, err error)
+	BumpGas(calldata []byte, originalGasPrice *big.Int, gasLimit uint64) (bumpedGasPrice *big.Int, chainSpecificGasLimit uint64, err error)
 }
 
 type Opt int
@@ -79,6 +83,8 @@ type Config interface {
 	EvmGasPriceDefault() *big.Int
 	EvmMaxGasPriceWei() *big.Int
 	EvmMinGasPriceWei() *big.Int
+	FeeHistoryEstimatorBlockCount() uint16
+	FeeHistoryEstimatorRewardPercentile() uint16
 	GasEstimatorMode() string
 }
 
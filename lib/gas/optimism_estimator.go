@@ -154,7 +154,7 @@ func (o *optimismEstimator) EstimateGas(calldata []byte, gasLimit uint64, opts .
 	return
 }
 
-func (o *optimismEstimator) BumpGas(originalGasPrice *big.Int, originalGasLimit uint64) (gasPrice *big.Int, gasLimit uint64, err error) {
+func (o *optimismEstimator) BumpGas(_ []byte, originalGasPrice *big.Int, originalGasLimit uint64) (gasPrice *big.Int, gasLimit uint64, err error) {
 	return nil, 0, errors.New("bump gas is not supported for optimism")
 }
 
@@ -312,7 +312,7 @@ func (o *optimism2Estimator) EstimateGas(_ []byte, l2GasLimit uint64, opts ...Op
 	return
 }
 
-func (o *optimism2Estimator) BumpGas(_ *big.Int, _ uint64) (bumpedGasPrice *big.Int, chainSpecificGasLimit uint64, err error) {
+func (o *optimism2Estimator) BumpGas(_ []byte, _ *big.Int, _ uint64) (bumpedGasPrice *big.Int, chainSpecificGasLimit uint64, err error) {
 	return nil, 0, errors.New("bump gas is not supported for optimism")
 }
 
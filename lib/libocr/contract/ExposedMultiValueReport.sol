@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: MIT
+pragma solidity ^0.7.0;
+pragma experimental ABIEncoderV2;
+
+/**
+ * @notice Decodes reports produced by the multi-value report plugin. Used to
+ * check that the off-chain encoding matches what a contract expects.
+ */
+contract ExposedMultiValueReport {
+
+  /**
+   * @notice splits a multi-value report into its parts
+   * @param _report serialized report, as produced by the multi-value plugin
+   * @return rawReportContext domain separation tag of the round
+   * @return rawObservers observer indices, in the order of the observations
+   * @return observations one row per observer, each with one value per column
+   */
+  function decodeReport(bytes memory _report)
+    public
+    pure
+    returns (
+      bytes32 rawReportContext,
+      bytes32 rawObservers,
+      int192[][] memory observations
+    )
+  {
+    (rawReportContext, rawObservers, observations) = abi.decode(
+      _report, (bytes32, bytes32, int192[][]));
+  }
+
+  /**
+   * @notice computes the median of each column of a multi-value report
+   * @param _report serialized report, as produced by the multi-value plugin
+   * @return result the median of each column, in column order
+   */
+  function medians(bytes memory _report)
+    public
+    pure
+    returns (int192[] memory result)
+  {
+    (,, int192[][] memory observations) = decodeReport(_report);
+    require(observations.length > 0, "no observations");
+    uint256 width = observations[0].length;
+    for (uint256 i = 1; i < observations.length; i++) {
+      require(observations[i].length == width, "ragged observations");
+    }
+
+    result = new int192[](width);
+    int192[] memory column = new int192[](observations.length);
+    for (uint256 c = 0; c < width; c++) {
+      for (uint256 i = 0; i < observations.length; i++) {
+        int192 v = observations[i][c];
+        uint256 j = i;
+        for (; j > 0 && column[j-1] > v; j--) {
+          column[j] = column[j-1];
+        }
+        column[j] = v;
+      }
+      result[c] = column[observations.length/2];
+    }
+  }
+}
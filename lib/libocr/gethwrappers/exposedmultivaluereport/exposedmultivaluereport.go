@@ -0,0 +1,256 @@
+// This binding was written by hand from the ABI of
+// contract/ExposedMultiValueReport.sol, in the shape abigen produces. It has
+// no bytecode, so it can only be bound to an already deployed contract.
+// Replace it with the abigen output (see go_generate.go, which needs solc
+// 0.7) to get a deployer.
+
+package exposedmultivaluereport
+
+import (
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+)
+
+// ExposedMultiValueReportABI is the input ABI used to generate the binding from.
+const ExposedMultiValueReportABI = "[{\"inputs\":[{\"internalType\":\"bytes\",\"name\":\"_report\",\"type\":\"bytes\"}],\"name\":\"decodeReport\",\"outputs\":[{\"internalType\":\"bytes32\",\"name\":\"rawReportContext\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"rawObservers\",\"type\":\"bytes32\"},{\"internalType\":\"int192[][]\",\"name\":\"observations\",\"type\":\"int192[][]\"}],\"stateMutability\":\"pure\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes\",\"name\":\"_report\",\"type\":\"bytes\"}],\"name\":\"medians\",\"outputs\":[{\"internalType\":\"int192[]\",\"name\":\"result\",\"type\":\"int192[]\"}],\"stateMutability\":\"pure\",\"type\":\"function\"}]"
+
+// ExposedMultiValueReport is an auto generated Go binding around an Ethereum contract.
+type ExposedMultiValueReport struct {
+	ExposedMultiValueReportCaller     // Read-only binding to the contract
+	ExposedMultiValueReportTransactor // Write-only binding to the contract
+	ExposedMultiValueReportFilterer   // Log filterer for contract events
+}
+
+// ExposedMultiValueReportCaller is an auto generated read-only Go binding around an Ethereum contract.
+type ExposedMultiValueReportCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ExposedMultiValueReportTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type ExposedMultiValueReportTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ExposedMultiValueReportFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type ExposedMultiValueReportFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ExposedMultiValueReportSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type ExposedMultiValueReportSession struct {
+	Contract     *ExposedMultiValueReport // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts            // Call options to use throughout this session
+	TransactOpts bind.TransactOpts        // Transaction auth options to use throughout this session
+}
+
+// ExposedMultiValueReportCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type ExposedMultiValueReportCallerSession struct {
+	Contract *ExposedMultiValueReportCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts                  // Call options to use throughout this session
+}
+
+// ExposedMultiValueReportTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type ExposedMultiValueReportTransactorSession struct {
+	Contract     *ExposedMultiValueReportTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts                  // Transaction auth options to use throughout this session
+}
+
+// ExposedMultiValueReportRaw is an auto generated low-level Go binding around an Ethereum contract.
+type ExposedMultiValueReportRaw struct {
+	Contract *ExposedMultiValueReport // Generic contract binding to access the raw methods on
+}
+
+// ExposedMultiValueReportCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type ExposedMultiValueReportCallerRaw struct {
+	Contract *ExposedMultiValueReportCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// ExposedMultiValueReportTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type ExposedMultiValueReportTransactorRaw struct {
+	Contract *ExposedMultiValueReportTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewExposedMultiValueReport creates a new instance of ExposedMultiValueReport, bound to a specific deployed contract.
+func NewExposedMultiValueReport(address common.Address, backend bind.ContractBackend) (*ExposedMultiValueReport, error) {
+	contract, err := bindExposedMultiValueReport(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &ExposedMultiValueReport{ExposedMultiValueReportCaller: ExposedMultiValueReportCaller{contract: contract}, ExposedMultiValueReportTransactor: ExposedMultiValueReportTransactor{contract: contract}, ExposedMultiValueReportFilterer: ExposedMultiValueReportFilterer{contract: contract}}, nil
+}
+
+// NewExposedMultiValueReportCaller creates a new read-only instance of ExposedMultiValueReport, bound to a specific deployed contract.
+func NewExposedMultiValueReportCaller(address common.Address, caller bind.ContractCaller) (*ExposedMultiValueReportCaller, error) {
+	contract, err := bindExposedMultiValueReport(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ExposedMultiValueReportCaller{contract: contract}, nil
+}
+
+// NewExposedMultiValueReportTransactor creates a new write-only instance of ExposedMultiValueReport, bound to a specific deployed contract.
+func NewExposedMultiValueReportTransactor(address common.Address, transactor bind.ContractTransactor) (*ExposedMultiValueReportTransactor, error) {
+	contract, err := bindExposedMultiValueReport(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ExposedMultiValueReportTransactor{contract: contract}, nil
+}
+
+// NewExposedMultiValueReportFilterer creates a new log filterer instance of ExposedMultiValueReport, bound to a specific deployed contract.
+func NewExposedMultiValueReportFilterer(address common.Address, filterer bind.ContractFilterer) (*ExposedMultiValueReportFilterer, error) {
+	contract, err := bindExposedMultiValueReport(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &ExposedMultiValueReportFilterer{contract: contract}, nil
+}
+
+// bindExposedMultiValueReport binds a generic wrapper to an already deployed contract.
+func bindExposedMultiValueReport(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(ExposedMultiValueReportABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_ExposedMultiValueReport *ExposedMultiValueReportRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _ExposedMultiValueReport.Contract.ExposedMultiValueReportCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_ExposedMultiValueReport *ExposedMultiValueReportRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _ExposedMultiValueReport.Contract.ExposedMultiValueReportTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_ExposedMultiValueReport *ExposedMultiValueReportRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _ExposedMultiValueReport.Contract.ExposedMultiValueReportTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_ExposedMultiValueReport *ExposedMultiValueReportCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _ExposedMultiValueReport.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_ExposedMultiValueReport *ExposedMultiValueReportTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _ExposedMultiValueReport.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_ExposedMultiValueReport *ExposedMultiValueReportTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _ExposedMultiValueReport.Contract.contract.Transact(opts, method, params...)
+}
+
+// DecodeReport is a free data retrieval call binding the contract method 0xd5853a62.
+//
+// Solidity: function decodeReport(bytes _report) pure returns(bytes32 rawReportContext, bytes32 rawObservers, int192[][] observations)
+func (_ExposedMultiValueReport *ExposedMultiValueReportCaller) DecodeReport(opts *bind.CallOpts, _report []byte) (struct {
+	RawReportContext [32]byte
+	RawObservers     [32]byte
+	Observations     [][]*big.Int
+}, error) {
+	var out []interface{}
+	err := _ExposedMultiValueReport.contract.Call(opts, &out, "decodeReport", _report)
+
+	outstruct := new(struct {
+		RawReportContext [32]byte
+		RawObservers     [32]byte
+		Observations     [][]*big.Int
+	})
+	if err != nil {
+		return *outstruct, err
+	}
+
+	outstruct.RawReportContext = *abi.ConvertType(out[0], new([32]byte)).(*[32]byte)
+	outstruct.RawObservers = *abi.ConvertType(out[1], new([32]byte)).(*[32]byte)
+	outstruct.Observations = *abi.ConvertType(out[2], new([][]*big.Int)).(*[][]*big.Int)
+
+	return *outstruct, err
+
+}
+
+// DecodeReport is a free data retrieval call binding the contract method 0xd5853a62.
+//
+// Solidity: function decodeReport(bytes _report) pure returns(bytes32 rawReportContext, bytes32 rawObservers, int192[][] observations)
+func (_ExposedMultiValueReport *ExposedMultiValueReportSession) DecodeReport(_report []byte) (struct {
+	RawReportContext [32]byte
+	RawObservers     [32]byte
+	Observations     [][]*big.Int
+}, error) {
+	return _ExposedMultiValueReport.Contract.DecodeReport(&_ExposedMultiValueReport.CallOpts, _report)
+}
+
+// DecodeReport is a free data retrieval call binding the contract method 0xd5853a62.
+//
+// Solidity: function decodeReport(bytes _report) pure returns(bytes32 rawReportContext, bytes32 rawObservers, int192[][] observations)
+func (_ExposedMultiValueReport *ExposedMultiValueReportCallerSession) DecodeReport(_report []byte) (struct {
+	RawReportContext [32]byte
+	RawObservers     [32]byte
+	Observations     [][]*big.Int
+}, error) {
+	return _ExposedMultiValueReport.Contract.DecodeReport(&_ExposedMultiValueReport.CallOpts, _report)
+}
+
+// Medians is a free data retrieval call binding the contract method 0x95e1d7c2.
+//
+// Solidity: function medians(bytes _report) pure returns(int192[] result)
+func (_ExposedMultiValueReport *ExposedMultiValueReportCaller) Medians(opts *bind.CallOpts, _report []byte) ([]*big.Int, error) {
+	var out []interface{}
+	err := _ExposedMultiValueReport.contract.Call(opts, &out, "medians", _report)
+
+	if err != nil {
+		return *new([]*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new([]*big.Int)).(*[]*big.Int)
+
+	return out0, err
+
+}
+
+// Medians is a free data retrieval call binding the contract method 0x95e1d7c2.
+//
+// Solidity: function medians(bytes _report) pure returns(int192[] result)
+func (_ExposedMultiValueReport *ExposedMultiValueReportSession) Medians(_report []byte) ([]*big.Int, error) {
+	return _ExposedMultiValueReport.Contract.Medians(&_ExposedMultiValueReport.CallOpts, _report)
+}
+
+// Medians is a free data retrieval call binding the contract method 0x95e1d7c2.
+//
+// Solidity: function medians(bytes _report) pure returns(int192[] result)
+func (_ExposedMultiValueReport *ExposedMultiValueReportCallerSession) Medians(_report []byte) ([]*big.Int, error) {
+	return _ExposedMultiValueReport.Contract.Medians(&_ExposedMultiValueReport.CallOpts, _report)
+}
@@ -0,0 +1,59 @@
+package exposedmultivaluereport
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"PhoenixOracle/lib/libocr/offchainreporting/reportplugin"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// TestDecodeReportABIMatchesMultiValuePlugin checks that the outputs of
+// decodeReport, which abi.decode the report as-is, unpack a report encoded by
+// reportplugin.MultiValue into the values it was built from.
+func TestDecodeReportABIMatchesMultiValuePlugin(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(ExposedMultiValueReportABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rawReportContext, rawObservers [32]byte
+	for i := range rawReportContext {
+		rawReportContext[i] = byte(i)
+	}
+	rawObservers[0], rawObservers[1], rawObservers[2] = 2, 0, 1
+	observations := [][]*big.Int{
+		{big.NewInt(1), big.NewInt(-100)},
+		{big.NewInt(2), big.NewInt(200)},
+		{big.NewInt(3), new(big.Int).Lsh(big.NewInt(1), 190)},
+	}
+
+	report, err := reportplugin.MultiValue{Width: 2}.EncodeReport(rawReportContext, rawObservers, observations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := parsed.Methods["decodeReport"].Outputs.Unpack(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 3 {
+		t.Fatalf("expected 3 outputs, got %d", len(values))
+	}
+	if got := values[0].([32]byte); got != rawReportContext {
+		t.Errorf("rawReportContext: expected %x, got %x", rawReportContext, got)
+	}
+	if got := values[1].([32]byte); got != rawObservers {
+		t.Errorf("rawObservers: expected %x, got %x", rawObservers, got)
+	}
+	got := values[2].([][]*big.Int)
+	if len(got) != len(observations) {
+		t.Fatalf("expected %d observations, got %d", len(observations), len(got))
+	}
+	for i := range observations {
+		for j := range observations[i] {
+			if got[i][j].Cmp(observations[i][j]) != 0 {
+				t.Errorf("observations[%d][%d]: expected %v, got %v", i, j, observations[i][j], got[i][j])
+			}
+		}
+	}
+}
@@ -8,3 +8,4 @@ package gethwrappers
 //go:generate ./compile.sh 1000 ../../contract/src/TestOffchainAggregator.sol
 //go:generate ./compile.sh 1000 ../../contract/src/TestValidator.sol
 //go:generate ./compile.sh 1000 ../../contract/src/AccessControlTestHelper.sol
+//go:generate ./compile.sh 1000 ../../contract/src/ExposedMultiValueReport.sol
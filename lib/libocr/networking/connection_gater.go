@@ -28,8 +28,10 @@ type allower interface {
 type connectionGater struct {
 	connLimiters map[p2ppeer.ID]*rate.Limiter
 	allowers     map[allower]struct{}
-	mutex        sync.RWMutex
-	logger       loghelper.LoggerWithContext
+	// banned peers are denied regardless of the allowers
+	banned map[p2ppeer.ID]struct{}
+	mutex  sync.RWMutex
+	logger loghelper.LoggerWithContext
 }
 
 func newConnectionGater(logger loghelper.LoggerWithContext) (*connectionGater, error) {
@@ -42,6 +44,7 @@ func newConnectionGater(logger loghelper.LoggerWithContext) (*connectionGater, e
 	return &connectionGater{
 		connLimiters: map[p2ppeer.ID]*rate.Limiter{},
 		allowers:     allowers,
+		banned:       make(map[p2ppeer.ID]struct{}),
 		mutex:        sync.RWMutex{},
 		logger:       logger,
 	}, nil
@@ -65,10 +68,38 @@ func (c *connectionGater) remove(g allower) {
 	delete(c.allowers, g)
 }
 
+func (c *connectionGater) ban(id p2ppeer.ID) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.banned[id] = struct{}{}
+}
+
+func (c *connectionGater) unban(id p2ppeer.ID) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.banned, id)
+}
+
+func (c *connectionGater) bannedPeers() (banned []p2ppeer.ID) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	for pid := range c.banned {
+		banned = append(banned, pid)
+	}
+	return
+}
+
 func (c *connectionGater) isAllowed(id p2ppeer.ID, checkRateLimit bool) bool {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
+	if _, isBanned := c.banned[id]; isBanned {
+		c.logger.Debug("ConnectionGater: denied access to banned peer", types.LogFields{
+			"remotePeerID": id,
+		})
+		return false
+	}
+
 	oneAllowerPasses := false
 	for g := range c.allowers {
 		if g.isAllowed(id) {
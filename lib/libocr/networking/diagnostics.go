@@ -0,0 +1,214 @@
+package networking
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	p2pnetwork "github.com/libp2p/go-libp2p-core/network"
+	p2ppeer "github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/peerstore"
+	ma "github.com/multiformats/go-multiaddr"
+	"github.com/pkg/errors"
+)
+
+// ErrV2Unsupported is returned by the Diagnostics methods that only see the
+// libp2p host of the V1 stack, when the peer also runs the V2 stack, whose
+// connections they would silently miss
+var ErrV2Unsupported = errors.New("not supported with the V2 networking stack")
+
+// Diagnostics exposes the connectivity of a peer for debugging and lets
+// operators intervene manually. It is implemented by the peer returned from
+// NewPeer.
+type Diagnostics interface {
+	// NetworkingStack is the stack the peer was configured with
+	NetworkingStack() NetworkingStack
+	// Connections lists the open connections to remote peers. It returns
+	// ErrV2Unsupported if the peer runs the V2 stack.
+	Connections() ([]ConnectionInfo, error)
+	// PeerAddresses lists the addresses known for each remote peer, as
+	// discovered through bootstrappers, the DHT or added manually
+	PeerAddresses() map[string][]string
+	// MessageCounters returns the OCR messages exchanged with each remote
+	// peer, keyed by peer ID
+	MessageCounters() map[string]MessageCounters
+
+	// AddPeerAddress permanently adds addr for peerID and dials it
+	AddPeerAddress(ctx context.Context, peerID string, addr string) error
+	// BanPeer refuses all connections to and from peerID and closes the
+	// open ones until UnbanPeer is called. It returns ErrV2Unsupported if the
+	// peer runs the V2 stack.
+	BanPeer(peerID string) error
+	UnbanPeer(peerID string) error
+	BannedPeers() []string
+}
+
+var _ Diagnostics = (*concretePeer)(nil)
+
+// ConnectionInfo describes an open connection to a remote peer
+type ConnectionInfo struct {
+	PeerID        string
+	Stack         NetworkingStack
+	Direction     string
+	RemoteAddress string
+	Opened        time.Time
+	// Latency is the moving average of the round trip time, or zero if it
+	// has not been measured yet
+	Latency time.Duration
+	Streams int
+}
+
+// MessageCounters counts the OCR messages exchanged with a remote peer
+type MessageCounters struct {
+	Sent          uint64
+	Received      uint64
+	Dropped       uint64
+	BytesSent     uint64
+	BytesReceived uint64
+}
+
+type messageCounters struct {
+	mu       sync.Mutex
+	counters map[p2ppeer.ID]*MessageCounters
+}
+
+func newMessageCounters() *messageCounters {
+	return &messageCounters{counters: make(map[p2ppeer.ID]*MessageCounters)}
+}
+
+func (m *messageCounters) get(id p2ppeer.ID) *MessageCounters {
+	c, ok := m.counters[id]
+	if !ok {
+		c = &MessageCounters{}
+		m.counters[id] = c
+	}
+	return c
+}
+
+func (m *messageCounters) sent(id p2ppeer.ID, n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := m.get(id)
+	c.Sent++
+	c.BytesSent += uint64(n)
+}
+
+func (m *messageCounters) received(id p2ppeer.ID, n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := m.get(id)
+	c.Received++
+	c.BytesReceived += uint64(n)
+}
+
+func (m *messageCounters) dropped(id p2ppeer.ID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.get(id).Dropped++
+}
+
+func (p *concretePeer) NetworkingStack() NetworkingStack {
+	return p.networkingStack
+}
+
+func (p *concretePeer) Connections() ([]ConnectionInfo, error) {
+	if p.networkingStack.needsv2() {
+		return nil, ErrV2Unsupported
+	}
+	var conns []ConnectionInfo
+	for _, c := range p.Network().Conns() {
+		stat := c.Stat()
+		direction := "outbound"
+		if stat.Direction == p2pnetwork.DirInbound {
+			direction = "inbound"
+		}
+		conns = append(conns, ConnectionInfo{
+			PeerID:        c.RemotePeer().Pretty(),
+			Stack:         NetworkingStackV1,
+			Direction:     direction,
+			RemoteAddress: c.RemoteMultiaddr().String(),
+			Opened:        stat.Opened,
+			Latency:       p.Peerstore().LatencyEWMA(c.RemotePeer()),
+			Streams:       len(c.GetStreams()),
+		})
+	}
+	sort.Slice(conns, func(i, j int) bool { return conns[i].PeerID < conns[j].PeerID })
+	return conns, nil
+}
+
+func (p *concretePeer) PeerAddresses() map[string][]string {
+	addrs := make(map[string][]string)
+	for _, id := range p.Peerstore().PeersWithAddrs() {
+		if id == p.ID() {
+			continue
+		}
+		for _, addr := range p.Peerstore().Addrs(id) {
+			addrs[id.Pretty()] = append(addrs[id.Pretty()], addr.String())
+		}
+	}
+	return addrs
+}
+
+func (p *concretePeer) MessageCounters() map[string]MessageCounters {
+	p.messageCounters.mu.Lock()
+	defer p.messageCounters.mu.Unlock()
+	counters := make(map[string]MessageCounters, len(p.messageCounters.counters))
+	for id, c := range p.messageCounters.counters {
+		counters[id.Pretty()] = *c
+	}
+	return counters
+}
+
+func (p *concretePeer) AddPeerAddress(ctx context.Context, peerID string, addr string) error {
+	id, err := p2ppeer.Decode(peerID)
+	if err != nil {
+		return errors.Wrap(err, "invalid peer ID")
+	}
+	maddr, err := ma.NewMultiaddr(addr)
+	if err != nil {
+		return errors.Wrap(err, "invalid multiaddr")
+	}
+	p.Peerstore().AddAddr(id, maddr, peerstore.PermanentAddrTTL)
+	// The connection gater only lets through peers in an active config, so
+	// the dial may legitimately fail; the address is kept either way
+	return errors.Wrap(p.Connect(ctx, p2ppeer.AddrInfo{ID: id, Addrs: []ma.Multiaddr{maddr}}), "could not connect to peer")
+}
+
+func (p *concretePeer) BanPeer(peerID string) error {
+	if p.networkingStack.needsv2() {
+		return ErrV2Unsupported
+	}
+	id, err := p2ppeer.Decode(peerID)
+	if err != nil {
+		return errors.Wrap(err, "invalid peer ID")
+	}
+	if id == p.ID() {
+		return errors.New("cannot ban own peer ID")
+	}
+	p.gater.ban(id)
+	p.logger.Warn("Peer: banned peer", map[string]interface{}{"remotePeerID": id})
+	return errors.Wrap(p.Network().ClosePeer(id), "could not close connections to banned peer")
+}
+
+func (p *concretePeer) UnbanPeer(peerID string) error {
+	if p.networkingStack.needsv2() {
+		return ErrV2Unsupported
+	}
+	id, err := p2ppeer.Decode(peerID)
+	if err != nil {
+		return errors.Wrap(err, "invalid peer ID")
+	}
+	p.gater.unban(id)
+	p.logger.Info("Peer: unbanned peer", map[string]interface{}{"remotePeerID": id})
+	return nil
+}
+
+func (p *concretePeer) BannedPeers() []string {
+	var banned []string
+	for _, id := range p.gater.bannedPeers() {
+		banned = append(banned, id.Pretty())
+	}
+	sort.Strings(banned)
+	return banned
+}
@@ -458,6 +458,7 @@ func (o *ocrEndpoint) sendOnStream(destPeerID p2ppeer.ID, chSend <-chan []byte)
 
 				return true
 			}
+			o.peer.messageCounters.sent(destPeerID, len(b))
 		}
 	}
 }
@@ -563,6 +564,7 @@ func (o *ocrEndpoint) streamReceiver(s p2pnetwork.Stream) {
 			return
 		}
 		if !isAllowed {
+			o.peer.messageCounters.dropped(remotePeerID)
 			countDropped += 1
 			if isPowerOfTwo(countDropped) {
 				o.logger.Info("Messages were dropped by the rate limiter", types.LogFields{
@@ -599,8 +601,10 @@ func (o *ocrEndpoint) streamReceiver(s p2pnetwork.Stream) {
 		chRecv := o.chRecvs[sender]
 		select {
 		case chRecv <- payload:
+			o.peer.messageCounters.received(remotePeerID, len(payload))
 			continue
 		default:
+			o.peer.messageCounters.dropped(remotePeerID)
 			o.logger.Warn("Incoming buffer is full, dropping message", types.LogFields{
 				"remotePeerID":    remotePeerID,
 				"remoteOracleID":  sender,
@@ -95,6 +95,9 @@ type concretePeer struct {
 
 	// list of bandwidth limiters, one for each connection to a remote peer.
 	bandwidthLimiters *knockingtls.Limiters
+
+	networkingStack NetworkingStack
+	messageCounters *messageCounters
 }
 
 var _ types.BinaryNetworkEndpointFactory = (*concretePeer)(nil)
@@ -190,6 +193,8 @@ func NewPeer(c PeerConfig) (*concretePeer, error) {
 		registrantsMu:                    &sync.Mutex{},
 		dhtAnnouncementCounterUserPrefix: c.V1DHTAnnouncementCounterUserPrefix,
 		bandwidthLimiters:                bandwidthLimiters,
+		networkingStack:                  c.NetworkingStack,
+		messageCounters:                  newMessageCounters(),
 	}, nil
 }
 
@@ -12,6 +12,7 @@ import (
 	"PhoenixOracle/lib/libocr/offchainreporting/internal/config"
 	"PhoenixOracle/lib/libocr/offchainreporting/types"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
 )
 
 // OracleIdentity is identical to the internal type in package config.
@@ -320,3 +321,91 @@ func ContractSetConfigArgs(
 	}
 	return config.XXXContractSetConfigArgsFromSharedConfig(sharedConfig, sharedSecretEncryptionPublicKeys)
 }
+
+// ConfigParameters are the protocol parameters of a config. See
+// PublicConfig for their meaning.
+type ConfigParameters struct {
+	DeltaProgress time.Duration
+	DeltaResend   time.Duration
+	DeltaRound    time.Duration
+	DeltaGrace    time.Duration
+	DeltaC        time.Duration
+	AlphaPPB      uint64
+	DeltaStage    time.Duration
+	RMax          uint8
+	S             []int
+	F             int
+}
+
+// CheckedContractSetConfigArgs generates setConfig args for oracles, with a
+// fresh shared secret encrypted to each oracle's
+// SharedSecretEncryptionPublicKey. Unlike ContractSetConfigArgs, it rejects
+// configs the oracles would refuse to run, so it is safe to use for
+// production feeds.
+func CheckedContractSetConfigArgs(
+	chainID *big.Int,
+	skipChainSpecificChecks bool,
+	params ConfigParameters,
+	oracles []OracleIdentityExtra,
+) (
+	signers []common.Address,
+	transmitters []common.Address,
+	threshold uint8,
+	encodedConfigVersion uint64,
+	encodedConfig []byte,
+	err error,
+) {
+	identities := []config.OracleIdentity{}
+	sharedSecretEncryptionPublicKeys := []types.SharedSecretEncryptionPublicKey{}
+	seen := make(map[string]int)
+	for i, oracle := range oracles {
+		for _, id := range []string{
+			"signer " + common.Address(oracle.OnChainSigningAddress).Hex(),
+			"transmitter " + oracle.TransmitAddress.Hex(),
+			"peer ID " + oracle.PeerID,
+		} {
+			if j, ok := seen[id]; ok {
+				return nil, nil, 0, 0, nil, errors.Errorf("oracles %d and %d share %s", j, i, id)
+			}
+			seen[id] = i
+		}
+		identities = append(identities, config.OracleIdentity{
+			oracle.PeerID,
+			oracle.OffchainPublicKey,
+			oracle.OnChainSigningAddress,
+			oracle.TransmitAddress,
+		})
+		sharedSecretEncryptionPublicKeys = append(sharedSecretEncryptionPublicKeys, oracle.SharedSecretEncryptionPublicKey)
+	}
+
+	publicConfig := config.PublicConfig{
+		params.DeltaProgress,
+		params.DeltaResend,
+		params.DeltaRound,
+		params.DeltaGrace,
+		params.DeltaC,
+		params.AlphaPPB,
+		params.DeltaStage,
+		params.RMax,
+		params.S,
+		identities,
+		params.F,
+		types.ConfigDigest{},
+	}
+	if err := publicConfig.CheckParameterBounds(); err != nil {
+		return nil, nil, 0, 0, nil, err
+	}
+	if err := config.CheckPublicConfig(chainID, skipChainSpecificChecks, publicConfig); err != nil {
+		return nil, nil, 0, 0, nil, errors.Wrap(err, "invalid config")
+	}
+
+	sharedSecret := [config.SharedSecretSize]byte{}
+	if _, err := io.ReadFull(rand.Reader, sharedSecret[:]); err != nil {
+		return nil, nil, 0, 0, nil, err
+	}
+
+	return config.XXXContractSetConfigArgsFromSharedConfig(
+		config.SharedConfig{publicConfig, &sharedSecret},
+		sharedSecretEncryptionPublicKeys,
+	)
+}
@@ -0,0 +1,140 @@
+package confighelper
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"PhoenixOracle/lib/libocr/offchainreporting/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func testOracles(t *testing.T) []OracleIdentityExtra {
+	transmitters := []string{
+		"0x6f7bADc7eD84D64DB7bF3bB8db6A6dADb52607e2",
+		"0x22D2a184da4E94625E3FdAd977b983e91312519E",
+		"0xdEc9025bAeBE9Ece7F1c07dCBf9852E218aB7A6e",
+		"0x4652C4a429Ba63a129b96ceea042660bC0017088",
+	}
+	signers := []string{
+		"0x8511adc4a1c6da31d3e50e533f4cca2ec1d081ab",
+		"0x865b601a966bd7ff8d554f545cf295c176c230f0",
+		"0xb87b4ddb1ef07e7a4dc97cf3764387518ab3571e",
+		"0x0cbf28aa7f8809b6a03f310fdcb4c084fe841ca6",
+	}
+	offchainPublicKeys := []string{
+		"ad46563af4762b90fcc20a831da8a4575243ac17fe521e8d50d81241790487b6",
+		"5ecec424b832d6c9a5185eb192b7ae333b59c97c9ec7bceadd7b1c6a746b321f",
+		"113b47c42c44542c2db532fc3bc1d256a9648d62c1390fe318011df630269514",
+		"7f5dbf1ed958bc7cb730ed182a655b3fc6feb66130bbfdd9ac884d253992937f",
+	}
+	configPublicKeys := []string{
+		"8119536ef0f9877f46dbbd6971c1c49176bc0266a83138b164d5e6c2ccc4225a",
+		"1f109ba50b9c3d53ac28297aeee5ddd296fb20796b548ef83b82ff2af0150b0d",
+		"f63f880372b42fb7595e445ba0da4ed833d83aaa34ffd85f3b1aa66bec2dcd30",
+		"706d2b50968c9808cfb27ce15f6900002927b1501f9f98e5c959334e05e9c359",
+	}
+	peerIDs := []string{
+		"12D3KooWLCGTxShexbb3wFu8qfnn3NmfnyK4KRE9SeFY3mNSCQBa",
+		"12D3KooWRp5dTPx8QNDqTQMMqCWQMAY3uHfuoU7rw3cjKg9QMmh6",
+		"12D3KooWGMRjindteF4ksd6oUqZ2TmURpijf4i5XrMDBsq4Tuwt1",
+		"12D3KooWQj8VgLSwRWSbxfza6qtHmwu1gor6CjcZHdPAQ1zkX2Dy",
+	}
+
+	var oracles []OracleIdentityExtra
+	for i := range signers {
+		offchainPublicKey, err := hex.DecodeString(offchainPublicKeys[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		configPublicKey, err := hex.DecodeString(configPublicKeys[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sharedSecretEncryptionPublicKey types.SharedSecretEncryptionPublicKey
+		copy(sharedSecretEncryptionPublicKey[:], configPublicKey)
+
+		oracles = append(oracles, OracleIdentityExtra{
+			OracleIdentity: OracleIdentity{
+				OnChainSigningAddress: types.OnChainSigningAddress(common.HexToAddress(signers[i])),
+				TransmitAddress:       common.HexToAddress(transmitters[i]),
+				OffchainPublicKey:     types.OffchainPublicKey(offchainPublicKey),
+				PeerID:                peerIDs[i],
+			},
+			SharedSecretEncryptionPublicKey: sharedSecretEncryptionPublicKey,
+		})
+	}
+	return oracles
+}
+
+func testConfigParameters() ConfigParameters {
+	return ConfigParameters{
+		DeltaProgress: 35 * time.Second,
+		DeltaResend:   17 * time.Second,
+		DeltaRound:    30 * time.Second,
+		DeltaGrace:    12 * time.Second,
+		DeltaC:        10 * time.Minute,
+		AlphaPPB:      1000000000 / 100,
+		DeltaStage:    1 * time.Minute,
+		RMax:          3,
+		S:             []int{1, 2, 2, 2},
+		F:             1,
+	}
+}
+
+func TestCheckedContractSetConfigArgs_RoundTrip(t *testing.T) {
+	oracles := testOracles(t)
+	params := testConfigParameters()
+
+	signers, transmitters, threshold, encodedConfigVersion, encodedConfig, err := CheckedContractSetConfigArgs(nil, true, params, oracles)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pc, err := PublicConfigFromContractConfig(nil, true, types.ContractConfig{
+		Signers:              signers,
+		Transmitters:         transmitters,
+		Threshold:            threshold,
+		EncodedConfigVersion: encodedConfigVersion,
+		Encoded:              encodedConfig,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pc.F != params.F || pc.DeltaProgress != params.DeltaProgress || pc.DeltaC != params.DeltaC || pc.RMax != params.RMax {
+		t.Fatalf("decoded config %+v does not match parameters %+v", pc, params)
+	}
+	if len(pc.OracleIdentities) != len(oracles) {
+		t.Fatalf("expected %d oracles, got %d", len(oracles), len(pc.OracleIdentities))
+	}
+	for i, identity := range pc.OracleIdentities {
+		if identity.PeerID != oracles[i].PeerID || identity.TransmitAddress != oracles[i].TransmitAddress {
+			t.Errorf("oracle %d: expected %+v, got %+v", i, oracles[i].OracleIdentity, identity)
+		}
+	}
+}
+
+func TestCheckedContractSetConfigArgs_RejectsInvalidConfigs(t *testing.T) {
+	tooFaulty := testConfigParameters()
+	tooFaulty.F = 2
+
+	badGrace := testConfigParameters()
+	badGrace.DeltaGrace = badGrace.DeltaRound
+
+	duplicate := testOracles(t)
+	duplicate[3].PeerID = duplicate[0].PeerID
+
+	for name, tc := range map[string]struct {
+		params  ConfigParameters
+		oracles []OracleIdentityExtra
+	}{
+		"F too large":            {tooFaulty, testOracles(t)},
+		"DeltaGrace too large":   {badGrace, testOracles(t)},
+		"duplicate oracle peers": {testConfigParameters(), duplicate},
+	} {
+		if _, _, _, _, _, err := CheckedContractSetConfigArgs(nil, true, tc.params, tc.oracles); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -126,6 +126,18 @@ func checkIdentityListsHaveTheSameLength(
 	return nil
 }
 
+// CheckPublicConfig applies the checks an oracle applies when it decodes cfg
+// from the contract. chainID is only used for the chain-specific checks.
+func CheckPublicConfig(chainID *big.Int, skipChainSpecificChecks bool, cfg PublicConfig) error {
+	if err := checkPublicConfigParameters(cfg); err != nil {
+		return err
+	}
+	if !skipChainSpecificChecks {
+		return checkPublicConfigParametersForChain(chainID, cfg)
+	}
+	return nil
+}
+
 // Sanity check on parameters:
 // (1) violations of fundamental constraints like 3*f<n;
 // (2) configurations that would trivially exhaust all of a node's resources;
@@ -27,27 +27,33 @@ func RunManagedOracle(
 	contractTransmitter types.ContractTransmitter,
 	database types.Database,
 	datasource types.DataSource,
+	leaderSelectionObserver types.LeaderSelectionObserver,
 	localConfig types.LocalConfig,
 	logger loghelper.LoggerWithContext,
 	monitoringEndpoint types.MonitoringEndpoint,
 	netEndpointFactory types.BinaryNetworkEndpointFactory,
 	privateKeys types.PrivateKeys,
+	reportPlugin types.ReportPlugin,
+	roundObserver types.RoundObserver,
 ) {
 	mo := managedOracleState{
 		ctx: ctx,
 
-		v1bootstrappers:     v1bootstrappers,
-		v2bootstrappers:     v2bootstrappers,
-		configOverrider:     configOverrider,
-		configTracker:       configTracker,
-		contractTransmitter: contractTransmitter,
-		database:            database,
-		datasource:          datasource,
-		localConfig:         localConfig,
-		logger:              logger,
-		monitoringEndpoint:  monitoringEndpoint,
-		netEndpointFactory:  netEndpointFactory,
-		privateKeys:         privateKeys,
+		v1bootstrappers:         v1bootstrappers,
+		v2bootstrappers:         v2bootstrappers,
+		configOverrider:         configOverrider,
+		configTracker:           configTracker,
+		contractTransmitter:     contractTransmitter,
+		database:                database,
+		datasource:              datasource,
+		leaderSelectionObserver: leaderSelectionObserver,
+		localConfig:             localConfig,
+		logger:                  logger,
+		monitoringEndpoint:      monitoringEndpoint,
+		netEndpointFactory:      netEndpointFactory,
+		privateKeys:             privateKeys,
+		reportPlugin:            reportPlugin,
+		roundObserver:           roundObserver,
 	}
 	mo.run()
 }
@@ -55,19 +61,22 @@ func RunManagedOracle(
 type managedOracleState struct {
 	ctx context.Context
 
-	v1bootstrappers     []string
-	v2bootstrappers     []types.BootstrapperLocator
-	config              config.SharedConfig
-	configOverrider     types.ConfigOverrider
-	configTracker       types.ContractConfigTracker
-	contractTransmitter types.ContractTransmitter
-	database            types.Database
-	datasource          types.DataSource
-	localConfig         types.LocalConfig
-	logger              loghelper.LoggerWithContext
-	monitoringEndpoint  types.MonitoringEndpoint
-	netEndpointFactory  types.BinaryNetworkEndpointFactory
-	privateKeys         types.PrivateKeys
+	v1bootstrappers         []string
+	v2bootstrappers         []types.BootstrapperLocator
+	config                  config.SharedConfig
+	configOverrider         types.ConfigOverrider
+	configTracker           types.ContractConfigTracker
+	contractTransmitter     types.ContractTransmitter
+	database                types.Database
+	datasource              types.DataSource
+	leaderSelectionObserver types.LeaderSelectionObserver
+	localConfig             types.LocalConfig
+	logger                  loghelper.LoggerWithContext
+	monitoringEndpoint      types.MonitoringEndpoint
+	netEndpointFactory      types.BinaryNetworkEndpointFactory
+	privateKeys             types.PrivateKeys
+	reportPlugin            types.ReportPlugin
+	roundObserver           types.RoundObserver
 
 	chTelemetry        chan<- *protobuf.TelemetryWrapper
 	netEndpoint        *shim.SerializingEndpoint
@@ -218,6 +227,7 @@ func (mo *managedOracleState) configChanged(contractConfig types.ContractConfig)
 		defer oracleCancel()
 		protocol.RunOracle(
 			oracleCtx,
+			protocol.RealClock{},
 			mo.config,
 			ConfigOverriderWrapper{mo.configOverrider},
 			mo.contractTransmitter,
@@ -228,7 +238,8 @@ func (mo *managedOracleState) configChanged(contractConfig types.ContractConfig)
 			mo.localConfig,
 			childLogger,
 			mo.netEndpoint,
-			shim.MakeTelemetrySender(mo.chTelemetry, childLogger),
+			mo.reportPlugin,
+			shim.MakeTelemetrySender(mo.chTelemetry, mo.leaderSelectionObserver, mo.roundObserver, childLogger),
 		)
 	})
 
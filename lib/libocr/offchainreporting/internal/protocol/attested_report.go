@@ -2,20 +2,16 @@ package protocol
 
 import (
 	"bytes"
-	"fmt"
 	"math/big"
 
 	"PhoenixOracle/lib/libocr/offchainreporting/internal/protocol/observation"
 	"PhoenixOracle/lib/libocr/offchainreporting/internal/signature"
 	"PhoenixOracle/lib/libocr/offchainreporting/types"
 
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
 )
 
-var reportTypes = getReportTypes()
-
 // AttributedObservation succinctly atrributes a value reported to an oracle
 type AttributedObservation struct {
 	Observation observation.Observation
@@ -66,22 +62,23 @@ func (aos AttributedObservations) observers() (rv common.Hash, err error) {
 	return rv, nil
 }
 
-func (aos AttributedObservations) onChainObservations() (rv []*big.Int) {
+func (aos AttributedObservations) onChainObservations() (rv [][]*big.Int) {
 	for _, ao := range aos {
-		rv = append(rv, ao.Observation.GoEthereumValue())
+		rv = append(rv, ao.Observation.Values())
 	}
 	return rv
 }
 
 // OnChainReport returns the serialized report which is transmitted to the
 // onchain contract, and signed by participating oracles using their onchain
-// identities
-func (aos AttributedObservations) OnChainReport(repctx ReportContext) ([]byte, error) {
+// identities. The encoding is determined by plugin.
+func (aos AttributedObservations) OnChainReport(repctx ReportContext, plugin types.ReportPlugin) ([]byte, error) {
 	observers, err := aos.observers()
 	if err != nil {
 		return nil, errors.Wrapf(err, "while collating observers for onChainReport")
 	}
-	return reportTypes.Pack(repctx.DomainSeparationTag(), observers, aos.onChainObservations())
+	report, err := plugin.EncodeReport(repctx.DomainSeparationTag(), observers, aos.onChainObservations())
+	return report, errors.Wrapf(err, "while encoding report with %s plugin", plugin.Name())
 }
 
 // AttestedReportOne is the collated report oracles sign off on, after they've
@@ -94,9 +91,10 @@ type AttestedReportOne struct {
 func MakeAttestedReportOne(
 	aos AttributedObservations,
 	repctx ReportContext,
+	plugin types.ReportPlugin,
 	signer func([]byte) ([]byte, error),
 ) (AttestedReportOne, error) {
-	onchainReport, err := aos.OnChainReport(repctx)
+	onchainReport, err := aos.OnChainReport(repctx, plugin)
 	if err != nil {
 		return AttestedReportOne{}, errors.Wrapf(err, "while serializing on-chain report")
 	}
@@ -115,8 +113,8 @@ func (rep AttestedReportOne) Equal(rep2 AttestedReportOne) bool {
 
 // Verify is used by the leader to check the signature a process attaches to its
 // report message (the c.Sig value.)
-func (c *AttestedReportOne) Verify(repctx ReportContext, a types.OnChainSigningAddress) (err error) {
-	report, err := c.AttributedObservations.OnChainReport(repctx)
+func (c *AttestedReportOne) Verify(repctx ReportContext, plugin types.ReportPlugin, a types.OnChainSigningAddress) (err error) {
+	report, err := c.AttributedObservations.OnChainReport(repctx, plugin)
 	if err != nil {
 		return err
 	}
@@ -156,9 +154,9 @@ func (rep *AttestedReportMany) collateSignatures() (rs, ss [][32]byte, vs [32]by
 	return rs, ss, vs
 }
 
-func (rep *AttestedReportMany) TransmissionArgs(repctx ReportContext) (report []byte, rs,
+func (rep *AttestedReportMany) TransmissionArgs(repctx ReportContext, plugin types.ReportPlugin) (report []byte, rs,
 	ss [][32]byte, vs [32]byte, err error) {
-	report, err = rep.AttributedObservations.OnChainReport(repctx)
+	report, err = rep.AttributedObservations.OnChainReport(repctx, plugin)
 	if err != nil {
 		return nil, nil, nil, [32]byte{}, errors.Wrapf(err,
 			"while constructing report for on-chain transmission")
@@ -172,9 +170,10 @@ func (rep *AttestedReportMany) TransmissionArgs(repctx ReportContext) (report []
 // from.
 func (rep *AttestedReportMany) VerifySignatures(
 	repctx ReportContext,
+	plugin types.ReportPlugin,
 	as signature.EthAddresses,
 ) error {
-	report, err := rep.AttributedObservations.OnChainReport(repctx)
+	report, err := rep.AttributedObservations.OnChainReport(repctx, plugin)
 	if err != nil {
 		return errors.Wrapf(err,
 			"while serializing report to check signatures on it")
@@ -193,18 +192,3 @@ func (rep *AttestedReportMany) VerifySignatures(
 	}
 	return nil
 }
-
-func getReportTypes() abi.Arguments {
-	mustNewType := func(t string) abi.Type {
-		result, err := abi.NewType(t, "", []abi.ArgumentMarshaling{})
-		if err != nil {
-			panic(fmt.Sprintf("Unexpected error during abi.NewType: %s", err))
-		}
-		return result
-	}
-	return abi.Arguments([]abi.Argument{
-		{Name: "rawReportContext", Type: mustNewType("bytes32")},
-		{Name: "rawObservers", Type: mustNewType("bytes32")},
-		{Name: "observations", Type: mustNewType("int192[]")},
-	})
-}
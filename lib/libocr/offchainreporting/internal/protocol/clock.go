@@ -0,0 +1,22 @@
+package protocol
+
+import "time"
+
+// Clock is the source of time for the protocol's timers (T_progress,
+// T_resend, T_round, T_grace, transmission delays) and for the timestamps
+// it compares against the chain. Production code uses RealClock; the
+// simulator injects a fake clock so that runs don't depend on wall-clock
+// time.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// RealClock is the wall clock
+type RealClock struct{}
+
+var _ Clock = RealClock{}
+
+func (RealClock) Now() time.Time { return time.Now() }
+
+func (RealClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
@@ -3,6 +3,7 @@ package protocol
 import (
 	"bytes"
 	"errors"
+	"fmt"
 
 	"PhoenixOracle/lib/libocr/offchainreporting/internal/protocol/observation"
 	"PhoenixOracle/lib/libocr/offchainreporting/internal/signature"
@@ -35,10 +36,13 @@ func (so SignedObservation) Equal(so2 SignedObservation) bool {
 		bytes.Equal(so.Signature, so2.Signature)
 }
 
-func (so SignedObservation) Verify(repctx ReportContext, publicKey types.OffchainPublicKey) error {
+func (so SignedObservation) Verify(repctx ReportContext, width int, publicKey types.OffchainPublicKey) error {
 	if so.Observation.IsMissingValue() {
 		return errors.New("Observation is missing value")
 	}
+	if so.Observation.Width() != width {
+		return fmt.Errorf("Observation has %d values, expected %d", so.Observation.Width(), width)
+	}
 
 	sigPublicKey := signature.OffchainPublicKey(publicKey)
 	if !sigPublicKey.Verify(signedObservationWireMessage(repctx, so.Observation), so.Signature) {
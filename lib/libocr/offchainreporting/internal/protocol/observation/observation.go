@@ -16,7 +16,10 @@ import (
 	"PhoenixOracle/lib/libocr/offchainreporting/types"
 )
 
-type Observation struct{ v *big.Int }
+// Observation is a vector of ethereum int192 values. Observations for the
+// median report plugin hold a single value; the protocol orders and compares
+// observations by their first value.
+type Observation struct{ vs []*big.Int }
 
 type Observations []Observation
 
@@ -35,37 +38,58 @@ func tooLarge(o *big.Int) error {
 
 // MakeObservation returns v as an ethereum int192, if it fits, errors otherwise.
 func MakeObservation(w types.Observation) (Observation, error) {
-	v := (*big.Int)(w)
-	// nil can sometimes occur here because it's the zero value for a pointer in a
-	// struct, and w comes from a zero struct with a *big.Int field. We always
-	// want the corresponding value for the "zero observation" to be zero.
-	if v == nil {
-		v = big.NewInt(0)
+	return MakeMultiObservation([]*big.Int{(*big.Int)(w)})
+}
+
+// MakeMultiObservation returns vs as a vector of ethereum int192, if they all
+// fit, errors otherwise.
+func MakeMultiObservation(vs []*big.Int) (Observation, error) {
+	if len(vs) == 0 {
+		return Observation{}, errors.New("observation must hold at least one value")
 	}
-	if v.Cmp(MaxObservation) > 0 || v.Cmp(MinObservation) < 0 {
-		return Observation{}, tooLarge(v)
+	o := Observation{make([]*big.Int, len(vs))}
+	for j, v := range vs {
+		// nil can sometimes occur here because it's the zero value for a pointer in a
+		// struct, and w comes from a zero struct with a *big.Int field. We always
+		// want the corresponding value for the "zero observation" to be zero.
+		if v == nil {
+			v = big.NewInt(0)
+		}
+		if v.Cmp(MaxObservation) > 0 || v.Cmp(MinObservation) < 0 {
+			return Observation{}, tooLarge(v)
+		}
+		o.vs[j] = v
 	}
-	return Observation{v}, nil
+	return o, nil
 }
 
-func (o Observation) RawObservation() *big.Int { return o.v }
+// RawObservation returns the first value of o
+func (o Observation) RawObservation() *big.Int { return o.vs[0] }
 
-func (o Observation) Less(o2 Observation) bool { return o.v.Cmp(o2.v) < 0 }
+// Values returns all values of o
+func (o Observation) Values() []*big.Int { return o.vs }
 
-func (o Observation) IsMissingValue() bool { return o.v == nil }
+// Width is the number of values in o
+func (o Observation) Width() int { return len(o.vs) }
 
-func (o Observation) GoEthereumValue() *big.Int { return o.v }
+func (o Observation) Less(o2 Observation) bool { return o.vs[0].Cmp(o2.vs[0]) < 0 }
 
+func (o Observation) IsMissingValue() bool { return len(o.vs) == 0 }
+
+func (o Observation) GoEthereumValue() *big.Int { return o.vs[0] }
+
+// Deviates compares the first values of o and old, which is the value
+// reported to the contract as its latest answer
 func (o Observation) Deviates(old Observation, thresholdPPB uint64) bool {
-	if old.v.Cmp(i(0)) == 0 {
-		if o.v.Cmp(i(0)) == 0 {
+	if old.vs[0].Cmp(i(0)) == 0 {
+		if o.vs[0].Cmp(i(0)) == 0 {
 			return false // Both values are zero; no deviation
 		}
 		return true // Any deviation from 0 is significant
 	}
 	// ||o.v - old.v|| / ||old.v||, approximated by a float
 	change := &big.Rat{}
-	change.SetFrac(i(0).Sub(o.v, old.v), old.v)
+	change.SetFrac(i(0).Sub(o.vs[0], old.vs[0]), old.vs[0])
 	change.Abs(change)
 	threshold := &big.Rat{}
 	threshold.SetFrac(
@@ -75,24 +99,34 @@ func (o Observation) Deviates(old Observation, thresholdPPB uint64) bool {
 	return change.Cmp(threshold) >= 0
 }
 
-// Bytes returns the twos-complement representation of o
+// Bytes returns the concatenated twos-complement representations of the
+// values of o. An observation with a single value is serialized exactly as
+// before multi-value observations were introduced.
 //
 // This panics on OOB values, because MakeObservation and UnmarshalObservation
 // are the only external ways to create an Observation, and that already checks
 // the bounds
 func (o Observation) Marshal() []byte {
-	if o.v.Cmp(MaxObservation) > 0 || o.v.Cmp(MinObservation) < 0 {
-		panic(tooLarge(o.v))
+	b := make([]byte, 0, byteWidth*len(o.vs))
+	for _, v := range o.vs {
+		b = append(b, marshalValue(v)...)
+	}
+	return b
+}
+
+func marshalValue(v *big.Int) []byte {
+	if v.Cmp(MaxObservation) > 0 || v.Cmp(MinObservation) < 0 {
+		panic(tooLarge(v))
 	}
-	negative := o.v.Sign() < 0
+	negative := v.Sign() < 0
 	val := (&big.Int{})
 	if negative {
-		// compute two's complement as 2**192 - abs(o.v) = 2**192 + o.v
+		// compute two's complement as 2**192 - abs(v) = 2**192 + v
 		val.SetInt64(1)
 		val.Lsh(val, bitWidth)
-		val.Add(val, o.v)
+		val.Add(val, v)
 	} else {
-		val.Set(o.v)
+		val.Set(v)
 	}
 	b := val.Bytes() // big-endian representation of abs(val)
 	if len(b) > byteWidth {
@@ -106,37 +140,56 @@ func (o Observation) Marshal() []byte {
 }
 
 func UnmarshalObservation(s []byte) (Observation, error) {
-	if len(s) != byteWidth {
+	if len(s) == 0 || len(s)%byteWidth != 0 {
 		return Observation{}, errors.Errorf("wrong length for serialized "+
 			"Observation: length %d 0x%x", len(s), s)
 	}
-	val := (&big.Int{}).SetBytes(s)
-	negative := val.Cmp(MaxObservation) > 0
-	if negative {
-		maxUint := (&big.Int{}).SetInt64(1)
-		maxUint.Lsh(maxUint, bitWidth)
-		val.Sub(maxUint, val)
-		val.Neg(val)
+	var vs []*big.Int
+	for start := 0; start < len(s); start += byteWidth {
+		val := (&big.Int{}).SetBytes(s[start : start+byteWidth])
+		negative := val.Cmp(MaxObservation) > 0
+		if negative {
+			maxUint := (&big.Int{}).SetInt64(1)
+			maxUint.Lsh(maxUint, bitWidth)
+			val.Sub(maxUint, val)
+			val.Neg(val)
+		}
+		vs = append(vs, val)
 	}
-	return MakeObservation(val)
+	return MakeMultiObservation(vs)
 }
 
 func (o Observation) String() string {
-	return fmt.Sprintf("Observation{%d}", o.v)
+	if len(o.vs) == 1 {
+		return fmt.Sprintf("Observation{%d}", o.vs[0])
+	}
+	return fmt.Sprintf("Observation%d", o.vs)
 }
 
 func (o Observation) Equal(o2 Observation) bool {
-	return o.v.Cmp(o2.v) == 0
+	if len(o.vs) != len(o2.vs) {
+		return false
+	}
+	for j := range o.vs {
+		if o.vs[j].Cmp(o2.vs[j]) != 0 {
+			return false
+		}
+	}
+	return true
 }
 
 var _ encoding.TextMarshaler = Observation{}
 
 func (o Observation) MarshalText() (text []byte, err error) {
-	if o.v == nil {
-		return []byte{}, nil
+	var texts [][]byte
+	for _, v := range o.vs {
+		t, err := v.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		texts = append(texts, t)
 	}
-
-	return o.v.MarshalText()
+	return bytes.Join(texts, []byte(",")), nil
 }
 
 func uInt64sToObservation(w1, w2, w3 uint64) Observation {
@@ -170,5 +223,5 @@ func GenObservation() gopter.Gen {
 // XXXTestingOnlyNewObservation returns a new observation with no bounds
 // checking on v.
 func XXXTestingOnlyNewObservation(v *big.Int) Observation {
-	return Observation{v: v}
+	return Observation{vs: []*big.Int{v}}
 }
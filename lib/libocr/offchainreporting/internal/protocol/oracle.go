@@ -19,6 +19,7 @@ const futureMessageBufferSize = 10 // big enough for a couple of full rounds of
 func RunOracle(
 	ctx context.Context,
 
+	clock Clock,
 	config config.SharedConfig,
 	configOverrider types.ConfigOverrider,
 	contractTransmitter types.ContractTransmitter,
@@ -29,11 +30,13 @@ func RunOracle(
 	localConfig types.LocalConfig,
 	logger loghelper.LoggerWithContext,
 	netEndpoint NetworkEndpoint,
+	reportPlugin types.ReportPlugin,
 	telemetrySender TelemetrySender,
 ) {
 	o := oracleState{
 		ctx: ctx,
 
+		clock:               clock,
 		Config:              config,
 		configOverrider:     configOverrider,
 		contractTransmitter: contractTransmitter,
@@ -44,6 +47,7 @@ func RunOracle(
 		logger:              logger,
 		netEndpoint:         netEndpoint,
 		PrivateKeys:         keys,
+		reportPlugin:        reportPlugin,
 		telemetrySender:     telemetrySender,
 	}
 	o.run()
@@ -52,6 +56,7 @@ func RunOracle(
 type oracleState struct {
 	ctx context.Context
 
+	clock               Clock
 	Config              config.SharedConfig
 	configOverrider     types.ConfigOverrider
 	contractTransmitter types.ContractTransmitter
@@ -62,6 +67,7 @@ type oracleState struct {
 	logger              loghelper.LoggerWithContext
 	netEndpoint         NetworkEndpoint
 	PrivateKeys         types.PrivateKeys
+	reportPlugin        types.ReportPlugin
 	telemetrySender     TelemetrySender
 
 	bufferedMessages        []*MessageBuffer
@@ -134,6 +140,7 @@ func (o *oracleState) run() {
 			chNetToReportGeneration,
 			chPacemakerToOracle,
 			chReportGenerationToTransmission,
+			o.clock,
 			o.Config,
 			o.configOverrider,
 			o.contractTransmitter,
@@ -144,6 +151,7 @@ func (o *oracleState) run() {
 			o.logger,
 			o.netEndpoint,
 			o.PrivateKeys,
+			o.reportPlugin,
 			o.telemetrySender,
 		)
 	})
@@ -152,6 +160,7 @@ func (o *oracleState) run() {
 			o.childCtx,
 			&o.subprocesses,
 
+			o.clock,
 			o.Config,
 			o.configOverrider,
 			chReportGenerationToTransmission,
@@ -159,6 +168,7 @@ func (o *oracleState) run() {
 			o.id,
 			o.localConfig,
 			o.logger,
+			o.reportPlugin,
 			o.contractTransmitter,
 		)
 	})
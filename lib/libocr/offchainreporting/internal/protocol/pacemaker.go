@@ -30,6 +30,7 @@ func RunPacemaker(
 	chNetToReportGeneration <-chan MessageToReportGenerationWithSender,
 	chPacemakerToOracle chan<- uint32,
 	chReportGenerationToTransmission chan<- EventToTransmission,
+	clock Clock,
 	config config.SharedConfig,
 	configOverrider types.ConfigOverrider,
 	contractTransmitter types.ContractTransmitter,
@@ -40,13 +41,14 @@ func RunPacemaker(
 	logger loghelper.LoggerWithContext,
 	netSender NetworkSender,
 	privateKeys types.PrivateKeys,
+	reportPlugin types.ReportPlugin,
 	telemetrySender TelemetrySender,
 ) {
 	pace := makePacemakerState(
 		ctx, subprocesses, chNetToPacemaker, chNetToReportGeneration, chPacemakerToOracle,
-		chReportGenerationToTransmission, config, configOverrider, contractTransmitter, database,
+		chReportGenerationToTransmission, clock, config, configOverrider, contractTransmitter, database,
 		datasource, id, localConfig, logger, netSender, privateKeys,
-		telemetrySender,
+		reportPlugin, telemetrySender,
 	)
 	pace.run()
 }
@@ -57,12 +59,12 @@ func makePacemakerState(ctx context.Context,
 	chNetToReportGeneration <-chan MessageToReportGenerationWithSender,
 	chPacemakerToOracle chan<- uint32,
 	chReportGenerationToTransmission chan<- EventToTransmission,
-	config config.SharedConfig, configOverrider types.ConfigOverrider,
+	clock Clock, config config.SharedConfig, configOverrider types.ConfigOverrider,
 	contractTransmitter types.ContractTransmitter,
 	database types.Database, datasource types.DataSource, id types.OracleID,
 	localConfig types.LocalConfig, logger loghelper.LoggerWithContext,
 	netSender NetworkSender, privateKeys types.PrivateKeys,
-	telemetrySender TelemetrySender,
+	reportPlugin types.ReportPlugin, telemetrySender TelemetrySender,
 ) pacemakerState {
 	return pacemakerState{
 		ctx:          ctx,
@@ -72,6 +74,7 @@ func makePacemakerState(ctx context.Context,
 		chNetToReportGeneration:          chNetToReportGeneration,
 		chPacemakerToOracle:              chPacemakerToOracle,
 		chReportGenerationToTransmission: chReportGenerationToTransmission,
+		clock:                            clock,
 		config:                           config,
 		configOverrider:                  configOverrider,
 		contractTransmitter:              contractTransmitter,
@@ -82,6 +85,7 @@ func makePacemakerState(ctx context.Context,
 		logger:                           logger,
 		netSender:                        netSender,
 		privateKeys:                      privateKeys,
+		reportPlugin:                     reportPlugin,
 		telemetrySender:                  telemetrySender,
 
 		newepoch: make([]uint32, config.N()),
@@ -97,6 +101,7 @@ type pacemakerState struct {
 	chReportGenerationToPacemaker    <-chan EventToPacemaker
 	chPacemakerToOracle              chan<- uint32
 	chReportGenerationToTransmission chan<- EventToTransmission
+	clock                            Clock
 	config                           config.SharedConfig
 	configOverrider                  types.ConfigOverrider
 	contractTransmitter              types.ContractTransmitter
@@ -107,6 +112,7 @@ type pacemakerState struct {
 	logger                           loghelper.LoggerWithContext
 	netSender                        NetworkSender
 	privateKeys                      types.PrivateKeys
+	reportPlugin                     types.ReportPlugin
 	telemetrySender                  TelemetrySender
 	// Test use only: send testBlocker an event to halt the pacemaker event loop,
 	// send testUnblocker an event to resume it.
@@ -169,7 +175,7 @@ func (pace *pacemakerState) run() {
 	// immediately terminated and superseded due to restoreNeFromTransmitter below
 	pace.e = 1
 	l := Leader(pace.e, pace.config.N(), pace.config.LeaderSelectionKey())
-	pace.l = pace.verifyLeader(l,pace.e)
+	pace.l = pace.verifyLeader(l, pace.e)
 
 	// Attempt to restore state from database. This is implicit in the
 	// design document.
@@ -179,7 +185,7 @@ func (pace *pacemakerState) run() {
 
 	pace.spawnReportGeneration()
 
-	pace.tProgress = time.After(pace.config.DeltaProgress)
+	pace.tProgress = pace.clock.After(pace.config.DeltaProgress)
 
 	pace.sendNewepoch(pace.ne)
 
@@ -279,7 +285,7 @@ func (pace *pacemakerState) restoreStateFromDatabase() {
 		pace.newepoch[i] = e
 	}
 	l := Leader(pace.e, pace.config.N(), pace.config.LeaderSelectionKey())
-	pace.l = pace.verifyLeader(l,pace.e)
+	pace.l = pace.verifyLeader(l, pace.e)
 	pace.logger.Info("Restored state from database", types.LogFields{
 		"epoch":  pace.e,
 		"leader": pace.l,
@@ -365,7 +371,7 @@ func (pace *pacemakerState) persist() {
 // prototol. It resets the timer which will trigger the oracle to broadcast a
 // "newepoch" message, if it runs out.
 func (pace *pacemakerState) eventProgress() {
-	pace.tProgress = time.After(pace.config.DeltaProgress)
+	pace.tProgress = pace.clock.After(pace.config.DeltaProgress)
 }
 
 func (pace *pacemakerState) sendNewepoch(newEpoch uint32) {
@@ -374,7 +380,7 @@ func (pace *pacemakerState) sendNewepoch(newEpoch uint32) {
 		pace.ne = newEpoch
 		pace.persist()
 	}
-	pace.tResend = time.After(pace.config.DeltaResend)
+	pace.tResend = pace.clock.After(pace.config.DeltaResend)
 }
 
 func (pace *pacemakerState) eventTResendTimeout() {
@@ -443,7 +449,7 @@ func (pace *pacemakerState) messageNewepoch(msg MessageNewEpoch, sender types.Or
 				"candidateEpochs": candidateEpochs,
 			})
 			l := Leader(newEpoch, pace.config.N(), pace.config.LeaderSelectionKey())
-			l = pace.verifyLeader(l,newEpoch)
+			l = pace.verifyLeader(l, newEpoch)
 			pace.e, pace.l = newEpoch, l // (e, l) ← (ē, leader(ē))
 			if pace.ne < pace.e {        // ne ← max{ne, e}
 				pace.ne = pace.e
@@ -455,7 +461,7 @@ func (pace *pacemakerState) messageNewepoch(msg MessageNewEpoch, sender types.Or
 
 			pace.notifyOracleOfNewEpoch = true
 
-			pace.tProgress = time.After(pace.config.DeltaProgress) // restart timer T_{progress}
+			pace.tProgress = pace.clock.After(pace.config.DeltaProgress) // restart timer T_{progress}
 		}
 	}
 }
@@ -486,6 +492,7 @@ func (pace *pacemakerState) spawnReportGeneration() {
 			p.chNetToReportGeneration,
 			chReportGenerationToPacemaker,
 			p.chReportGenerationToTransmission,
+			p.clock,
 			p.config,
 			p.configOverrider,
 			p.contractTransmitter,
@@ -497,16 +504,20 @@ func (pace *pacemakerState) spawnReportGeneration() {
 			p.logger,
 			p.netSender,
 			p.privateKeys,
+			p.reportPlugin,
 			p.telemetrySender,
 		)
 	})
 	pace.cancelReportGeneration = cancelReportGeneration
 }
 
-func(pace *pacemakerState) getLatestNewIndexes() []int {
+// getLatestNewIndexes returns the active oracle set reported by the
+// contract, restricted to valid oracle ids. If no usable set is available,
+// it returns the reason to fall back to the default leader.
+func (pace *pacemakerState) getLatestNewIndexes() ([]int, types.LeaderSelectionReason) {
 	var resultNewIndexes struct {
 		newIndexes []int
-		err          error
+		err        error
 	}
 	ok := pace.subprocesses.BlockForAtMost(pace.ctx, pace.localConfig.BlockchainTimeout,
 		func(ctx context.Context) {
@@ -518,22 +529,68 @@ func(pace *pacemakerState) getLatestNewIndexes() []int {
 		},
 	)
 	if !ok {
-		pace.logger.Error("pacemakerState getLatestNewIndexes: blockchain interaction timed out, returning true", types.LogFields{
-			"timeout":           pace.localConfig.BlockchainTimeout,
-			"err":               resultNewIndexes.err,
+		pace.logger.Error("Pacemaker: LatestNewIndexes timed out, falling back to default leader", types.LogFields{
+			"timeout": pace.localConfig.BlockchainTimeout,
+		})
+		return nil, types.LeaderSelectionFallbackTimeout
+	}
+	if resultNewIndexes.err != nil {
+		pace.logger.Error("Pacemaker: LatestNewIndexes returned error, falling back to default leader", types.LogFields{
+			"error": resultNewIndexes.err,
 		})
+		return nil, types.LeaderSelectionFallbackError
 	}
-	return resultNewIndexes.newIndexes
+
+	newIndexes := make([]int, 0, len(resultNewIndexes.newIndexes))
+	for _, i := range resultNewIndexes.newIndexes {
+		if 0 <= i && i < pace.config.N() {
+			newIndexes = append(newIndexes, i)
+		}
+	}
+	if len(newIndexes) != len(resultNewIndexes.newIndexes) {
+		pace.logger.Warn("Pacemaker: LatestNewIndexes returned invalid oracle ids, ignoring them", types.LogFields{
+			"newIndexes": resultNewIndexes.newIndexes,
+			"N":          pace.config.N(),
+		})
+	}
+	if len(newIndexes) == 0 {
+		return nil, types.LeaderSelectionFallbackEmpty
+	}
+	return newIndexes, ""
 }
 
-//judge whether the leader is in newIndexes or not.If not,it should change leader.
-func (pace *pacemakerState) verifyLeader(leader types.OracleID,epoch uint32) types.OracleID {
-	newIndexes:=pace.getLatestNewIndexes()
-	if IsExist(newIndexes,int(leader)){
-		return leader
+// verifyLeader checks whether leader is in the active oracle set reported
+// by the contract. If not, it deterministically picks an active oracle for
+// epoch instead. If the active set is unavailable, leader is kept.
+func (pace *pacemakerState) verifyLeader(leader types.OracleID, epoch uint32) types.OracleID {
+	selection := types.LeaderSelection{
+		ConfigDigest:  pace.config.ConfigDigest,
+		Epoch:         epoch,
+		DefaultLeader: leader,
+		Leader:        leader,
+		Time:          pace.clock.Now(),
 	}
-	index:=int(epoch)%len(newIndexes)
-	return types.OracleID(newIndexes[index])
+
+	newIndexes, fallback := pace.getLatestNewIndexes()
+	selection.ActiveIndexes = newIndexes
+	switch {
+	case fallback != "":
+		selection.Reason = fallback
+	case IsExist(newIndexes, int(leader)):
+		selection.Reason = types.LeaderSelectionActive
+	default:
+		selection.Reason = types.LeaderSelectionOverride
+		selection.Leader = types.OracleID(newIndexes[int(epoch)%len(newIndexes)])
+		pace.logger.Info("Pacemaker: default leader is not active, overriding", types.LogFields{
+			"epoch":         epoch,
+			"defaultLeader": leader,
+			"leader":        selection.Leader,
+			"newIndexes":    newIndexes,
+		})
+	}
+
+	pace.telemetrySender.LeaderSelected(selection)
+	return selection.Leader
 }
 
 // sortedGreaterThan returns the *sorted* elements of xs which are greater than y
@@ -18,6 +18,7 @@ func RunReportGeneration(
 	chNetToReportGeneration <-chan MessageToReportGenerationWithSender,
 	chReportGenerationToPacemaker chan<- EventToPacemaker,
 	chReportGenerationToTransmission chan<- EventToTransmission,
+	clock Clock,
 	config config.SharedConfig,
 	configOverrider types.ConfigOverrider,
 	contractTransmitter types.ContractTransmitter,
@@ -29,6 +30,7 @@ func RunReportGeneration(
 	logger loghelper.LoggerWithContext,
 	netSender NetworkSender,
 	privateKeys types.PrivateKeys,
+	reportPlugin types.ReportPlugin,
 	telemetrySender TelemetrySender,
 ) {
 	repgen := reportGenerationState{
@@ -38,6 +40,7 @@ func RunReportGeneration(
 		chNetToReportGeneration:          chNetToReportGeneration,
 		chReportGenerationToPacemaker:    chReportGenerationToPacemaker,
 		chReportGenerationToTransmission: chReportGenerationToTransmission,
+		clock:                            clock,
 		config:                           config,
 		configOverrider:                  configOverrider,
 		contractTransmitter:              contractTransmitter,
@@ -49,6 +52,7 @@ func RunReportGeneration(
 		logger:                           logger.MakeChild(types.LogFields{"epoch": e, "leader": l}),
 		netSender:                        netSender,
 		privateKeys:                      privateKeys,
+		reportPlugin:                     reportPlugin,
 		telemetrySender:                  telemetrySender,
 	}
 	repgen.run()
@@ -61,6 +65,7 @@ type reportGenerationState struct {
 	chNetToReportGeneration          <-chan MessageToReportGenerationWithSender
 	chReportGenerationToPacemaker    chan<- EventToPacemaker
 	chReportGenerationToTransmission chan<- EventToTransmission
+	clock                            Clock
 	config                           config.SharedConfig
 	configOverrider                  types.ConfigOverrider
 	contractTransmitter              types.ContractTransmitter
@@ -72,6 +77,7 @@ type reportGenerationState struct {
 	logger                           loghelper.LoggerWithContext
 	netSender                        NetworkSender
 	privateKeys                      types.PrivateKeys
+	reportPlugin                     types.ReportPlugin
 	telemetrySender                  TelemetrySender
 
 	leaderState   leaderState
@@ -119,7 +119,7 @@ func (repgen *reportGenerationState) messageObserveReq(msg MessageObserveReq, se
 		return
 	}
 
-	if err := so.Verify(repgen.followerReportContext(), repgen.privateKeys.PublicKeyOffChain()); err != nil {
+	if err := so.Verify(repgen.followerReportContext(), repgen.reportPlugin.ObservationWidth(), repgen.privateKeys.PublicKeyOffChain()); err != nil {
 		repgen.logger.Error("MakeSignedObservation produced invalid signature:", types.LogFields{
 			"round": repgen.followerState.r,
 			"error": err,
@@ -195,6 +195,7 @@ func (repgen *reportGenerationState) messageReportReq(msg MessageReportReq, send
 		report, err := MakeAttestedReportOne(
 			attributedValues,
 			repgen.followerReportContext(),
+			repgen.reportPlugin,
 			repgen.privateKeys.SignOnChain,
 		)
 		if err != nil {
@@ -211,7 +212,7 @@ func (repgen *reportGenerationState) messageReportReq(msg MessageReportReq, send
 		}
 
 		{
-			err := report.Verify(repgen.followerReportContext(), repgen.privateKeys.PublicKeyAddressOnChain())
+			err := report.Verify(repgen.followerReportContext(), repgen.reportPlugin, repgen.privateKeys.PublicKeyAddressOnChain())
 			if err != nil {
 				repgen.logger.Error("could not verify my own signature", types.LogFields{
 					"round":  repgen.followerState.r,
@@ -376,6 +377,10 @@ func (repgen *reportGenerationState) observeValue() observation.Observation {
 			// when this context is cancelled.
 			warnCtx, cancel := context.WithTimeout(ctx, repgen.localConfig.DataSourceTimeout)
 			defer cancel()
+			if width := repgen.reportPlugin.ObservationWidth(); width > 1 {
+				value, err = repgen.observeValues(warnCtx, width)
+				return
+			}
 			var rawValue types.Observation
 			rawValue, err = repgen.datasource.Observe(warnCtx)
 			if err != nil {
@@ -404,6 +409,27 @@ func (repgen *reportGenerationState) observeValue() observation.Observation {
 	return value
 }
 
+// observeValues gets an observation with width values from a datasource
+// implementing types.MultiValueDataSource, as required by multi-value report
+// plugins
+func (repgen *reportGenerationState) observeValues(ctx context.Context, width int) (observation.Observation, error) {
+	datasource, ok := repgen.datasource.(types.MultiValueDataSource)
+	if !ok {
+		return observation.Observation{}, errors.Errorf(
+			"report plugin %s needs %d values per observation, but DataSource does not implement MultiValueDataSource",
+			repgen.reportPlugin.Name(), width)
+	}
+	values, err := datasource.ObserveValues(ctx)
+	if err != nil {
+		return observation.Observation{}, err
+	}
+	if len(values) != width {
+		return observation.Observation{}, errors.Errorf(
+			"DataSource returned %d values, expected %d", len(values), width)
+	}
+	return observation.MakeMultiObservation(values)
+}
+
 func (repgen *reportGenerationState) shouldReport(observations []AttributedSignedObservation) bool {
 	var resultTransmissionDetails struct {
 		configDigest    types.ConfigDigest
@@ -509,7 +535,7 @@ func (repgen *reportGenerationState) shouldReport(observations []AttributedSigne
 			Deviates(answer, alphaPPB)
 	deltaCTimeout := // Has enough time passed since the last report, to merit a new one?
 		resultTransmissionDetails.latestTimestamp.Add(deltaC).
-			Before(time.Now())
+			Before(repgen.clock.Now())
 	unfulfilledRequest := // Has a new report been requested explicitly?
 		resultRoundRequested.configDigest == repgen.config.ConfigDigest &&
 			!(EpochRound{resultRoundRequested.epoch, resultRoundRequested.round}).
@@ -609,7 +635,7 @@ func (repgen *reportGenerationState) verifyReportReq(msg MessageReportReq) error
 				counted[obs.Observer] = true
 			}
 			observerOffchainPublicKey := repgen.config.OracleIdentities[obs.Observer].OffchainPublicKey
-			if err := obs.SignedObservation.Verify(repgen.followerReportContext(), observerOffchainPublicKey); err != nil {
+			if err := obs.SignedObservation.Verify(repgen.followerReportContext(), repgen.reportPlugin.ObservationWidth(), observerOffchainPublicKey); err != nil {
 				return errors.Errorf("invalid signed observation: %s", err)
 			}
 		}
@@ -640,7 +666,7 @@ func (repgen *reportGenerationState) verifyAttestedReport(
 			types.OracleID(oid)
 	}
 
-	err := report.VerifySignatures(repgen.followerReportContext(), keys)
+	err := report.VerifySignatures(repgen.followerReportContext(), repgen.reportPlugin, keys)
 	if err != nil {
 		repgen.logger.Error("could not validate signatures on final report",
 			types.LogFields{
@@ -2,7 +2,6 @@ package protocol
 
 import (
 	"sort"
-	"time"
 
 	"PhoenixOracle/lib/libocr/offchainreporting/types"
 )
@@ -66,7 +65,7 @@ func (repgen *reportGenerationState) startRound() {
 	repgen.leaderState.report = make([]*AttestedReportOne, repgen.config.N())
 	repgen.leaderState.phase = phaseObserve
 	repgen.netSender.Broadcast(MessageObserveReq{Epoch: repgen.e, Round: repgen.leaderState.r})
-	repgen.leaderState.tRound = time.After(repgen.config.DeltaRound)
+	repgen.leaderState.tRound = repgen.clock.After(repgen.config.DeltaRound)
 }
 
 // messageObserve is called when the current leader has received an "observe"
@@ -119,7 +118,7 @@ func (repgen *reportGenerationState) messageObserve(msg MessageObserve, sender t
 		return
 	}
 
-	if err := msg.SignedObservation.Verify(repgen.leaderReportContext(), repgen.config.OracleIdentities[sender].OffchainPublicKey); err != nil {
+	if err := msg.SignedObservation.Verify(repgen.leaderReportContext(), repgen.reportPlugin.ObservationWidth(), repgen.config.OracleIdentities[sender].OffchainPublicKey); err != nil {
 		repgen.logger.Warn("MessageObserve carries invalid SignedObservation", types.LogFields{
 			"round":  repgen.leaderState.r,
 			"sender": sender,
@@ -157,7 +156,7 @@ func (repgen *reportGenerationState) messageObserve(msg MessageObserve, sender t
 			repgen.logger.Debug("starting observation grace period", types.LogFields{
 				"round": repgen.leaderState.r,
 			})
-			repgen.leaderState.tGrace = time.After(repgen.config.DeltaGrace)
+			repgen.leaderState.tGrace = repgen.clock.After(repgen.config.DeltaGrace)
 			repgen.leaderState.phase = phaseGrace
 		}
 	case phaseGrace:
@@ -225,7 +224,7 @@ func (repgen *reportGenerationState) messageReport(msg MessageReport, sender typ
 	}
 
 	a := types.OnChainSigningAddress(repgen.config.OracleIdentities[sender].OnChainSigningAddress)
-	err := msg.Report.Verify(repgen.leaderReportContext(), a)
+	err := msg.Report.Verify(repgen.leaderReportContext(), repgen.reportPlugin, a)
 	if err != nil {
 		repgen.logger.Error("could not validate signature", types.LogFields{
 			"round": repgen.leaderState.r,
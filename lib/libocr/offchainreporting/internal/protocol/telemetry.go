@@ -9,4 +9,8 @@ type TelemetrySender interface {
 		round uint8,
 		leader types.OracleID,
 	)
+
+	// LeaderSelected is called whenever the pacemaker selects the leader
+	// of an epoch
+	LeaderSelected(selection types.LeaderSelection)
 }
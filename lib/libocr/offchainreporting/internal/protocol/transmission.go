@@ -27,6 +27,7 @@ func RunTransmission(
 	ctx context.Context,
 	subprocesses *subprocesses.Subprocesses,
 
+	clock Clock,
 	config config.SharedConfig,
 	configOverrider types.ConfigOverrider,
 	chReportGenerationToTransmission <-chan EventToTransmission,
@@ -34,6 +35,7 @@ func RunTransmission(
 	id types.OracleID,
 	localConfig types.LocalConfig,
 	logger loghelper.LoggerWithContext,
+	reportPlugin types.ReportPlugin,
 	transmitter types.ContractTransmitter,
 ) {
 	t := transmissionState{
@@ -41,12 +43,14 @@ func RunTransmission(
 		subprocesses: subprocesses,
 
 		chReportGenerationToTransmission: chReportGenerationToTransmission,
+		clock:                            clock,
 		config:                           config,
 		configOverrider:                  configOverrider,
 		database:                         database,
 		id:                               id,
 		localConfig:                      localConfig,
 		logger:                           logger,
+		reportPlugin:                     reportPlugin,
 		transmitter:                      transmitter,
 	}
 	t.run()
@@ -57,12 +61,14 @@ type transmissionState struct {
 	subprocesses *subprocesses.Subprocesses
 
 	chReportGenerationToTransmission <-chan EventToTransmission
+	clock                            Clock
 	config                           config.SharedConfig
 	configOverrider                  types.ConfigOverrider
 	database                         types.Database
 	id                               types.OracleID
 	localConfig                      types.LocalConfig
 	logger                           loghelper.LoggerWithContext
+	reportPlugin                     types.ReportPlugin
 	transmitter                      types.ContractTransmitter
 
 	latestEpochRound EpochRound
@@ -105,7 +111,7 @@ func (t *transmissionState) restoreFromDatabase() {
 		return
 	}
 
-	now := time.Now()
+	now := t.clock.Now()
 
 	// insert non-expired transmissions into queue
 	for key, trans := range pending {
@@ -136,7 +142,7 @@ func (t *transmissionState) restoreFromDatabase() {
 
 	// if queue isn't empty, set tTransmit to expire at next transmission time
 	if t.times.Len() != 0 {
-		t.tTransmit = time.After(now.Sub(t.times.Peek().Time))
+		t.tTransmit = t.clock.After(now.Sub(t.times.Peek().Time))
 	}
 }
 
@@ -179,7 +185,7 @@ func (t *transmissionState) eventTransmit(ev EventTransmit) {
 		t.logger.Error("could not compute median", types.LogFields{"error": err})
 	}
 
-	now := time.Now()
+	now := t.clock.Now()
 	delayMaybe := t.transmitDelay(ev.Epoch, ev.Round)
 	if delayMaybe == nil {
 		return
@@ -189,7 +195,7 @@ func (t *transmissionState) eventTransmit(ev EventTransmit) {
 		t.config.ConfigDigest,
 		ev.Epoch,
 		ev.Round,
-	})
+	}, t.reportPlugin)
 	if err != nil {
 		t.logger.Error("Failed to serialize contract report", types.LogFields{"error": err})
 		return
@@ -230,7 +236,7 @@ func (t *transmissionState) eventTransmit(ev EventTransmit) {
 
 	next := t.times.Peek()
 	if (EpochRound{ev.Epoch, ev.Round}) == (EpochRound{next.Epoch, next.Round}) {
-		t.tTransmit = time.After(delay)
+		t.tTransmit = t.clock.After(delay)
 	}
 }
 
@@ -239,7 +245,7 @@ func (t *transmissionState) eventTTransmitTimeout() {
 		if t.times.Len() != 0 { // If there's other transmissions due later...
 			// ...reset timer to expire when the next one is due
 			item := t.times.Peek()
-			t.tTransmit = time.After(time.Until(item.Time))
+			t.tTransmit = t.clock.After(item.Time.Sub(t.clock.Now()))
 		}
 	}()
 
@@ -9,13 +9,22 @@ import (
 )
 
 type TelemetrySender struct {
-	chTelemetry chan<- *protobuf.TelemetryWrapper
-	logger      types.Logger
-	taper       loghelper.LogarithmicTaper
+	chTelemetry             chan<- *protobuf.TelemetryWrapper
+	leaderSelectionObserver types.LeaderSelectionObserver
+	roundObserver           types.RoundObserver
+	logger                  types.Logger
+	taper                   loghelper.LogarithmicTaper
 }
 
-func MakeTelemetrySender(chTelemetry chan<- *protobuf.TelemetryWrapper, logger types.Logger) TelemetrySender {
-	return TelemetrySender{chTelemetry, logger, loghelper.LogarithmicTaper{}}
+// MakeTelemetrySender returns a TelemetrySender sending on chTelemetry.
+// leaderSelectionObserver and roundObserver may be nil.
+func MakeTelemetrySender(
+	chTelemetry chan<- *protobuf.TelemetryWrapper,
+	leaderSelectionObserver types.LeaderSelectionObserver,
+	roundObserver types.RoundObserver,
+	logger types.Logger,
+) TelemetrySender {
+	return TelemetrySender{chTelemetry, leaderSelectionObserver, roundObserver, logger, loghelper.LogarithmicTaper{}}
 }
 
 func (ts TelemetrySender) send(t *protobuf.TelemetryWrapper) {
@@ -41,6 +50,10 @@ func (ts TelemetrySender) RoundStarted(
 	round uint8,
 	leader types.OracleID,
 ) {
+	if ts.roundObserver != nil {
+		ts.roundObserver.RoundStarted(configDigest, epoch, round, leader)
+	}
+
 	ts.send(&protobuf.TelemetryWrapper{
 		Wrapped: &protobuf.TelemetryWrapper_RoundStarted{&protobuf.TelemetryRoundStarted{
 			ConfigDigest: configDigest[:],
@@ -51,3 +64,25 @@ func (ts TelemetrySender) RoundStarted(
 		}},
 	})
 }
+
+func (ts TelemetrySender) LeaderSelected(selection types.LeaderSelection) {
+	if ts.leaderSelectionObserver != nil {
+		ts.leaderSelectionObserver.LeaderSelected(selection)
+	}
+
+	activeIndexes := make([]uint32, 0, len(selection.ActiveIndexes))
+	for _, i := range selection.ActiveIndexes {
+		activeIndexes = append(activeIndexes, uint32(i))
+	}
+	ts.send(&protobuf.TelemetryWrapper{
+		Wrapped: &protobuf.TelemetryWrapper_LeaderSelected{&protobuf.TelemetryLeaderSelected{
+			ConfigDigest:  selection.ConfigDigest[:],
+			Epoch:         uint64(selection.Epoch),
+			DefaultLeader: uint64(selection.DefaultLeader),
+			Leader:        uint64(selection.Leader),
+			ActiveIndexes: activeIndexes,
+			Reason:        string(selection.Reason),
+			Time:          uint64(selection.Time.UnixNano()),
+		}},
+	})
+}
@@ -0,0 +1,165 @@
+package test
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"PhoenixOracle/lib/libocr/offchainreporting/internal/protocol"
+)
+
+// FakeClock is a protocol.Clock whose time only moves when it is advanced.
+// Timers that fall due at the same instant fire in the order they were
+// created.
+type FakeClock struct {
+	mu        sync.Mutex
+	now       time.Time
+	seq       uint64
+	timers    fakeTimers
+	scheduled uint64
+	fired     uint64
+}
+
+var _ protocol.Clock = (*FakeClock)(nil)
+
+// NewFakeClock returns a FakeClock reading start
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	c.AfterFunc(d, func(now time.Time) { ch <- now })
+	return ch
+}
+
+// AfterFunc calls f with the current fake time once the clock has been
+// advanced by at least d. f runs on the goroutine advancing the clock, or
+// right away if d <= 0.
+func (c *FakeClock) AfterFunc(d time.Duration, f func(now time.Time)) {
+	c.mu.Lock()
+	c.scheduled++
+	if d <= 0 {
+		now := c.now
+		c.fired++
+		c.mu.Unlock()
+		f(now)
+		return
+	}
+	c.seq++
+	heap.Push(&c.timers, &fakeTimer{at: c.now.Add(d), seq: c.seq, f: f})
+	c.mu.Unlock()
+}
+
+// NextDeadline returns the time at which the next pending timer fires
+func (c *FakeClock) NextDeadline() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.timers) == 0 {
+		return time.Time{}, false
+	}
+	return c.timers[0].at, true
+}
+
+// AdvanceTo moves the clock forward to t, firing every timer due at or
+// before t in deadline order. Timers created by a firing timer also fire if
+// they are due by t. The clock never moves backwards.
+func (c *FakeClock) AdvanceTo(t time.Time) {
+	for {
+		c.mu.Lock()
+		if len(c.timers) == 0 || c.timers[0].at.After(t) {
+			if t.After(c.now) {
+				c.now = t
+			}
+			c.mu.Unlock()
+			return
+		}
+		timer := heap.Pop(&c.timers).(*fakeTimer)
+		if timer.at.After(c.now) {
+			c.now = timer.at
+		}
+		now := c.now
+		c.fired++
+		c.mu.Unlock()
+		timer.f(now)
+	}
+}
+
+// Advance moves the clock forward by d, see AdvanceTo
+func (c *FakeClock) Advance(d time.Duration) {
+	c.AdvanceTo(c.Now().Add(d))
+}
+
+func (c *FakeClock) counters() (scheduled, fired uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.scheduled, c.fired
+}
+
+type fakeTimer struct {
+	at  time.Time
+	seq uint64
+	f   func(time.Time)
+}
+
+// fakeTimers is a min-heap of timers ordered by deadline, then creation
+type fakeTimers []*fakeTimer
+
+func (h fakeTimers) Len() int { return len(h) }
+func (h fakeTimers) Less(i, j int) bool {
+	if h[i].at.Equal(h[j].at) {
+		return h[i].seq < h[j].seq
+	}
+	return h[i].at.Before(h[j].at)
+}
+func (h fakeTimers) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *fakeTimers) Push(x interface{}) { *h = append(*h, x.(*fakeTimer)) }
+
+func (h *fakeTimers) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return x
+}
+
+// skewedClock is one oracle's view of a shared FakeClock. Its reading is
+// shifted by skew; durations are not affected.
+type skewedClock struct {
+	base *FakeClock
+
+	mu   sync.RWMutex
+	skew time.Duration
+}
+
+var _ protocol.Clock = (*skewedClock)(nil)
+
+func (c *skewedClock) setSkew(skew time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.skew = skew
+}
+
+func (c *skewedClock) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.base.Now().Add(c.skew)
+}
+
+func (c *skewedClock) After(d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	c.base.AfterFunc(d, func(now time.Time) {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		ch <- now.Add(c.skew)
+	})
+	return ch
+}
@@ -0,0 +1,300 @@
+package test
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"PhoenixOracle/lib/libocr/offchainreporting/internal/signature"
+	"PhoenixOracle/lib/libocr/offchainreporting/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+var reportArgs = func() abi.Arguments {
+	mustNewType := func(t string) abi.Type {
+		result, err := abi.NewType(t, "", []abi.ArgumentMarshaling{})
+		if err != nil {
+			panic(err)
+		}
+		return result
+	}
+	return abi.Arguments([]abi.Argument{
+		{Name: "rawReportContext", Type: mustNewType("bytes32")},
+		{Name: "rawObservers", Type: mustNewType("bytes32")},
+		{Name: "observations", Type: mustNewType("int192[]")},
+	})
+}()
+
+// Transmission is a report accepted by a SimulatedContract
+type Transmission struct {
+	Transmitter  types.OracleID
+	ConfigDigest types.ConfigDigest
+	Epoch        uint32
+	Round        uint8
+	Observations []*big.Int
+	Median       *big.Int
+	Timestamp    time.Time
+}
+
+// SimulatedContract mimics the on-chain OffchainAggregator: it verifies
+// signatures on transmitted reports, enforces that (epoch, round) strictly
+// increases, and records accepted transmissions.
+type SimulatedContract struct {
+	mu           sync.RWMutex
+	chainID      *big.Int
+	config       types.ContractConfig
+	signers      signature.EthAddresses
+	f            int
+	blockHeight  uint64
+	configBlock  uint64
+	latest       *Transmission
+	transmitted  []Transmission
+	rejected     int
+	activeSet    []int
+	subscribers  []*configSubscription
+	transmitHook func(Transmission)
+}
+
+func newSimulatedContract(chainID *big.Int, config types.ContractConfig, f int) *SimulatedContract {
+	signers := make(signature.EthAddresses)
+	for i, s := range config.Signers {
+		signers[types.OnChainSigningAddress(s)] = types.OracleID(i)
+	}
+	return &SimulatedContract{
+		chainID:     chainID,
+		config:      config,
+		signers:     signers,
+		f:           f,
+		blockHeight: 1,
+		configBlock: 1,
+	}
+}
+
+// Transmissions returns all transmissions accepted so far, oldest first
+func (c *SimulatedContract) Transmissions() []Transmission {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]Transmission(nil), c.transmitted...)
+}
+
+// Rejected returns the number of transmissions the contract refused, either
+// because they were stale or because they carried invalid signatures
+func (c *SimulatedContract) Rejected() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.rejected
+}
+
+// SetActiveSet sets the oracle indexes returned by LatestNewIndexes. A nil
+// slice means all oracles are active.
+func (c *SimulatedContract) SetActiveSet(indexes []int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.activeSet = append([]int(nil), indexes...)
+}
+
+func (c *SimulatedContract) onTransmit(hook func(Transmission)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.transmitHook = hook
+}
+
+func (c *SimulatedContract) transmit(from types.OracleID, now time.Time, report []byte, rs, ss [][32]byte, vs [32]byte) error {
+	t, err := c.verify(from, now, report, rs, ss, vs)
+
+	c.mu.Lock()
+	if err != nil {
+		c.rejected++
+		c.mu.Unlock()
+		return err
+	}
+	if c.latest != nil && c.latest.ConfigDigest == t.ConfigDigest &&
+		(t.Epoch < c.latest.Epoch || (t.Epoch == c.latest.Epoch && t.Round <= c.latest.Round)) {
+		c.rejected++
+		c.mu.Unlock()
+		return errors.Errorf("stale report: (%d, %d) is not newer than (%d, %d)",
+			t.Epoch, t.Round, c.latest.Epoch, c.latest.Round)
+	}
+	c.blockHeight++
+	c.transmitted = append(c.transmitted, *t)
+	c.latest = t
+	hook := c.transmitHook
+	c.mu.Unlock()
+
+	if hook != nil {
+		hook(*t)
+	}
+	return nil
+}
+
+func (c *SimulatedContract) verify(from types.OracleID, now time.Time, report []byte, rs, ss [][32]byte, vs [32]byte) (*Transmission, error) {
+	if len(rs) != len(ss) {
+		return nil, errors.Errorf("signature length mismatch: %d rs, %d ss", len(rs), len(ss))
+	}
+	if len(rs) <= c.f {
+		return nil, errors.Errorf("need more than %d signatures, got %d", c.f, len(rs))
+	}
+	seen := make(map[types.OracleID]bool)
+	for i := range rs {
+		sig := append(append(append([]byte{}, rs[i][:]...), ss[i][:]...), vs[i])
+		oid, err := signature.VerifyOnChain(report, sig, c.signers)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid signature #%d", i)
+		}
+		if seen[oid] {
+			return nil, errors.Errorf("oracle #%d signed more than once", oid)
+		}
+		seen[oid] = true
+	}
+
+	values, err := reportArgs.Unpack(report)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode report")
+	}
+	rawCtx, ok := values[0].([32]byte)
+	if !ok {
+		return nil, errors.Errorf("unexpected report context type %T", values[0])
+	}
+	observations, ok := values[2].([]*big.Int)
+	if !ok {
+		return nil, errors.Errorf("unexpected observations type %T", values[2])
+	}
+	if len(observations) == 0 {
+		return nil, errors.New("report contains no observations")
+	}
+	for i := 1; i < len(observations); i++ {
+		if observations[i-1].Cmp(observations[i]) > 0 {
+			return nil, errors.New("observations not sorted")
+		}
+	}
+
+	var digest types.ConfigDigest
+	copy(digest[:], rawCtx[11:27])
+	if digest != c.config.ConfigDigest {
+		return nil, errors.Errorf("config digest mismatch: %x", digest)
+	}
+	epochRound := rawCtx[27:]
+	return &Transmission{
+		Transmitter:  from,
+		ConfigDigest: digest,
+		Epoch:        uint32(epochRound[0])<<24 | uint32(epochRound[1])<<16 | uint32(epochRound[2])<<8 | uint32(epochRound[3]),
+		Round:        epochRound[4],
+		Observations: observations,
+		Median:       observations[len(observations)/2],
+		Timestamp:    now,
+	}, nil
+}
+
+// simulatedTransmitter is oracle id's view of a SimulatedContract. Reports
+// are timestamped with the chain clock, which is never skewed, so an oracle
+// whose own clock is skewed sees chain timestamps shifted relative to its
+// notion of now.
+type simulatedTransmitter struct {
+	contract *SimulatedContract
+	id       types.OracleID
+	from     common.Address
+	clock    *FakeClock
+}
+
+var _ types.ContractTransmitter = (*simulatedTransmitter)(nil)
+
+func (t *simulatedTransmitter) Transmit(ctx context.Context, report []byte, rs, ss [][32]byte, vs [32]byte) error {
+	return t.contract.transmit(t.id, t.clock.Now(), report, rs, ss, vs)
+}
+
+func (t *simulatedTransmitter) LatestTransmissionDetails(ctx context.Context) (
+	configDigest types.ConfigDigest,
+	epoch uint32,
+	round uint8,
+	latestAnswer types.Observation,
+	latestTimestamp time.Time,
+	err error,
+) {
+	c := t.contract
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.latest == nil {
+		return c.config.ConfigDigest, 0, 0, big.NewInt(0), time.Time{}, nil
+	}
+	return c.latest.ConfigDigest, c.latest.Epoch, c.latest.Round,
+		new(big.Int).Set(c.latest.Median), c.latest.Timestamp, nil
+}
+
+func (t *simulatedTransmitter) LatestRoundRequested(ctx context.Context, lookback time.Duration) (types.ConfigDigest, uint32, uint8, error) {
+	return types.ConfigDigest{}, 0, 0, nil
+}
+
+func (t *simulatedTransmitter) LatestNewIndexes(ctx context.Context, lookback time.Duration) ([]int, error) {
+	c := t.contract
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.activeSet != nil {
+		return append([]int(nil), c.activeSet...), nil
+	}
+	indexes := make([]int, len(c.config.Signers))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return indexes, nil
+}
+
+func (t *simulatedTransmitter) FromAddress() common.Address {
+	return t.from
+}
+
+func (t *simulatedTransmitter) ChainID() *big.Int {
+	return new(big.Int).Set(t.contract.chainID)
+}
+
+// simulatedConfigTracker implements types.ContractConfigTracker on top of a
+// SimulatedContract
+type simulatedConfigTracker struct {
+	contract *SimulatedContract
+}
+
+var _ types.ContractConfigTracker = simulatedConfigTracker{}
+
+func (t simulatedConfigTracker) SubscribeToNewConfigs(ctx context.Context) (types.ContractConfigSubscription, error) {
+	sub := &configSubscription{ch: make(chan types.ContractConfig)}
+	t.contract.mu.Lock()
+	defer t.contract.mu.Unlock()
+	t.contract.subscribers = append(t.contract.subscribers, sub)
+	return sub, nil
+}
+
+func (t simulatedConfigTracker) LatestConfigDetails(ctx context.Context) (uint64, types.ConfigDigest, error) {
+	t.contract.mu.RLock()
+	defer t.contract.mu.RUnlock()
+	return t.contract.configBlock, t.contract.config.ConfigDigest, nil
+}
+
+func (t simulatedConfigTracker) ConfigFromLogs(ctx context.Context, changedInBlock uint64) (types.ContractConfig, error) {
+	t.contract.mu.RLock()
+	defer t.contract.mu.RUnlock()
+	if changedInBlock != t.contract.configBlock {
+		return types.ContractConfig{}, errors.Errorf("no config change in block %d", changedInBlock)
+	}
+	return t.contract.config, nil
+}
+
+func (t simulatedConfigTracker) LatestBlockHeight(ctx context.Context) (uint64, error) {
+	t.contract.mu.RLock()
+	defer t.contract.mu.RUnlock()
+	return t.contract.blockHeight, nil
+}
+
+type configSubscription struct {
+	ch        chan types.ContractConfig
+	closeOnce sync.Once
+}
+
+func (s *configSubscription) Configs() <-chan types.ContractConfig {
+	return s.ch
+}
+
+func (s *configSubscription) Close() {
+	s.closeOnce.Do(func() { close(s.ch) })
+}
@@ -0,0 +1,101 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"PhoenixOracle/lib/libocr/offchainreporting/types"
+)
+
+// InMemoryDatabase is a types.Database that keeps everything in memory. A
+// crashed oracle that is restarted with the same InMemoryDatabase behaves
+// like a node restarting with its on-disk state intact.
+type InMemoryDatabase struct {
+	mu                   sync.Mutex
+	states               map[types.ConfigDigest]types.PersistentState
+	config               *types.ContractConfig
+	pendingTransmissions map[types.PendingTransmissionKey]types.PendingTransmission
+}
+
+var _ types.Database = (*InMemoryDatabase)(nil)
+
+func NewInMemoryDatabase() *InMemoryDatabase {
+	return &InMemoryDatabase{
+		states:               make(map[types.ConfigDigest]types.PersistentState),
+		pendingTransmissions: make(map[types.PendingTransmissionKey]types.PendingTransmission),
+	}
+}
+
+func (db *InMemoryDatabase) ReadState(ctx context.Context, configDigest types.ConfigDigest) (*types.PersistentState, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	state, ok := db.states[configDigest]
+	if !ok {
+		return nil, nil
+	}
+	state.HighestReceivedEpoch = append([]uint32(nil), state.HighestReceivedEpoch...)
+	return &state, nil
+}
+
+func (db *InMemoryDatabase) WriteState(ctx context.Context, configDigest types.ConfigDigest, state types.PersistentState) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	state.HighestReceivedEpoch = append([]uint32(nil), state.HighestReceivedEpoch...)
+	db.states[configDigest] = state
+	return nil
+}
+
+func (db *InMemoryDatabase) ReadConfig(ctx context.Context) (*types.ContractConfig, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.config == nil {
+		return nil, nil
+	}
+	config := *db.config
+	return &config, nil
+}
+
+func (db *InMemoryDatabase) WriteConfig(ctx context.Context, config types.ContractConfig) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.config = &config
+	return nil
+}
+
+func (db *InMemoryDatabase) StorePendingTransmission(ctx context.Context, key types.PendingTransmissionKey, p types.PendingTransmission) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.pendingTransmissions[key] = p
+	return nil
+}
+
+func (db *InMemoryDatabase) PendingTransmissionsWithConfigDigest(ctx context.Context, configDigest types.ConfigDigest) (map[types.PendingTransmissionKey]types.PendingTransmission, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	result := make(map[types.PendingTransmissionKey]types.PendingTransmission)
+	for k, p := range db.pendingTransmissions {
+		if k.ConfigDigest == configDigest {
+			result[k] = p
+		}
+	}
+	return result, nil
+}
+
+func (db *InMemoryDatabase) DeletePendingTransmission(ctx context.Context, key types.PendingTransmissionKey) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	delete(db.pendingTransmissions, key)
+	return nil
+}
+
+func (db *InMemoryDatabase) DeletePendingTransmissionsOlderThan(ctx context.Context, t time.Time) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	for k, p := range db.pendingTransmissions {
+		if p.Time.Before(t) {
+			delete(db.pendingTransmissions, k)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package test
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"PhoenixOracle/lib/libocr/offchainreporting/types"
+	"github.com/pkg/errors"
+)
+
+// ObservationFunc computes the value observed by an oracle on its nth call
+// to Observe, starting at zero
+type ObservationFunc func(n int) (*big.Int, error)
+
+// ScriptedDataSource is a types.DataSource whose observations are produced
+// by an ObservationFunc. It can additionally be made slow, which is useful
+// for exercising DataSourceTimeout handling.
+type ScriptedDataSource struct {
+	mu      sync.Mutex
+	observe ObservationFunc
+	delay   time.Duration
+	calls   int
+}
+
+var _ types.DataSource = (*ScriptedDataSource)(nil)
+
+func NewScriptedDataSource(observe ObservationFunc) *ScriptedDataSource {
+	return &ScriptedDataSource{observe: observe}
+}
+
+// ConstantDataSource always observes value
+func ConstantDataSource(value int64) *ScriptedDataSource {
+	return NewScriptedDataSource(func(int) (*big.Int, error) {
+		return big.NewInt(value), nil
+	})
+}
+
+// SequenceDataSource observes the given values in order, repeating the last
+// one once the sequence is exhausted
+func SequenceDataSource(values ...int64) *ScriptedDataSource {
+	return NewScriptedDataSource(func(n int) (*big.Int, error) {
+		if len(values) == 0 {
+			return nil, errors.New("empty sequence")
+		}
+		if n >= len(values) {
+			n = len(values) - 1
+		}
+		return big.NewInt(values[n]), nil
+	})
+}
+
+// FailingDataSource always returns an error
+func FailingDataSource() *ScriptedDataSource {
+	return NewScriptedDataSource(func(int) (*big.Int, error) {
+		return nil, errors.New("scripted data source failure")
+	})
+}
+
+// SetObservationFunc replaces the function producing observations. The call
+// counter is not reset.
+func (ds *ScriptedDataSource) SetObservationFunc(observe ObservationFunc) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	ds.observe = observe
+}
+
+// SetDelay makes every subsequent call to Observe take at least delay, or
+// until its context expires
+func (ds *ScriptedDataSource) SetDelay(delay time.Duration) {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	ds.delay = delay
+}
+
+// Calls returns the number of times Observe has been called
+func (ds *ScriptedDataSource) Calls() int {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+	return ds.calls
+}
+
+func (ds *ScriptedDataSource) Observe(ctx context.Context) (types.Observation, error) {
+	ds.mu.Lock()
+	n := ds.calls
+	ds.calls++
+	observe, delay := ds.observe, ds.delay
+	ds.mu.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	value, err := observe(n)
+	if err != nil {
+		return nil, err
+	}
+	return types.Observation(value), nil
+}
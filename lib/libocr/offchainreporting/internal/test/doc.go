@@ -1 +1,20 @@
+// Package test contains an in-process simulator for running several
+// offchain reporting oracles against each other without libp2p or a chain.
+//
+// A Simulation wires N protocol oracles through an in-memory
+// types.BinaryNetworkEndpoint (SimulatedNetwork), a fake contract
+// (SimulatedContract) that stands in for both the ContractTransmitter and
+// the ContractConfigTracker, in-memory databases and scripted DataSources.
+// The network can delay, drop and tamper with messages, oracles can be made
+// Byzantine or crashed, and each oracle's clock can be skewed relative to the
+// chain to simulate clock drift.
+//
+// Oracles don't read the wall clock: their protocol timers, the network's
+// link delays and the contract's timestamps all run on a FakeClock that the
+// Simulation steps from one timer to the next, waiting for the oracles to go
+// idle in between. Network decisions (delays, drops) are drawn from seeded
+// per-link sources, so a run is reproducible for a given seed. The exception
+// is messages that reach the same oracle at exactly the same simulated
+// instant, e.g. over links with a fixed delay, which may arrive in either
+// order.
 package test
@@ -0,0 +1,78 @@
+package test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"io"
+
+	"PhoenixOracle/lib/libocr/offchainreporting/internal/signature"
+	"PhoenixOracle/lib/libocr/offchainreporting/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/curve25519"
+)
+
+// privateKeys is a throwaway in-memory implementation of types.PrivateKeys
+type privateKeys struct {
+	onChain   signature.OnchainPrivateKey
+	offChain  signature.OffchainPrivateKey
+	configKey [curve25519.ScalarSize]byte
+}
+
+var _ types.PrivateKeys = (*privateKeys)(nil)
+
+func newPrivateKeys(rand io.Reader) (*privateKeys, error) {
+	onChain, err := ecdsa.GenerateKey(crypto.S256(), rand)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not generate on-chain key")
+	}
+	_, offChain, err := ed25519.GenerateKey(rand)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not generate off-chain key")
+	}
+	k := &privateKeys{
+		onChain:  signature.OnchainPrivateKey(*onChain),
+		offChain: signature.OffchainPrivateKey(offChain),
+	}
+	if _, err = io.ReadFull(rand, k.configKey[:]); err != nil {
+		return nil, errors.Wrap(err, "could not generate config key")
+	}
+	return k, nil
+}
+
+func (k *privateKeys) SignOnChain(msg []byte) ([]byte, error) {
+	return k.onChain.Sign(msg)
+}
+
+func (k *privateKeys) SignOffChain(msg []byte) ([]byte, error) {
+	return k.offChain.Sign(msg)
+}
+
+func (k *privateKeys) ConfigDiffieHellman(base *[curve25519.ScalarSize]byte) (*[curve25519.PointSize]byte, error) {
+	p, err := curve25519.X25519(k.configKey[:], base[:])
+	if err != nil {
+		return nil, err
+	}
+	var sharedPoint [curve25519.PointSize]byte
+	copy(sharedPoint[:], p)
+	return &sharedPoint, nil
+}
+
+func (k *privateKeys) PublicKeyAddressOnChain() types.OnChainSigningAddress {
+	return k.onChain.Address()
+}
+
+func (k *privateKeys) PublicKeyOffChain() types.OffchainPublicKey {
+	return types.OffchainPublicKey(k.offChain.PublicKey())
+}
+
+func (k *privateKeys) PublicKeyConfig() [curve25519.PointSize]byte {
+	var pub [curve25519.PointSize]byte
+	p, err := curve25519.X25519(k.configKey[:], curve25519.Basepoint)
+	if err != nil {
+		// only possible for a low order basepoint, which Basepoint is not
+		panic(err)
+	}
+	copy(pub[:], p)
+	return pub
+}
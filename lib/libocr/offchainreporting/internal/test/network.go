@@ -0,0 +1,281 @@
+package test
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"PhoenixOracle/lib/libocr/offchainreporting/types"
+	"github.com/pkg/errors"
+)
+
+const simulatedEndpointBufferSize = 1000
+
+// LinkConfig controls how messages travel over a directed link between two
+// oracles. Each message is delayed by a uniformly random duration in
+// [MinDelay, MaxDelay] and dropped with probability DropProbability.
+type LinkConfig struct {
+	MinDelay        time.Duration
+	MaxDelay        time.Duration
+	DropProbability float64
+}
+
+// Interceptor is called for every message before it is put on the wire. It
+// may return a modified payload, or deliver=false to drop the message. It is
+// the hook used to implement Byzantine behaviours.
+type Interceptor func(from, to types.OracleID, payload []byte) (out []byte, deliver bool)
+
+// NetworkStats counts messages handled by a SimulatedNetwork
+type NetworkStats struct {
+	Sent      uint64
+	Delivered uint64
+	Dropped   uint64
+}
+
+type link struct {
+	from, to types.OracleID
+}
+
+// SimulatedNetwork is an in-memory network connecting n oracles. Each
+// directed link draws its randomness from its own source derived from the
+// seed given at construction, so the fate of a message doesn't depend on
+// how sends on other links interleave. Delayed messages are delivered when
+// clock is advanced past their delay.
+type SimulatedNetwork struct {
+	mu           sync.Mutex
+	n            int
+	clock        *FakeClock
+	seed         int64
+	rands        map[link]*rand.Rand
+	defaultLink  LinkConfig
+	links        map[link]LinkConfig
+	cut          map[link]bool
+	interceptors []Interceptor
+	endpoints    []*simulatedEndpoint
+	stats        NetworkStats
+}
+
+// NewSimulatedNetwork returns a network for n oracles where every link
+// behaves according to defaultLink unless overridden with SetLink
+func NewSimulatedNetwork(n int, seed int64, defaultLink LinkConfig, clock *FakeClock) *SimulatedNetwork {
+	return &SimulatedNetwork{
+		n:           n,
+		clock:       clock,
+		seed:        seed,
+		rands:       make(map[link]*rand.Rand),
+		defaultLink: defaultLink,
+		links:       make(map[link]LinkConfig),
+		cut:         make(map[link]bool),
+		endpoints:   make([]*simulatedEndpoint, n),
+	}
+}
+
+// Endpoint returns a fresh endpoint for oracle id, replacing any previous
+// endpoint for that oracle (e.g. after a simulated crash)
+func (net *SimulatedNetwork) Endpoint(id types.OracleID) (types.BinaryNetworkEndpoint, error) {
+	if int(id) < 0 || int(id) >= net.n {
+		return nil, errors.Errorf("oracle id %d out of range [0, %d)", id, net.n)
+	}
+	end := &simulatedEndpoint{
+		net: net,
+		id:  id,
+		ch:  make(chan types.BinaryMessageWithSender, simulatedEndpointBufferSize),
+	}
+	net.mu.Lock()
+	defer net.mu.Unlock()
+	net.endpoints[id] = end
+	return end, nil
+}
+
+// SetDefaultLink changes the behaviour of all links without an override
+func (net *SimulatedNetwork) SetDefaultLink(cfg LinkConfig) {
+	net.mu.Lock()
+	defer net.mu.Unlock()
+	net.defaultLink = cfg
+}
+
+// SetLink overrides the behaviour of the directed link from -> to
+func (net *SimulatedNetwork) SetLink(from, to types.OracleID, cfg LinkConfig) {
+	net.mu.Lock()
+	defer net.mu.Unlock()
+	net.links[link{from, to}] = cfg
+}
+
+// Partition cuts all links between oracles in different groups. Oracles not
+// mentioned in any group are isolated from everyone.
+func (net *SimulatedNetwork) Partition(groups ...[]types.OracleID) {
+	group := make(map[types.OracleID]int)
+	for i, g := range groups {
+		for _, id := range g {
+			group[id] = i + 1
+		}
+	}
+	net.mu.Lock()
+	defer net.mu.Unlock()
+	net.cut = make(map[link]bool)
+	for from := 0; from < net.n; from++ {
+		for to := 0; to < net.n; to++ {
+			f, t := types.OracleID(from), types.OracleID(to)
+			if f == t {
+				continue
+			}
+			if group[f] == 0 || group[f] != group[t] {
+				net.cut[link{f, t}] = true
+			}
+		}
+	}
+}
+
+// Heal removes any partition
+func (net *SimulatedNetwork) Heal() {
+	net.mu.Lock()
+	defer net.mu.Unlock()
+	net.cut = make(map[link]bool)
+}
+
+// AddInterceptor registers an interceptor. Interceptors run in the order
+// they were added.
+func (net *SimulatedNetwork) AddInterceptor(i Interceptor) {
+	net.mu.Lock()
+	defer net.mu.Unlock()
+	net.interceptors = append(net.interceptors, i)
+}
+
+// Stats returns a snapshot of the message counters
+func (net *SimulatedNetwork) Stats() NetworkStats {
+	net.mu.Lock()
+	defer net.mu.Unlock()
+	return net.stats
+}
+
+func (net *SimulatedNetwork) send(from, to types.OracleID, payload []byte) {
+	net.mu.Lock()
+	net.stats.Sent++
+	if net.cut[link{from, to}] {
+		net.stats.Dropped++
+		net.mu.Unlock()
+		return
+	}
+	cfg, ok := net.links[link{from, to}]
+	if !ok {
+		cfg = net.defaultLink
+	}
+	r := net.linkRand(link{from, to})
+	if cfg.DropProbability > 0 && r.Float64() < cfg.DropProbability {
+		net.stats.Dropped++
+		net.mu.Unlock()
+		return
+	}
+	delay := cfg.MinDelay
+	if cfg.MaxDelay > cfg.MinDelay {
+		delay += time.Duration(r.Int63n(int64(cfg.MaxDelay - cfg.MinDelay)))
+	}
+	// copy so that interceptors and receivers never share the sender's buffer
+	msg := append([]byte(nil), payload...)
+	for _, intercept := range net.interceptors {
+		var deliver bool
+		msg, deliver = intercept(from, to, msg)
+		if !deliver {
+			net.stats.Dropped++
+			net.mu.Unlock()
+			return
+		}
+	}
+	end := net.endpoints[to]
+	net.mu.Unlock()
+
+	if end == nil {
+		net.countDelivery(false)
+		return
+	}
+	net.clock.AfterFunc(delay, func(time.Time) {
+		net.countDelivery(end.deliver(types.BinaryMessageWithSender{Msg: msg, Sender: from}))
+	})
+}
+
+// linkRand returns the random source of l. Callers must hold net.mu.
+func (net *SimulatedNetwork) linkRand(l link) *rand.Rand {
+	r, ok := net.rands[l]
+	if !ok {
+		r = rand.New(rand.NewSource(net.seed + int64(l.from)*int64(net.n) + int64(l.to)))
+		net.rands[l] = r
+	}
+	return r
+}
+
+func (net *SimulatedNetwork) countDelivery(delivered bool) {
+	net.mu.Lock()
+	defer net.mu.Unlock()
+	if delivered {
+		net.stats.Delivered++
+	} else {
+		net.stats.Dropped++
+	}
+}
+
+// simulatedEndpoint implements types.BinaryNetworkEndpoint on top of a
+// SimulatedNetwork. Like a real endpoint, it never blocks the sender and
+// drops messages when the receive buffer is full.
+type simulatedEndpoint struct {
+	net *SimulatedNetwork
+	id  types.OracleID
+	ch  chan types.BinaryMessageWithSender
+
+	mu      sync.Mutex
+	started bool
+	closed  bool
+}
+
+var _ types.BinaryNetworkEndpoint = (*simulatedEndpoint)(nil)
+
+func (end *simulatedEndpoint) SendTo(payload []byte, to types.OracleID) {
+	if int(to) < 0 || int(to) >= end.net.n {
+		return
+	}
+	end.net.send(end.id, to, payload)
+}
+
+func (end *simulatedEndpoint) Broadcast(payload []byte) {
+	for to := 0; to < end.net.n; to++ {
+		end.net.send(end.id, types.OracleID(to), payload)
+	}
+}
+
+func (end *simulatedEndpoint) Receive() <-chan types.BinaryMessageWithSender {
+	return end.ch
+}
+
+func (end *simulatedEndpoint) Start() error {
+	end.mu.Lock()
+	defer end.mu.Unlock()
+	if end.started {
+		return errors.New("simulated endpoint already started")
+	}
+	end.started = true
+	return nil
+}
+
+func (end *simulatedEndpoint) Close() error {
+	end.mu.Lock()
+	defer end.mu.Unlock()
+	if end.closed {
+		return errors.New("simulated endpoint already closed")
+	}
+	end.closed = true
+	close(end.ch)
+	return nil
+}
+
+func (end *simulatedEndpoint) deliver(msg types.BinaryMessageWithSender) bool {
+	end.mu.Lock()
+	defer end.mu.Unlock()
+	if !end.started || end.closed {
+		return false
+	}
+	select {
+	case end.ch <- msg:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,516 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sync"
+	"time"
+
+	"PhoenixOracle/lib/libocr/offchainreporting/internal/config"
+	"PhoenixOracle/lib/libocr/offchainreporting/internal/protocol"
+	"PhoenixOracle/lib/libocr/offchainreporting/internal/serialization/protobuf"
+	"PhoenixOracle/lib/libocr/offchainreporting/internal/shim"
+	"PhoenixOracle/lib/libocr/offchainreporting/loghelper"
+	"PhoenixOracle/lib/libocr/offchainreporting/reportplugin"
+	"PhoenixOracle/lib/libocr/offchainreporting/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// Behaviour describes how an oracle treats the messages it sends
+type Behaviour int
+
+const (
+	// Honest oracles send their messages unmodified
+	Honest Behaviour = iota
+	// Silent oracles run the protocol but none of their messages arrive
+	Silent
+	// Corrupting oracles flip a random bit in every message they send
+	Corrupting
+)
+
+// SimulationConfig configures a Simulation. Zero durations are replaced by
+// the defaults from DefaultSimulationConfig.
+type SimulationConfig struct {
+	N    int
+	F    int
+	Seed int64
+
+	DeltaProgress time.Duration
+	DeltaResend   time.Duration
+	DeltaRound    time.Duration
+	DeltaGrace    time.Duration
+	DeltaC        time.Duration
+	DeltaStage    time.Duration
+	AlphaPPB      uint64
+	RMax          uint8
+
+	Link        LinkConfig
+	LocalConfig types.LocalConfig
+	// Logger receives the logs of all oracles. Defaults to discarding them.
+	Logger types.Logger
+}
+
+// DefaultSimulationConfig returns a configuration with timings short enough
+// for oracles to produce several reports per second
+func DefaultSimulationConfig(n, f int) SimulationConfig {
+	return SimulationConfig{
+		N:             n,
+		F:             f,
+		DeltaProgress: 2 * time.Second,
+		DeltaResend:   500 * time.Millisecond,
+		DeltaRound:    200 * time.Millisecond,
+		DeltaGrace:    50 * time.Millisecond,
+		DeltaC:        100 * time.Millisecond,
+		DeltaStage:    500 * time.Millisecond,
+		AlphaPPB:      0,
+		RMax:          20,
+		Link:          LinkConfig{MaxDelay: 5 * time.Millisecond},
+		LocalConfig: types.LocalConfig{
+			BlockchainTimeout:                  time.Second,
+			ContractConfigConfirmations:        1,
+			ContractConfigTrackerPollInterval:  time.Second,
+			ContractTransmitterTransmitTimeout: time.Second,
+			DatabaseTimeout:                    time.Second,
+			DataSourceTimeout:                  100 * time.Millisecond,
+			DataSourceGracePeriod:              10 * time.Millisecond,
+		},
+	}
+}
+
+func (c SimulationConfig) validate() error {
+	if c.N <= 0 || c.N > types.MaxOracles {
+		return errors.Errorf("N must be in [1, %d], got %d", types.MaxOracles, c.N)
+	}
+	if c.F < 0 || 3*c.F >= c.N {
+		return errors.Errorf("F must satisfy 0 <= 3F < N, got N=%d F=%d", c.N, c.F)
+	}
+	return nil
+}
+
+// simulationStart is the simulated time at which every Simulation begins
+var simulationStart = time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+const (
+	// settleInterval and settleRounds control how long the simulation waits
+	// for the oracles to go idle before advancing its clock: it waits until
+	// nothing was sent, delivered, scheduled or transmitted for settleRounds
+	// consecutive settleIntervals of real time.
+	settleInterval = time.Millisecond
+	settleRounds   = 5
+)
+
+type simulatedOracle struct {
+	id          types.OracleID
+	clock       *skewedClock
+	keys        *privateKeys
+	db          *InMemoryDatabase
+	dataSource  types.DataSource
+	transmitter *simulatedTransmitter
+
+	running  bool
+	cancel   context.CancelFunc
+	endpoint *shim.SerializingEndpoint
+	done     chan struct{}
+}
+
+// Simulation runs N oracles in a single process. Use NewSimulation to
+// create one.
+//
+// Oracles run on simulated time read from Clock. The clock only moves in
+// WaitForTransmissions and AdvanceTime, which step it from one pending timer
+// to the next and let the oracles go idle in between.
+type Simulation struct {
+	Clock    *FakeClock
+	Network  *SimulatedNetwork
+	Contract *SimulatedContract
+
+	config       SimulationConfig
+	sharedConfig config.SharedConfig
+	logger       loghelper.LoggerWithContext
+	rand         *rand.Rand
+
+	mu         sync.Mutex
+	oracles    []*simulatedOracle
+	chTransmit chan Transmission
+
+	// behaviourMu is separate from mu because intercept runs with the
+	// network lock held, while StartOracle takes the network lock with mu
+	// held
+	behaviourMu sync.Mutex
+	behaviours  map[types.OracleID]Behaviour
+
+	selectionsMu     sync.Mutex
+	leaderSelections []types.LeaderSelection
+}
+
+// NewSimulation creates the keys, shared config, network and contract for a
+// simulation. Every oracle starts with a ConstantDataSource until
+// SetDataSource is called. No oracle runs until Start.
+func NewSimulation(cfg SimulationConfig) (*Simulation, error) {
+	defaults := DefaultSimulationConfig(cfg.N, cfg.F)
+	if cfg.DeltaProgress == 0 {
+		cfg.DeltaProgress = defaults.DeltaProgress
+	}
+	if cfg.DeltaResend == 0 {
+		cfg.DeltaResend = defaults.DeltaResend
+	}
+	if cfg.DeltaRound == 0 {
+		cfg.DeltaRound = defaults.DeltaRound
+	}
+	if cfg.DeltaGrace == 0 {
+		cfg.DeltaGrace = defaults.DeltaGrace
+	}
+	if cfg.DeltaC == 0 {
+		cfg.DeltaC = defaults.DeltaC
+	}
+	if cfg.DeltaStage == 0 {
+		cfg.DeltaStage = defaults.DeltaStage
+	}
+	if cfg.RMax == 0 {
+		cfg.RMax = defaults.RMax
+	}
+	if cfg.LocalConfig == (types.LocalConfig{}) {
+		cfg.LocalConfig = defaults.LocalConfig
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = discardLogger{}
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	r := rand.New(rand.NewSource(cfg.Seed))
+	sim := &Simulation{
+		Clock:      NewFakeClock(simulationStart),
+		config:     cfg,
+		logger:     loghelper.MakeRootLoggerWithContext(cfg.Logger),
+		rand:       r,
+		behaviours: make(map[types.OracleID]Behaviour),
+		chTransmit: make(chan Transmission, 1000),
+	}
+
+	var digest types.ConfigDigest
+	r.Read(digest[:])
+	var sharedSecret [config.SharedSecretSize]byte
+	r.Read(sharedSecret[:])
+
+	s := []int{1}
+	if cfg.N > 1 {
+		s = append(s, cfg.N-1)
+	}
+	sim.sharedConfig = config.SharedConfig{
+		PublicConfig: config.PublicConfig{
+			DeltaProgress: cfg.DeltaProgress,
+			DeltaResend:   cfg.DeltaResend,
+			DeltaRound:    cfg.DeltaRound,
+			DeltaGrace:    cfg.DeltaGrace,
+			DeltaC:        cfg.DeltaC,
+			AlphaPPB:      cfg.AlphaPPB,
+			DeltaStage:    cfg.DeltaStage,
+			RMax:          cfg.RMax,
+			S:             s,
+			F:             cfg.F,
+			ConfigDigest:  digest,
+		},
+		SharedSecret: &sharedSecret,
+	}
+
+	contractConfig := types.ContractConfig{ConfigDigest: digest, Threshold: uint8(cfg.F)}
+	for i := 0; i < cfg.N; i++ {
+		keys, err := newPrivateKeys(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not generate keys for oracle %d", i)
+		}
+		var transmitAddress common.Address
+		r.Read(transmitAddress[:])
+
+		sim.sharedConfig.OracleIdentities = append(sim.sharedConfig.OracleIdentities, config.OracleIdentity{
+			PeerID:                fmt.Sprintf("simulated-oracle-%d", i),
+			OffchainPublicKey:     keys.PublicKeyOffChain(),
+			OnChainSigningAddress: keys.PublicKeyAddressOnChain(),
+			TransmitAddress:       transmitAddress,
+		})
+		contractConfig.Signers = append(contractConfig.Signers, common.Address(keys.PublicKeyAddressOnChain()))
+		contractConfig.Transmitters = append(contractConfig.Transmitters, transmitAddress)
+		sim.oracles = append(sim.oracles, &simulatedOracle{
+			id:         types.OracleID(i),
+			clock:      &skewedClock{base: sim.Clock},
+			keys:       keys,
+			db:         NewInMemoryDatabase(),
+			dataSource: ConstantDataSource(int64(1000 + i)),
+		})
+	}
+
+	sim.Network = NewSimulatedNetwork(cfg.N, r.Int63(), cfg.Link, sim.Clock)
+	sim.Network.AddInterceptor(sim.intercept)
+	sim.Contract = newSimulatedContract(big.NewInt(1337), contractConfig, cfg.F)
+	sim.Contract.onTransmit(func(t Transmission) {
+		select {
+		case sim.chTransmit <- t:
+		default:
+		}
+	})
+	for i, o := range sim.oracles {
+		o.transmitter = &simulatedTransmitter{
+			contract: sim.Contract,
+			id:       o.id,
+			from:     contractConfig.Transmitters[i],
+			clock:    sim.Clock,
+		}
+	}
+	return sim, nil
+}
+
+// SharedConfig returns the protocol configuration shared by all oracles
+func (sim *Simulation) SharedConfig() config.SharedConfig {
+	return sim.sharedConfig
+}
+
+// ConfigTracker returns a types.ContractConfigTracker backed by the
+// simulated contract
+func (sim *Simulation) ConfigTracker() types.ContractConfigTracker {
+	return simulatedConfigTracker{sim.Contract}
+}
+
+// Database returns the database of oracle id, e.g. to inspect its persisted
+// pacemaker state
+func (sim *Simulation) Database(id types.OracleID) *InMemoryDatabase {
+	return sim.oracles[id].db
+}
+
+// SetDataSource replaces the data source of oracle id. It takes effect the
+// next time the oracle is (re)started.
+func (sim *Simulation) SetDataSource(id types.OracleID, ds types.DataSource) {
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+	sim.oracles[id].dataSource = ds
+}
+
+// SetBehaviour changes how the messages sent by oracle id are treated
+func (sim *Simulation) SetBehaviour(id types.OracleID, b Behaviour) {
+	sim.behaviourMu.Lock()
+	defer sim.behaviourMu.Unlock()
+	sim.behaviours[id] = b
+}
+
+// SetClockSkew shifts oracle id's clock relative to the simulated chain,
+// whose timestamps follow sim.Clock. A positive skew makes the oracle's
+// clock run ahead.
+func (sim *Simulation) SetClockSkew(id types.OracleID, skew time.Duration) {
+	sim.oracles[id].clock.setSkew(skew)
+}
+
+// LeaderSelected implements types.LeaderSelectionObserver for all oracles
+func (sim *Simulation) LeaderSelected(selection types.LeaderSelection) {
+	sim.selectionsMu.Lock()
+	defer sim.selectionsMu.Unlock()
+	sim.leaderSelections = append(sim.leaderSelections, selection)
+}
+
+// LeaderSelections returns every leader selection made by any oracle so far
+func (sim *Simulation) LeaderSelections() []types.LeaderSelection {
+	sim.selectionsMu.Lock()
+	defer sim.selectionsMu.Unlock()
+	return append([]types.LeaderSelection(nil), sim.leaderSelections...)
+}
+
+// Start starts all oracles
+func (sim *Simulation) Start() error {
+	for i := range sim.oracles {
+		if err := sim.StartOracle(types.OracleID(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StartOracle starts oracle id. Restarting a crashed oracle keeps its
+// database, so it resumes from its persisted state.
+func (sim *Simulation) StartOracle(id types.OracleID) error {
+	sim.mu.Lock()
+	defer sim.mu.Unlock()
+	o := sim.oracles[id]
+	if o.running {
+		return errors.Errorf("oracle %d is already running", id)
+	}
+
+	logger := sim.logger.MakeChild(types.LogFields{"oid": id})
+	binEndpoint, err := sim.Network.Endpoint(id)
+	if err != nil {
+		return err
+	}
+	chTelemetry := make(chan *protobuf.TelemetryWrapper, 100)
+	endpoint := shim.NewSerializingEndpoint(chTelemetry, sim.sharedConfig.ConfigDigest, binEndpoint, logger)
+	if err := endpoint.Start(); err != nil {
+		return errors.Wrapf(err, "could not start endpoint of oracle %d", id)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	o.running = true
+	o.cancel = cancel
+	o.endpoint = endpoint
+	o.done = make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-chTelemetry:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func(done chan struct{}, ds types.DataSource) {
+		defer close(done)
+		protocol.RunOracle(
+			ctx,
+			o.clock,
+			sim.sharedConfig,
+			noopConfigOverrider{},
+			o.transmitter,
+			o.db,
+			ds,
+			id,
+			o.keys,
+			sim.config.LocalConfig,
+			logger,
+			endpoint,
+			// SimulatedContract only decodes median reports
+			reportplugin.Median{},
+			shim.MakeTelemetrySender(chTelemetry, sim, nil, logger),
+		)
+	}(o.done, o.dataSource)
+	return nil
+}
+
+// Crash stops oracle id abruptly, as if its process was killed. Its
+// database survives.
+func (sim *Simulation) Crash(id types.OracleID) error {
+	sim.mu.Lock()
+	o := sim.oracles[id]
+	if !o.running {
+		sim.mu.Unlock()
+		return errors.Errorf("oracle %d is not running", id)
+	}
+	o.running = false
+	cancel, endpoint, done := o.cancel, o.endpoint, o.done
+	sim.mu.Unlock()
+
+	cancel()
+	<-done
+	return endpoint.Close()
+}
+
+// Stop stops all running oracles
+func (sim *Simulation) Stop() error {
+	var result error
+	for i, o := range sim.oracles {
+		sim.mu.Lock()
+		running := o.running
+		sim.mu.Unlock()
+		if !running {
+			continue
+		}
+		if err := sim.Crash(types.OracleID(i)); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+// WaitForTransmissions advances simulated time until the contract has
+// accepted at least n transmissions in total, or timeout of simulated time
+// has elapsed
+func (sim *Simulation) WaitForTransmissions(n int, timeout time.Duration) ([]Transmission, error) {
+	deadline := sim.Clock.Now().Add(timeout)
+	for {
+		sim.settle()
+		if ts := sim.Contract.Transmissions(); len(ts) >= n {
+			return ts, nil
+		}
+		next, ok := sim.Clock.NextDeadline()
+		if !ok || next.After(deadline) {
+			sim.Clock.AdvanceTo(deadline)
+			ts := sim.Contract.Transmissions()
+			return ts, errors.Errorf("timed out after %v of simulated time waiting for %d transmissions, got %d", timeout, n, len(ts))
+		}
+		sim.Clock.AdvanceTo(next)
+	}
+}
+
+// AdvanceTime runs the simulation for d of simulated time
+func (sim *Simulation) AdvanceTime(d time.Duration) {
+	deadline := sim.Clock.Now().Add(d)
+	for {
+		sim.settle()
+		next, ok := sim.Clock.NextDeadline()
+		if !ok || next.After(deadline) {
+			sim.Clock.AdvanceTo(deadline)
+			sim.settle()
+			return
+		}
+		sim.Clock.AdvanceTo(next)
+	}
+}
+
+type simulationActivity struct {
+	scheduled, fired uint64
+	network          NetworkStats
+}
+
+func (sim *Simulation) activity() simulationActivity {
+	scheduled, fired := sim.Clock.counters()
+	return simulationActivity{scheduled, fired, sim.Network.Stats()}
+}
+
+// settle blocks until the oracles have stopped reacting to the last clock
+// step, i.e. until they have been idle for settleRounds settleIntervals
+func (sim *Simulation) settle() {
+	last := sim.activity()
+	for quiet := 0; quiet < settleRounds; {
+		select {
+		case <-sim.chTransmit:
+			quiet = 0
+			continue
+		case <-time.After(settleInterval):
+		}
+		if a := sim.activity(); a != last {
+			last = a
+			quiet = 0
+		} else {
+			quiet++
+		}
+	}
+}
+
+// intercept applies the Behaviour configured for the sender. After
+// construction, sim.rand is only used here.
+func (sim *Simulation) intercept(from, to types.OracleID, payload []byte) ([]byte, bool) {
+	sim.behaviourMu.Lock()
+	defer sim.behaviourMu.Unlock()
+	switch sim.behaviours[from] {
+	case Silent:
+		return nil, false
+	case Corrupting:
+		if len(payload) > 0 {
+			i := sim.rand.Intn(len(payload))
+			payload[i] ^= 1 << uint(sim.rand.Intn(8))
+		}
+	}
+	return payload, true
+}
+
+type noopConfigOverrider struct{}
+
+func (noopConfigOverrider) ConfigOverride() *types.ConfigOverride { return nil }
+
+type discardLogger struct{}
+
+func (discardLogger) Trace(string, types.LogFields) {}
+func (discardLogger) Debug(string, types.LogFields) {}
+func (discardLogger) Info(string, types.LogFields)  {}
+func (discardLogger) Warn(string, types.LogFields)  {}
+func (discardLogger) Error(string, types.LogFields) {}
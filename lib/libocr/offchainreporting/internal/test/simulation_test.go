@@ -0,0 +1,71 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"PhoenixOracle/lib/libocr/offchainreporting/types"
+)
+
+func TestSimulation_ReportsWithOneSilentOracle(t *testing.T) {
+	cfg := DefaultSimulationConfig(4, 1)
+	cfg.Seed = 42
+	sim, err := NewSimulation(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.SetBehaviour(types.OracleID(3), Silent)
+	if err := sim.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := sim.Stop(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	ts, err := sim.WaitForTransmissions(3, 30*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(ts); i++ {
+		if ts[i].Epoch < ts[i-1].Epoch || (ts[i].Epoch == ts[i-1].Epoch && ts[i].Round <= ts[i-1].Round) {
+			t.Errorf("transmission %d (%d, %d) does not follow (%d, %d)",
+				i, ts[i].Epoch, ts[i].Round, ts[i-1].Epoch, ts[i-1].Round)
+		}
+	}
+}
+
+func TestSimulation_IsReproducibleForSeed(t *testing.T) {
+	run := func() []Transmission {
+		cfg := DefaultSimulationConfig(4, 1)
+		cfg.Seed = 7
+		sim, err := NewSimulation(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sim.SetClockSkew(types.OracleID(2), 300*time.Millisecond)
+		if err := sim.Start(); err != nil {
+			t.Fatal(err)
+		}
+		defer func() {
+			if err := sim.Stop(); err != nil {
+				t.Error(err)
+			}
+		}()
+		ts, err := sim.WaitForTransmissions(3, 30*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts[:3]
+	}
+
+	first, second := run(), run()
+	for i := range first {
+		a, b := first[i], second[i]
+		if a.Epoch != b.Epoch || a.Round != b.Round || !a.Timestamp.Equal(b.Timestamp) || a.Median.Cmp(b.Median) != 0 {
+			t.Errorf("transmission %d differs between runs: (%d, %d, %v, %v) vs (%d, %d, %v, %v)",
+				i, a.Epoch, a.Round, a.Timestamp, a.Median, b.Epoch, b.Round, b.Timestamp, b.Median)
+		}
+	}
+}
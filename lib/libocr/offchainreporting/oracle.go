@@ -5,6 +5,7 @@ import (
 
 	"PhoenixOracle/lib/libocr/offchainreporting/internal/managed"
 	"PhoenixOracle/lib/libocr/offchainreporting/loghelper"
+	"PhoenixOracle/lib/libocr/offchainreporting/reportplugin"
 	"PhoenixOracle/lib/libocr/offchainreporting/types"
 	"PhoenixOracle/lib/libocr/subprocesses"
 	"github.com/pkg/errors"
@@ -50,9 +51,20 @@ type OracleArgs struct {
 	// Used to send logs to a monitor. This may be nil.
 	MonitoringEndpoint types.MonitoringEndpoint
 
+	// Notified whenever the leader of an epoch is selected. This may be nil.
+	LeaderSelectionObserver types.LeaderSelectionObserver
+
+	// Notified whenever a new round is started. This may be nil.
+	RoundObserver types.RoundObserver
+
 	// PrivateKeys contains the secret keys needed for the OCR protocol, and methods
 	// which use those keys without exposing them to the rest of the application.
 	PrivateKeys types.PrivateKeys
+
+	// ReportPlugin determines how observations are encoded into the report
+	// sent to the contract. This may be nil, in which case reportplugin.Median
+	// is used. All oracles of a contract must use the same plugin.
+	ReportPlugin types.ReportPlugin
 }
 
 type Oracle struct {
@@ -74,6 +86,14 @@ func NewOracle(args OracleArgs) (*Oracle, error) {
 	if err := SanityCheckLocalConfig(args.LocalConfig); err != nil {
 		return nil, errors.Wrapf(err, "bad local config while creating new oracle")
 	}
+	if args.ReportPlugin == nil {
+		args.ReportPlugin = reportplugin.Median{}
+	}
+	if width := args.ReportPlugin.ObservationWidth(); width < 1 {
+		return nil, errors.Errorf("report plugin %s has invalid observation width %d", args.ReportPlugin.Name(), width)
+	} else if _, ok := args.Datasource.(types.MultiValueDataSource); width > 1 && !ok {
+		return nil, errors.Errorf("report plugin %s needs %d values per observation, but Datasource does not implement MultiValueDataSource", args.ReportPlugin.Name(), width)
+	}
 	return &Oracle{
 		oracleArgs: args,
 		started:    semaphore.NewWeighted(1),
@@ -100,11 +120,14 @@ func (o *Oracle) Start() error {
 			o.oracleArgs.ContractTransmitter,
 			o.oracleArgs.Database,
 			o.oracleArgs.Datasource,
+			o.oracleArgs.LeaderSelectionObserver,
 			o.oracleArgs.LocalConfig,
 			logger,
 			o.oracleArgs.MonitoringEndpoint,
 			o.oracleArgs.BinaryNetworkEndpointFactory,
 			o.oracleArgs.PrivateKeys,
+			o.oracleArgs.ReportPlugin,
+			o.oracleArgs.RoundObserver,
 		)
 	})
 	return nil
@@ -0,0 +1,113 @@
+// Package reportplugin contains the report encodings an oracle can be
+// configured with through OracleArgs.ReportPlugin.
+package reportplugin
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"PhoenixOracle/lib/libocr/gethwrappers/exposedmultivaluereport"
+	"PhoenixOracle/lib/libocr/offchainreporting/types"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/pkg/errors"
+)
+
+var (
+	medianReportTypes     = getMedianReportTypes()
+	multiValueReportTypes = getMultiValueReportTypes()
+)
+
+// Median is the default plugin. Every oracle observes a single value, and the
+// report contains the sorted observations as an int192[], as expected by
+// OffchainAggregator.
+type Median struct{}
+
+var _ types.ReportPlugin = Median{}
+
+func (Median) Name() string { return "median" }
+
+func (Median) ObservationWidth() int { return 1 }
+
+func (Median) EncodeReport(rawReportContext [32]byte, rawObservers [32]byte, observations [][]*big.Int) ([]byte, error) {
+	values := make([]*big.Int, 0, len(observations))
+	for i, o := range observations {
+		if len(o) != 1 {
+			return nil, errors.Errorf("observation #%d has %d values, expected 1", i, len(o))
+		}
+		values = append(values, o[0])
+	}
+	return medianReportTypes.Pack(rawReportContext, rawObservers, values)
+}
+
+// MultiValue reports Width values per oracle, e.g. several prices observed in
+// the same round. Observations are sorted by their first value, and the
+// report contains them as an int192[][] with one row per observer, as decoded
+// by ExposedMultiValueReport.
+type MultiValue struct {
+	Width int
+}
+
+var _ types.ReportPlugin = MultiValue{}
+
+func (MultiValue) Name() string { return "multi-value" }
+
+func (p MultiValue) ObservationWidth() int { return p.Width }
+
+func (p MultiValue) EncodeReport(rawReportContext [32]byte, rawObservers [32]byte, observations [][]*big.Int) ([]byte, error) {
+	if p.Width < 1 {
+		return nil, errors.Errorf("invalid width %d", p.Width)
+	}
+	for i, o := range observations {
+		if len(o) != p.Width {
+			return nil, errors.Errorf("observation #%d has %d values, expected %d", i, len(o), p.Width)
+		}
+	}
+	return multiValueReportTypes.Pack(rawReportContext, rawObservers, observations)
+}
+
+// MultiValueReport is a report encoded by the MultiValue plugin
+type MultiValueReport struct {
+	RawReportContext [32]byte
+	RawObservers     [32]byte
+	Observations     [][]*big.Int
+}
+
+// DecodeMultiValueReport is the inverse of MultiValue.EncodeReport
+func DecodeMultiValueReport(report []byte) (MultiValueReport, error) {
+	var rv MultiValueReport
+	values, err := multiValueReportTypes.Unpack(report)
+	if err != nil {
+		return rv, errors.Wrap(err, "could not unpack multi-value report")
+	}
+	if err := multiValueReportTypes.Copy(&rv, values); err != nil {
+		return rv, errors.Wrap(err, "could not copy multi-value report")
+	}
+	return rv, nil
+}
+
+func getMedianReportTypes() abi.Arguments {
+	mustNewType := func(t string) abi.Type {
+		result, err := abi.NewType(t, "", []abi.ArgumentMarshaling{})
+		if err != nil {
+			panic(fmt.Sprintf("Unexpected error during abi.NewType: %s", err))
+		}
+		return result
+	}
+	return abi.Arguments([]abi.Argument{
+		{Name: "rawReportContext", Type: mustNewType("bytes32")},
+		{Name: "rawObservers", Type: mustNewType("bytes32")},
+		{Name: "observations", Type: mustNewType("int192[]")},
+	})
+}
+
+// getMultiValueReportTypes takes the argument types from the contract ABI, so
+// that the encoding cannot drift from what the contract decodes
+func getMultiValueReportTypes() abi.Arguments {
+	contractABI, err := abi.JSON(strings.NewReader(exposedmultivaluereport.ExposedMultiValueReportABI))
+	if err != nil {
+		panic(fmt.Sprintf("Unexpected error while parsing ExposedMultiValueReport ABI: %s", err))
+	}
+	return contractABI.Methods["decodeReport"].Outputs
+}
@@ -0,0 +1,72 @@
+package reportplugin
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func testContext() (rawReportContext [32]byte, rawObservers [32]byte) {
+	for i := range rawReportContext {
+		rawReportContext[i] = byte(i)
+	}
+	rawObservers[0], rawObservers[1], rawObservers[2] = 2, 0, 1
+	return rawReportContext, rawObservers
+}
+
+func TestMedianEncodesSingleValueReport(t *testing.T) {
+	ctx, observers := testContext()
+	values := []*big.Int{big.NewInt(-5), big.NewInt(10), big.NewInt(42)}
+
+	report, err := Median{}.EncodeReport(ctx, observers, [][]*big.Int{{values[0]}, {values[1]}, {values[2]}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := medianReportTypes.Pack(ctx, observers, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(report, expected) {
+		t.Fatalf("median report differs from the int192[] encoding:\n%x\n%x", report, expected)
+	}
+
+	if _, err := (Median{}).EncodeReport(ctx, observers, [][]*big.Int{{values[0], values[1]}}); err == nil {
+		t.Fatal("expected error for observation with two values")
+	}
+}
+
+func TestMultiValueRoundTrip(t *testing.T) {
+	ctx, observers := testContext()
+	observations := [][]*big.Int{
+		{big.NewInt(1), big.NewInt(-100), big.NewInt(7)},
+		{big.NewInt(2), big.NewInt(200), big.NewInt(7)},
+		{big.NewInt(3), big.NewInt(0), new(big.Int).Lsh(big.NewInt(1), 190)},
+	}
+	plugin := MultiValue{Width: 3}
+
+	report, err := plugin.EncodeReport(ctx, observers, observations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeMultiValueReport(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.RawReportContext != ctx || decoded.RawObservers != observers {
+		t.Fatalf("context or observers did not round trip: %+v", decoded)
+	}
+	if len(decoded.Observations) != len(observations) {
+		t.Fatalf("expected %d observations, got %d", len(observations), len(decoded.Observations))
+	}
+	for i := range observations {
+		for j := range observations[i] {
+			if decoded.Observations[i][j].Cmp(observations[i][j]) != 0 {
+				t.Fatalf("observation [%d][%d]: expected %v, got %v", i, j, observations[i][j], decoded.Observations[i][j])
+			}
+		}
+	}
+
+	if _, err := plugin.EncodeReport(ctx, observers, [][]*big.Int{{big.NewInt(1)}}); err == nil {
+		t.Fatal("expected error for observation of the wrong width")
+	}
+}
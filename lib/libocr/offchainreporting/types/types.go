@@ -164,6 +164,34 @@ type DataSource interface {
 	Observe(context.Context) (Observation, error)
 }
 
+// MultiValueDataSource is a DataSource whose observations hold several
+// int192 values. Oracles whose ReportPlugin has an ObservationWidth greater
+// than one call ObserveValues instead of Observe, which must return exactly
+// ObservationWidth values. The same timing constraints as for Observe apply.
+type MultiValueDataSource interface {
+	DataSource
+	ObserveValues(context.Context) ([]*big.Int, error)
+}
+
+// ReportPlugin determines the shape of observations and how the observations
+// of a round are encoded into the report that oracles sign and transmit.
+// All oracles of a protocol instance must use the same plugin, and the
+// contract receiving the reports must decode them accordingly.
+//
+// Regardless of the plugin, the protocol orders observations by their first
+// value and checks deviation of the median first value against the latest
+// answer of the contract.
+type ReportPlugin interface {
+	// Name identifies the plugin in logs
+	Name() string
+	// ObservationWidth is the number of int192 values in each observation
+	ObservationWidth() int
+	// EncodeReport serializes the observations of a round. observations[i]
+	// was made by the oracle in byte i of rawObservers, and observations are
+	// sorted by their first value.
+	EncodeReport(rawReportContext [32]byte, rawObservers [32]byte, observations [][]*big.Int) ([]byte, error)
+}
+
 // MonitoringEndpoint is where the OCR protocol sends monitoring output
 //
 // All its functions should be thread-safe.
@@ -188,6 +216,67 @@ type ConfigOverrider interface {
 	ConfigOverride() *ConfigOverride
 }
 
+// LeaderSelectionReason explains how the leader of an epoch was chosen
+type LeaderSelectionReason string
+
+const (
+	// The leader chosen by the leader selection function is in the active
+	// oracle set reported by the contract
+	LeaderSelectionActive LeaderSelectionReason = "active"
+	// The leader chosen by the leader selection function is not in the
+	// active oracle set and was replaced by an active oracle
+	LeaderSelectionOverride LeaderSelectionReason = "override"
+	// The active oracle set could not be fetched in time, so the leader
+	// chosen by the leader selection function is used
+	LeaderSelectionFallbackTimeout LeaderSelectionReason = "fallback_timeout"
+	// Fetching the active oracle set failed, so the leader chosen by the
+	// leader selection function is used
+	LeaderSelectionFallbackError LeaderSelectionReason = "fallback_error"
+	// The contract reported no usable active oracle set, so the leader
+	// chosen by the leader selection function is used
+	LeaderSelectionFallbackEmpty LeaderSelectionReason = "fallback_empty"
+)
+
+// IsFallback returns true if the active oracle set was not available when
+// the leader was selected
+func (r LeaderSelectionReason) IsFallback() bool {
+	switch r {
+	case LeaderSelectionFallbackTimeout, LeaderSelectionFallbackError, LeaderSelectionFallbackEmpty:
+		return true
+	}
+	return false
+}
+
+// LeaderSelection records the choice of leader for an epoch
+type LeaderSelection struct {
+	ConfigDigest ConfigDigest
+	Epoch        uint32
+	// DefaultLeader is the leader chosen by the leader selection function
+	DefaultLeader OracleID
+	// Leader is the oracle that actually leads the epoch
+	Leader OracleID
+	// ActiveIndexes is the active oracle set reported by the contract, if
+	// it was available
+	ActiveIndexes []int
+	Reason        LeaderSelectionReason
+	Time          time.Time
+}
+
+// LeaderSelectionObserver is notified every time an oracle selects the
+// leader of an epoch.
+//
+// All its functions should be thread-safe and return quickly.
+type LeaderSelectionObserver interface {
+	LeaderSelected(LeaderSelection)
+}
+
+// RoundObserver is notified every time a follower starts a new round.
+//
+// All its functions should be thread-safe and return quickly.
+type RoundObserver interface {
+	RoundStarted(configDigest ConfigDigest, epoch uint32, round uint8, leader OracleID)
+}
+
 // ContractTransmitter sends new reports to the OffchainAggregator smart contract.
 //
 // All its functions should be thread-safe.
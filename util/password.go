@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+
+	"go.uber.org/multierr"
+)
+
+var (
+	lowercaseRegexp = regexp.MustCompile("[a-z]")
+	uppercaseRegexp = regexp.MustCompile("[A-Z]")
+	numbersRegexp   = regexp.MustCompile("[0-9]")
+	symbolsRegexp   = regexp.MustCompile(`[!@#$%^&*()-=_+\[\]\\|;:'",<.>/?~` + "`]")
+)
+
+// VerifyPasswordComplexity checks that a keystore password is strong enough
+func VerifyPasswordComplexity(password string) error {
+	// Must be longer than 12 characters
+	// Must comprise at least 3 of:
+	//     lowercase characters
+	//     uppercase characters
+	//     numbers
+	//     symbols
+
+	var merr error
+	if len(password) <= 12 {
+		merr = multierr.Append(merr, fmt.Errorf("must be longer than 12 characters"))
+	}
+	if len(lowercaseRegexp.FindAllString(password, -1)) < 3 {
+		merr = multierr.Append(merr, fmt.Errorf("must contain at least 3 lowercase characters"))
+	}
+	if len(uppercaseRegexp.FindAllString(password, -1)) < 3 {
+		merr = multierr.Append(merr, fmt.Errorf("must contain at least 3 uppercase characters"))
+	}
+	if len(numbersRegexp.FindAllString(password, -1)) < 3 {
+		merr = multierr.Append(merr, fmt.Errorf("must contain at least 3 numbers"))
+	}
+	if len(symbolsRegexp.FindAllString(password, -1)) < 3 {
+		merr = multierr.Append(merr, fmt.Errorf("must contain at least 3 symbols"))
+	}
+	var c byte
+	var instances int
+	for i := 0; i < len(password); i++ {
+		if password[i] == c {
+			instances++
+		} else {
+			instances = 1
+		}
+		if instances > 3 {
+			merr = multierr.Append(merr, fmt.Errorf("must not contain more than 3 identical consecutive characters"))
+			break
+		}
+		c = password[i]
+	}
+
+	if merr != nil {
+		merr = fmt.Errorf("password does not meet the requirements.\n%+v", merr)
+	}
+	return merr
+}
@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"PhoenixOracle/core/service/alerting"
+	"PhoenixOracle/core/service/phoenix"
+	"PhoenixOracle/db/models"
+	"PhoenixOracle/web"
+	"PhoenixOracle/web/presenters"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"gopkg.in/guregu/null.v4"
+	"gorm.io/datatypes"
+)
+
+// AlertsController manages alert notifiers and the rules that fire them.
+type AlertsController struct {
+	App phoenix.Application
+}
+
+type CreateAlertNotifierRequest struct {
+	Name   string                `json:"name"`
+	Type   alerting.NotifierType `json:"type"`
+	Config json.RawMessage       `json:"config"`
+}
+
+func (ac *AlertsController) IndexNotifiers(c *gin.Context) {
+	ns, err := ac.App.GetAlertingService().ORM().ListNotifiers(c.Request.Context())
+	if err != nil {
+		web.JsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	web.JsonAPIResponse(c, presenters.NewAlertNotifierResources(ns), "alert_notifiers")
+}
+
+func (ac *AlertsController) CreateNotifier(c *gin.Context) {
+	request := CreateAlertNotifierRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	n := alerting.Notifier{
+		Name:   request.Name,
+		Type:   request.Type,
+		Config: datatypes.JSON(request.Config),
+	}
+	if err := ac.App.GetAlertingService().ORM().CreateNotifier(c.Request.Context(), &n); err != nil {
+		web.JsonAPIError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	web.JsonAPIResponseWithStatus(c, presenters.NewAlertNotifierResource(n), "alert_notifiers", http.StatusCreated)
+}
+
+func (ac *AlertsController) DeleteNotifier(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("ID"), 10, 64)
+	if err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	err = ac.App.GetAlertingService().ORM().DeleteNotifier(c.Request.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		web.JsonAPIError(c, http.StatusNotFound, errors.New("notifier not found"))
+		return
+	} else if err != nil {
+		web.JsonAPIError(c, http.StatusConflict, err)
+		return
+	}
+
+	web.JsonAPIResponseWithStatus(c, nil, "alert_notifiers", http.StatusNoContent)
+}
+
+// TestNotifier sends a synthetic alert through the notifier so delivery can be
+// verified without waiting for a rule to fire.
+func (ac *AlertsController) TestNotifier(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("ID"), 10, 64)
+	if err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	err = ac.App.GetAlertingService().TestNotifier(c.Request.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		web.JsonAPIError(c, http.StatusNotFound, errors.New("notifier not found"))
+		return
+	} else if err != nil {
+		web.JsonAPIError(c, http.StatusBadGateway, err)
+		return
+	}
+
+	web.JsonAPIResponseWithStatus(c, nil, "alert_notifiers", http.StatusNoContent)
+}
+
+type CreateAlertRuleRequest struct {
+	Name        string            `json:"name"`
+	Type        alerting.RuleType `json:"type"`
+	Threshold   decimal.Decimal   `json:"threshold"`
+	Window      models.Interval   `json:"window"`
+	Cooldown    models.Interval   `json:"cooldown"`
+	JobID       null.Int32        `json:"jobID"`
+	NotifierIDs []int64           `json:"notifierIDs"`
+	Disabled    bool              `json:"disabled"`
+}
+
+func (ac *AlertsController) IndexRules(c *gin.Context) {
+	rs, err := ac.App.GetAlertingService().ORM().ListRules(c.Request.Context())
+	if err != nil {
+		web.JsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	web.JsonAPIResponse(c, presenters.NewAlertRuleResources(rs), "alert_rules")
+}
+
+func (ac *AlertsController) CreateRule(c *gin.Context) {
+	request := CreateAlertRuleRequest{}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	r := alerting.Rule{
+		Name:        request.Name,
+		Type:        request.Type,
+		Threshold:   request.Threshold,
+		Window:      request.Window,
+		Cooldown:    request.Cooldown,
+		JobID:       request.JobID,
+		NotifierIDs: request.NotifierIDs,
+		Enabled:     !request.Disabled,
+	}
+	if err := ac.App.GetAlertingService().ORM().CreateRule(c.Request.Context(), &r); err != nil {
+		web.JsonAPIError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	web.JsonAPIResponseWithStatus(c, presenters.NewAlertRuleResource(r), "alert_rules", http.StatusCreated)
+}
+
+func (ac *AlertsController) DeleteRule(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("ID"), 10, 64)
+	if err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	err = ac.App.GetAlertingService().ORM().DeleteRule(c.Request.Context(), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		web.JsonAPIError(c, http.StatusNotFound, errors.New("rule not found"))
+		return
+	} else if err != nil {
+		web.JsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	web.JsonAPIResponseWithStatus(c, nil, "alert_rules", http.StatusNoContent)
+}
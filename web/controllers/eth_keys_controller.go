@@ -88,6 +88,49 @@ func (ekc *ETHKeysController) Create(c *gin.Context) {
 	web.JsonAPIResponseWithStatus(c, r, "account", http.StatusCreated)
 }
 
+// AddExternalETHKeyRequest registers a key held by an external signer
+type AddExternalETHKeyRequest struct {
+	Address   common.Address `json:"address"`
+	Signer    string         `json:"signer"`
+	KeyRef    string         `json:"keyRef"`
+	IsFunding bool           `json:"isFunding"`
+}
+
+// AddExternal registers an ETH key whose private key is held by an external
+// signer (Clef or PKCS#11)
+func (ekc *ETHKeysController) AddExternal(c *gin.Context) {
+	ethKeyStore := ekc.App.GetKeyStore().Eth()
+	var request AddExternalETHKeyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if request.Address == (common.Address{}) {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, errors.New("address is required"))
+		return
+	}
+	key, err := ethKeyStore.AddExternal(request.Address, request.Signer, request.KeyRef, request.IsFunding)
+	if err != nil {
+		web.JsonAPIError(c, http.StatusBadRequest, err)
+		return
+	}
+	state, err := ethKeyStore.GetState(key.ID())
+	if err != nil {
+		web.JsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	r, err := presenters.NewETHKeyResource(key, state,
+		ekc.setEthBalance(c.Request.Context(), key.Address.Address()),
+		ekc.setPhbBalance(key.Address.Address()),
+	)
+	if err != nil {
+		web.JsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	web.JsonAPIResponseWithStatus(c, r, "account", http.StatusCreated)
+}
+
 func (ekc *ETHKeysController) Delete(c *gin.Context) {
 	ethKeyStore := ekc.App.GetKeyStore().Eth()
 	var hardDelete bool
@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	"fmt"
 	"net/http"
 
+	"PhoenixOracle/core/service/ha"
 	"PhoenixOracle/core/service/phoenix"
 	"PhoenixOracle/lib/health"
 	"PhoenixOracle/web"
@@ -85,5 +87,23 @@ func (hc *HealthController) Health(c *gin.Context) {
 		})
 	}
 
+	if lease := hc.App.GetLease(); lease != nil {
+		checks = append(checks, haCheck(lease.Status()))
+	}
+
 	web.JsonAPIResponse(c, checks, "checks")
 }
+
+// haCheck reports whether this node is the HA leader or a standby
+func haCheck(status ha.Status) presenters.Check {
+	output := "standby"
+	if status.Leader {
+		output = fmt.Sprintf("leader (fencing token %d)", status.FencingToken)
+	}
+	return presenters.Check{
+		JAID:   presenters.NewJAID("HA"),
+		Name:   "HA",
+		Status: health.StatusPassing,
+		Output: output,
+	}
+}
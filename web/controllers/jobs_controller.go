@@ -5,6 +5,8 @@ import (
 	"net/http"
 
 	"PhoenixOracle/core/service/job"
+	"PhoenixOracle/core/service/jobs/blocktrigger"
+	"PhoenixOracle/core/service/jobs/eventtrigger"
 	"PhoenixOracle/core/service/jobs/fluxmonitor"
 	"PhoenixOracle/core/service/jobs/offchainreporting"
 	requestPackage "PhoenixOracle/core/service/jobs/request"
@@ -99,6 +101,10 @@ func (jc *JobsController) Create(c *gin.Context) {
 		jb, err = vrf.ValidatedVRFSpec(request.TOML)
 	case job.Webhook:
 		jb, err = webhook.ValidatedWebhookSpec(request.TOML, jc.App.GetExternalInitiatorManager())
+	case job.EventTrigger:
+		jb, err = eventtrigger.ValidatedEventTriggerSpec(request.TOML)
+	case job.BlockTrigger:
+		jb, err = blocktrigger.ValidatedBlockTriggerSpec(jc.App.GetEVMConfig(), request.TOML)
 	default:
 		web.JsonAPIError(c, http.StatusUnprocessableEntity, errors.Errorf("unknown job type: %s", jobType))
 	}
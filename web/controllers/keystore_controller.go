@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"net/http"
+
+	"PhoenixOracle/core/keystore"
+	"PhoenixOracle/core/service/phoenix"
+	"PhoenixOracle/util"
+	"PhoenixOracle/web"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// KeystoreController manages the master keystore
+type KeystoreController struct {
+	App phoenix.Application
+}
+
+// RotateKeystorePasswordRequest changes the password protecting all keys.
+// ScryptN and ScryptP are optional; when both are zero the current scrypt
+// params are kept.
+type RotateKeystorePasswordRequest struct {
+	OldPassword string `json:"oldPassword"`
+	NewPassword string `json:"newPassword"`
+	ScryptN     int    `json:"scryptN,omitempty"`
+	ScryptP     int    `json:"scryptP,omitempty"`
+}
+
+// RotatePassword re-encrypts every key in the keystore under a new password
+func (kc *KeystoreController) RotatePassword(c *gin.Context) {
+	var request RotateKeystorePasswordRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if request.NewPassword == "" {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, errors.New("newPassword is required"))
+		return
+	}
+	if err := utils.VerifyPasswordComplexity(request.NewPassword); err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	var scryptParams *utils.ScryptParams
+	if request.ScryptN != 0 || request.ScryptP != 0 {
+		if request.ScryptN <= 1 || request.ScryptN&(request.ScryptN-1) != 0 {
+			web.JsonAPIError(c, http.StatusUnprocessableEntity, errors.New("scryptN must be a power of 2 greater than 1"))
+			return
+		}
+		if request.ScryptP < 1 {
+			web.JsonAPIError(c, http.StatusUnprocessableEntity, errors.New("scryptP must be at least 1"))
+			return
+		}
+		scryptParams = &utils.ScryptParams{N: request.ScryptN, P: request.ScryptP}
+	}
+
+	err := kc.App.GetKeyStore().RotatePassword(request.OldPassword, request.NewPassword, scryptParams)
+	if errors.Is(err, keystore.ErrWrongPassword) {
+		web.JsonAPIError(c, http.StatusConflict, err)
+		return
+	} else if errors.Is(err, keystore.ErrWeakScryptParams) {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	} else if err != nil {
+		web.JsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	web.JsonAPIResponseWithStatus(c, nil, "keystore", http.StatusNoContent)
+}
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"net/http"
+
+	"PhoenixOracle/core/service/jobs/offchainreporting"
+	"PhoenixOracle/core/service/phoenix"
+	"PhoenixOracle/core/service/txmanager"
+	"PhoenixOracle/web"
+	"PhoenixOracle/web/presenters"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// OCRConfigController generates configs for OffchainAggregator contracts
+type OCRConfigController struct {
+	App phoenix.Application
+}
+
+// Create generates a setConfig call and optionally submits it
+// Example:
+// "POST <application>/ocr/set_config"
+func (occ *OCRConfigController) Create(c *gin.Context) {
+	var request offchainreporting.SetConfigRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	args, err := offchainreporting.BuildSetConfig(occ.App.GetEVMConfig().ChainID(), request)
+	if err != nil {
+		web.JsonAPIError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	contractAddress := request.ContractAddress.Address()
+	if !request.Send {
+		web.JsonAPIResponse(c, presenters.NewOCRSetConfigResource(contractAddress, args, nil), "ocr_set_config")
+		return
+	}
+
+	if contractAddress == (common.Address{}) {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, errors.New("contractAddress is required to send the transaction"))
+		return
+	}
+	if request.FromAddress == nil {
+		web.JsonAPIError(c, http.StatusBadRequest, errors.New("fromAddress is required to send the transaction"))
+		return
+	}
+	if _, err = occ.App.GetKeyStore().Eth().Get(request.FromAddress.Hex()); err != nil {
+		web.JsonAPIError(c, http.StatusBadRequest, errors.Wrapf(err, "no key for from address %s", request.FromAddress))
+		return
+	}
+	gasLimit := request.GasLimit
+	if gasLimit == 0 {
+		gasLimit = occ.App.GetEVMConfig().EvmGasLimitDefault()
+	}
+	etx, err := occ.App.GetTxManager().CreateEthTransaction(occ.App.GetStore().DB.WithContext(c.Request.Context()), txmanager.NewTx{
+		FromAddress:    request.FromAddress.Address(),
+		ToAddress:      contractAddress,
+		EncodedPayload: args.Calldata,
+		GasLimit:       gasLimit,
+		Strategy:       txmanager.SendEveryStrategy{},
+	})
+	if err != nil {
+		web.JsonAPIError(c, http.StatusInternalServerError, errors.Wrap(err, "failed to create setConfig transaction"))
+		return
+	}
+
+	web.JsonAPIResponseWithStatus(c, presenters.NewOCRSetConfigResource(contractAddress, args, &etx.ID), "ocr_set_config", http.StatusCreated)
+}
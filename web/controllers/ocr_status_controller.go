@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"net/http"
+
+	"PhoenixOracle/core/service/job"
+	"PhoenixOracle/core/service/phoenix"
+	"PhoenixOracle/db/orm"
+	"PhoenixOracle/web"
+	"PhoenixOracle/web/presenters"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// OCRStatusController reports the live state of OCR jobs
+type OCRStatusController struct {
+	App phoenix.Application
+}
+
+// Show returns the status of the OCR oracle for a job
+// Example:
+// "GET <application>/jobs/:ID/ocr/status"
+func (oc *OCRStatusController) Show(c *gin.Context) {
+	jb := job.Job{}
+	if err := jb.SetID(c.Param("ID")); err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	jb, err := oc.App.JobORM().FindJobTx(jb.ID)
+	if errors.Cause(err) == orm.ErrorNotFound {
+		web.JsonAPIError(c, http.StatusNotFound, errors.New("job not found"))
+		return
+	} else if err != nil {
+		web.JsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if jb.OffchainreportingOracleSpec == nil {
+		web.JsonAPIError(c, http.StatusBadRequest, errors.New("job is not an OCR job"))
+		return
+	}
+
+	status, err := oc.App.GetOCRStatusRegistry().Status(c.Request.Context(), jb)
+	if err != nil {
+		web.JsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	web.JsonAPIResponse(c, presenters.NewOCRStatusResource(status), "ocr_status")
+}
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"PhoenixOracle/core/service/job"
+	"PhoenixOracle/core/service/jobs/offchainreporting"
+	"PhoenixOracle/core/service/phoenix"
+	"PhoenixOracle/web"
+	"PhoenixOracle/web/presenters"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// defaultTransmissionCostsWindow is the window reported on when no "from"
+// parameter is given
+const defaultTransmissionCostsWindow = 30 * 24 * time.Hour
+
+// OCRTransmissionCostsController reports what OCR transmissions cost
+type OCRTransmissionCostsController struct {
+	App phoenix.Application
+}
+
+// Index returns the gas used and ETH spent on OCR transmissions per job and
+// period. All parameters are optional: "jobID" restricts the report to one
+// job, "from" and "to" are RFC3339 timestamps and "period" is "day" or
+// "month".
+// Example:
+// "GET <application>/ocr/transmission_costs?jobID=1&from=2021-09-01T00:00:00Z&period=month"
+func (tcc *OCRTransmissionCostsController) Index(c *gin.Context) {
+	query := offchainreporting.TransmissionCostsQuery{To: time.Now()}
+
+	var err error
+	if query.Period, err = offchainreporting.ParseCostPeriod(c.Query("period")); err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if id := c.Query("jobID"); id != "" {
+		jb := job.Job{}
+		if err = jb.SetID(id); err != nil {
+			web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+			return
+		}
+		query.JobID = jb.ID
+	}
+	if to := c.Query("to"); to != "" {
+		if query.To, err = time.Parse(time.RFC3339, to); err != nil {
+			web.JsonAPIError(c, http.StatusUnprocessableEntity, errors.Wrap(err, "invalid to"))
+			return
+		}
+	}
+	query.From = query.To.Add(-defaultTransmissionCostsWindow)
+	if from := c.Query("from"); from != "" {
+		if query.From, err = time.Parse(time.RFC3339, from); err != nil {
+			web.JsonAPIError(c, http.StatusUnprocessableEntity, errors.Wrap(err, "invalid from"))
+			return
+		}
+	}
+	if !query.From.Before(query.To) {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, errors.New("from must be before to"))
+		return
+	}
+
+	costs, err := offchainreporting.TransmissionCosts(tcc.App.GetStore().DB.WithContext(c.Request.Context()), query)
+	if err != nil {
+		web.JsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	web.JsonAPIResponse(c, presenters.NewOCRTransmissionCostResources(costs), "ocr_transmission_costs")
+}
@@ -0,0 +1,217 @@
+package controllers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"PhoenixOracle/core/assets"
+	"PhoenixOracle/core/keystore/keys/ethkey"
+	"PhoenixOracle/core/service/job"
+	"PhoenixOracle/core/service/jobs/request"
+	"PhoenixOracle/core/service/phoenix"
+	"PhoenixOracle/core/service/txmanager"
+	"PhoenixOracle/db/models"
+	"PhoenixOracle/util"
+	"PhoenixOracle/web"
+	"PhoenixOracle/web/presenters"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// defaultEarningsWindow is the window reported on when no "from" parameter
+// is given
+const defaultEarningsWindow = 30 * 24 * time.Hour
+
+// OperatorController reports what direct request jobs earned and withdraws
+// PHB from Operator contracts
+type OperatorController struct {
+	App phoenix.Application
+}
+
+// Earnings returns the requests fulfilled and PHB earned per job or
+// requester and period. All parameters are optional: "jobID" and
+// "requester" restrict the report, "groupBy" is "job" or "requester",
+// "from" and "to" are RFC3339 timestamps and "period" is "day" or "month".
+// Example:
+// "GET <application>/operator/earnings?groupBy=requester&period=month"
+func (oc *OperatorController) Earnings(c *gin.Context) {
+	query := request.EarningsQuery{To: time.Now()}
+
+	var err error
+	if query.GroupBy, err = request.ParseEarningsGroupBy(c.Query("groupBy")); err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if query.Period, err = request.ParseEarningsPeriod(c.Query("period")); err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if id := c.Query("jobID"); id != "" {
+		jb := job.Job{}
+		if err = jb.SetID(id); err != nil {
+			web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+			return
+		}
+		query.JobID = jb.ID
+	}
+	if requester := c.Query("requester"); requester != "" {
+		addr, err := utils.ParseEthereumAddress(requester)
+		if err != nil {
+			web.JsonAPIError(c, http.StatusUnprocessableEntity, errors.Wrap(err, "invalid requester"))
+			return
+		}
+		query.Requester = &addr
+	}
+	if to := c.Query("to"); to != "" {
+		if query.To, err = time.Parse(time.RFC3339, to); err != nil {
+			web.JsonAPIError(c, http.StatusUnprocessableEntity, errors.Wrap(err, "invalid to"))
+			return
+		}
+	}
+	query.From = query.To.Add(-defaultEarningsWindow)
+	if from := c.Query("from"); from != "" {
+		if query.From, err = time.Parse(time.RFC3339, from); err != nil {
+			web.JsonAPIError(c, http.StatusUnprocessableEntity, errors.Wrap(err, "invalid from"))
+			return
+		}
+	}
+	if !query.From.Before(query.To) {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, errors.New("from must be before to"))
+		return
+	}
+
+	earnings, err := request.Earnings(oc.App.GetStore().DB.WithContext(c.Request.Context()), query)
+	if err != nil {
+		web.JsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	web.JsonAPIResponse(c, presenters.NewDirectRequestEarningResources(earnings), "direct_request_earnings")
+}
+
+// Withdraw submits a withdraw call to an Operator contract through the
+// txmanager, after checking the amount against withdrawable()
+// Example:
+// "POST <application>/operator/withdrawals"
+func (oc *OperatorController) Withdraw(c *gin.Context) {
+	var req request.WithdrawRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	args, err := request.BuildWithdraw(c.Request.Context(), oc.App.GetEthClient(), req)
+	if err != nil {
+		web.JsonAPIError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// Only the owner may withdraw, so the transaction is sent from the owner
+	// key unless another key is given, in which case it would revert
+	from := args.Owner
+	if req.FromAddress != nil && req.FromAddress.Address() != args.Owner {
+		web.JsonAPIError(c, http.StatusBadRequest, errors.Errorf("from address %s is not the operator owner %s", req.FromAddress, args.Owner.Hex()))
+		return
+	}
+	if _, err = oc.App.GetKeyStore().Eth().Get(from.Hex()); err != nil {
+		web.JsonAPIError(c, http.StatusBadRequest, errors.Wrapf(err, "no key for operator owner %s", from.Hex()))
+		return
+	}
+	gasLimit := req.GasLimit
+	if gasLimit == 0 {
+		gasLimit = oc.App.GetEVMConfig().EvmGasLimitDefault()
+	}
+	etx, err := oc.App.GetTxManager().CreateEthTransaction(oc.App.GetStore().DB.WithContext(c.Request.Context()), txmanager.NewTx{
+		FromAddress:    from,
+		ToAddress:      req.ContractAddress.Address(),
+		EncodedPayload: args.Calldata,
+		GasLimit:       gasLimit,
+		Strategy:       txmanager.SendEveryStrategy{},
+	})
+	if err != nil {
+		web.JsonAPIError(c, http.StatusInternalServerError, errors.Wrap(err, "failed to create withdraw transaction"))
+		return
+	}
+
+	web.JsonAPIResponseWithStatus(c, presenters.NewOperatorWithdrawalResource(req, from, args, etx.ID), "operator_withdrawals", http.StatusCreated)
+}
+
+type UpsertRequesterPolicyRequest struct {
+	Requester          *ethkey.EIP55Address `json:"requester"`
+	Denied             bool                 `json:"denied"`
+	MinContractPayment *assets.Phb          `json:"minContractPayment"`
+	MinPaymentPerGwei  *assets.Phb          `json:"minPaymentPerGwei"`
+	MaxRequests        uint32               `json:"maxRequests"`
+	RateLimitPeriod    models.Interval      `json:"rateLimitPeriod"`
+	NoRateLimit        bool                 `json:"noRateLimit"`
+}
+
+// RequesterPolicies lists the node's requester policies for direct request
+// jobs, the default policy first
+// Example:
+// "GET <application>/operator/requester_policies"
+func (oc *OperatorController) RequesterPolicies(c *gin.Context) {
+	policies, err := request.RequesterPolicies(oc.App.GetStore().DB.WithContext(c.Request.Context()))
+	if err != nil {
+		web.JsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	web.JsonAPIResponse(c, presenters.NewRequesterPolicyResources(policies), "requester_policies")
+}
+
+// UpsertRequesterPolicy sets the policy of a requester, or the default policy
+// if no requester is given, replacing any existing one
+// Example:
+// "POST <application>/operator/requester_policies"
+func (oc *OperatorController) UpsertRequesterPolicy(c *gin.Context) {
+	var req UpsertRequesterPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	policy := request.RequesterPolicy{
+		Requester:          req.Requester,
+		Denied:             req.Denied,
+		MinContractPayment: req.MinContractPayment,
+		MinPaymentPerGwei:  req.MinPaymentPerGwei,
+		MaxRequests:        req.MaxRequests,
+		RateLimitPeriod:    req.RateLimitPeriod,
+		NoRateLimit:        req.NoRateLimit,
+	}
+	if err := request.ValidateRequesterPolicy(policy); err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if err := request.UpsertRequesterPolicy(oc.App.GetStore().DB.WithContext(c.Request.Context()), &policy); err != nil {
+		web.JsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	web.JsonAPIResponse(c, presenters.NewRequesterPolicyResource(policy), "requester_policies")
+}
+
+// DeleteRequesterPolicy deletes a requester policy by ID
+// Example:
+// "DELETE <application>/operator/requester_policies/:ID"
+func (oc *OperatorController) DeleteRequesterPolicy(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("ID"), 10, 64)
+	if err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	err = request.DeleteRequesterPolicy(oc.App.GetStore().DB.WithContext(c.Request.Context()), id)
+	if errors.Is(err, sql.ErrNoRows) {
+		web.JsonAPIError(c, http.StatusNotFound, errors.New("requester policy not found"))
+		return
+	} else if err != nil {
+		web.JsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	web.JsonAPIResponseWithStatus(c, nil, "requester_policies", http.StatusNoContent)
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"net/http"
+
+	"PhoenixOracle/core/service/jobs/offchainreporting"
+	"PhoenixOracle/core/service/phoenix"
+	"PhoenixOracle/web"
+	"PhoenixOracle/web/presenters"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// P2PBootstrapPeerListsController manages the shared bootstrap peer lists
+// that OCR jobs can reference by name
+type P2PBootstrapPeerListsController struct {
+	App phoenix.Application
+}
+
+// P2PBootstrapPeerListRequest creates or replaces a shared bootstrap peer
+// list
+type P2PBootstrapPeerListRequest struct {
+	Name  string   `json:"name"`
+	Peers []string `json:"peers"`
+}
+
+// Index lists the shared bootstrap peer lists
+// Example:
+// "GET <application>/p2p/bootstrap_peer_lists"
+func (bc *P2PBootstrapPeerListsController) Index(c *gin.Context) {
+	lists, err := offchainreporting.BootstrapPeerLists(bc.App.GetStore().DB)
+	if err != nil {
+		web.JsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	web.JsonAPIResponse(c, presenters.NewP2PBootstrapPeerListResources(lists), "p2p_bootstrap_peer_lists")
+}
+
+// Create creates a shared bootstrap peer list, or replaces the peers of an
+// existing one. Running jobs referencing the list pick up the new peers
+// without being restarted.
+// Example:
+// "POST <application>/p2p/bootstrap_peer_lists"
+func (bc *P2PBootstrapPeerListsController) Create(c *gin.Context) {
+	var request P2PBootstrapPeerListRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	list, err := offchainreporting.UpsertBootstrapPeerList(bc.App.GetStore().DB, request.Name, request.Peers)
+	if err != nil {
+		web.JsonAPIError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	web.JsonAPIResponse(c, presenters.NewP2PBootstrapPeerListResource(list), "p2p_bootstrap_peer_lists")
+}
+
+// Delete deletes a shared bootstrap peer list that no job references
+// Example:
+// "DELETE <application>/p2p/bootstrap_peer_lists/:name"
+func (bc *P2PBootstrapPeerListsController) Delete(c *gin.Context) {
+	err := offchainreporting.DeleteBootstrapPeerList(bc.App.GetStore().DB, c.Param("name"))
+	switch errors.Cause(err) {
+	case nil:
+		web.JsonAPIResponseWithStatus(c, nil, "p2p_bootstrap_peer_lists", http.StatusNoContent)
+	case offchainreporting.ErrBootstrapPeerListNotFound:
+		web.JsonAPIError(c, http.StatusNotFound, err)
+	case offchainreporting.ErrBootstrapPeerListInUse:
+		web.JsonAPIError(c, http.StatusConflict, err)
+	default:
+		web.JsonAPIError(c, http.StatusInternalServerError, err)
+	}
+}
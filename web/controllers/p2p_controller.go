@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"net/http"
+
+	"PhoenixOracle/core/service/jobs/offchainreporting"
+	"PhoenixOracle/core/service/phoenix"
+	ocrnetworking "PhoenixOracle/lib/libocr/networking"
+	"PhoenixOracle/web"
+	"PhoenixOracle/web/presenters"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// P2PController inspects and manages the P2P peer of this node
+type P2PController struct {
+	App phoenix.Application
+}
+
+// P2PPeerRequest identifies a remote peer and, when adding a peer, one of
+// its addresses
+type P2PPeerRequest struct {
+	PeerID  string `json:"peerID"`
+	Address string `json:"address"`
+}
+
+// Show returns the connections, known addresses and message counters of the
+// peer
+// Example:
+// "GET <application>/p2p/diagnostics"
+func (pc *P2PController) Show(c *gin.Context) {
+	pw := pc.App.GetPeerWrapper()
+	diagnostics, err := pw.Diagnostics()
+	if err != nil {
+		web.JsonAPIError(c, http.StatusConflict, err)
+		return
+	}
+	conns, err := diagnostics.Connections()
+	if err != nil && !errors.Is(err, ocrnetworking.ErrV2Unsupported) {
+		web.JsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	anns, err := pw.Announcements(c.Request.Context())
+	if err != nil {
+		web.JsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	web.JsonAPIResponse(c, presenters.NewP2PDiagnosticsResource(pw.PeerID.Raw(), diagnostics, conns, anns), "p2p_diagnostics")
+}
+
+// AddPeer adds a static address for a remote peer and dials it
+// Example:
+// "POST <application>/p2p/peers"
+func (pc *P2PController) AddPeer(c *gin.Context) {
+	var request P2PPeerRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if request.PeerID == "" || request.Address == "" {
+		web.JsonAPIError(c, http.StatusBadRequest, errors.New("peerID and address are required"))
+		return
+	}
+
+	pw := pc.App.GetPeerWrapper()
+	if err := pw.AddPeerAddress(c.Request.Context(), request.PeerID, request.Address); err != nil {
+		pc.respondWithError(c, err)
+		return
+	}
+	pc.Show(c)
+}
+
+// Ban disconnects from a remote peer and refuses further connections with it
+// Example:
+// "POST <application>/p2p/bans"
+func (pc *P2PController) Ban(c *gin.Context) {
+	var request P2PPeerRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	if request.PeerID == "" {
+		web.JsonAPIError(c, http.StatusBadRequest, errors.New("peerID is required"))
+		return
+	}
+
+	if err := pc.App.GetPeerWrapper().BanPeer(request.PeerID); err != nil {
+		pc.respondWithError(c, err)
+		return
+	}
+	pc.Show(c)
+}
+
+// Unban lifts the ban of a remote peer
+// Example:
+// "DELETE <application>/p2p/bans/:peerID"
+func (pc *P2PController) Unban(c *gin.Context) {
+	if err := pc.App.GetPeerWrapper().UnbanPeer(c.Param("peerID")); err != nil {
+		pc.respondWithError(c, err)
+		return
+	}
+	pc.Show(c)
+}
+
+func (pc *P2PController) respondWithError(c *gin.Context, err error) {
+	if errors.Cause(err) == offchainreporting.ErrPeerNotStarted || errors.Cause(err) == ocrnetworking.ErrV2Unsupported {
+		web.JsonAPIError(c, http.StatusConflict, err)
+		return
+	}
+	web.JsonAPIError(c, http.StatusBadRequest, err)
+}
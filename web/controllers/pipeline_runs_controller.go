@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strconv"
+	"time"
 
 	"PhoenixOracle/web/presenters"
 	"github.com/pkg/errors"
@@ -19,6 +20,20 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	// DefaultSyncRunTimeout is how long a synchronous run request waits for the
+	// run to finish when no timeout is given. It is shorter than the default
+	// HTTP_SERVER_WRITE_TIMEOUT.
+	DefaultSyncRunTimeout = 5 * time.Second
+	// MaxSyncRunTimeout bounds the timeout of a synchronous run request
+	MaxSyncRunTimeout = 5 * time.Minute
+	// syncRunWriteMargin is left between the end of the wait and
+	// HTTP_SERVER_WRITE_TIMEOUT to write the response
+	syncRunWriteMargin = 2 * time.Second
+
+	syncRunPollInterval = 250 * time.Millisecond
+)
+
 type PipelineRunsController struct {
 	App phoenix.Application
 }
@@ -73,14 +88,20 @@ func (prc *PipelineRunsController) Show(c *gin.Context) {
 	web.JsonAPIResponse(c, presenters.NewPipelineRunResource(pipelineRun), "pipelineRun")
 }
 
+// Create triggers a run of a job. With ?sync=true the request waits for the
+// run to finish, for up to ?timeout (e.g. 5s), and responds with its final
+// outputs and errors. The wait always ends before HTTP_SERVER_WRITE_TIMEOUT.
+// If the run is still in progress when the wait ends, it responds with 202
+// Accepted and the run as it stands, whose ID can be polled.
 func (prc *PipelineRunsController) Create(c *gin.Context) {
+	sync, timeout, err := syncRunOptions(c, prc.App.GetConfig().HTTPServerWriteTimeout())
+	if err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+
 	respondWithPipelineRun := func(jobRunID int64) {
-		pipelineRun, err := prc.App.PipelineORM().FindRun(jobRunID)
-		if err != nil {
-			web.JsonAPIError(c, http.StatusInternalServerError, err)
-			return
-		}
-		web.JsonAPIResponse(c, presenters.NewPipelineRunResource(pipelineRun), "pipelineRun")
+		respondWithRun(c, prc.App.PipelineORM(), jobRunID, sync, timeout)
 	}
 
 	bodyBytes, err := ioutil.ReadAll(c.Request.Body)
@@ -135,6 +156,160 @@ func (prc *PipelineRunsController) Create(c *gin.Context) {
 	web.JsonAPIError(c, http.StatusUnprocessableEntity, errors.New("bad job ID"))
 }
 
+// signedRequestBodyKey is the context key under which VerifySigned stores
+// the body of a verified request for CreateSigned
+const signedRequestBodyKey = "signed_request_body"
+
+// VerifySigned authenticates a request by a signature made with the signing
+// secret of the job in the ID route parameter, see webhook.SignatureHeader.
+// It aborts the request unless the signature is valid, so that handlers
+// after it only see verified callers.
+func (prc *PipelineRunsController) VerifySigned(c *gin.Context) {
+	jobUUID, err := uuid.FromString(c.Param("ID"))
+	if err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, errors.New("bad job ID"))
+		c.Abort()
+		return
+	}
+	bodyBytes, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		c.Abort()
+		return
+	}
+
+	err = webhook.VerifySignedRequest(c.Request.Context(), prc.App.GetStore().DB, jobUUID, webhook.SignedRequest{
+		Timestamp: c.GetHeader(webhook.TimestampHeader),
+		Signature: c.GetHeader(webhook.SignatureHeader),
+		Body:      bodyBytes,
+	}, time.Now())
+	switch errors.Cause(err) {
+	case nil:
+	case webhook.ErrJobNotSigned, webhook.ErrSignatureMissing, webhook.ErrSignatureInvalid,
+		webhook.ErrSignatureTimestampExpired, webhook.ErrSignatureReplayed:
+		web.JsonAPIError(c, http.StatusUnauthorized, err)
+		c.Abort()
+		return
+	default:
+		web.JsonAPIError(c, http.StatusInternalServerError, err)
+		c.Abort()
+		return
+	}
+	c.Set(signedRequestBodyKey, bodyBytes)
+	c.Next()
+}
+
+// CreateSigned triggers a run of a webhook job for a caller verified by
+// VerifySigned. It accepts the same sync and timeout parameters as Create.
+func (prc *PipelineRunsController) CreateSigned(c *gin.Context) {
+	sync, timeout, err := syncRunOptions(c, prc.App.GetConfig().HTTPServerWriteTimeout())
+	if err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	jobUUID, err := uuid.FromString(c.Param("ID"))
+	if err != nil {
+		web.JsonAPIError(c, http.StatusUnprocessableEntity, errors.New("bad job ID"))
+		return
+	}
+	body, _ := c.Get(signedRequestBodyKey)
+	bodyBytes, ok := body.([]byte)
+	if !ok {
+		web.JsonAPIError(c, http.StatusInternalServerError, errors.New("request was not verified"))
+		return
+	}
+
+	jobRunID, err := prc.App.RunWebhookJobV2(c.Request.Context(), jobUUID, string(bodyBytes), pipeline.JSONSerializable{Null: true})
+	if errors.Is(err, webhook.ErrJobNotExists) {
+		web.JsonAPIError(c, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		web.JsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	respondWithRun(c, prc.App.PipelineORM(), jobRunID, sync, timeout)
+}
+
+// runFinder loads pipeline runs, see pipeline.ORM
+type runFinder interface {
+	FindRun(id int64) (pipeline.Run, error)
+}
+
+// respondWithRun responds with a run, awaiting it first if sync is set
+func respondWithRun(c *gin.Context, runs runFinder, runID int64, sync bool, timeout time.Duration) {
+	var pipelineRun pipeline.Run
+	var err error
+	if sync {
+		pipelineRun, err = awaitRun(c.Request.Context(), runs, runID, timeout)
+	} else {
+		pipelineRun, err = runs.FindRun(runID)
+	}
+	if err != nil {
+		web.JsonAPIError(c, http.StatusInternalServerError, err)
+		return
+	}
+	if sync && !pipelineRun.State.Finished() {
+		web.JsonAPIResponseWithStatus(c, presenters.NewPipelineRunResource(pipelineRun), "pipelineRun", http.StatusAccepted)
+		return
+	}
+	web.JsonAPIResponse(c, presenters.NewPipelineRunResource(pipelineRun), "pipelineRun")
+}
+
+// syncRunOptions parses the sync and timeout query parameters of Create. The
+// timeout is clamped to end syncRunWriteMargin before writeTimeout, or half
+// way through it if it is shorter than twice the margin, so that the server
+// does not drop the connection before the response is written.
+func syncRunOptions(c *gin.Context, writeTimeout time.Duration) (sync bool, timeout time.Duration, err error) {
+	if s := c.Query("sync"); s != "" {
+		sync, err = strconv.ParseBool(s)
+		if err != nil {
+			return false, 0, errors.Wrap(err, "invalid sync parameter")
+		}
+	}
+	timeout = DefaultSyncRunTimeout
+	if s := c.Query("timeout"); s != "" {
+		timeout, err = time.ParseDuration(s)
+		if err != nil {
+			return false, 0, errors.Wrap(err, "invalid timeout parameter")
+		}
+		if timeout <= 0 || timeout > MaxSyncRunTimeout {
+			return false, 0, errors.Errorf("timeout must be positive and at most %s", MaxSyncRunTimeout)
+		}
+	}
+	// A zero write timeout means the server never times out writes
+	if writeTimeout > 0 {
+		limit := writeTimeout - syncRunWriteMargin
+		if limit < writeTimeout/2 {
+			limit = writeTimeout / 2
+		}
+		if timeout > limit {
+			timeout = limit
+		}
+	}
+	return sync, timeout, nil
+}
+
+// awaitRun polls a run until it has finished or the timeout has elapsed, and
+// returns it as last seen. Runs with async tasks are resumed in the
+// background, so there is no completion to wait on in this request.
+func awaitRun(ctx context.Context, runs runFinder, runID int64, timeout time.Duration) (pipeline.Run, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(syncRunPollInterval)
+	defer ticker.Stop()
+	for {
+		run, err := runs.FindRun(runID)
+		if err != nil || run.State.Finished() {
+			return run, err
+		}
+		select {
+		case <-ctx.Done():
+			return run, nil
+		case <-ticker.C:
+		}
+	}
+}
+
 func (prc *PipelineRunsController) Resume(c *gin.Context) {
 	taskID, err := uuid.FromString(c.Param("runID"))
 	if err != nil {
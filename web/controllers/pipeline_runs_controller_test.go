@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"PhoenixOracle/core/service/pipeline"
+	"github.com/gin-gonic/gin"
+)
+
+// fakeRunFinder returns a running run until it has been loaded finishAfter
+// times, then a completed one. A zero finishAfter never finishes.
+type fakeRunFinder struct {
+	finishAfter int
+	loads       int
+}
+
+func (f *fakeRunFinder) FindRun(id int64) (pipeline.Run, error) {
+	f.loads++
+	run := pipeline.Run{ID: id, State: pipeline.RunStatusRunning, Outputs: pipeline.JSONSerializable{Val: []interface{}{}, Null: true}}
+	if f.finishAfter > 0 && f.loads >= f.finishAfter {
+		run.State = pipeline.RunStatusCompleted
+	}
+	return run, nil
+}
+
+func newTestContext(target string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, target, nil)
+	return c, w
+}
+
+func TestRespondWithRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		sync        bool
+		finishAfter int
+		status      int
+	}{
+		{"async", false, 0, http.StatusOK},
+		{"sync run finishes in time", true, 2, http.StatusOK},
+		{"sync run still in progress", true, 0, http.StatusAccepted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := newTestContext("/v2/jobs/1/runs")
+			runs := &fakeRunFinder{finishAfter: tt.finishAfter}
+			respondWithRun(c, runs, 1, tt.sync, time.Second)
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body)
+			}
+		})
+	}
+}
+
+func TestSyncRunOptions(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		writeTimeout time.Duration
+		sync         bool
+		timeout      time.Duration
+	}{
+		{"defaults", "", 10 * time.Second, false, DefaultSyncRunTimeout},
+		{"sync with timeout", "?sync=true&timeout=3s", 10 * time.Second, true, 3 * time.Second},
+		{"clamped below write timeout", "?sync=true&timeout=1m", 10 * time.Second, true, 8 * time.Second},
+		{"default clamped below short write timeout", "?sync=true", 3 * time.Second, true, 1500 * time.Millisecond},
+		{"no write timeout", "?sync=true&timeout=1m", 0, true, time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestContext("/v2/jobs/1/runs" + tt.query)
+			sync, timeout, err := syncRunOptions(c, tt.writeTimeout)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sync != tt.sync || timeout != tt.timeout {
+				t.Errorf("expected sync %t and timeout %s, got %t and %s", tt.sync, tt.timeout, sync, timeout)
+			}
+		})
+	}
+
+	for _, query := range []string{"?sync=maybe", "?timeout=soon", "?timeout=0s", "?timeout=1h"} {
+		t.Run(query, func(t *testing.T) {
+			c, _ := newTestContext("/v2/jobs/1/runs" + query)
+			if _, _, err := syncRunOptions(c, 10*time.Second); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
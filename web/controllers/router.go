@@ -235,8 +235,12 @@ func v2Routes(app phoenix.Application, r *gin.RouterGroup) {
 		authv2.POST("/keys/eth", ekc.Create)
 		authv2.DELETE("/keys/eth/:keyID", ekc.Delete)
 		authv2.POST("/keys/eth/import", ekc.Import)
+		authv2.POST("/keys/eth/external", ekc.AddExternal)
 		authv2.POST("/keys/eth/export/:address", ekc.Export)
 
+		kc := KeystoreController{app}
+		authv2.POST("/keys/rotate_password", kc.RotatePassword)
+
 		ocrkc := OCRKeysController{app}
 		authv2.GET("/keys/ocr", ocrkc.Index)
 		authv2.POST("/keys/ocr", ocrkc.Create)
@@ -268,6 +272,33 @@ func v2Routes(app phoenix.Application, r *gin.RouterGroup) {
 		authv2.POST("/jobs", jc.Create)
 		authv2.DELETE("/jobs/:ID", jc.Delete)
 
+		osc := OCRStatusController{app}
+		authv2.GET("/jobs/:ID/ocr/status", osc.Show)
+
+		occ := OCRConfigController{app}
+		authv2.POST("/ocr/set_config", occ.Create)
+
+		otcc := OCRTransmissionCostsController{app}
+		authv2.GET("/ocr/transmission_costs", otcc.Index)
+
+		opc := OperatorController{app}
+		authv2.GET("/operator/earnings", opc.Earnings)
+		authv2.POST("/operator/withdrawals", opc.Withdraw)
+		authv2.GET("/operator/requester_policies", opc.RequesterPolicies)
+		authv2.POST("/operator/requester_policies", opc.UpsertRequesterPolicy)
+		authv2.DELETE("/operator/requester_policies/:ID", opc.DeleteRequesterPolicy)
+
+		p2pc := P2PController{app}
+		authv2.GET("/p2p/diagnostics", p2pc.Show)
+		authv2.POST("/p2p/peers", p2pc.AddPeer)
+		authv2.POST("/p2p/bans", p2pc.Ban)
+		authv2.DELETE("/p2p/bans/:peerID", p2pc.Unban)
+
+		bplc := P2PBootstrapPeerListsController{app}
+		authv2.GET("/p2p/bootstrap_peer_lists", bplc.Index)
+		authv2.POST("/p2p/bootstrap_peer_lists", bplc.Create)
+		authv2.DELETE("/p2p/bootstrap_peer_lists/:name", bplc.Delete)
+
 		jpc := JobProposalsController{app}
 		authv2.GET("/job_proposals", jpc.Index)
 		authv2.GET("/job_proposals/:id", jpc.Show)
@@ -296,6 +327,15 @@ func v2Routes(app phoenix.Application, r *gin.RouterGroup) {
 		authv2.POST("/chains/evm", chc.Create)
 		authv2.DELETE("/chains/evm/:ID", chc.Delete)
 
+		ac := AlertsController{app}
+		authv2.GET("/alerts/notifiers", ac.IndexNotifiers)
+		authv2.POST("/alerts/notifiers", ac.CreateNotifier)
+		authv2.DELETE("/alerts/notifiers/:ID", ac.DeleteNotifier)
+		authv2.POST("/alerts/notifiers/:ID/test", ac.TestNotifier)
+		authv2.GET("/alerts/rules", ac.IndexRules)
+		authv2.POST("/alerts/rules", ac.CreateRule)
+		authv2.DELETE("/alerts/rules/:ID", ac.DeleteRule)
+
 		nc := NodesController{app}
 		authv2.GET("/nodes", web.PaginatedRequest(nc.Index))
 		authv2.GET("/chains/evm/:ID/nodes", web.PaginatedRequest(nc.Index))
@@ -311,6 +351,47 @@ func v2Routes(app phoenix.Application, r *gin.RouterGroup) {
 	))
 	userOrEI.GET("/ping", ping.Show)
 	userOrEI.POST("/jobs/:ID/runs", prc.Create)
+
+	// Signed webhooks authenticate with the job's signing secret instead.
+	// Unverified requests only count against the per-IP limit, so that
+	// nobody without the secret can exhaust a job's limit.
+	signed := r.Group("/v2/webhooks",
+		limits.RequestSizeLimiter(signedWebhookSizeLimit),
+		rateLimiter(signedWebhookRateLimitPeriod, signedWebhookIPRateLimit),
+	)
+	signed.POST("/:ID",
+		prc.VerifySigned,
+		jobRateLimiter(signedWebhookRateLimitPeriod, signedWebhookRateLimit),
+		prc.CreateSigned,
+	)
+}
+
+var signedWebhookSizeLimit = int64(64 * 1024)
+var signedWebhookRateLimit = int64(60)
+var signedWebhookIPRateLimit = int64(120)
+var signedWebhookRateLimitPeriod = 1 * time.Minute
+
+// jobRateLimiter limits the rate of requests to each job, identified by the
+// ID route parameter
+func jobRateLimiter(period time.Duration, limit int64) gin.HandlerFunc {
+	rateLimiter := limiter.New(memory.NewStore(), limiter.Rate{
+		Period: period,
+		Limit:  limit,
+	})
+	return func(c *gin.Context) {
+		lc, err := rateLimiter.Get(c, "job:"+c.Param("ID"))
+		if err != nil {
+			web.JsonAPIError(c, http.StatusInternalServerError, err)
+			c.Abort()
+			return
+		}
+		if lc.Reached {
+			web.JsonAPIError(c, http.StatusTooManyRequests, fmt.Errorf("rate limit of %d requests per %s exceeded for job %s", limit, period, c.Param("ID")))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
 }
 
 var staticAssetsRateLimit = int64(100)
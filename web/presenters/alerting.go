@@ -0,0 +1,100 @@
+package presenters
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"PhoenixOracle/core/service/alerting"
+	"github.com/shopspring/decimal"
+	"gopkg.in/guregu/null.v4"
+)
+
+type AlertNotifierResource struct {
+	JAID
+	Name      string                 `json:"name"`
+	Type      alerting.NotifierType  `json:"type"`
+	Config    map[string]interface{} `json:"config"`
+	CreatedAt time.Time              `json:"createdAt"`
+}
+
+func (r AlertNotifierResource) GetName() string {
+	return "alert_notifiers"
+}
+
+func NewAlertNotifierResource(n alerting.Notifier) *AlertNotifierResource {
+	cfg := map[string]interface{}{}
+	_ = json.Unmarshal(n.Config, &cfg)
+	for k, v := range cfg {
+		switch key := strings.ToLower(k); {
+		case strings.Contains(key, "password"), key == "webhookurl":
+			// Slack webhook URLs are credentials in themselves
+			cfg[k] = "*REDACTED*"
+		case key == "headers":
+			// Header names are kept, their values usually carry auth tokens
+			if headers, ok := v.(map[string]interface{}); ok {
+				for h := range headers {
+					headers[h] = "*REDACTED*"
+				}
+			}
+		}
+	}
+
+	return &AlertNotifierResource{
+		JAID:      NewJAIDInt64(n.ID),
+		Name:      n.Name,
+		Type:      n.Type,
+		Config:    cfg,
+		CreatedAt: n.CreatedAt,
+	}
+}
+
+func NewAlertNotifierResources(ns []alerting.Notifier) []AlertNotifierResource {
+	rs := []AlertNotifierResource{}
+	for _, n := range ns {
+		rs = append(rs, *NewAlertNotifierResource(n))
+	}
+	return rs
+}
+
+type AlertRuleResource struct {
+	JAID
+	Name        string            `json:"name"`
+	Type        alerting.RuleType `json:"type"`
+	Threshold   decimal.Decimal   `json:"threshold"`
+	Window      string            `json:"window"`
+	Cooldown    string            `json:"cooldown"`
+	JobID       null.Int32        `json:"jobID"`
+	NotifierIDs []int64           `json:"notifierIDs"`
+	Enabled     bool              `json:"enabled"`
+	LastFiredAt null.Time         `json:"lastFiredAt"`
+	CreatedAt   time.Time         `json:"createdAt"`
+}
+
+func (r AlertRuleResource) GetName() string {
+	return "alert_rules"
+}
+
+func NewAlertRuleResource(r alerting.Rule) *AlertRuleResource {
+	return &AlertRuleResource{
+		JAID:        NewJAIDInt64(r.ID),
+		Name:        r.Name,
+		Type:        r.Type,
+		Threshold:   r.Threshold,
+		Window:      r.Window.Duration().String(),
+		Cooldown:    r.Cooldown.Duration().String(),
+		JobID:       r.JobID,
+		NotifierIDs: r.NotifierIDs,
+		Enabled:     r.Enabled,
+		LastFiredAt: r.LastFiredAt,
+		CreatedAt:   r.CreatedAt,
+	}
+}
+
+func NewAlertRuleResources(rs []alerting.Rule) []AlertRuleResource {
+	resources := []AlertRuleResource{}
+	for _, r := range rs {
+		resources = append(resources, *NewAlertRuleResource(r))
+	}
+	return resources
+}
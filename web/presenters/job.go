@@ -14,6 +14,7 @@ import (
 	"PhoenixOracle/db/models"
 	clnull "PhoenixOracle/lib/null"
 	"PhoenixOracle/lib/signatures/secp256k1"
+	"PhoenixOracle/util"
 )
 
 type JobSpecType string
@@ -30,6 +31,8 @@ const (
 	CronJobSpec              JobSpecType = "cron"
 	VRFJobSpec               JobSpecType = "vrf"
 	WebhookJobSpec           JobSpecType = "webhook"
+	EventTriggerJobSpec      JobSpecType = "eventtrigger"
+	BlockTriggerJobSpec      JobSpecType = "blocktrigger"
 )
 
 type DirectRequestSpec struct {
@@ -101,14 +104,22 @@ type OffChainReportingSpec struct {
 	ContractAddress                        ethkey.EIP55Address  `json:"contractAddress"`
 	P2PPeerID                              *p2pkey.PeerID       `json:"p2pPeerID"`
 	P2PBootstrapPeers                      pq.StringArray       `json:"p2pBootstrapPeers"`
+	P2PBootstrapPeerList                   *string              `json:"p2pBootstrapPeerList"`
+	P2PBootstrapDNSName                    *string              `json:"p2pBootstrapDNSName"`
 	IsBootstrapPeer                        bool                 `json:"isBootstrapPeer"`
 	EncryptedOCRKeyBundleID                *models.Sha256Hash   `json:"keyBundleID"`
 	TransmitterAddress                     *ethkey.EIP55Address `json:"transmitterAddress"`
 	ObservationTimeout                     models.Interval      `json:"observationTimeout"`
+	ObservationFallbackStaleness           models.Interval      `json:"observationFallbackStaleness"`
 	BlockchainTimeout                      models.Interval      `json:"blockchainTimeout"`
 	ContractConfigTrackerSubscribeInterval models.Interval      `json:"contractConfigTrackerSubscribeInterval"`
 	ContractConfigTrackerPollInterval      models.Interval      `json:"contractConfigTrackerPollInterval"`
 	ContractConfigConfirmations            uint16               `json:"contractConfigConfirmations"`
+	TransmissionMaintenanceWindows         pq.StringArray       `json:"transmissionMaintenanceWindows"`
+	MaxTransmissionsPerHour                uint32               `json:"maxTransmissionsPerHour"`
+	GasSpikeGasPriceWei                    *utils.Big           `json:"gasSpikeGasPriceWei"`
+	GasSpikeAlphaPPB                       uint64               `json:"gasSpikeAlphaPPB"`
+	GasSpikeDeltaC                         models.Interval      `json:"gasSpikeDeltaC"`
 	CreatedAt                              time.Time            `json:"createdAt"`
 	UpdatedAt                              time.Time            `json:"updatedAt"`
 }
@@ -118,14 +129,22 @@ func NewOffChainReportingSpec(spec *job.OffchainReportingOracleSpec) *OffChainRe
 		ContractAddress:                        spec.ContractAddress,
 		P2PPeerID:                              spec.P2PPeerID,
 		P2PBootstrapPeers:                      spec.P2PBootstrapPeers,
+		P2PBootstrapPeerList:                   spec.P2PBootstrapPeerList,
+		P2PBootstrapDNSName:                    spec.P2PBootstrapDNSName,
 		IsBootstrapPeer:                        spec.IsBootstrapPeer,
 		EncryptedOCRKeyBundleID:                spec.EncryptedOCRKeyBundleID,
 		TransmitterAddress:                     spec.TransmitterAddress,
 		ObservationTimeout:                     spec.ObservationTimeout,
+		ObservationFallbackStaleness:           spec.ObservationFallbackStaleness,
 		BlockchainTimeout:                      spec.BlockchainTimeout,
 		ContractConfigTrackerSubscribeInterval: spec.ContractConfigTrackerSubscribeInterval,
 		ContractConfigTrackerPollInterval:      spec.ContractConfigTrackerPollInterval,
 		ContractConfigConfirmations:            spec.ContractConfigConfirmations,
+		TransmissionMaintenanceWindows:         spec.TransmissionMaintenanceWindows,
+		MaxTransmissionsPerHour:                spec.MaxTransmissionsPerHour,
+		GasSpikeGasPriceWei:                    spec.GasSpikeGasPriceWei,
+		GasSpikeAlphaPPB:                       spec.GasSpikeAlphaPPB,
+		GasSpikeDeltaC:                         spec.GasSpikeDeltaC,
 		CreatedAt:                              spec.CreatedAt,
 		UpdatedAt:                              spec.UpdatedAt,
 	}
@@ -162,46 +181,102 @@ func NewKeeperSpec(spec *job.KeeperSpec) *KeeperSpec {
 }
 
 type WebhookSpec struct {
+	Signed    bool      `json:"signed"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
 func NewWebhookSpec(spec *job.WebhookSpec) *WebhookSpec {
 	return &WebhookSpec{
+		Signed:    spec.SigningSecret.Valid,
 		CreatedAt: spec.CreatedAt,
 		UpdatedAt: spec.UpdatedAt,
 	}
 }
 
+type EventTriggerSpec struct {
+	ContractAddress          ethkey.EIP55Address   `json:"contractAddress"`
+	Event                    string                `json:"event"`
+	TopicFilters             job.EventTopicFilters `json:"topicFilters"`
+	MinIncomingConfirmations clnull.Uint32         `json:"minIncomingConfirmations"`
+	CreatedAt                time.Time             `json:"createdAt"`
+	UpdatedAt                time.Time             `json:"updatedAt"`
+}
+
+func NewEventTriggerSpec(spec *job.EventTriggerSpec) *EventTriggerSpec {
+	return &EventTriggerSpec{
+		ContractAddress:          spec.ContractAddress,
+		Event:                    spec.Event,
+		TopicFilters:             spec.TopicFilters,
+		MinIncomingConfirmations: spec.MinIncomingConfirmations,
+		CreatedAt:                spec.CreatedAt,
+		UpdatedAt:                spec.UpdatedAt,
+	}
+}
+
+type BlockTriggerSpec struct {
+	BlockInterval uint32    `json:"blockInterval"`
+	StartOffset   uint64    `json:"startOffset"`
+	Confirmations uint32    `json:"confirmations"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+func NewBlockTriggerSpec(spec *job.BlockTriggerSpec) *BlockTriggerSpec {
+	return &BlockTriggerSpec{
+		BlockInterval: spec.BlockInterval,
+		StartOffset:   spec.StartOffset,
+		Confirmations: spec.Confirmations,
+		CreatedAt:     spec.CreatedAt,
+		UpdatedAt:     spec.UpdatedAt,
+	}
+}
+
 type CronSpec struct {
-	CronSchedule string    `json:"schedule" tom:"schedule"`
-	CreatedAt    time.Time `json:"createdAt"`
-	UpdatedAt    time.Time `json:"updatedAt"`
+	CronSchedule    string                `json:"schedule" tom:"schedule"`
+	MisfirePolicy   job.CronMisfirePolicy `json:"misfirePolicy"`
+	Jitter          models.Interval       `json:"jitter"`
+	LastExecutionAt *time.Time            `json:"lastExecutionAt"`
+	NextExecutionAt *time.Time            `json:"nextExecutionAt"`
+	CreatedAt       time.Time             `json:"createdAt"`
+	UpdatedAt       time.Time             `json:"updatedAt"`
 }
 
 func NewCronSpec(spec *job.CronSpec) *CronSpec {
 	return &CronSpec{
-		CronSchedule: spec.CronSchedule,
-		CreatedAt:    spec.CreatedAt,
-		UpdatedAt:    spec.UpdatedAt,
+		CronSchedule:    spec.CronSchedule,
+		MisfirePolicy:   spec.MisfirePolicy,
+		Jitter:          spec.Jitter,
+		LastExecutionAt: spec.LastExecutionAt,
+		NextExecutionAt: spec.NextExecutionAt,
+		CreatedAt:       spec.CreatedAt,
+		UpdatedAt:       spec.UpdatedAt,
 	}
 }
 
 type VRFSpec struct {
-	CoordinatorAddress ethkey.EIP55Address `json:"coordinatorAddress"`
-	PublicKey          secp256k1.PublicKey `json:"publicKey"`
-	Confirmations      uint32              `json:"confirmations"`
-	CreatedAt          time.Time           `json:"createdAt"`
-	UpdatedAt          time.Time           `json:"updatedAt"`
+	CoordinatorAddress      ethkey.EIP55Address  `json:"coordinatorAddress"`
+	PublicKey               secp256k1.PublicKey  `json:"publicKey"`
+	Confirmations           uint32               `json:"confirmations"`
+	BatchFulfillmentEnabled bool                 `json:"batchFulfillmentEnabled"`
+	BatchCoordinatorAddress *ethkey.EIP55Address `json:"batchCoordinatorAddress"`
+	BatchFulfillmentSize    uint32               `json:"batchFulfillmentSize"`
+	BatchFulfillmentTimeout models.Interval      `json:"batchFulfillmentTimeout"`
+	CreatedAt               time.Time            `json:"createdAt"`
+	UpdatedAt               time.Time            `json:"updatedAt"`
 }
 
 func NewVRFSpec(spec *job.VRFSpec) *VRFSpec {
 	return &VRFSpec{
-		CoordinatorAddress: spec.CoordinatorAddress,
-		PublicKey:          spec.PublicKey,
-		Confirmations:      spec.Confirmations,
-		CreatedAt:          spec.CreatedAt,
-		UpdatedAt:          spec.UpdatedAt,
+		CoordinatorAddress:      spec.CoordinatorAddress,
+		PublicKey:               spec.PublicKey,
+		Confirmations:           spec.Confirmations,
+		BatchFulfillmentEnabled: spec.BatchFulfillmentEnabled,
+		BatchCoordinatorAddress: spec.BatchCoordinatorAddress,
+		BatchFulfillmentSize:    spec.BatchFulfillmentSize,
+		BatchFulfillmentTimeout: spec.BatchFulfillmentTimeout,
+		CreatedAt:               spec.CreatedAt,
+		UpdatedAt:               spec.UpdatedAt,
 	}
 }
 
@@ -237,6 +312,8 @@ type JobResource struct {
 	KeeperSpec            *KeeperSpec            `json:"keeperSpec"`
 	VRFSpec               *VRFSpec               `json:"vrfSpec"`
 	WebhookSpec           *WebhookSpec           `json:"webhookSpec"`
+	EventTriggerSpec      *EventTriggerSpec      `json:"eventTriggerSpec"`
+	BlockTriggerSpec      *BlockTriggerSpec      `json:"blockTriggerSpec"`
 	PipelineSpec          PipelineSpec           `json:"pipelineSpec"`
 	Errors                []JobError             `json:"errors"`
 }
@@ -267,6 +344,10 @@ func NewJobResource(j job.Job) *JobResource {
 		resource.VRFSpec = NewVRFSpec(j.VRFSpec)
 	case job.Webhook:
 		resource.WebhookSpec = NewWebhookSpec(j.WebhookSpec)
+	case job.EventTrigger:
+		resource.EventTriggerSpec = NewEventTriggerSpec(j.EventTriggerSpec)
+	case job.BlockTrigger:
+		resource.BlockTriggerSpec = NewBlockTriggerSpec(j.BlockTriggerSpec)
 	}
 
 	jes := []JobError{}
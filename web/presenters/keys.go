@@ -18,6 +18,7 @@ type ETHKeyResource struct {
 	EthBalance  *assets.Eth  `json:"ethBalance"`
 	PhbBalance *assets.Phb `json:"phbBalance"`
 	IsFunding   bool         `json:"isFunding"`
+	ExternalSigner string    `json:"externalSigner,omitempty"`
 	CreatedAt   time.Time    `json:"createdAt"`
 	UpdatedAt   time.Time    `json:"updatedAt"`
 }
@@ -35,6 +36,7 @@ func NewETHKeyResource(k ethkey.KeyV2, state ethkey.State, opts ...NewETHKeyOpti
 		EthBalance:  nil,
 		PhbBalance: nil,
 		IsFunding:   state.IsFunding,
+		ExternalSigner: state.ExternalSigner.String,
 		CreatedAt:   state.CreatedAt,
 		UpdatedAt:   state.UpdatedAt,
 	}
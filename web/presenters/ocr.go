@@ -0,0 +1,295 @@
+package presenters
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"PhoenixOracle/core/assets"
+	"PhoenixOracle/core/service/jobs/offchainreporting"
+	ocrtypes "PhoenixOracle/lib/libocr/offchainreporting/types"
+)
+
+// OCRLeaderSelectionResource represents how the leader of an OCR epoch was
+// chosen
+type OCRLeaderSelectionResource struct {
+	ConfigDigest  string                         `json:"configDigest"`
+	Epoch         uint32                         `json:"epoch"`
+	DefaultLeader int                            `json:"defaultLeader"`
+	Leader        int                            `json:"leader"`
+	ActiveIndexes []int                          `json:"activeIndexes"`
+	Reason        ocrtypes.LeaderSelectionReason `json:"reason"`
+	Time          time.Time                      `json:"time"`
+}
+
+func NewOCRLeaderSelectionResource(s ocrtypes.LeaderSelection) OCRLeaderSelectionResource {
+	activeIndexes := s.ActiveIndexes
+	if activeIndexes == nil {
+		activeIndexes = []int{}
+	}
+	return OCRLeaderSelectionResource{
+		ConfigDigest:  s.ConfigDigest.Hex(),
+		Epoch:         s.Epoch,
+		DefaultLeader: int(s.DefaultLeader),
+		Leader:        int(s.Leader),
+		ActiveIndexes: activeIndexes,
+		Reason:        s.Reason,
+		Time:          s.Time,
+	}
+}
+
+func newOCRLeaderSelectionResources(ss []ocrtypes.LeaderSelection) []OCRLeaderSelectionResource {
+	rs := []OCRLeaderSelectionResource{}
+	for _, s := range ss {
+		rs = append(rs, NewOCRLeaderSelectionResource(s))
+	}
+	return rs
+}
+
+// OCRRoundResource represents the latest round started by an OCR oracle
+type OCRRoundResource struct {
+	ConfigDigest string    `json:"configDigest"`
+	Epoch        uint32    `json:"epoch"`
+	Round        uint8     `json:"round"`
+	Leader       int       `json:"leader"`
+	StartedAt    time.Time `json:"startedAt"`
+}
+
+// OCRObservationResource represents the latest observation of an OCR oracle
+type OCRObservationResource struct {
+	Value         *string                             `json:"value"`
+	Source        offchainreporting.ObservationSource `json:"source,omitempty"`
+	FailedTasks   int                                 `json:"failedTasks"`
+	Error         string                              `json:"error,omitempty"`
+	PipelineRunID int64                               `json:"pipelineRunID,omitempty"`
+	ObservedAt    time.Time                           `json:"observedAt"`
+}
+
+// OCRTransmissionResource represents the latest report transmitted by an
+// OCR oracle
+type OCRTransmissionResource struct {
+	ConfigDigest  string    `json:"configDigest"`
+	Epoch         uint32    `json:"epoch"`
+	Round         uint8     `json:"round"`
+	EthTxID       int64     `json:"ethTxID,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	TransmittedAt time.Time `json:"transmittedAt"`
+}
+
+// OCRPeerResource represents the connectivity to a member of the oracle set
+type OCRPeerResource struct {
+	OracleID      int    `json:"oracleID"`
+	PeerID        string `json:"peerID"`
+	Self          bool   `json:"self"`
+	Connectedness string `json:"connectedness"`
+}
+
+// OCRProtocolErrorResource represents an error logged by the OCR protocol
+type OCRProtocolErrorResource struct {
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// OCRPersistentStateResource represents the persisted protocol state of an
+// OCR oracle
+type OCRPersistentStateResource struct {
+	ConfigDigest         string    `json:"configDigest"`
+	Epoch                uint32    `json:"epoch"`
+	HighestSentEpoch     uint32    `json:"highestSentEpoch"`
+	HighestReceivedEpoch []uint32  `json:"highestReceivedEpoch"`
+	UpdatedAt            time.Time `json:"updatedAt"`
+}
+
+// OCRPendingTransmissionResource represents a report waiting to be confirmed
+// on chain
+type OCRPendingTransmissionResource struct {
+	ConfigDigest string    `json:"configDigest"`
+	Epoch        uint32    `json:"epoch"`
+	Round        uint8     `json:"round"`
+	Median       string    `json:"median"`
+	Time         time.Time `json:"time"`
+}
+
+// OCRStatusResource represents the status of an OCR job
+type OCRStatusResource struct {
+	JAID
+	ContractAddress      string                           `json:"contractAddress"`
+	Running              bool                             `json:"running"`
+	Round                *OCRRoundResource                `json:"round"`
+	ActiveIndexes        []int                            `json:"activeIndexes"`
+	LatestSelection      *OCRLeaderSelectionResource      `json:"latestLeaderSelection"`
+	RecentOverrides      []OCRLeaderSelectionResource     `json:"recentLeaderOverrides"`
+	RecentFallbacks      []OCRLeaderSelectionResource     `json:"recentLeaderFallbacks"`
+	OverrideCount        uint64                           `json:"leaderOverrideCount"`
+	FallbackCount        uint64                           `json:"leaderFallbackCount"`
+	LastObservation      *OCRObservationResource          `json:"lastObservation"`
+	LastTransmission     *OCRTransmissionResource         `json:"lastTransmission"`
+	Peers                []OCRPeerResource                `json:"peers"`
+	RecentErrors         []OCRProtocolErrorResource       `json:"recentErrors"`
+	PersistentState      *OCRPersistentStateResource      `json:"persistentState"`
+	PendingTransmissions []OCRPendingTransmissionResource `json:"pendingTransmissions"`
+	GasSpike             bool                             `json:"gasSpike"`
+}
+
+func (r OCRStatusResource) GetName() string {
+	return "ocr_status"
+}
+
+func NewOCRStatusResource(s offchainreporting.OracleStatus) *OCRStatusResource {
+	r := &OCRStatusResource{
+		JAID:            NewJAIDInt32(s.JobID),
+		ContractAddress: s.ContractAddress.Hex(),
+		Running:         s.Running,
+		ActiveIndexes:   s.ActiveIndexes,
+		RecentOverrides: newOCRLeaderSelectionResources(s.RecentOverrides),
+		RecentFallbacks: newOCRLeaderSelectionResources(s.RecentFallbacks),
+		OverrideCount:   s.OverrideCount,
+		FallbackCount:   s.FallbackCount,
+		GasSpike:        s.GasSpike,
+	}
+	if r.ActiveIndexes == nil {
+		r.ActiveIndexes = []int{}
+	}
+	if s.LatestSelection != nil {
+		latest := NewOCRLeaderSelectionResource(*s.LatestSelection)
+		r.LatestSelection = &latest
+	}
+	if round := s.Round; round != nil {
+		r.Round = &OCRRoundResource{
+			ConfigDigest: round.ConfigDigest.Hex(),
+			Epoch:        round.Epoch,
+			Round:        round.Round,
+			Leader:       int(round.Leader),
+			StartedAt:    round.StartedAt,
+		}
+	}
+	if o := s.LastObservation; o != nil {
+		r.LastObservation = &OCRObservationResource{
+			Source:        o.Source,
+			FailedTasks:   o.FailedTasks,
+			Error:         o.Error,
+			PipelineRunID: o.PipelineRunID,
+			ObservedAt:    o.ObservedAt,
+		}
+		if o.Value != nil {
+			value := o.Value.String()
+			r.LastObservation.Value = &value
+		}
+	}
+	if t := s.LastTransmission; t != nil {
+		r.LastTransmission = &OCRTransmissionResource{
+			ConfigDigest:  t.ConfigDigest.Hex(),
+			Epoch:         t.Epoch,
+			Round:         t.Round,
+			EthTxID:       t.EthTxID,
+			Error:         t.Error,
+			TransmittedAt: t.TransmittedAt,
+		}
+	}
+	r.Peers = []OCRPeerResource{}
+	for _, p := range s.Peers {
+		r.Peers = append(r.Peers, OCRPeerResource{
+			OracleID:      int(p.OracleID),
+			PeerID:        p.PeerID,
+			Self:          p.Self,
+			Connectedness: p.Connectedness,
+		})
+	}
+	r.RecentErrors = []OCRProtocolErrorResource{}
+	for _, e := range s.RecentErrors {
+		r.RecentErrors = append(r.RecentErrors, OCRProtocolErrorResource{Message: e.Message, Time: e.Time})
+	}
+	if ps := s.PersistentState; ps != nil {
+		r.PersistentState = &OCRPersistentStateResource{
+			ConfigDigest:         ps.ConfigDigest.Hex(),
+			Epoch:                ps.Epoch,
+			HighestSentEpoch:     ps.HighestSentEpoch,
+			HighestReceivedEpoch: ps.HighestReceivedEpoch,
+			UpdatedAt:            ps.UpdatedAt,
+		}
+	}
+	r.PendingTransmissions = []OCRPendingTransmissionResource{}
+	for _, pt := range s.PendingTransmissions {
+		r.PendingTransmissions = append(r.PendingTransmissions, OCRPendingTransmissionResource{
+			ConfigDigest: pt.ConfigDigest.Hex(),
+			Epoch:        pt.Epoch,
+			Round:        pt.Round,
+			Median:       pt.Median.String(),
+			Time:         pt.Time,
+		})
+	}
+	return r
+}
+
+// OCRSetConfigResource represents a generated setConfig call for an
+// OffchainAggregator contract
+type OCRSetConfigResource struct {
+	JAID
+	ContractAddress      string           `json:"contractAddress"`
+	Signers              []common.Address `json:"signers"`
+	Transmitters         []common.Address `json:"transmitters"`
+	Threshold            uint8            `json:"threshold"`
+	EncodedConfigVersion uint64           `json:"encodedConfigVersion"`
+	EncodedConfig        hexutil.Bytes    `json:"encodedConfig"`
+	Calldata             hexutil.Bytes    `json:"calldata"`
+	// EthTxID is set when the call was submitted through the txmanager
+	EthTxID *int64 `json:"ethTxID"`
+}
+
+func (r OCRSetConfigResource) GetName() string {
+	return "ocr_set_configs"
+}
+
+func NewOCRSetConfigResource(contractAddress common.Address, args offchainreporting.SetConfigArgs, ethTxID *int64) *OCRSetConfigResource {
+	return &OCRSetConfigResource{
+		JAID:                 NewJAID(contractAddress.Hex()),
+		ContractAddress:      contractAddress.Hex(),
+		Signers:              args.Signers,
+		Transmitters:         args.Transmitters,
+		Threshold:            args.Threshold,
+		EncodedConfigVersion: args.EncodedConfigVersion,
+		EncodedConfig:        args.EncodedConfig,
+		Calldata:             args.Calldata,
+		EthTxID:              ethTxID,
+	}
+}
+
+// OCRTransmissionCostResource represents the cost of the OCR transmissions of
+// a job during one period
+type OCRTransmissionCostResource struct {
+	JAID
+	JobID         int32      `json:"jobID"`
+	PeriodStart   time.Time  `json:"periodStart"`
+	Transmissions int        `json:"transmissions"`
+	Confirmed     int        `json:"confirmed"`
+	Reverted      int        `json:"reverted"`
+	Failed        int        `json:"failed"`
+	Pending       int        `json:"pending"`
+	GasUsed       uint64     `json:"gasUsed"`
+	EthSpent      assets.Eth `json:"ethSpent"`
+}
+
+func (r OCRTransmissionCostResource) GetName() string {
+	return "ocr_transmission_costs"
+}
+
+func NewOCRTransmissionCostResources(costs []offchainreporting.TransmissionCost) []OCRTransmissionCostResource {
+	rs := []OCRTransmissionCostResource{}
+	for _, c := range costs {
+		rs = append(rs, OCRTransmissionCostResource{
+			JAID:          NewJAID(fmt.Sprintf("%d-%s", c.JobID, c.PeriodStart.Format("2006-01-02"))),
+			JobID:         c.JobID,
+			PeriodStart:   c.PeriodStart,
+			Transmissions: c.Transmissions,
+			Confirmed:     c.Confirmed,
+			Reverted:      c.Reverted,
+			Failed:        c.Failed,
+			Pending:       c.Pending,
+			GasUsed:       c.GasUsed,
+			EthSpent:      c.EthSpent,
+		})
+	}
+	return rs
+}
@@ -0,0 +1,124 @@
+package presenters
+
+import (
+	"fmt"
+	"time"
+
+	"PhoenixOracle/core/assets"
+	"PhoenixOracle/core/keystore/keys/ethkey"
+	"PhoenixOracle/core/service/jobs/request"
+	"PhoenixOracle/db/models"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// DirectRequestEarningResource represents the PHB earned by fulfilling
+// direct requests during one period, for one job or one requester
+type DirectRequestEarningResource struct {
+	JAID
+	JobID       *int32          `json:"jobID"`
+	Requester   *common.Address `json:"requester"`
+	PeriodStart time.Time       `json:"periodStart"`
+	Fulfilled   int             `json:"fulfilled"`
+	Earned      assets.Phb      `json:"earned"`
+}
+
+func (r DirectRequestEarningResource) GetName() string {
+	return "direct_request_earnings"
+}
+
+func NewDirectRequestEarningResources(earnings []request.Earning) []DirectRequestEarningResource {
+	rs := []DirectRequestEarningResource{}
+	for _, e := range earnings {
+		var group string
+		switch {
+		case e.Requester != nil:
+			group = e.Requester.Hex()
+		case e.JobID != nil:
+			group = fmt.Sprintf("%d", *e.JobID)
+		default:
+			group = "deleted"
+		}
+		rs = append(rs, DirectRequestEarningResource{
+			JAID:        NewJAID(fmt.Sprintf("%s-%s", group, e.PeriodStart.Format("2006-01-02"))),
+			JobID:       e.JobID,
+			Requester:   e.Requester,
+			PeriodStart: e.PeriodStart,
+			Fulfilled:   e.Fulfilled,
+			Earned:      e.Earned,
+		})
+	}
+	return rs
+}
+
+// OperatorWithdrawalResource represents a withdraw call submitted to an
+// Operator contract
+type OperatorWithdrawalResource struct {
+	JAID
+	ContractAddress string        `json:"contractAddress"`
+	Recipient       string        `json:"recipient"`
+	FromAddress     string        `json:"fromAddress"`
+	Amount          assets.Phb    `json:"amount"`
+	Withdrawable    assets.Phb    `json:"withdrawable"`
+	Calldata        hexutil.Bytes `json:"calldata"`
+	EthTxID         int64         `json:"ethTxID"`
+}
+
+func (r OperatorWithdrawalResource) GetName() string {
+	return "operator_withdrawals"
+}
+
+func NewOperatorWithdrawalResource(req request.WithdrawRequest, from common.Address, args request.WithdrawArgs, ethTxID int64) *OperatorWithdrawalResource {
+	return &OperatorWithdrawalResource{
+		JAID:            NewJAID(fmt.Sprintf("%d", ethTxID)),
+		ContractAddress: req.ContractAddress.String(),
+		Recipient:       req.Recipient.String(),
+		FromAddress:     from.Hex(),
+		Amount:          args.Amount,
+		Withdrawable:    args.Withdrawable,
+		Calldata:        args.Calldata,
+		EthTxID:         ethTxID,
+	}
+}
+
+// RequesterPolicyResource represents a node-level policy for the requests of
+// a requester, or the default policy when Requester is nil
+type RequesterPolicyResource struct {
+	JAID
+	Requester          *ethkey.EIP55Address `json:"requester"`
+	Denied             bool                 `json:"denied"`
+	MinContractPayment *assets.Phb          `json:"minContractPayment"`
+	MinPaymentPerGwei  *assets.Phb          `json:"minPaymentPerGwei"`
+	MaxRequests        uint32               `json:"maxRequests"`
+	RateLimitPeriod    models.Interval      `json:"rateLimitPeriod"`
+	NoRateLimit        bool                 `json:"noRateLimit"`
+	CreatedAt          time.Time            `json:"createdAt"`
+	UpdatedAt          time.Time            `json:"updatedAt"`
+}
+
+func (r RequesterPolicyResource) GetName() string {
+	return "requester_policies"
+}
+
+func NewRequesterPolicyResource(p request.RequesterPolicy) *RequesterPolicyResource {
+	return &RequesterPolicyResource{
+		JAID:               NewJAIDInt64(p.ID),
+		Requester:          p.Requester,
+		Denied:             p.Denied,
+		MinContractPayment: p.MinContractPayment,
+		MinPaymentPerGwei:  p.MinPaymentPerGwei,
+		MaxRequests:        p.MaxRequests,
+		RateLimitPeriod:    p.RateLimitPeriod,
+		NoRateLimit:        p.NoRateLimit,
+		CreatedAt:          p.CreatedAt,
+		UpdatedAt:          p.UpdatedAt,
+	}
+}
+
+func NewRequesterPolicyResources(policies []request.RequesterPolicy) []RequesterPolicyResource {
+	rs := []RequesterPolicyResource{}
+	for _, p := range policies {
+		rs = append(rs, *NewRequesterPolicyResource(p))
+	}
+	return rs
+}
@@ -0,0 +1,153 @@
+package presenters
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"PhoenixOracle/core/service/jobs/offchainreporting"
+	ocrnetworking "PhoenixOracle/lib/libocr/networking"
+)
+
+// P2PConnectionResource represents an open connection to a remote peer
+type P2PConnectionResource struct {
+	PeerID        string                        `json:"peerID"`
+	Stack         ocrnetworking.NetworkingStack `json:"stack"`
+	Direction     string                        `json:"direction"`
+	RemoteAddress string                        `json:"remoteAddress"`
+	Opened        time.Time                     `json:"opened"`
+	LatencyMillis int64                         `json:"latencyMillis"`
+	Streams       int                           `json:"streams"`
+}
+
+// P2PPeerAddressesResource represents the known addresses of a remote peer
+type P2PPeerAddressesResource struct {
+	PeerID    string   `json:"peerID"`
+	Addresses []string `json:"addresses"`
+}
+
+// P2PAnnouncementResource represents an announcement received by the v2
+// networking stack
+type P2PAnnouncementResource struct {
+	PeerID       string        `json:"peerID"`
+	Announcement hexutil.Bytes `json:"announcement"`
+	UpdatedAt    time.Time     `json:"updatedAt"`
+}
+
+// P2PMessageCountersResource represents the OCR messages exchanged with a
+// remote peer
+type P2PMessageCountersResource struct {
+	PeerID        string `json:"peerID"`
+	Sent          uint64 `json:"sent"`
+	Received      uint64 `json:"received"`
+	Dropped       uint64 `json:"dropped"`
+	BytesSent     uint64 `json:"bytesSent"`
+	BytesReceived uint64 `json:"bytesReceived"`
+}
+
+// P2PDiagnosticsResource represents the connectivity of the P2P peer of this
+// node. Connections is empty with the V2 networking stack, whose connections
+// are not tracked.
+type P2PDiagnosticsResource struct {
+	JAID
+	PeerID          string                        `json:"peerID"`
+	Stack           ocrnetworking.NetworkingStack `json:"stack"`
+	Connections     []P2PConnectionResource       `json:"connections"`
+	PeerAddresses   []P2PPeerAddressesResource    `json:"peerAddresses"`
+	Announcements   []P2PAnnouncementResource     `json:"announcements"`
+	MessageCounters []P2PMessageCountersResource  `json:"messageCounters"`
+	BannedPeers     []string                      `json:"bannedPeers"`
+}
+
+// GetName implements the api2go EntityNamer interface
+func (r P2PDiagnosticsResource) GetName() string {
+	return "p2p_diagnostics"
+}
+
+// NewP2PDiagnosticsResource constructs a new P2PDiagnosticsResource
+func NewP2PDiagnosticsResource(peerID string, d ocrnetworking.Diagnostics, conns []ocrnetworking.ConnectionInfo, anns []offchainreporting.DiscoveredAnnouncement) *P2PDiagnosticsResource {
+	r := &P2PDiagnosticsResource{
+		JAID:            NewJAID(peerID),
+		PeerID:          peerID,
+		Stack:           d.NetworkingStack(),
+		Connections:     []P2PConnectionResource{},
+		PeerAddresses:   []P2PPeerAddressesResource{},
+		Announcements:   []P2PAnnouncementResource{},
+		MessageCounters: []P2PMessageCountersResource{},
+		BannedPeers:     []string{},
+	}
+
+	for _, c := range conns {
+		r.Connections = append(r.Connections, P2PConnectionResource{
+			PeerID:        c.PeerID,
+			Stack:         c.Stack,
+			Direction:     c.Direction,
+			RemoteAddress: c.RemoteAddress,
+			Opened:        c.Opened,
+			LatencyMillis: c.Latency.Milliseconds(),
+			Streams:       c.Streams,
+		})
+	}
+	for id, addrs := range d.PeerAddresses() {
+		r.PeerAddresses = append(r.PeerAddresses, P2PPeerAddressesResource{PeerID: id, Addresses: addrs})
+	}
+	sort.Slice(r.PeerAddresses, func(i, j int) bool { return r.PeerAddresses[i].PeerID < r.PeerAddresses[j].PeerID })
+	for _, a := range anns {
+		r.Announcements = append(r.Announcements, P2PAnnouncementResource{
+			PeerID:       a.PeerID,
+			Announcement: a.Announcement,
+			UpdatedAt:    a.UpdatedAt,
+		})
+	}
+	for id, c := range d.MessageCounters() {
+		r.MessageCounters = append(r.MessageCounters, P2PMessageCountersResource{
+			PeerID:        id,
+			Sent:          c.Sent,
+			Received:      c.Received,
+			Dropped:       c.Dropped,
+			BytesSent:     c.BytesSent,
+			BytesReceived: c.BytesReceived,
+		})
+	}
+	sort.Slice(r.MessageCounters, func(i, j int) bool { return r.MessageCounters[i].PeerID < r.MessageCounters[j].PeerID })
+	r.BannedPeers = append(r.BannedPeers, d.BannedPeers()...)
+
+	return r
+}
+
+// P2PBootstrapPeerListResource represents a shared list of bootstrap peers
+// that OCR jobs can reference by name
+type P2PBootstrapPeerListResource struct {
+	JAID
+	Name      string    `json:"name"`
+	Peers     []string  `json:"peers"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// GetName implements the api2go EntityNamer interface
+func (r P2PBootstrapPeerListResource) GetName() string {
+	return "p2p_bootstrap_peer_lists"
+}
+
+// NewP2PBootstrapPeerListResource constructs a new P2PBootstrapPeerListResource
+func NewP2PBootstrapPeerListResource(list offchainreporting.BootstrapPeerList) *P2PBootstrapPeerListResource {
+	return &P2PBootstrapPeerListResource{
+		JAID:      NewJAID(list.Name),
+		Name:      list.Name,
+		Peers:     list.Peers,
+		CreatedAt: list.CreatedAt,
+		UpdatedAt: list.UpdatedAt,
+	}
+}
+
+// NewP2PBootstrapPeerListResources constructs a slice of
+// P2PBootstrapPeerListResource
+func NewP2PBootstrapPeerListResources(lists []offchainreporting.BootstrapPeerList) []P2PBootstrapPeerListResource {
+	rs := []P2PBootstrapPeerListResource{}
+	for _, list := range lists {
+		rs = append(rs, *NewP2PBootstrapPeerListResource(list))
+	}
+	return rs
+}
@@ -14,6 +14,7 @@ import (
 
 type PipelineRunResource struct {
 	JAID
+	State        pipeline.RunStatus        `json:"state"`
 	Outputs      []*string                 `json:"outputs"`
 	Errors       []*string                 `json:"errors"`
 	Inputs       pipeline.JSONSerializable `json:"inputs"`
@@ -74,6 +75,7 @@ func NewPipelineRunResource(pr pipeline.Run) PipelineRunResource {
 
 	return PipelineRunResource{
 		JAID:         NewJAIDInt64(pr.ID),
+		State:        pr.State,
 		Outputs:      outputs,
 		Errors:       errors,
 		Inputs:       pr.Inputs,